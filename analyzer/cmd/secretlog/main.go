@@ -0,0 +1,19 @@
+// Command secretlog runs the secretlog analyzer, standalone or as a vet tool:
+//
+//	go vet -vettool=$(which secretlog) ./...
+//
+// The analyzer is a separate module, because golang.org/x/tools only supports
+// the two latest Go releases: building it requires the Go version of its
+// go.mod, while the library itself supports Go 1.21. It checks code targeting
+// any Go version.
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/juev/go-password/analyzer/secretlog"
+)
+
+func main() {
+	singlechecker.Main(secretlog.Analyzer)
+}
@@ -0,0 +1,160 @@
+// Package secretlog defines an analyzer reporting secrets passed to logging
+// and formatting functions.
+//
+// The password.Secret, password.Password and render.Credential types of
+// github.com/juev/go-password redact themselves when printed, so passing them
+// to a logger is at best useless and often a sign that the secret was meant to
+// be logged. Passing the result of Secret.ExposeSecret, Password.Reveal or
+// Password.Bytes to a logger leaks the secret. Both are reported.
+package secretlog
+
+import (
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// Analyzer reports secrets passed to logging and formatting functions.
+var Analyzer = &analysis.Analyzer{
+	Name:     "secretlog",
+	Doc:      "report secrets of github.com/juev/go-password passed to logging and formatting functions",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// module is the import path of the module defining the secret types.
+const module = "github.com/juev/go-password"
+
+// secretTypes are the secret types, by package path.
+var secretTypes = map[string][]string{
+	module + "/password": {"Secret", "Password"},
+	module + "/render":   {"Credential"},
+}
+
+// exposers are the methods returning the secret of a secret type, with the
+// name of the type.
+var exposers = map[string]string{
+	"ExposeSecret": "Secret",
+	"Reveal":       "Password",
+	"Bytes":        "Password",
+}
+
+// sinks are the logging and formatting functions, by package path. Methods of
+// the loggers of the same packages are sinks too.
+var sinks = map[string]map[string]bool{
+	"fmt": {
+		"Print": true, "Printf": true, "Println": true,
+		"Sprint": true, "Sprintf": true, "Sprintln": true,
+		"Fprint": true, "Fprintf": true, "Fprintln": true,
+		"Append": true, "Appendf": true, "Appendln": true,
+		"Errorf": true,
+	},
+	"log": {
+		"Print": true, "Printf": true, "Println": true,
+		"Fatal": true, "Fatalf": true, "Fatalln": true,
+		"Panic": true, "Panicf": true, "Panicln": true,
+	},
+	"log/slog": {
+		"Debug": true, "DebugContext": true,
+		"Info": true, "InfoContext": true,
+		"Warn": true, "WarnContext": true,
+		"Error": true, "ErrorContext": true,
+		"Log": true, "LogAttrs": true,
+		"Any": true, "String": true, "Group": true,
+	},
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		name, ok := sink(pass.TypesInfo, call)
+		if !ok {
+			return
+		}
+
+		for _, arg := range call.Args {
+			if t, ok := secretType(pass.TypesInfo.TypeOf(arg)); ok {
+				pass.Reportf(arg.Pos(), "%s passed to %s", t, name)
+				continue
+			}
+			if m, ok := exposed(pass.TypesInfo, arg); ok {
+				pass.Reportf(arg.Pos(), "secret exposed by %s passed to %s", m, name)
+			}
+		}
+	})
+	return nil, nil
+}
+
+// sink returns the name of the function called, if it is a sink.
+func sink(info *types.Info, call *ast.CallExpr) (string, bool) {
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return "", false
+	}
+
+	names, ok := sinks[fn.Pkg().Path()]
+	if !ok {
+		return "", false
+	}
+	if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+		// Methods of loggers, such as (*slog.Logger).Info.
+		if !names[fn.Name()] {
+			return "", false
+		}
+		return types.TypeString(recv.Type(), nil) + "." + fn.Name(), true
+	}
+	if !names[fn.Name()] {
+		return "", false
+	}
+	return fn.Pkg().Path() + "." + fn.Name(), true
+}
+
+// secretType returns the name of t if it is a secret type or a pointer to one.
+func secretType(t types.Type) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	if p, ok := t.Underlying().(*types.Pointer); ok {
+		t = p.Elem()
+	}
+
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return "", false
+	}
+	for _, name := range secretTypes[named.Obj().Pkg().Path()] {
+		if named.Obj().Name() == name {
+			return named.Obj().Pkg().Name() + "." + name, true
+		}
+	}
+	return "", false
+}
+
+// exposed returns the name of the method called by expr, if it is a call to a
+// method exposing the secret of a secret type.
+func exposed(info *types.Info, expr ast.Expr) (string, bool) {
+	call, ok := astutil.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	fn, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok {
+		return "", false
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil || exposers[fn.Name()] == "" {
+		return "", false
+	}
+	t, ok := secretType(recv.Type())
+	if !ok || !strings.HasSuffix(t, "."+exposers[fn.Name()]) {
+		return "", false
+	}
+	return t + "." + fn.Name(), true
+}
@@ -0,0 +1,13 @@
+package secretlog_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/juev/go-password/analyzer/secretlog"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), secretlog.Analyzer, "example")
+}
@@ -0,0 +1,33 @@
+package example
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+
+	"github.com/juev/go-password/password"
+)
+
+func use(s password.Secret, logger *slog.Logger) {
+	fmt.Println(s)                                     // want `password.Secret passed to fmt.Println`
+	fmt.Printf("%s\n", &s)                             // want `password.Secret passed to fmt.Printf`
+	log.Printf("pw=%s", s.ExposeSecret())              // want `secret exposed by password.Secret.ExposeSecret passed to log.Printf`
+	slog.Info("generated", "pw", s)                    // want `password.Secret passed to log/slog.Info`
+	logger.Info("generated", "pw", (s.ExposeSecret())) // want `secret exposed by password.Secret.ExposeSecret passed to \*log/slog.Logger.Info`
+
+	// Using the secret is fine.
+	os.Setenv("PASSWORD", s.ExposeSecret())
+	_ = len(s.ExposeSecret())
+	fmt.Println("generated a password")
+}
+
+func usePassword(p password.Password) {
+	fmt.Printf("%v\n", p)                   // want `password.Password passed to fmt.Printf`
+	log.Println(p.Reveal())                 // want `secret exposed by password.Password.Reveal passed to log.Println`
+	slog.Info("generated", "pw", p.Bytes()) // want `secret exposed by password.Password.Bytes passed to log/slog.Info`
+
+	// Using the password is fine.
+	os.Setenv("PASSWORD", p.Reveal())
+	p.Zero()
+}
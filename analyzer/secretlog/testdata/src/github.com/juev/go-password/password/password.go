@@ -0,0 +1,17 @@
+package password
+
+type Secret struct{ value string }
+
+func (s Secret) ExposeSecret() string { return s.value }
+
+func (s Secret) String() string { return "[REDACTED]" }
+
+type Password struct{ b []byte }
+
+func (p Password) Reveal() string { return string(p.b) }
+
+func (p Password) Bytes() []byte { return p.b }
+
+func (p Password) Zero() { clear(p.b) }
+
+func (p Password) String() string { return "[REDACTED]" }
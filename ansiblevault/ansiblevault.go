@@ -0,0 +1,217 @@
+// Package ansiblevault encrypts generated secrets in the Ansible Vault format,
+// so that generated credentials can be committed to playbook repositories and
+// decrypted by ansible-vault with the vault password.
+//
+// Vaults use the 1.1 format, or the 1.2 format when they carry a vault ID
+// label. Both encrypt with AES-256-CTR and authenticate with HMAC-SHA256,
+// with keys derived from the vault password with PBKDF2.
+//
+//	pw, err := password.Generate(password.Input{Length: 32, Digits: 6, Symbols: 6})
+//	if err != nil {
+//		return err
+//	}
+//	vault, err := ansiblevault.Encrypt([]byte(pw), vaultPassword, ansiblevault.Options{Label: "prod"})
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Print(ansiblevault.Variable("db_password", vault))
+package ansiblevault
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Header is the prefix of the first line of a vault.
+const Header = "$ANSIBLE_VAULT"
+
+const (
+	// iterations is the PBKDF2 iteration count of Ansible.
+	iterations = 10000
+
+	// saltSize is the size of the salt, in bytes.
+	saltSize = 32
+
+	// lineLength is the length of the lines of hex of a vault.
+	lineLength = 80
+
+	// indent is the indentation of inline vaults, as written by ansible-vault
+	// encrypt_string.
+	indent = "          "
+)
+
+var (
+	// ErrInvalidVault is the error returned when a vault cannot be parsed.
+	ErrInvalidVault = errors.New("invalid vault")
+
+	// ErrInvalidLabel is the error returned when a vault ID label contains a
+	// semicolon or whitespace.
+	ErrInvalidLabel = errors.New("invalid vault ID label")
+
+	// ErrIntegrity is the error returned when the HMAC of a vault does not
+	// match, because the vault password is wrong or the vault was modified.
+	ErrIntegrity = errors.New("vault HMAC mismatch")
+)
+
+// Options used to define options for Encrypt.
+type Options struct {
+	// Label is the vault ID label, such as "prod". If set, the vault uses the
+	// 1.2 format, which records it.
+	Label string
+
+	// Reader is the source of randomness of the salt. The default is
+	// crypto/rand.Reader.
+	Reader io.Reader
+	_      struct{}
+}
+
+// Encrypt returns the vault of the plaintext encrypted with the vault
+// password, ending with a newline.
+func Encrypt(plaintext, vaultPassword []byte, opts Options) (string, error) {
+	header := Header + ";1.1;AES256"
+	if opts.Label != "" {
+		if strings.ContainsFunc(opts.Label, func(r rune) bool { return r == ';' || r <= ' ' }) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidLabel, opts.Label)
+		}
+		header = Header + ";1.2;AES256;" + opts.Label
+	}
+
+	r := opts.Reader
+	if r == nil {
+		r = rand.Reader
+	}
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	cipherKey, hmacKey, iv := deriveKeys(vaultPassword, salt)
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return "", err
+	}
+
+	// Ansible pads the plaintext with PKCS #7 although CTR does not need it.
+	n := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := append(bytes.Clone(plaintext), bytes.Repeat([]byte{byte(n)}, n)...)
+	cipher.NewCTR(block, iv).XORKeyStream(ciphertext, ciphertext)
+
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(ciphertext)
+	body := hex.EncodeToString([]byte(hex.EncodeToString(salt) + "\n" +
+		hex.EncodeToString(mac.Sum(nil)) + "\n" + hex.EncodeToString(ciphertext)))
+
+	var b strings.Builder
+	b.WriteString(header + "\n")
+	for i := 0; i < len(body); i += lineLength {
+		b.WriteString(body[i:min(i+lineLength, len(body))] + "\n")
+	}
+	return b.String(), nil
+}
+
+// Decrypt returns the plaintext of the vault, or ErrIntegrity if the vault
+// password is wrong. Leading indentation, as in inline vaults, is ignored.
+func Decrypt(vault string, vaultPassword []byte) ([]byte, error) {
+	lines := strings.Fields(vault)
+	if len(lines) < 2 {
+		return nil, ErrInvalidVault
+	}
+	header := strings.Split(lines[0], ";")
+	if header[0] != Header || len(header) < 3 || header[2] != "AES256" ||
+		!(header[1] == "1.1" && len(header) == 3 || header[1] == "1.2" && len(header) == 4) {
+		return nil, fmt.Errorf("%w: unsupported header %q", ErrInvalidVault, lines[0])
+	}
+
+	body, err := hex.DecodeString(strings.Join(lines[1:], ""))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidVault, err)
+	}
+	parts := strings.Split(string(body), "\n")
+	if len(parts) != 3 {
+		return nil, ErrInvalidVault
+	}
+	var fields [3][]byte
+	for i, part := range parts {
+		if fields[i], err = hex.DecodeString(part); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidVault, err)
+		}
+	}
+	salt, sum, ciphertext := fields[0], fields[1], fields[2]
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, ErrInvalidVault
+	}
+
+	cipherKey, hmacKey, iv := deriveKeys(vaultPassword, salt)
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(ciphertext)
+	if !hmac.Equal(mac.Sum(nil), sum) {
+		return nil, ErrIntegrity
+	}
+
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCTR(block, iv).XORKeyStream(plaintext, ciphertext)
+
+	n := int(plaintext[len(plaintext)-1])
+	if n < 1 || n > aes.BlockSize || !bytes.Equal(plaintext[len(plaintext)-n:], bytes.Repeat([]byte{byte(n)}, n)) {
+		return nil, fmt.Errorf("%w: invalid padding", ErrInvalidVault)
+	}
+	return plaintext[:len(plaintext)-n], nil
+}
+
+// Variable returns the YAML variable of the given name holding the vault
+// inline, as written by ansible-vault encrypt_string.
+func Variable(name, vault string) string {
+	var b strings.Builder
+	b.WriteString(name + ": !vault |\n")
+	for _, line := range strings.Fields(vault) {
+		b.WriteString(indent + line + "\n")
+	}
+	return b.String()
+}
+
+// deriveKeys returns the AES key, HMAC key and CTR initial counter derived from
+// the vault password and salt.
+func deriveKeys(vaultPassword, salt []byte) (cipherKey, hmacKey, iv []byte) {
+	key := pbkdf2SHA256(vaultPassword, salt, iterations, 80)
+	return key[:32], key[32:64], key[64:]
+}
+
+// pbkdf2SHA256 returns the key of the given size derived from the password and
+// salt with PBKDF2-HMAC-SHA256, as specified by RFC 8018.
+func pbkdf2SHA256(pw, salt []byte, iterations, size int) []byte {
+	prf := hmac.New(sha256.New, pw)
+	key := make([]byte, 0, size+sha256.Size)
+	u := make([]byte, sha256.Size)
+	for block := uint32(1); len(key) < size; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, block))
+		u = prf.Sum(u[:0])
+		t := bytes.Clone(u)
+
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:size]
+}
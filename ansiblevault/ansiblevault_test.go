@@ -0,0 +1,110 @@
+package ansiblevault
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// vault11 is "s3cr3t-value!" encrypted with the vault password "vaultpw" and a
+// salt of 32 bytes 0x07, checked with the openssl command line.
+const vault11 = `$ANSIBLE_VAULT;1.1;AES256
+30373037303730373037303730373037303730373037303730373037303730373037303730373037
+3037303730373037303730373037303730373037303730370a336463343866666435656131663962
+31393337616236373532626234626432643264643431323662326533323039323538396563623964
+3462306133643363340a363233306166316665643933366636636534633762326665373737656139
+3564
+`
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	salt := bytes.Repeat([]byte{7}, saltSize)
+	got, err := Encrypt([]byte("s3cr3t-value!"), []byte("vaultpw"), Options{Reader: bytes.NewReader(salt)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != vault11 {
+		t.Errorf("expected %v to be %v", got, vault11)
+	}
+
+	got, err = Encrypt([]byte("s3cr3t-value!"), []byte("vaultpw"), Options{Label: "prod", Reader: bytes.NewReader(salt)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := strings.Replace(vault11, ";1.1;AES256", ";1.2;AES256;prod", 1); got != want {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	t.Parallel()
+
+	for _, plaintext := range []string{"", "x", "exactly 16 bytes", strings.Repeat("long secret ", 20)} {
+		for _, label := range []string{"", "dev"} {
+			vault, err := Encrypt([]byte(plaintext), []byte("vaultpw"), Options{Label: label})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, line := range strings.Split(strings.TrimSuffix(vault, "\n"), "\n") {
+				if len(line) > lineLength {
+					t.Errorf("line %q is longer than %d", line, lineLength)
+				}
+			}
+
+			got, err := Decrypt(vault, []byte("vaultpw"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != plaintext {
+				t.Errorf("expected %q to be %q", got, plaintext)
+			}
+
+			if _, err := Decrypt(vault, []byte("wrong")); !errors.Is(err, ErrIntegrity) {
+				t.Errorf("expected %v to be %v", err, ErrIntegrity)
+			}
+		}
+	}
+}
+
+func TestVariable(t *testing.T) {
+	t.Parallel()
+
+	got := Variable("db_password", vault11)
+	if !strings.HasPrefix(got, "db_password: !vault |\n          $ANSIBLE_VAULT;1.1;AES256\n          3037") {
+		t.Errorf("unexpected variable %q", got)
+	}
+
+	// Inline vaults decrypt once indented.
+	plaintext, err := Decrypt(strings.TrimPrefix(got, "db_password: !vault |\n"), []byte("vaultpw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != "s3cr3t-value!" {
+		t.Errorf("expected %q to be %q", plaintext, "s3cr3t-value!")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, label := range []string{"a;b", "a b", "a\nb"} {
+		if _, err := Encrypt([]byte("x"), []byte("pw"), Options{Label: label}); !errors.Is(err, ErrInvalidLabel) {
+			t.Errorf("expected %v to be %v", err, ErrInvalidLabel)
+		}
+	}
+
+	for _, vault := range []string{
+		"",
+		"$ANSIBLE_VAULT;1.1;AES256",
+		"$ANSIBLE_VAULT;1.0;AES\n3030",
+		"$ANSIBLE_VAULT;1.2;AES256\n3030",
+		"$ANSIBLE_VAULT;1.1;AES256\nzz",
+		"$ANSIBLE_VAULT;1.1;AES256\n3030",
+	} {
+		if _, err := Decrypt(vault, []byte("pw")); !errors.Is(err, ErrInvalidVault) {
+			t.Errorf("expected %v to be %v", err, ErrInvalidVault)
+		}
+	}
+}
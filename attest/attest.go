@@ -0,0 +1,318 @@
+// Package attest issues signed attestations that credentials were generated,
+// rather than chosen by a person, under a given policy at a given time, for
+// regulated environments which must prove it to auditors later.
+//
+// An Attestation holds a payload of Claims, encoded as JSON in the SchemaV1
+// schema, and the signature of the payload bytes, made with HMAC-SHA256 or
+// Ed25519. The payload is stored as signed, so that verifiers in any language
+// check the signature over the bytes as they are before decoding them, and
+// changes to password.Input never invalidate existing attestations. With
+// Ed25519, auditors verify attestations with the public key alone.
+//
+// The claims hold a fingerprint of the credential, never the credential
+// itself: an HMAC-SHA256 of the credential with a random salt and, if given, a
+// fingerprint key. Without a key, anyone holding the attestation can test
+// guesses against the fingerprint, which is only safe for credentials with
+// plenty of entropy; attestations of PINs and short codes need a key.
+//
+//	pw, a, err := attest.Generate(password.NewGenerator(), "pci-dss", input, attest.Ed25519Signer{Key: priv}, attest.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	b, err := json.Marshal(a)
+//	...
+//	claims, err := a.Verify(attest.Ed25519Verifier{Key: pub})
+//	ok := claims.Matches(pw, nil)
+package attest
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juev/go-password/password"
+)
+
+// SchemaV1 identifies the first version of the schema of the claims.
+const SchemaV1 = "go-password/attestation/v1"
+
+// saltSize is the size of the fingerprint salt, in bytes.
+const saltSize = 16
+
+// Algorithms of signatures.
+const (
+	// AlgorithmHMAC is the algorithm of HMAC-SHA256 signatures.
+	AlgorithmHMAC = "HS256"
+
+	// AlgorithmEd25519 is the algorithm of Ed25519 signatures.
+	AlgorithmEd25519 = "EdDSA"
+)
+
+var (
+	// ErrInvalidSignature is the error returned when the signature of an
+	// attestation does not verify.
+	ErrInvalidSignature = errors.New("invalid attestation signature")
+
+	// ErrAlgorithm is the error returned when an attestation is verified with
+	// a key of another algorithm.
+	ErrAlgorithm = errors.New("attestation algorithm mismatch")
+
+	// ErrVersion is the error returned for claims of an unknown schema.
+	ErrVersion = errors.New("unsupported attestation schema")
+)
+
+// Signer signs attestations.
+type Signer interface {
+	Algorithm() string
+	Sign(msg []byte) ([]byte, error)
+}
+
+// Verifier verifies the signatures of attestations.
+type Verifier interface {
+	Algorithm() string
+	Verify(msg, sig []byte) bool
+}
+
+// Options used to define options for Generate.
+type Options struct {
+	// KeyID, if set, identifies the signing key, so that verifiers can pick
+	// among rotated keys.
+	KeyID string
+
+	// FingerprintKey, if set, keys the fingerprint of the credential, so that
+	// it cannot be tested without the key.
+	FingerprintKey []byte
+
+	// Reader is the source of randomness of the fingerprint salt. The default
+	// is crypto/rand.Reader.
+	Reader io.Reader
+	_      struct{}
+}
+
+// Attestation is a signed payload of Claims.
+type Attestation struct {
+	// Payload is the JSON encoding of the Claims, as signed.
+	Payload []byte `json:"payload"`
+
+	// KeyID, if set, identifies the signing key.
+	KeyID string `json:"kid,omitempty"`
+
+	Algorithm string `json:"alg"`
+	Signature []byte `json:"signature"`
+}
+
+// Claims state that the credential of the fingerprint was generated with the
+// requirements of the policy at the time it was issued.
+type Claims struct {
+	// Schema is SchemaV1.
+	Schema string `json:"schema"`
+
+	// Fingerprint is the hex-encoded HMAC-SHA256 of the credential keyed by
+	// the salt or, if FingerprintKeyed is set, of the salt followed by the
+	// credential keyed by the fingerprint key. Salt is hex-encoded.
+	Fingerprint      string `json:"fingerprint"`
+	Salt             string `json:"salt"`
+	FingerprintKeyed bool   `json:"fingerprint_keyed"`
+
+	// Policy names the policy the credential was generated under, and
+	// Requirements are its requirements.
+	Policy       string       `json:"policy"`
+	Requirements Requirements `json:"requirements"`
+
+	// EntropyBits is the entropy of the generation, as computed by
+	// password.Generator.Entropy.
+	EntropyBits float64 `json:"entropy_bits"`
+
+	IssuedAt time.Time `json:"issued_at"`
+}
+
+// Requirements are the requirements of password.Input recorded by SchemaV1.
+type Requirements struct {
+	Length               int  `json:"length"`
+	Digits               int  `json:"digits"`
+	Symbols              int  `json:"symbols"`
+	MinDigits            int  `json:"min_digits"`
+	MaxDigits            int  `json:"max_digits"`
+	MinSymbols           int  `json:"min_symbols"`
+	MaxSymbols           int  `json:"max_symbols"`
+	NoUpper              bool `json:"no_upper"`
+	AllowRepeat          bool `json:"allow_repeat"`
+	PreserveClassOrder   bool `json:"preserve_class_order"`
+	MaxSameClassRun      int  `json:"max_same_class_run"`
+	RequireFromEachClass bool `json:"require_from_each_class"`
+}
+
+// requirementsOf returns the requirements of the input.
+func requirementsOf(in password.Input) Requirements {
+	return Requirements{
+		Length:               in.Length,
+		Digits:               in.Digits,
+		Symbols:              in.Symbols,
+		MinDigits:            in.MinDigits,
+		MaxDigits:            in.MaxDigits,
+		MinSymbols:           in.MinSymbols,
+		MaxSymbols:           in.MaxSymbols,
+		NoUpper:              in.NoUpper,
+		AllowRepeat:          in.AllowRepeat,
+		PreserveClassOrder:   in.PreserveClassOrder,
+		MaxSameClassRun:      in.MaxSameClassRun,
+		RequireFromEachClass: in.RequireFromEachClass,
+	}
+}
+
+// Generate generates a credential with the Generator and input, and returns it
+// with its attestation signed by s. The attestation is only ever issued for a
+// credential generated here, so that it cannot vouch for a chosen one.
+func Generate(g password.Generator, policy string, input password.Input, s Signer, opts Options) (string, Attestation, error) {
+	return generate(g, policy, input, s, opts, time.Now())
+}
+
+// generate is Generate at the given time.
+func generate(g password.Generator, policy string, input password.Input, s Signer, opts Options, now time.Time) (string, Attestation, error) {
+	r := opts.Reader
+	if r == nil {
+		r = rand.Reader
+	}
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		return "", Attestation{}, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+
+	bits, err := g.Entropy(input)
+	if err != nil {
+		return "", Attestation{}, err
+	}
+	pw, err := g.Generate(input)
+	if err != nil {
+		return "", Attestation{}, err
+	}
+
+	payload, err := json.Marshal(Claims{
+		Schema:           SchemaV1,
+		Fingerprint:      fingerprint(pw, salt, opts.FingerprintKey),
+		Salt:             hex.EncodeToString(salt),
+		FingerprintKeyed: opts.FingerprintKey != nil,
+		Policy:           policy,
+		Requirements:     requirementsOf(input),
+		EntropyBits:      bits,
+		IssuedAt:         now.UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		return "", Attestation{}, fmt.Errorf("failed to encode attestation: %w", err)
+	}
+	sig, err := s.Sign(payload)
+	if err != nil {
+		return "", Attestation{}, fmt.Errorf("failed to sign attestation: %w", err)
+	}
+	return pw, Attestation{
+		Payload:   payload,
+		KeyID:     opts.KeyID,
+		Algorithm: s.Algorithm(),
+		Signature: sig,
+	}, nil
+}
+
+// Verify checks the signature of the payload with v, and returns its claims.
+func (a Attestation) Verify(v Verifier) (Claims, error) {
+	if a.Algorithm != v.Algorithm() {
+		return Claims{}, fmt.Errorf("%w: %s, expected %s", ErrAlgorithm, a.Algorithm, v.Algorithm())
+	}
+	if !v.Verify(a.Payload, a.Signature) {
+		return Claims{}, ErrInvalidSignature
+	}
+
+	var c Claims
+	if err := json.Unmarshal(a.Payload, &c); err != nil {
+		return Claims{}, fmt.Errorf("failed to decode attestation: %w", err)
+	}
+	if c.Schema != SchemaV1 {
+		return Claims{}, fmt.Errorf("%w: %q", ErrVersion, c.Schema)
+	}
+	return c, nil
+}
+
+// Matches reports whether the claims are about the credential. The fingerprint
+// key must be given if the fingerprint is keyed, and nil otherwise.
+func (c Claims) Matches(pw string, fingerprintKey []byte) bool {
+	salt, err := hex.DecodeString(c.Salt)
+	if err != nil || c.FingerprintKeyed != (fingerprintKey != nil) {
+		return false
+	}
+	return hmac.Equal([]byte(c.Fingerprint), []byte(fingerprint(pw, salt, fingerprintKey)))
+}
+
+// fingerprint returns the fingerprint of the credential with the salt and
+// optional key.
+func fingerprint(pw string, salt, key []byte) string {
+	mac := hmac.New(sha256.New, salt)
+	if key != nil {
+		mac = hmac.New(sha256.New, key)
+		mac.Write(salt)
+	}
+	mac.Write([]byte(pw))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// HMAC signs and verifies attestations with HMAC-SHA256. The key is shared by
+// issuers and verifiers, which can thus also issue attestations.
+type HMAC struct {
+	Key []byte
+}
+
+// Algorithm returns AlgorithmHMAC.
+func (h HMAC) Algorithm() string {
+	return AlgorithmHMAC
+}
+
+// Sign returns the HMAC of the message.
+func (h HMAC) Sign(msg []byte) ([]byte, error) {
+	mac := hmac.New(sha256.New, h.Key)
+	mac.Write(msg)
+	return mac.Sum(nil), nil
+}
+
+// Verify reports whether sig is the HMAC of the message.
+func (h HMAC) Verify(msg, sig []byte) bool {
+	want, _ := h.Sign(msg)
+	return hmac.Equal(want, sig)
+}
+
+// Ed25519Signer signs attestations with an Ed25519 private key.
+type Ed25519Signer struct {
+	Key ed25519.PrivateKey
+}
+
+// Algorithm returns AlgorithmEd25519.
+func (s Ed25519Signer) Algorithm() string {
+	return AlgorithmEd25519
+}
+
+// Sign returns the Ed25519 signature of the message.
+func (s Ed25519Signer) Sign(msg []byte) ([]byte, error) {
+	if len(s.Key) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("invalid Ed25519 private key size %d", len(s.Key))
+	}
+	return ed25519.Sign(s.Key, msg), nil
+}
+
+// Ed25519Verifier verifies attestations with an Ed25519 public key.
+type Ed25519Verifier struct {
+	Key ed25519.PublicKey
+}
+
+// Algorithm returns AlgorithmEd25519.
+func (v Ed25519Verifier) Algorithm() string {
+	return AlgorithmEd25519
+}
+
+// Verify reports whether sig is a valid Ed25519 signature of the message.
+func (v Ed25519Verifier) Verify(msg, sig []byte) bool {
+	return len(v.Key) == ed25519.PublicKeySize && ed25519.Verify(v.Key, msg, sig)
+}
@@ -0,0 +1,124 @@
+package attest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juev/go-password/password"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := password.Input{Length: 20, Digits: 4, Symbols: 4}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 500, time.FixedZone("CET", 3600))
+
+	pw, a, err := generate(password.NewGenerator(), "pci-dss", input, Ed25519Signer{Key: priv}, Options{KeyID: "2024-03"}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Attestation
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	c, err := decoded.Verify(Ed25519Verifier{Key: pub})
+	if err != nil {
+		t.Fatalf("expected the decoded attestation to verify, got %v", err)
+	}
+	if c.Schema != SchemaV1 || c.Policy != "pci-dss" || c.Requirements.Digits != 4 || c.EntropyBits < 100 ||
+		!c.IssuedAt.Equal(time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)) || decoded.KeyID != "2024-03" {
+		t.Errorf("unexpected claims %+v", c)
+	}
+	if !c.Matches(pw, nil) || c.Matches(pw+"x", nil) || strings.Contains(string(a.Payload), pw) {
+		t.Errorf("unexpected fingerprint %q", c.Fingerprint)
+	}
+
+	forged := decoded
+	forged.Payload = bytes.Replace(forged.Payload, []byte("pci-dss"), []byte("none"), 1)
+	if _, err := forged.Verify(Ed25519Verifier{Key: pub}); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidSignature)
+	}
+	other, _, _ := ed25519.GenerateKey(nil)
+	if _, err := decoded.Verify(Ed25519Verifier{Key: other}); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidSignature)
+	}
+	if _, err := decoded.Verify(HMAC{Key: []byte("key")}); !errors.Is(err, ErrAlgorithm) {
+		t.Errorf("expected %v to be %v", err, ErrAlgorithm)
+	}
+}
+
+func TestGeneratePayload(t *testing.T) {
+	t.Parallel()
+
+	// The payload is pinned, so that its schema only changes with its version.
+	g := password.NewGenerator().WithReader(strings.NewReader(strings.Repeat("\xff", 4096)))
+	h := HMAC{Key: []byte("0123456789abcdef0123456789abcdef")}
+	input := password.Input{Length: 4, Digits: 4, AllowRepeat: true}
+	opts := Options{Reader: bytes.NewReader(make([]byte, saltSize))}
+	_, a, err := generate(g, "pin", input, h, opts, time.Unix(1700000000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"schema":"go-password/attestation/v1","fingerprint":"6af24f05f07f0af2470579bdc1a8a3dd141919065c90d8f06de734b391893750",` +
+		`"salt":"00000000000000000000000000000000","fingerprint_keyed":false,"policy":"pin",` +
+		`"requirements":{"length":4,"digits":4,"symbols":0,"min_digits":0,"max_digits":0,"min_symbols":0,"max_symbols":0,` +
+		`"no_upper":false,"allow_repeat":true,"preserve_class_order":false,"max_same_class_run":0,"require_from_each_class":false},` +
+		`"entropy_bits":13.287712379549449,"issued_at":"2023-11-14T22:13:20Z"}`
+	if string(a.Payload) != want {
+		t.Errorf("expected payload\n%s\nto be\n%s", a.Payload, want)
+	}
+}
+
+func TestGenerateKeyedFingerprint(t *testing.T) {
+	t.Parallel()
+
+	h := HMAC{Key: []byte("0123456789abcdef0123456789abcdef")}
+	key := []byte("fingerprint key")
+	pw, a, err := Generate(password.NewGenerator(), "pin", password.Input{Length: 6, Digits: 6, AllowRepeat: true}, h, Options{FingerprintKey: key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := a.Verify(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.FingerprintKeyed || !c.Matches(pw, key) {
+		t.Errorf("expected %q to match with the key", pw)
+	}
+	if c.Matches(pw, nil) || c.Matches(pw, []byte("other key")) {
+		t.Errorf("expected %q not to match without the key", pw)
+	}
+
+	if _, err := a.Verify(HMAC{Key: []byte("wrong")}); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidSignature)
+	}
+	if _, _, err := Generate(password.NewGenerator(), "pin", password.Input{Length: 2, Digits: 3}, h, Options{}); err == nil {
+		t.Error("expected an invalid input to fail")
+	}
+}
+
+func TestVerifySchema(t *testing.T) {
+	t.Parallel()
+
+	h := HMAC{Key: []byte("0123456789abcdef0123456789abcdef")}
+	payload := []byte(`{"schema":"go-password/attestation/v2"}`)
+	sig, _ := h.Sign(payload)
+	a := Attestation{Payload: payload, Algorithm: AlgorithmHMAC, Signature: sig}
+	if _, err := a.Verify(h); !errors.Is(err, ErrVersion) {
+		t.Errorf("expected %v to be %v", err, ErrVersion)
+	}
+}
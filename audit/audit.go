@@ -0,0 +1,108 @@
+// Package audit defines the events emitted when credentials are issued or
+// policies change, and sinks which deliver them to files and webhooks so that
+// issuance events reach SIEM systems out of the box.
+//
+// Events never contain secrets.
+package audit
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Event types.
+const (
+	// TypeGenerate is the type of events emitted when a credential is issued.
+	TypeGenerate = "generate"
+
+	// TypeRetrieve is the type of events emitted when a one-time credential
+	// is retrieved.
+	TypeRetrieve = "retrieve"
+
+	// TypeGenerateFailure is the type of events emitted when a credential
+	// could not be issued.
+	TypeGenerateFailure = "generate.failure"
+
+	// TypeAuthFailure is the type of events emitted when a request is rejected
+	// for missing or invalid credentials.
+	TypeAuthFailure = "auth.failure"
+
+	// TypeEscrowDeposit is the type of events emitted when a credential is
+	// deposited in escrow.
+	TypeEscrowDeposit = "escrow.deposit"
+
+	// TypeEscrowRecover is the type of events emitted when a credential is
+	// recovered from escrow.
+	TypeEscrowRecover = "escrow.recover"
+
+	// TypePolicyPut is the type of events emitted when a policy is created or
+	// updated.
+	TypePolicyPut = "policy.put"
+
+	// TypePolicyDelete is the type of events emitted when a policy is deleted.
+	TypePolicyDelete = "policy.delete"
+
+	// TypeUsageReset is the type of events emitted when the usage of all
+	// clients and policies is reset.
+	TypeUsageReset = "usage.reset"
+)
+
+// Event is an audit event.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Type   string    `json:"type"`
+	Policy string    `json:"policy,omitempty"`
+
+	// Client identifies the caller without revealing its credentials, such as
+	// a prefix of the hash of its token.
+	Client string `json:"client,omitempty"`
+
+	// RemoteAddr is the network address of the caller.
+	RemoteAddr string `json:"remote_addr,omitempty"`
+
+	// Record identifies the escrow record a credential was deposited in or
+	// recovered from, and Reason is the justification given for recovering it.
+	Record string `json:"record,omitempty"`
+	Reason string `json:"reason,omitempty"`
+
+	// RandomBytes is the number of bytes read from the entropy source to
+	// generate a credential.
+	RandomBytes uint64 `json:"random_bytes,omitempty"`
+
+	// Error describes why the operation failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Sink receives audit events. Implementations must be safe for concurrent use.
+type Sink interface {
+	Write(ctx context.Context, e Event) error
+}
+
+// SinkFunc is an adapter to allow the use of ordinary functions as sinks.
+type SinkFunc func(ctx context.Context, e Event) error
+
+// Write calls f(ctx, e).
+func (f SinkFunc) Write(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
+
+// multiSink writes to several sinks.
+type multiSink []Sink
+
+// MultiSink returns a Sink which writes every event to all of the given sinks,
+// returning the errors of all sinks which failed.
+func MultiSink(sinks ...Sink) Sink {
+	return multiSink(append([]Sink(nil), sinks...))
+}
+
+// Write writes the event to all sinks.
+func (m multiSink) Write(ctx context.Context, e Event) error {
+	var errs []error
+	for _, s := range m {
+		if err := s.Write(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestMultiSink(t *testing.T) {
+	t.Parallel()
+
+	errSink := errors.New("sink failed")
+
+	var got []string
+	s := MultiSink(
+		SinkFunc(func(_ context.Context, e Event) error {
+			got = append(got, "a:"+e.Type)
+			return nil
+		}),
+		SinkFunc(func(context.Context, Event) error {
+			return errSink
+		}),
+		SinkFunc(func(_ context.Context, e Event) error {
+			got = append(got, "c:"+e.Type)
+			return nil
+		}),
+	)
+
+	if err := s.Write(context.Background(), Event{Type: TypeGenerate}); !errors.Is(err, errSink) {
+		t.Errorf("expected %v to be %v", err, errSink)
+	}
+	if len(got) != 2 || got[0] != "a:generate" || got[1] != "c:generate" {
+		t.Errorf("expected every sink to receive the event, got %q", got)
+	}
+}
@@ -0,0 +1,113 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrWebhookStatus is the error returned when a webhook responds with a
+// non-successful status.
+var ErrWebhookStatus = errors.New("webhook returned unsuccessful status")
+
+// WebhookOptions used to define input parameters for NewWebhookSink.
+type WebhookOptions struct {
+	// Client is the HTTP client used to deliver events. The default is a
+	// client with a 10 second timeout.
+	Client *http.Client
+
+	// Header is added to every request, for example for authorization.
+	Header http.Header
+
+	// MaxRetries is the number of times delivery is retried after a network
+	// error, a 429 or a 5xx response. The default is 3.
+	MaxRetries int
+
+	// Backoff is the delay before the first retry, doubled for each further
+	// retry. The default is 500 milliseconds.
+	Backoff time.Duration
+	_       struct{}
+}
+
+// WebhookSink is a Sink which POSTs every event as JSON to a URL, retrying
+// transient failures with exponential backoff.
+type WebhookSink struct {
+	url  string
+	opts WebhookOptions
+}
+
+var _ Sink = (*WebhookSink)(nil)
+
+// NewWebhookSink creates a new WebhookSink delivering events to the given URL.
+func NewWebhookSink(url string, opts WebhookOptions) *WebhookSink {
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = 3
+	}
+	if opts.Backoff == 0 {
+		opts.Backoff = 500 * time.Millisecond
+	}
+	return &WebhookSink{url: url, opts: opts}
+}
+
+// Write delivers the event, blocking until it is accepted, retries are
+// exhausted or the context is done.
+func (s *WebhookSink) Write(ctx context.Context, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit event: %w", err)
+	}
+
+	backoff := s.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= s.opts.MaxRetries {
+			return err
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return fmt.Errorf("failed to deliver audit event: %w", ctx.Err())
+		case <-t.C:
+		}
+		backoff *= 2
+	}
+}
+
+// post delivers the body once and reports whether a failure may be retried.
+func (s *WebhookSink) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	for k, v := range s.opts.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("failed to deliver audit event: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("%w: %s", ErrWebhookStatus, resp.Status)
+}
@@ -0,0 +1,66 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookSink(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		statuses []int
+		err      error
+		attempts int32
+	}{
+		{"ok", []int{http.StatusNoContent}, nil, 1},
+		{"retry", []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK}, nil, 3},
+		{"exhausted", []int{http.StatusBadGateway}, ErrWebhookStatus, 3},
+		{"permanent", []int{http.StatusBadRequest}, ErrWebhookStatus, 1},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(attempts.Add(1)) - 1
+
+				var e Event
+				if err := json.NewDecoder(r.Body).Decode(&e); err != nil || e.Type != TypeGenerate {
+					t.Errorf("unexpected event %+v: %v", e, err)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("expected authorization header, got %q", got)
+				}
+
+				w.WriteHeader(tc.statuses[min(n, len(tc.statuses)-1)])
+			}))
+			defer ts.Close()
+
+			s := NewWebhookSink(ts.URL, WebhookOptions{
+				Header:     http.Header{"Authorization": {"Bearer secret"}},
+				MaxRetries: 2,
+				Backoff:    time.Millisecond,
+			})
+
+			err := s.Write(context.Background(), Event{Type: TypeGenerate})
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected %v to be %v", err, tc.err)
+			}
+			if got := attempts.Load(); got != tc.attempts {
+				t.Errorf("expected %d attempts, got %d", tc.attempts, got)
+			}
+		})
+	}
+}
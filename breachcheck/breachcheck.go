@@ -0,0 +1,141 @@
+// Package breachcheck checks passwords against the Pwned Passwords database of
+// Have I Been Pwned, without revealing them.
+//
+// Only the first 5 hexadecimal characters of the SHA-1 hash of the password
+// are sent to the range API, which returns the suffixes of all breached hashes
+// sharing that prefix; the match is made locally. Responses are padded with
+// fake entries, so that their size does not reveal the prefix either.
+//
+// A Client implements password.BreachChecker:
+//
+//	g := password.NewGenerator().WithBreachChecker(breachcheck.New(breachcheck.Options{}))
+//	res, err := g.GenerateUnbreached(ctx, input)
+package breachcheck
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juev/go-password/password"
+)
+
+// DefaultURL is the address of the Pwned Passwords range API.
+const DefaultURL = "https://api.pwnedpasswords.com/range/"
+
+// prefixLength is the length of the hash prefix sent to the API, in
+// hexadecimal characters.
+const prefixLength = 5
+
+var (
+	// ErrStatus is the error returned when the API responds with a
+	// non-successful status.
+	ErrStatus = errors.New("pwned passwords returned unsuccessful status")
+
+	// ErrInvalidResponse is the error returned when a line of the response
+	// cannot be parsed.
+	ErrInvalidResponse = errors.New("invalid pwned passwords response")
+)
+
+// Options used to define input parameters for New.
+type Options struct {
+	// URL is the address of the range API, to which the hash prefix is
+	// appended. The default is DefaultURL; a mirror of the database can be
+	// used instead.
+	URL string
+
+	// UserAgent is sent in the User-Agent header, which the API requires. The
+	// default is "go-password-breachcheck".
+	UserAgent string
+
+	// Client is the HTTP client. The default is a client with a 10 second
+	// timeout.
+	Client *http.Client
+	_      struct{}
+}
+
+// Client checks passwords with the range API.
+type Client struct {
+	opts Options
+}
+
+var _ password.BreachChecker = (*Client)(nil)
+
+// New creates a new Client from the given options.
+func New(opts Options) *Client {
+	if opts.URL == "" {
+		opts.URL = DefaultURL
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = "go-password-breachcheck"
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Client{opts: opts}
+}
+
+// Check returns the number of times the password appears in breaches, which is
+// zero if it was never breached.
+func (c *Client) Check(ctx context.Context, pw string) (int, error) {
+	sum := sha1.Sum([]byte(pw))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.opts.URL+prefix, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create pwned passwords request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.opts.UserAgent)
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := c.opts.Client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call pwned passwords: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		return 0, fmt.Errorf("%w: %s", ErrStatus, resp.Status)
+	}
+
+	s := bufio.NewScanner(io.LimitReader(resp.Body, 1<<22))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(line, ":")
+		if !ok {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidResponse, line)
+		}
+		if !strings.EqualFold(k, suffix) {
+			continue
+		}
+		// Padding entries have a count of zero.
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidResponse, line)
+		}
+		return n, nil
+	}
+	if err := s.Err(); err != nil {
+		return 0, fmt.Errorf("failed to read pwned passwords response: %w", err)
+	}
+	return 0, nil
+}
+
+// Check returns the number of times the password appears in breaches, with a
+// Client using the default options.
+func Check(ctx context.Context, pw string) (int, error) {
+	return New(Options{}).Check(ctx, pw)
+}
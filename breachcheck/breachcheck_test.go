@@ -0,0 +1,72 @@
+package breachcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/juev/go-password/password"
+)
+
+// testAPI is a fake range API knowing the hash of "password", with a padding
+// entry.
+func testAPI(tb testing.TB) *httptest.Server {
+	tb.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("User-Agent") == "" || r.Header.Get("Add-Padding") != "true" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/range/5BAA6":
+			fmt.Fprint(w, "003D68EB55068C33ACE09247EE4C639306B:3\r\n1E4C9B93F3F0682250B6CF8331B7EE68FD8:9659365\r\n")
+		case "/range/00000":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			fmt.Fprint(w, "0018A45C4D1DEF81644B54AB7F969B88D65:0\r\n")
+		}
+	}))
+	tb.Cleanup(ts.Close)
+	return ts
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	c := New(Options{URL: testAPI(t).URL + "/range/"})
+
+	n, err := c.Check(ctx, "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 9659365 {
+		t.Errorf("expected %v to be %v", n, 9659365)
+	}
+
+	if n, err := c.Check(ctx, "x7#Kq9!vLm2@"); err != nil || n != 0 {
+		t.Errorf("expected an unbreached password, got %v, %v", n, err)
+	}
+
+	// The SHA-1 hash of "acU8y" starts with 00000.
+	if _, err := c.Check(ctx, "acU8y"); !errors.Is(err, ErrStatus) {
+		t.Errorf("expected %v to be %v", err, ErrStatus)
+	}
+}
+
+func TestGenerateUnbreached(t *testing.T) {
+	t.Parallel()
+
+	g := password.NewGenerator().WithBreachChecker(New(Options{URL: testAPI(t).URL + "/range/"}))
+	res, err := g.GenerateUnbreached(context.Background(), password.Input{Length: 16, Digits: 3, Symbols: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 16 {
+		t.Errorf("expected 16 characters, got %q", res)
+	}
+}
@@ -0,0 +1,167 @@
+// Package bulk runs generation jobs producing many credentials, such as the
+// provisioning of accounts, which may be interrupted and resumed.
+//
+// A job reports its progress as a Cursor after every credential. If the job is
+// killed, running it again from the last persisted cursor continues with the
+// next credential instead of starting over. Resuming is deterministic in terms
+// of workflow, not randomness: the credentials generated after resuming are
+// new random ones.
+//
+// Jobs whose accounts need different requirements are read from CSV or JSON
+// Lines with ReadCSV and ReadJSONL, and generated with Generate, which reports
+// errors row by row.
+//
+//	job := bulk.Job{Input: password.Input{Length: 20, Digits: 4}, Total: 500}
+//	cursor, err := bulk.ParseCursor(saved)
+//	if err != nil {
+//		cursor = job.Start()
+//	}
+//	_, err = job.Run(ctx, cursor, func(c bulk.Cursor, pw string) error {
+//		if err := provision(c.Completed-1, pw); err != nil {
+//			return err
+//		}
+//		return save(c.String())
+//	})
+package bulk
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/juev/go-password/password"
+)
+
+var (
+	// ErrInvalidCursor is the error returned when a cursor token cannot be
+	// parsed or describes an impossible progress.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrCursorMismatch is the error returned when a job is resumed from the
+	// cursor of a job with another policy or size.
+	ErrCursorMismatch = errors.New("cursor does not belong to the job")
+)
+
+// Cursor is the serializable progress of a job. Its text form is a URL-safe
+// token.
+type Cursor struct {
+	// Completed is the number of credentials handled, and Total the number of
+	// credentials of the job.
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+
+	// PolicyHash identifies the password requirements of the job, as returned
+	// by PolicyHash, so that a cursor cannot resume another job.
+	PolicyHash string `json:"policy"`
+}
+
+// Done reports whether the job of the cursor is complete.
+func (c Cursor) Done() bool {
+	return c.Completed >= c.Total
+}
+
+// String returns the cursor token.
+func (c Cursor) String() string {
+	b, _ := c.MarshalText()
+	return string(b)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Cursor) MarshalText() ([]byte, error) {
+	b, err := json.Marshal(cursorJSON(c))
+	if err != nil {
+		return nil, err
+	}
+	return []byte(base64.RawURLEncoding.EncodeToString(b)), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Cursor) UnmarshalText(text []byte) error {
+	b, err := base64.RawURLEncoding.DecodeString(string(text))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+
+	var res cursorJSON
+	if err := json.Unmarshal(b, &res); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidCursor, err)
+	}
+	if res.Completed < 0 || res.Total < 0 || res.Completed > res.Total || res.PolicyHash == "" {
+		return ErrInvalidCursor
+	}
+	*c = Cursor(res)
+	return nil
+}
+
+// cursorJSON is the JSON form of a Cursor, without its text marshaling.
+type cursorJSON Cursor
+
+// ParseCursor parses a cursor token.
+func ParseCursor(token string) (Cursor, error) {
+	var c Cursor
+	if err := c.UnmarshalText([]byte(token)); err != nil {
+		return Cursor{}, err
+	}
+	return c, nil
+}
+
+// PolicyHash returns a short hash identifying the password requirements. The
+// character sets of the generator are not part of it.
+func PolicyHash(in password.Input) string {
+	b, _ := json.Marshal(in)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Job generates Total credentials with the same requirements.
+type Job struct {
+	Input password.Input
+	Total int
+
+	// Generator is the generator of the credentials. The default is
+	// password.NewGenerator().
+	Generator *password.Generator
+}
+
+// Start returns the cursor of the job before its first credential.
+func (j Job) Start() Cursor {
+	return Cursor{Total: j.Total, PolicyHash: PolicyHash(j.Input)}
+}
+
+// Run generates the credentials of the job after the given cursor, and calls
+// emit for each with the cursor to persist once the credential is handled. It
+// stops at the first error, returning the cursor of the last credential
+// emitted successfully, from which the job can be resumed.
+func (j Job) Run(ctx context.Context, c Cursor, emit func(c Cursor, pw string) error) (Cursor, error) {
+	if c.Total != j.Total || c.PolicyHash != PolicyHash(j.Input) {
+		return c, ErrCursorMismatch
+	}
+
+	gen := password.NewGenerator()
+	if j.Generator != nil {
+		gen = *j.Generator
+	}
+
+	for !c.Done() {
+		if err := ctx.Err(); err != nil {
+			return c, err
+		}
+
+		pw, err := gen.Generate(j.Input)
+		if err != nil {
+			return c, fmt.Errorf("failed to generate credential %d: %w", c.Completed, err)
+		}
+
+		next := c
+		next.Completed++
+		if err := emit(next, pw); err != nil {
+			return c, err
+		}
+		c = next
+	}
+	return c, nil
+}
@@ -0,0 +1,91 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juev/go-password/password"
+)
+
+func TestCursorToken(t *testing.T) {
+	t.Parallel()
+
+	c := Cursor{Completed: 3, Total: 10, PolicyHash: PolicyHash(password.Input{Length: 12})}
+	got, err := ParseCursor(c.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != c {
+		t.Errorf("expected %+v, got %+v", c, got)
+	}
+
+	for _, token := range []string{
+		"",
+		"not base64!",
+		Cursor{Completed: 11, Total: 10, PolicyHash: "x"}.String(),
+		Cursor{Completed: 1, Total: 10}.String(),
+	} {
+		if _, err := ParseCursor(token); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("expected %q to be rejected, got %v", token, err)
+		}
+	}
+}
+
+func TestPolicyHash(t *testing.T) {
+	t.Parallel()
+
+	a := PolicyHash(password.Input{Length: 12, Digits: 2})
+	if a != PolicyHash(password.Input{Length: 12, Digits: 2}) {
+		t.Error("expected the hash to be stable")
+	}
+	if a == PolicyHash(password.Input{Length: 12, Digits: 3}) {
+		t.Error("expected different policies to have different hashes")
+	}
+}
+
+func TestJobRun(t *testing.T) {
+	t.Parallel()
+
+	job := Job{Input: password.Input{Length: 12, Digits: 2}, Total: 10}
+	errStop := errors.New("stop")
+
+	// The job is killed while handling the fifth credential.
+	var handled []string
+	c, err := job.Run(context.Background(), job.Start(), func(c Cursor, pw string) error {
+		if c.Completed == 5 {
+			return errStop
+		}
+		handled = append(handled, pw)
+		return nil
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("expected %v to be %v", err, errStop)
+	}
+	if c.Completed != 4 || len(handled) != 4 {
+		t.Fatalf("expected 4 completed credentials, got %+v", c)
+	}
+
+	resumed, err := ParseCursor(c.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = job.Run(context.Background(), resumed, func(c Cursor, pw string) error {
+		if c.Completed != len(handled)+1 {
+			t.Errorf("expected credential %d, got cursor %+v", len(handled)+1, c)
+		}
+		handled = append(handled, pw)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Done() || len(handled) != 10 {
+		t.Errorf("expected the job to be done, got %+v after %d credentials", c, len(handled))
+	}
+
+	other := Job{Input: password.Input{Length: 16}, Total: 10}
+	if _, err := other.Run(context.Background(), resumed, nil); !errors.Is(err, ErrCursorMismatch) {
+		t.Errorf("expected %v to be %v", err, ErrCursorMismatch)
+	}
+}
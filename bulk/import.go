@@ -0,0 +1,244 @@
+package bulk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/juev/go-password/password"
+)
+
+var (
+	// ErrMissingAccount is the error returned when a row has no account.
+	ErrMissingAccount = errors.New("missing account")
+
+	// ErrUnknownColumn is the error returned when a CSV header has a column
+	// which is not an override.
+	ErrUnknownColumn = errors.New("unknown column")
+)
+
+// Columns of the CSV format and fields of the JSONL format. Only account is
+// required; the other columns override the requirements of the row, and
+// empty cells or missing fields keep the default requirements.
+const (
+	ColumnAccount         = "account"
+	ColumnLength          = "length"
+	ColumnDigits          = "digits"
+	ColumnSymbols         = "symbols"
+	ColumnNoUpper         = "no_upper"
+	ColumnAllowRepeat     = "allow_repeat"
+	ColumnMaxSameClassRun = "max_same_class_run"
+)
+
+// Row is an account to provision, with its password requirements.
+type Row struct {
+	// Line is the line of the row in its file, starting at 1.
+	Line    int
+	Account string
+	Input   password.Input
+
+	// Err is the error of the row if it could not be parsed, as a *RowError.
+	Err error
+}
+
+// Result is the credential generated for a row.
+type Result struct {
+	Line     int
+	Account  string
+	Password string
+
+	// Err is the error of the row if it could not be parsed or its
+	// credential could not be generated, as a *RowError.
+	Err error
+}
+
+// RowError is the error of a single row.
+type RowError struct {
+	Line    int
+	Account string
+	Err     error
+}
+
+// Error implements error.
+func (e *RowError) Error() string {
+	if e.Account == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+	}
+	return fmt.Sprintf("line %d (%s): %s", e.Line, e.Account, e.Err)
+}
+
+// Unwrap returns the cause of the error.
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// ReadCSV reads rows from CSV with a header line naming the columns. The
+// requirements of every row are the defaults overridden by its non-empty
+// cells. Rows which cannot be parsed are returned with their error; only
+// errors affecting the whole file are returned as the error.
+func ReadCSV(r io.Reader, defaults password.Input) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+		if !isColumn(header[i]) {
+			return nil, fmt.Errorf("%w %q", ErrUnknownColumn, name)
+		}
+	}
+
+	var rows []Row
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("failed to read CSV: %w", err)
+			}
+			rows = append(rows, Row{Line: parseErr.Line, Err: &RowError{Line: parseErr.Line, Err: parseErr.Err}})
+			continue
+		}
+
+		values := make(map[string]string, len(header))
+		for i, v := range record {
+			if i < len(header) && strings.TrimSpace(v) != "" {
+				values[header[i]] = strings.TrimSpace(v)
+			}
+		}
+		rows = append(rows, newRow(line, values, defaults))
+	}
+}
+
+// ReadJSONL reads rows from JSON Lines, one object per line with the column
+// names as fields. Blank lines are skipped. Rows which cannot be parsed are
+// returned with their error; only errors affecting the whole file are
+// returned as the error.
+func ReadJSONL(r io.Reader, defaults password.Input) ([]Row, error) {
+	var rows []Row
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		b := bytes.TrimSpace(sc.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal(b, &fields); err != nil {
+			rows = append(rows, Row{Line: line, Err: &RowError{Line: line, Err: err}})
+			continue
+		}
+
+		values := make(map[string]string, len(fields))
+		var err error
+		for name, v := range fields {
+			if !isColumn(name) {
+				err = fmt.Errorf("%w %q", ErrUnknownColumn, name)
+				break
+			}
+			if v != nil {
+				values[name] = fmt.Sprint(v)
+			}
+		}
+		if err != nil {
+			rows = append(rows, Row{Line: line, Err: &RowError{Line: line, Err: err}})
+			continue
+		}
+		rows = append(rows, newRow(line, values, defaults))
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSONL: %w", err)
+	}
+	return rows, nil
+}
+
+// Generate generates a credential for every row without an error, with the
+// given generator or password.NewGenerator() if nil. It returns a result for
+// every row, in order, so that failed rows can be reported and retried
+// individually. It stops early only if ctx is done, in which case the
+// remaining rows fail with the context error.
+func Generate(ctx context.Context, rows []Row, gen *password.Generator) []Result {
+	g := password.NewGenerator()
+	if gen != nil {
+		g = *gen
+	}
+
+	res := make([]Result, len(rows))
+	for i, row := range rows {
+		res[i] = Result{Line: row.Line, Account: row.Account, Err: row.Err}
+		if row.Err != nil {
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			res[i].Err = &RowError{Line: row.Line, Account: row.Account, Err: err}
+			continue
+		}
+
+		pw, err := g.Generate(row.Input)
+		if err != nil {
+			res[i].Err = &RowError{Line: row.Line, Account: row.Account, Err: err}
+			continue
+		}
+		res[i].Password = pw
+	}
+	return res
+}
+
+// newRow creates a row from the values of its columns.
+func newRow(line int, values map[string]string, defaults password.Input) Row {
+	row := Row{Line: line, Account: values[ColumnAccount], Input: defaults}
+	if row.Account == "" {
+		row.Err = &RowError{Line: line, Err: ErrMissingAccount}
+		return row
+	}
+
+	for name, v := range values {
+		var err error
+		switch name {
+		case ColumnLength:
+			row.Input.Length, err = strconv.Atoi(v)
+		case ColumnDigits:
+			row.Input.Digits, err = strconv.Atoi(v)
+		case ColumnSymbols:
+			row.Input.Symbols, err = strconv.Atoi(v)
+		case ColumnMaxSameClassRun:
+			row.Input.MaxSameClassRun, err = strconv.Atoi(v)
+		case ColumnNoUpper:
+			row.Input.NoUpper, err = strconv.ParseBool(v)
+		case ColumnAllowRepeat:
+			row.Input.AllowRepeat, err = strconv.ParseBool(v)
+		}
+		if err != nil {
+			row.Err = &RowError{Line: line, Account: row.Account, Err: fmt.Errorf("invalid %s: %w", name, err)}
+			return row
+		}
+	}
+	return row
+}
+
+// isColumn reports whether name is a known column.
+func isColumn(name string) bool {
+	switch name {
+	case ColumnAccount, ColumnLength, ColumnDigits, ColumnSymbols, ColumnNoUpper, ColumnAllowRepeat, ColumnMaxSameClassRun:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,121 @@
+package bulk
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/juev/go-password/password"
+)
+
+var testDefaults = password.Input{Length: 24, Digits: 4, Symbols: 4}
+
+func TestReadCSV(t *testing.T) {
+	t.Parallel()
+
+	rows, err := ReadCSV(strings.NewReader(`account,length,digits,symbols,no_upper,allow_repeat
+alice,,,,,
+pin-kiosk,8,8,0,,true
+bob,ten,,,,
+,12,,,,
+`), testDefaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %+v", rows)
+	}
+
+	if rows[0].Err != nil || rows[0].Account != "alice" || rows[0].Input != testDefaults {
+		t.Errorf("unexpected row %+v", rows[0])
+	}
+	want := password.Input{Length: 8, Digits: 8, AllowRepeat: true}
+	if rows[1].Err != nil || rows[1].Input != want || rows[1].Line != 3 {
+		t.Errorf("expected row with input %+v, got %+v", want, rows[1])
+	}
+
+	var rowErr *RowError
+	if !errors.As(rows[2].Err, &rowErr) || rowErr.Line != 4 || rowErr.Account != "bob" {
+		t.Errorf("expected row error on line 4, got %v", rows[2].Err)
+	}
+	if !errors.Is(rows[3].Err, ErrMissingAccount) {
+		t.Errorf("expected %v to be %v", rows[3].Err, ErrMissingAccount)
+	}
+
+	if _, err := ReadCSV(strings.NewReader("account,colour\nalice,red\n"), testDefaults); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("expected %v to be %v", err, ErrUnknownColumn)
+	}
+}
+
+func TestReadJSONL(t *testing.T) {
+	t.Parallel()
+
+	rows, err := ReadJSONL(strings.NewReader(`{"account": "alice"}
+
+{"account": "pin-kiosk", "length": 8, "digits": 8, "symbols": 0, "allow_repeat": true}
+{"account": "bob", "colour": "red"}
+not json
+`), testDefaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %+v", rows)
+	}
+
+	if rows[0].Err != nil || rows[0].Input != testDefaults {
+		t.Errorf("unexpected row %+v", rows[0])
+	}
+	want := password.Input{Length: 8, Digits: 8, AllowRepeat: true}
+	if rows[1].Err != nil || rows[1].Input != want || rows[1].Line != 3 {
+		t.Errorf("expected row with input %+v, got %+v", want, rows[1])
+	}
+	if !errors.Is(rows[2].Err, ErrUnknownColumn) {
+		t.Errorf("expected %v to be %v", rows[2].Err, ErrUnknownColumn)
+	}
+	if rows[3].Err == nil || rows[3].Line != 5 {
+		t.Errorf("expected row error on line 5, got %+v", rows[3])
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	rows, err := ReadCSV(strings.NewReader(`account,length,digits,symbols,allow_repeat
+alice,,,,
+pin-kiosk,8,8,0,true
+impossible,4,8,,
+,,,,
+`), testDefaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := Generate(context.Background(), rows, nil)
+	if len(res) != len(rows) {
+		t.Fatalf("expected %d results, got %d", len(rows), len(res))
+	}
+
+	if res[0].Err != nil || len(res[0].Password) != 24 {
+		t.Errorf("unexpected result %+v", res[0])
+	}
+	if _, err := strconv.Atoi(res[1].Password); res[1].Err != nil || len(res[1].Password) != 8 || err != nil {
+		t.Errorf("expected numeric PIN, got %+v", res[1])
+	}
+	if !errors.Is(res[2].Err, password.ErrExceedsTotalLength) || res[2].Password != "" {
+		t.Errorf("expected %v to be %v", res[2].Err, password.ErrExceedsTotalLength)
+	}
+	if !errors.Is(res[3].Err, ErrMissingAccount) {
+		t.Errorf("expected %v to be %v", res[3].Err, ErrMissingAccount)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, r := range Generate(ctx, rows[:2], nil) {
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("expected %v to be %v", r.Err, context.Canceled)
+		}
+	}
+}
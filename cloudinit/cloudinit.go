@@ -0,0 +1,157 @@
+// Package cloudinit writes the cloud-config blocks which set the passwords of
+// virtual machine users at first boot with cloud-init: a chpasswd block for
+// existing users, such as the default user of the image, and a users block
+// creating new ones.
+//
+// Passwords are written in plain text unless they are hashed, for instance
+// with the shadow package, so that user data exposed by the metadata service
+// does not reveal them.
+//
+//	users, err := cloudinit.NewUsers(password.NewGenerator(), input, func(pw string) (string, error) {
+//		return shadow.Yescrypt(pw, shadow.YescryptOptions{})
+//	}, "alice", "bob")
+//	if err != nil {
+//		return err
+//	}
+//	block, err := cloudinit.Users(users)
+//	if err != nil {
+//		return err
+//	}
+//	userData := cloudinit.Document(block)
+package cloudinit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/juev/go-password/password"
+)
+
+// Header is the first line of cloud-config user data.
+const Header = "#cloud-config"
+
+var (
+	// ErrInvalidName is the error returned when a user name is empty or
+	// contains spaces, control characters or colons.
+	ErrInvalidName = errors.New("invalid user name")
+
+	// ErrMissingPassword is the error returned when a user has neither a
+	// password nor a hashed password.
+	ErrMissingPassword = errors.New("missing password")
+)
+
+// User is a user whose password is set by cloud-init.
+type User struct {
+	Name string
+
+	// Password is the plain text password, which is handed over to the user.
+	// It is only written if HashedPassword is empty.
+	Password string
+
+	// HashedPassword is the crypt(3) hash of the password, written instead of
+	// Password when set.
+	HashedPassword string
+}
+
+// HashFunc hashes a password into a crypt(3) hash, such as shadow.Yescrypt.
+type HashFunc func(pw string) (string, error)
+
+// NewUsers returns the users of the given names, with passwords generated by
+// the Generator with the given input. If hash is not nil, the passwords are
+// also hashed with it, so that only the hashes are written.
+func NewUsers(g password.Generator, input password.Input, hash HashFunc, names ...string) ([]User, error) {
+	users := make([]User, 0, len(names))
+	for _, name := range names {
+		if err := checkName(name); err != nil {
+			return nil, err
+		}
+
+		pw, err := g.Generate(input)
+		if err != nil {
+			return nil, err
+		}
+
+		u := User{Name: name, Password: pw}
+		if hash != nil {
+			if u.HashedPassword, err = hash(pw); err != nil {
+				return nil, err
+			}
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+// Chpasswd returns the chpasswd block setting the passwords of existing users.
+// If expire is set, the users must change their password at first login.
+func Chpasswd(users []User, expire bool) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "chpasswd:\n  expire: %t\n  users:\n", expire)
+	for _, u := range users {
+		if err := u.check(); err != nil {
+			return "", err
+		}
+
+		pw, typ := u.Password, "text"
+		if u.HashedPassword != "" {
+			pw, typ = u.HashedPassword, "hash"
+		}
+		fmt.Fprintf(&b, "  - name: %s\n    password: %s\n    type: %s\n", quote(u.Name), quote(pw), typ)
+	}
+	return b.String(), nil
+}
+
+// Users returns the users block creating the users after the default user of
+// the image, with password login enabled.
+func Users(users []User) (string, error) {
+	var b strings.Builder
+	b.WriteString("users:\n- default\n")
+	for _, u := range users {
+		if err := u.check(); err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(&b, "- name: %s\n  lock_passwd: false\n", quote(u.Name))
+		if u.HashedPassword != "" {
+			fmt.Fprintf(&b, "  hashed_passwd: %s\n", quote(u.HashedPassword))
+		} else {
+			fmt.Fprintf(&b, "  plain_text_passwd: %s\n", quote(u.Password))
+		}
+	}
+	return b.String(), nil
+}
+
+// Document returns the cloud-config user data made of the given blocks.
+func Document(blocks ...string) string {
+	return Header + "\n" + strings.Join(blocks, "")
+}
+
+// check verifies that the user can be written.
+func (u User) check() error {
+	if err := checkName(u.Name); err != nil {
+		return err
+	}
+	if u.Password == "" && u.HashedPassword == "" {
+		return fmt.Errorf("%w: %q", ErrMissingPassword, u.Name)
+	}
+	return nil
+}
+
+// checkName verifies that the user name is valid.
+func checkName(name string) error {
+	if name == "" || strings.ContainsFunc(name, func(r rune) bool {
+		return r == ':' || unicode.IsSpace(r) || unicode.IsControl(r)
+	}) {
+		return fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	return nil
+}
+
+// quote returns s as a double-quoted YAML scalar. The escapes of Go strings
+// are also valid in YAML, so that any UTF-8 password is written verbatim.
+func quote(s string) string {
+	return strconv.Quote(s)
+}
@@ -0,0 +1,121 @@
+package cloudinit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/juev/go-password/password"
+	"github.com/juev/go-password/shadow"
+)
+
+func TestChpasswd(t *testing.T) {
+	t.Parallel()
+
+	got, err := Chpasswd([]User{
+		{Name: "ubuntu", Password: `p"a\ss#: x`},
+		{Name: "alice", Password: "secret", HashedPassword: "$6$salt$hash"},
+	}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `chpasswd:
+  expire: true
+  users:
+  - name: "ubuntu"
+    password: "p\"a\\ss#: x"
+    type: text
+  - name: "alice"
+    password: "$6$salt$hash"
+    type: hash
+`
+	if got != want {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+}
+
+func TestUsers(t *testing.T) {
+	t.Parallel()
+
+	got, err := Users([]User{
+		{Name: "alice", Password: "secret"},
+		{Name: "bob", HashedPassword: "$y$j9T$salt$hash"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `users:
+- default
+- name: "alice"
+  lock_passwd: false
+  plain_text_passwd: "secret"
+- name: "bob"
+  lock_passwd: false
+  hashed_passwd: "$y$j9T$salt$hash"
+`
+	if got != want {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+	if doc := Document(got); !strings.HasPrefix(doc, Header+"\nusers:\n") {
+		t.Errorf("unexpected document %q", doc)
+	}
+}
+
+func TestNewUsers(t *testing.T) {
+	t.Parallel()
+
+	input := password.Input{Length: 20, Digits: 4, Symbols: 4}
+	users, err := NewUsers(password.NewGenerator(), input, func(pw string) (string, error) {
+		return shadow.SHA512Crypt(pw, shadow.SHA512Options{Rounds: shadow.MinSHA512Rounds})
+	}, "alice", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(users) != 2 || users[0].Name != "alice" || users[1].Name != "bob" {
+		t.Fatalf("unexpected users %+v", users)
+	}
+	for _, u := range users {
+		if len(u.Password) != input.Length {
+			t.Errorf("expected %v to be %v", len(u.Password), input.Length)
+		}
+		if !strings.HasPrefix(u.HashedPassword, "$6$rounds=1000$") {
+			t.Errorf("unexpected hash %q", u.HashedPassword)
+		}
+	}
+
+	users, err = NewUsers(password.NewGenerator(), input, nil, "carol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if users[0].HashedPassword != "" {
+		t.Errorf("expected %q to be empty", users[0].HashedPassword)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"", "a b", "a:b", "a\nb"} {
+		if _, err := NewUsers(password.NewGenerator(), password.Input{Length: 8}, nil, name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("expected %v to be %v", err, ErrInvalidName)
+		}
+		if _, err := Users([]User{{Name: name, Password: "x"}}); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("expected %v to be %v", err, ErrInvalidName)
+		}
+	}
+
+	if _, err := Chpasswd([]User{{Name: "alice"}}, false); !errors.Is(err, ErrMissingPassword) {
+		t.Errorf("expected %v to be %v", err, ErrMissingPassword)
+	}
+
+	hashErr := errors.New("hash failed")
+	_, err := NewUsers(password.NewGenerator(), password.Input{Length: 8}, func(string) (string, error) {
+		return "", hashErr
+	}, "alice")
+	if !errors.Is(err, hashErr) {
+		t.Errorf("expected %v to be %v", err, hashErr)
+	}
+}
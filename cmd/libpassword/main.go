@@ -0,0 +1,93 @@
+//go:build cgo
+
+// Command libpassword is a thin C ABI over the password package, so that the
+// generator can be embedded into Python, Ruby and other tooling without
+// reimplementing its policies. Build it as a shared library:
+//
+//	go build -buildmode=c-shared -o libpassword.so ./cmd/libpassword
+//
+// This produces libpassword.so and libpassword.h. Every string returned by the
+// library, including error messages, must be released with password_free.
+//
+//	char *err = NULL;
+//	char *pw = password_generate(32, 4, 4, 0, 0, &err);
+//	if (pw == NULL) {
+//	  fprintf(stderr, "%s\n", err);
+//	  password_free(err);
+//	}
+//	password_free(pw);
+//
+// Passphrases of words from the EFF long wordlist are generated with
+// password_generate_passphrase:
+//
+//	char *phrase = password_generate_passphrase(6, "-", 0, &err);
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"unsafe"
+
+	"github.com/juev/go-password/password"
+)
+
+// password_generate generates a password with the given requirements. On
+// success it returns the password. On failure it returns NULL and, if err is
+// not NULL, stores an error message in *err.
+//
+//export password_generate
+func password_generate(length, digits, symbols, noUpper, allowRepeat C.int, err **C.char) *C.char {
+	res, genErr := password.Generate(password.Input{
+		Length:      int(length),
+		Digits:      int(digits),
+		Symbols:     int(symbols),
+		NoUpper:     noUpper != 0,
+		AllowRepeat: allowRepeat != 0,
+	})
+	if genErr != nil {
+		if err != nil {
+			*err = C.CString(genErr.Error())
+		}
+		return nil
+	}
+	return C.CString(res)
+}
+
+// password_generate_passphrase generates a passphrase of words from the EFF
+// long wordlist, joined by separator, which may be NULL for none.
+// Capitalization is 0 to keep words lowercase, 1 to capitalize every word, 2 to
+// uppercase every word and 3 to capitalize words at random. Results and errors
+// are returned as by password_generate.
+//
+//export password_generate_passphrase
+func password_generate_passphrase(words C.int, separator *C.char, capitalization C.int, err **C.char) *C.char {
+	var sep string
+	if separator != nil {
+		sep = C.GoString(separator)
+	}
+	res, genErr := password.GeneratePassphrase(password.PassphraseInput{
+		Words:          int(words),
+		Separator:      sep,
+		Capitalization: password.Capitalization(capitalization),
+	})
+	if genErr != nil {
+		if err != nil {
+			*err = C.CString(genErr.Error())
+		}
+		return nil
+	}
+	return C.CString(res)
+}
+
+// password_free releases a string returned by the library. It is safe to call
+// with NULL.
+//
+//export password_free
+func password_free(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+func main() {}
@@ -0,0 +1,138 @@
+// Command password-server runs the password generation service of package
+// server over HTTPS.
+//
+//	password-server -config config.json -tls-cert cert.pem -tls-key key.pem
+//
+// The configuration file is JSON:
+//
+//	{
+//	  "policies": [
+//	    {"name": "database", "length": 32, "digits": 6, "symbols": 6},
+//	    {"name": "root", "length": 64, "digits": 8, "symbols": 8, "signing_keys": ["deploy"]}
+//	  ],
+//	  "token_sha256": ["<hex-encoded SHA-256 of a bearer token>"],
+//	  "signing_keys": {"deploy": "<hex-encoded HMAC key of at least 32 bytes>"},
+//	  "admin_token_sha256": ["<hex-encoded SHA-256 of an admin token>"],
+//	  "rate_limit": 5,
+//	  "rate_burst": 10,
+//	  "audit": {
+//	    "file": "/var/log/password-server/audit.jsonl",
+//	    "max_bytes": 104857600,
+//	    "max_backups": 10,
+//	    "webhook_url": "https://siem.example.com/ingest"
+//	  }
+//	}
+//
+// The hash of a token can be computed with:
+//
+//	printf '%s' "$TOKEN" | sha256sum
+//
+// Plain HTTP is only served when -insecure is given, for use behind a
+// TLS-terminating proxy.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/juev/go-password/audit"
+	"github.com/juev/go-password/server"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("password-server", flag.ContinueOnError)
+	addr := flags.String("addr", ":8443", "address to listen on")
+	configPath := flags.String("config", "config.json", "path to the JSON configuration file")
+	certFile := flags.String("tls-cert", "", "path to the TLS certificate")
+	keyFile := flags.String("tls-key", "", "path to the TLS private key")
+	insecure := flags.Bool("insecure", false, "serve plain HTTP instead of HTTPS")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+
+	if !*insecure && (*certFile == "" || *keyFile == "") {
+		return errors.New("-tls-cert and -tls-key are required unless -insecure is given")
+	}
+
+	cfg, err := server.LoadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	var sinks []audit.Sink
+	if cfg.Audit.File != "" {
+		fileSink, err := audit.NewFileSink(cfg.Audit.File, cfg.Audit.MaxBytes, cfg.Audit.MaxBackups)
+		if err != nil {
+			return fmt.Errorf("failed to open audit log: %w", err)
+		}
+		defer fileSink.Close()
+		sinks = append(sinks, fileSink)
+	}
+	if cfg.Audit.WebhookURL != "" {
+		sinks = append(sinks, audit.NewWebhookSink(cfg.Audit.WebhookURL, audit.WebhookOptions{}))
+	}
+	if len(sinks) > 0 {
+		cfg.AuditSink = audit.MultiSink(sinks...)
+	}
+
+	srv, err := server.New(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+	defer srv.Close()
+
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           srv,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		WriteTimeout:      10 * time.Second,
+		IdleTimeout:       60 * time.Second,
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Printf("listening on %s", *addr)
+		if *insecure {
+			errCh <- httpServer.ListenAndServe()
+		} else {
+			errCh <- httpServer.ListenAndServeTLS(*certFile, *keyFile)
+		}
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("failed to serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	log.Print("shutting down")
+	srv.Drain()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+// Package dpapi caches generated secrets on Windows, encrypted with the Data
+// Protection API for the current user, for desktop agents which must keep a
+// secret between runs. Only the same user on the same machine can decrypt
+// it, without the agent managing a key.
+//
+// On other platforms, every function returns ErrUnsupported.
+//
+//	pw, err := password.Generate(password.Input{Length: 32, Digits: 6, Symbols: 6})
+//	if err != nil {
+//		return err
+//	}
+//	path, err := dpapi.DefaultPath("agent", "token")
+//	if err != nil {
+//		return err
+//	}
+//	if err := dpapi.Save(path, []byte(pw), nil); err != nil {
+//		return err
+//	}
+//	...
+//	secret, err := dpapi.Load(path, nil)
+package dpapi
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var (
+	// ErrUnsupported is the error returned on platforms other than Windows.
+	ErrUnsupported = errors.New("DPAPI is only available on Windows")
+
+	// ErrInvalidName is the error returned when an application or secret
+	// name is empty or is not a plain file name.
+	ErrInvalidName = errors.New("invalid name")
+)
+
+// DefaultPath returns the path of the secret of the given name of the given
+// application, in the roaming application data directory of the current user.
+func DefaultPath(app, name string) (string, error) {
+	for _, s := range []string{app, name} {
+		if s == "" || s == "." || s == ".." || filepath.Base(s) != s {
+			return "", fmt.Errorf("%w: %q", ErrInvalidName, s)
+		}
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the application data directory: %w", err)
+	}
+	return filepath.Join(dir, app, name+".dpapi"), nil
+}
+
+// Save encrypts the secret with Protect and writes it to the file at path,
+// creating its directory if needed. The file is replaced atomically, so that a
+// crash never leaves a partial secret. entropy, if set, must be passed to Load.
+func Save(path string, secret, entropy []byte) error {
+	b, err := Protect(secret, entropy)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create secret directory: %w", err)
+	}
+	return writeFileAtomic(path, b)
+}
+
+// Load reads the file at path written by Save and decrypts it with Unprotect.
+func Load(path string, entropy []byte) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret: %w", err)
+	}
+	return Unprotect(b, entropy)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// to path.
+func writeFileAtomic(path string, data []byte) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create secret file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write secret file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to write secret file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write secret file: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace secret file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package dpapi
+
+// Protect encrypts data for the current user with CryptProtectData. entropy,
+// if set, is additional secret data which must be passed to Unprotect, so
+// that other applications of the same user cannot decrypt it alone.
+//
+// It returns ErrUnsupported on this platform.
+func Protect(data, entropy []byte) ([]byte, error) {
+	return nil, ErrUnsupported
+}
+
+// Unprotect decrypts data encrypted by Protect for the current user, with
+// the same entropy.
+//
+// It returns ErrUnsupported on this platform.
+func Unprotect(data, entropy []byte) ([]byte, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,42 @@
+package dpapi
+
+import (
+	"errors"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDefaultPath(t *testing.T) {
+	t.Parallel()
+
+	path, err := DefaultPath("agent", "token")
+	if err != nil {
+		t.Skipf("no application data directory: %v", err)
+	}
+	if filepath.Base(path) != "token.dpapi" || filepath.Base(filepath.Dir(path)) != "agent" {
+		t.Errorf("unexpected path %q", path)
+	}
+
+	for _, names := range [][2]string{{"", "token"}, {"agent", ""}, {"..", "token"}, {"agent", "a/b"}} {
+		if _, err := DefaultPath(names[0], names[1]); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("expected %v to be %v", err, ErrInvalidName)
+		}
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("DPAPI is supported")
+	}
+
+	path := filepath.Join(t.TempDir(), "token.dpapi")
+	if err := Save(path, []byte("secret"), nil); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected %v to be %v", err, ErrUnsupported)
+	}
+	if _, err := Unprotect([]byte("secret"), nil); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("expected %v to be %v", err, ErrUnsupported)
+	}
+}
@@ -0,0 +1,101 @@
+//go:build windows
+
+package dpapi
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// cryptProtectUIForbidden fails instead of prompting the user.
+const cryptProtectUIForbidden = 0x1
+
+var (
+	crypt32                = syscall.NewLazyDLL("crypt32.dll")
+	procCryptProtectData   = crypt32.NewProc("CryptProtectData")
+	procCryptUnprotectData = crypt32.NewProc("CryptUnprotectData")
+
+	kernel32      = syscall.NewLazyDLL("kernel32.dll")
+	procLocalFree = kernel32.NewProc("LocalFree")
+)
+
+// dataBlob is the DATA_BLOB structure of the Data Protection API.
+type dataBlob struct {
+	cbData uint32
+	pbData *byte
+}
+
+// newBlob returns a DATA_BLOB pointing to b, or nil if b is empty.
+func newBlob(b []byte) *dataBlob {
+	if len(b) == 0 {
+		return nil
+	}
+	return &dataBlob{cbData: uint32(len(b)), pbData: &b[0]}
+}
+
+// Protect encrypts data for the current user with CryptProtectData. entropy,
+// if set, is additional secret data which must be passed to Unprotect, so
+// that other applications of the same user cannot decrypt it alone.
+func Protect(data, entropy []byte) ([]byte, error) {
+	in := newBlob(data)
+	if in == nil {
+		in = &dataBlob{}
+	}
+
+	var out dataBlob
+	r, _, err := procCryptProtectData.Call(
+		uintptr(unsafe.Pointer(in)),
+		0,
+		uintptr(unsafe.Pointer(newBlob(entropy))),
+		0,
+		0,
+		cryptProtectUIForbidden,
+		uintptr(unsafe.Pointer(&out)),
+	)
+	if r == 0 {
+		return nil, fmt.Errorf("failed to protect data: %w", err)
+	}
+	defer localFree(out.pbData)
+
+	return append([]byte(nil), unsafe.Slice(out.pbData, out.cbData)...), nil
+}
+
+// Unprotect decrypts data encrypted by Protect for the current user, with
+// the same entropy.
+func Unprotect(data, entropy []byte) ([]byte, error) {
+	in := newBlob(data)
+	if in == nil {
+		in = &dataBlob{}
+	}
+
+	var out dataBlob
+	r, _, err := procCryptUnprotectData.Call(
+		uintptr(unsafe.Pointer(in)),
+		0,
+		uintptr(unsafe.Pointer(newBlob(entropy))),
+		0,
+		0,
+		cryptProtectUIForbidden,
+		uintptr(unsafe.Pointer(&out)),
+	)
+	if r == 0 {
+		return nil, fmt.Errorf("failed to unprotect data: %w", err)
+	}
+
+	// The plaintext is wiped before its buffer is released.
+	plain := unsafe.Slice(out.pbData, out.cbData)
+	defer func() {
+		clear(plain)
+		localFree(out.pbData)
+	}()
+
+	return append([]byte(nil), plain...), nil
+}
+
+// localFree releases memory allocated by the Data Protection API.
+func localFree(p *byte) {
+	if p != nil {
+		_, _, _ = procLocalFree.Call(uintptr(unsafe.Pointer(p)))
+	}
+}
@@ -0,0 +1,50 @@
+//go:build windows
+
+package dpapi
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/juev/go-password/password"
+)
+
+func TestSaveLoad(t *testing.T) {
+	t.Parallel()
+
+	pw := []byte(password.MustGenerate(password.Input{Length: 32, Digits: 6, Symbols: 6}))
+	entropy := []byte("agent")
+	path := filepath.Join(t.TempDir(), "agent", "token.dpapi")
+	if err := Save(path, pw, entropy); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Load(path, entropy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, pw) {
+		t.Errorf("expected %q to be %q", got, pw)
+	}
+
+	if _, err := Load(path, []byte("other")); err == nil {
+		t.Error("expected decryption with other entropy to fail")
+	}
+}
+
+func TestProtectEmpty(t *testing.T) {
+	t.Parallel()
+
+	b, err := Protect(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Unprotect(b, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected %q to be empty", got)
+	}
+}
@@ -0,0 +1,190 @@
+// Package dsn assembles database connection strings around generated
+// passwords. Generated symbols such as '@', ':', '/', '?', '#', '%', quotes
+// and spaces break connection strings built by concatenation; the functions of
+// this package escape every part as the drivers expect.
+//
+//	pw, err := password.Generate(password.Input{Length: 32, Digits: 6, Symbols: 6})
+//	if err != nil {
+//		return err
+//	}
+//	s, err := dsn.PostgresURL(dsn.Config{Host: "db", User: "app", Password: pw, Database: "app"})
+package dsn
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMissingHost is the error returned when the Config has no host.
+	ErrMissingHost = errors.New("missing host")
+
+	// ErrInvalidUser is the error returned when the user name cannot be
+	// represented in a MySQL DSN, which ends the user name at the first ':'.
+	ErrInvalidUser = errors.New("user name must not contain ':' in a MySQL DSN")
+)
+
+// Config is the connection parameters of a database.
+type Config struct {
+	Host string
+
+	// Port is the TCP port. If zero, the driver default is used.
+	Port int
+
+	User     string
+	Password string
+	Database string
+
+	// Params are additional driver parameters, such as "sslmode" for
+	// Postgres or "tls" for MySQL.
+	Params map[string]string
+}
+
+// address returns the host and port, with brackets around IPv6 hosts.
+func (c Config) address() string {
+	if c.Port == 0 {
+		if strings.Contains(c.Host, ":") {
+			return "[" + c.Host + "]"
+		}
+		return c.Host
+	}
+	return net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+}
+
+// query returns the parameters encoded as a URL query, sorted by key.
+func (c Config) query() string {
+	values := make(url.Values, len(c.Params))
+	for k, v := range c.Params {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+// dbURL returns a URL with the given scheme.
+func dbURL(scheme string, c Config) (string, error) {
+	if c.Host == "" {
+		return "", ErrMissingHost
+	}
+
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     c.address(),
+		RawQuery: c.query(),
+	}
+	switch {
+	case c.Password != "":
+		u.User = url.UserPassword(c.User, c.Password)
+	case c.User != "":
+		u.User = url.User(c.User)
+	}
+	if c.Database != "" {
+		u.Path = "/" + c.Database
+	}
+	return u.String(), nil
+}
+
+// PostgresURL returns a postgres:// connection URI, as understood by libpq,
+// pgx and lib/pq. The user name, password and database name are
+// percent-encoded.
+func PostgresURL(c Config) (string, error) {
+	return dbURL("postgres", c)
+}
+
+// Postgres returns a keyword/value connection string, such as
+// "host=db user=app password='s3cr3t' dbname=app", as understood by libpq,
+// pgx and lib/pq. Values containing spaces, quotes or backslashes are quoted
+// and escaped; the password is always quoted.
+func Postgres(c Config) (string, error) {
+	if c.Host == "" {
+		return "", ErrMissingHost
+	}
+
+	var b strings.Builder
+	add := func(key, value string, quote bool) {
+		if b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(postgresValue(value, quote))
+	}
+
+	add("host", c.Host, false)
+	if c.Port != 0 {
+		add("port", strconv.Itoa(c.Port), false)
+	}
+	if c.User != "" {
+		add("user", c.User, false)
+	}
+	if c.Password != "" {
+		add("password", c.Password, true)
+	}
+	if c.Database != "" {
+		add("dbname", c.Database, false)
+	}
+
+	keys := make([]string, 0, len(c.Params))
+	for k := range c.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		add(k, c.Params[k], false)
+	}
+	return b.String(), nil
+}
+
+// postgresValue returns the value of a keyword/value connection string,
+// quoting it if needed or if quote is set.
+func postgresValue(v string, quote bool) string {
+	if !quote && v != "" && !strings.ContainsAny(v, " \t\n\r\v\f'\\") {
+		return v
+	}
+	v = strings.ReplaceAll(v, `\`, `\\`)
+	v = strings.ReplaceAll(v, `'`, `\'`)
+	return "'" + v + "'"
+}
+
+// MySQLURL returns a mysql:// connection URL, as understood by migration
+// tools and most drivers other than go-sql-driver/mysql. The user name,
+// password and database name are percent-encoded.
+func MySQLURL(c Config) (string, error) {
+	return dbURL("mysql", c)
+}
+
+// MySQL returns a DSN in the format of go-sql-driver/mysql, such as
+// "app:s3cr3t@tcp(db:3306)/app?tls=true". The driver takes the password
+// verbatim up to the last '@', so it is not escaped; the database name and the
+// parameters are percent-encoded. ErrInvalidUser is returned if the user name
+// contains ':'.
+func MySQL(c Config) (string, error) {
+	if c.Host == "" {
+		return "", ErrMissingHost
+	}
+	if strings.Contains(c.User, ":") {
+		return "", ErrInvalidUser
+	}
+
+	var b strings.Builder
+	if c.User != "" || c.Password != "" {
+		b.WriteString(c.User)
+		if c.Password != "" {
+			b.WriteByte(':')
+			b.WriteString(c.Password)
+		}
+		b.WriteByte('@')
+	}
+	b.WriteString("tcp(")
+	b.WriteString(c.address())
+	b.WriteString(")/")
+	b.WriteString(url.PathEscape(c.Database))
+	if q := c.query(); q != "" {
+		b.WriteByte('?')
+		b.WriteString(q)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,168 @@
+package dsn
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/juev/go-password/password"
+)
+
+// tricky is a password with every character known to break connection
+// strings.
+const tricky = `p@ss:w/o?r#d% 'q"\&=+`
+
+func TestURL(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []func(Config) (string, error){PostgresURL, MySQLURL} {
+		for i := 0; i < 100; i++ {
+			pw := tricky
+			if i > 0 {
+				pw = password.MustGenerate(password.Input{Length: 32, Digits: 6, Symbols: 10})
+			}
+
+			s, err := f(Config{
+				Host:     "db.example.com",
+				Port:     5432,
+				User:     "app",
+				Password: pw,
+				Database: "my db",
+				Params:   map[string]string{"sslmode": "verify-full", "application_name": "a&b"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			u, err := url.Parse(s)
+			if err != nil {
+				t.Fatalf("failed to parse %q: %v", s, err)
+			}
+			if got, _ := u.User.Password(); got != pw {
+				t.Errorf("expected password %q, got %q from %q", pw, got, s)
+			}
+			if u.User.Username() != "app" || u.Host != "db.example.com:5432" || u.Path != "/my db" {
+				t.Errorf("unexpected URL %q", s)
+			}
+			if q := u.Query(); q.Get("sslmode") != "verify-full" || q.Get("application_name") != "a&b" {
+				t.Errorf("unexpected parameters in %q", s)
+			}
+		}
+	}
+
+	s, err := PostgresURL(Config{Host: "::1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "postgres://[::1]" {
+		t.Errorf("unexpected URL %q", s)
+	}
+}
+
+func TestPostgres(t *testing.T) {
+	t.Parallel()
+
+	s, err := Postgres(Config{
+		Host:     "db",
+		Port:     5432,
+		User:     "app",
+		Password: tricky,
+		Database: "app",
+		Params:   map[string]string{"sslmode": "require", "options": "-c search_path=app"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `host=db port=5432 user=app password='p@ss:w/o?r#d% \'q"\\&=+' dbname=app options='-c search_path=app' sslmode=require`
+	if s != want {
+		t.Errorf("expected %s, got %s", want, s)
+	}
+	if got := parsePostgres(t, s)["password"]; got != tricky {
+		t.Errorf("expected password %q, got %q", tricky, got)
+	}
+}
+
+// parsePostgres parses a keyword/value connection string as libpq does.
+func parsePostgres(t *testing.T, s string) map[string]string {
+	t.Helper()
+
+	res := make(map[string]string)
+	for s != "" {
+		key, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			t.Fatalf("missing '=' in %q", s)
+		}
+		var value strings.Builder
+		if strings.HasPrefix(rest, "'") {
+			rest = rest[1:]
+			for {
+				if rest == "" {
+					t.Fatalf("unterminated quote in %q", s)
+				}
+				c := rest[0]
+				rest = rest[1:]
+				if c == '\\' {
+					c = rest[0]
+					rest = rest[1:]
+				} else if c == '\'' {
+					break
+				}
+				value.WriteByte(c)
+			}
+		} else {
+			v, r, _ := strings.Cut(rest, " ")
+			value.WriteString(v)
+			rest = " " + r
+		}
+		res[key] = value.String()
+		s = strings.TrimLeft(rest, " ")
+	}
+	return res
+}
+
+func TestMySQL(t *testing.T) {
+	t.Parallel()
+
+	s, err := MySQL(Config{
+		Host:     "db",
+		Port:     3306,
+		User:     "app",
+		Password: tricky,
+		Database: "my/db",
+		Params:   map[string]string{"tls": "true", "loc": "Europe/Paris"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `app:p@ss:w/o?r#d% 'q"\&=+@tcp(db:3306)/my%2Fdb?loc=Europe%2FParis&tls=true`
+	if s != want {
+		t.Errorf("expected %s, got %s", want, s)
+	}
+
+	// The driver splits at the last '/', then at the last '@' before it and
+	// the first ':' of the user information.
+	slash := strings.LastIndex(s, "/")
+	at := strings.LastIndex(s[:slash], "@")
+	_, pw, _ := strings.Cut(s[:at], ":")
+	if pw != tricky {
+		t.Errorf("expected password %q, got %q", tricky, pw)
+	}
+
+	if s, err := MySQL(Config{Host: "::1", Port: 3306}); err != nil || s != "tcp([::1]:3306)/" {
+		t.Errorf("unexpected DSN %q (%v)", s, err)
+	}
+	if _, err := MySQL(Config{Host: "db", User: "a:b"}); !errors.Is(err, ErrInvalidUser) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidUser)
+	}
+}
+
+func TestMissingHost(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range []func(Config) (string, error){PostgresURL, Postgres, MySQLURL, MySQL} {
+		if _, err := f(Config{User: "app"}); !errors.Is(err, ErrMissingHost) {
+			t.Errorf("expected %v to be %v", err, ErrMissingHost)
+		}
+	}
+}
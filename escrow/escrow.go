@@ -0,0 +1,207 @@
+// Package escrow stores generated credentials envelope-encrypted for
+// break-glass recovery.
+//
+// Every credential is encrypted with its own random data key, which is in turn
+// wrapped by a keywrap.KeyWrapper, typically backed by a KMS, so that the store never
+// holds a usable key. Deposits and recoveries are recorded as audit events.
+//
+//	store, err := escrow.NewPostgresStore(db, "escrow")
+//	...
+//	e := escrow.New(store, wrapper, sink)
+//	id, err := e.Deposit(ctx, "database", "db-primary", secret)
+//	...
+//	secret, err := e.Recover(ctx, id, "alice", "INC-1234: primary unreachable")
+package escrow
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juev/go-password/audit"
+	"github.com/juev/go-password/keywrap"
+)
+
+var (
+	// ErrRecordNotFound is the error returned by a Store when a record does
+	// not exist.
+	ErrRecordNotFound = errors.New("escrow record not found")
+
+	// ErrMissingReason is the error returned when a credential is recovered
+	// without an accessor or a reason.
+	ErrMissingReason = errors.New("escrow recovery requires an accessor and a reason")
+)
+
+// dataKeySize is the size of the data key of a record in bytes.
+const dataKeySize = 32
+
+// Record is an envelope-encrypted credential.
+type Record struct {
+	ID      string
+	Policy  string
+	Subject string
+
+	// KeyID identifies the key encryption key WrappedKey is wrapped with.
+	KeyID      string
+	WrappedKey []byte
+
+	// Ciphertext is the credential encrypted with AES-256-GCM under the data
+	// key, with the nonce prepended and the record ID as additional data.
+	Ciphertext []byte
+	CreatedAt  time.Time
+}
+
+// Escrow deposits credentials into a Store and recovers them. It is safe for
+// concurrent use.
+type Escrow struct {
+	store   Store
+	wrapper keywrap.KeyWrapper
+	sink    audit.Sink
+	now     func() time.Time
+}
+
+// New creates a new Escrow which keeps records in the given store, wraps data
+// keys with the given wrapper and writes audit events to the given sink. The
+// sink may be nil.
+func New(store Store, wrapper keywrap.KeyWrapper, sink audit.Sink) *Escrow {
+	return &Escrow{
+		store:   store,
+		wrapper: wrapper,
+		sink:    sink,
+		now:     time.Now,
+	}
+}
+
+// Deposit encrypts the credential generated for the given policy and subject
+// and stores it. It returns the ID of the new record.
+func (e *Escrow) Deposit(ctx context.Context, policy, subject, secret string) (string, error) {
+	id, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+
+	key := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return "", fmt.Errorf("failed to generate data key: %w", err)
+	}
+	defer clear(key)
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	wrapped, err := e.wrapper.Wrap(ctx, key)
+	if err != nil {
+		return "", fmt.Errorf("failed to wrap data key: %w", err)
+	}
+
+	rec := Record{
+		ID:         id,
+		Policy:     policy,
+		Subject:    subject,
+		KeyID:      e.wrapper.KeyID(),
+		WrappedKey: wrapped,
+		Ciphertext: aead.Seal(nonce, nonce, []byte(secret), []byte(id)),
+		CreatedAt:  e.now().UTC(),
+	}
+	if err := e.store.Put(ctx, rec); err != nil {
+		return "", fmt.Errorf("failed to store escrow record: %w", err)
+	}
+
+	e.audit(ctx, audit.Event{Type: audit.TypeEscrowDeposit, Policy: policy, Record: id})
+	return id, nil
+}
+
+// Recover decrypts the credential of the record with the given ID. The
+// accessor and reason are mandatory and recorded in the audit event, which is
+// written before the credential is returned. If the audit event cannot be
+// written, the credential is not returned.
+func (e *Escrow) Recover(ctx context.Context, id, accessor, reason string) (string, error) {
+	if accessor == "" || reason == "" {
+		return "", ErrMissingReason
+	}
+
+	rec, err := e.store.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if e.sink != nil {
+		err := e.sink.Write(ctx, audit.Event{
+			Time:   e.now().UTC(),
+			Type:   audit.TypeEscrowRecover,
+			Policy: rec.Policy,
+			Client: accessor,
+			Record: id,
+			Reason: reason,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to audit escrow recovery: %w", err)
+		}
+	}
+
+	key, err := e.wrapper.Unwrap(ctx, rec.WrappedKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	defer clear(key)
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	n := aead.NonceSize()
+	if len(rec.Ciphertext) < n {
+		return "", errors.New("failed to decrypt escrow record: ciphertext too short")
+	}
+	b, err := aead.Open(nil, rec.Ciphertext[:n], rec.Ciphertext[n:], []byte(rec.ID))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt escrow record: %w", err)
+	}
+	return string(b), nil
+}
+
+// audit writes the event to the sink, if any. Deposits are not failed when
+// the event cannot be written, since the credential is safely stored.
+func (e *Escrow) audit(ctx context.Context, ev audit.Event) {
+	if e.sink == nil {
+		return
+	}
+	ev.Time = e.now().UTC()
+	_ = e.sink.Write(ctx, ev)
+}
+
+// newAEAD returns AES-GCM with the given key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return aead, nil
+}
+
+// randomHex returns n random bytes, hex-encoded.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", fmt.Errorf("failed to generate record id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
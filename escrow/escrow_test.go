@@ -0,0 +1,124 @@
+package escrow
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/juev/go-password/audit"
+	"github.com/juev/go-password/keywrap"
+)
+
+func testEscrow(tb testing.TB, sink audit.Sink) (*Escrow, *MemoryStore) {
+	tb.Helper()
+
+	wrapper, err := keywrap.NewAES("test-kek", []byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	store := NewMemoryStore()
+	return New(store, wrapper, sink), store
+}
+
+func TestEscrow(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var events []audit.Event
+	e, store := testEscrow(t, audit.SinkFunc(func(_ context.Context, ev audit.Event) error {
+		events = append(events, ev)
+		return nil
+	}))
+
+	const secret = "correct horse battery staple"
+	id, err := e.Deposit(ctx, "database", "db-primary", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := store.Get(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Policy != "database" || rec.Subject != "db-primary" || rec.KeyID != "test-kek" {
+		t.Errorf("unexpected record %+v", rec)
+	}
+	if strings.Contains(string(rec.Ciphertext), secret) {
+		t.Error("expected credential to be encrypted")
+	}
+
+	if _, err := e.Recover(ctx, id, "alice", ""); !errors.Is(err, ErrMissingReason) {
+		t.Errorf("expected %v to be %v", err, ErrMissingReason)
+	}
+
+	got, err := e.Recover(ctx, id, "alice", "INC-1234")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != secret {
+		t.Errorf("expected %q to be %q", got, secret)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("expected %d events, got %+v", 2, events)
+	}
+	if events[0].Type != audit.TypeEscrowDeposit || events[0].Record != id {
+		t.Errorf("unexpected deposit event %+v", events[0])
+	}
+	if ev := events[1]; ev.Type != audit.TypeEscrowRecover || ev.Client != "alice" || ev.Reason != "INC-1234" || ev.Record != id {
+		t.Errorf("unexpected recovery event %+v", ev)
+	}
+
+	if _, err := e.Recover(ctx, "unknown", "alice", "INC-1234"); !errors.Is(err, ErrRecordNotFound) {
+		t.Errorf("expected %v to be %v", err, ErrRecordNotFound)
+	}
+}
+
+func TestEscrowRecoverRequiresAudit(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	errSink := errors.New("sink unavailable")
+	fail := false
+	e, _ := testEscrow(t, audit.SinkFunc(func(context.Context, audit.Event) error {
+		if fail {
+			return errSink
+		}
+		return nil
+	}))
+
+	id, err := e.Deposit(ctx, "database", "db-primary", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fail = true
+	if got, err := e.Recover(ctx, id, "alice", "INC-1234"); !errors.Is(err, errSink) || got != "" {
+		t.Errorf("expected recovery to fail without audit, got %q, %v", got, err)
+	}
+}
+
+func TestEscrowTampered(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	e, store := testEscrow(t, nil)
+
+	id, err := e.Deposit(ctx, "database", "db-primary", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Moving a ciphertext to another record must not decrypt.
+	rec := store.records[id]
+	rec.ID = "other"
+	store.records["other"] = rec
+	if _, err := e.Recover(ctx, "other", "alice", "INC-1234"); err == nil {
+		t.Error("expected moved ciphertext not to decrypt")
+	}
+
+	if err := store.Put(ctx, store.records[id]); !errors.Is(err, ErrRecordExists) {
+		t.Errorf("expected %v to be %v", err, ErrRecordExists)
+	}
+}
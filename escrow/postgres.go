@@ -0,0 +1,84 @@
+package escrow
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// tableName matches the permitted names of escrow tables.
+var tableName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// PostgresStore is a Store which keeps records in a PostgreSQL table. The
+// caller opens the *sql.DB with a driver of their choice, such as pgx or
+// lib/pq; this package does not import one.
+type PostgresStore struct {
+	db    *sql.DB
+	table string
+}
+
+var _ Store = (*PostgresStore)(nil)
+
+// NewPostgresStore creates a new PostgresStore keeping records in the given
+// table. The table name must consist of lowercase letters, digits and
+// underscores.
+func NewPostgresStore(db *sql.DB, table string) (*PostgresStore, error) {
+	if !tableName.MatchString(table) {
+		return nil, fmt.Errorf("invalid escrow table name %q", table)
+	}
+	return &PostgresStore{db: db, table: table}, nil
+}
+
+// Migrate creates the table if it does not exist.
+func (p *PostgresStore) Migrate(ctx context.Context) error {
+	_, err := p.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+p.table+` (
+	id          TEXT PRIMARY KEY,
+	policy      TEXT NOT NULL,
+	subject     TEXT NOT NULL,
+	key_id      TEXT NOT NULL,
+	wrapped_key BYTEA NOT NULL,
+	ciphertext  BYTEA NOT NULL,
+	created_at  TIMESTAMPTZ NOT NULL
+)`)
+	if err != nil {
+		return fmt.Errorf("failed to create escrow table: %w", err)
+	}
+	return nil
+}
+
+// Put stores a new record.
+func (p *PostgresStore) Put(ctx context.Context, rec Record) error {
+	res, err := p.db.ExecContext(ctx,
+		`INSERT INTO `+p.table+` (id, policy, subject, key_id, wrapped_key, ciphertext, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7) ON CONFLICT (id) DO NOTHING`,
+		rec.ID, rec.Policy, rec.Subject, rec.KeyID, rec.WrappedKey, rec.Ciphertext, rec.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert escrow record: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to insert escrow record: %w", err)
+	}
+	if n == 0 {
+		return ErrRecordExists
+	}
+	return nil
+}
+
+// Get returns the record with the given ID.
+func (p *PostgresStore) Get(ctx context.Context, id string) (Record, error) {
+	rec := Record{ID: id}
+	err := p.db.QueryRowContext(ctx,
+		`SELECT policy, subject, key_id, wrapped_key, ciphertext, created_at FROM `+p.table+` WHERE id = $1`,
+		id).Scan(&rec.Policy, &rec.Subject, &rec.KeyID, &rec.WrappedKey, &rec.Ciphertext, &rec.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Record{}, ErrRecordNotFound
+	}
+	if err != nil {
+		return Record{}, fmt.Errorf("failed to read escrow record: %w", err)
+	}
+	return rec, nil
+}
@@ -0,0 +1,18 @@
+package escrow
+
+import "testing"
+
+func TestNewPostgresStore(t *testing.T) {
+	t.Parallel()
+
+	for _, table := range []string{"escrow", "_escrow_v2"} {
+		if _, err := NewPostgresStore(nil, table); err != nil {
+			t.Errorf("expected %q to be accepted: %v", table, err)
+		}
+	}
+	for _, table := range []string{"", "Escrow", "escrow; DROP TABLE users", "2escrow", "public.escrow"} {
+		if _, err := NewPostgresStore(nil, table); err == nil {
+			t.Errorf("expected %q to be rejected", table)
+		}
+	}
+}
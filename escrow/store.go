@@ -0,0 +1,59 @@
+package escrow
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrRecordExists is the error returned by a Store when a record with the same
+// ID already exists.
+var ErrRecordExists = errors.New("escrow record already exists")
+
+// Store stores escrow records. Implementations must be safe for concurrent use,
+// return ErrRecordNotFound for unknown records and never overwrite records.
+type Store interface {
+	// Put stores a new record.
+	Put(ctx context.Context, rec Record) error
+
+	// Get returns the record with the given ID.
+	Get(ctx context.Context, id string) (Record, error)
+}
+
+// MemoryStore is a Store which keeps records in memory. Records are lost when
+// the process exits, so it is only meant for tests.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	records map[string]Record
+}
+
+var _ Store = (*MemoryStore)(nil)
+
+// NewMemoryStore creates a new, empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+// Put stores a new record.
+func (m *MemoryStore) Put(_ context.Context, rec Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.records[rec.ID]; ok {
+		return ErrRecordExists
+	}
+	m.records[rec.ID] = rec
+	return nil
+}
+
+// Get returns the record with the given ID.
+func (m *MemoryStore) Get(_ context.Context, id string) (Record, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rec, ok := m.records[id]
+	if !ok {
+		return Record{}, ErrRecordNotFound
+	}
+	return rec, nil
+}
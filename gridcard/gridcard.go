@@ -0,0 +1,223 @@
+// Package gridcard generates grid cards, also called challenge or coordinate
+// cards: printable grids of random characters used as a second factor, where
+// the user is asked for the characters at a few coordinates such as B3 and
+// F7.
+//
+//	card, err := gridcard.New(gridcard.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Print(card.Printable())
+//	coords, err := card.Challenge(3)
+//	...
+//	ok := card.Verify(coords, []string{"K4", "7P", "XW"})
+package gridcard
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/juev/go-password/password"
+)
+
+// DefaultAlphabet is the default alphabet of the cells: digits and uppercase
+// letters, without the easily confused 0, 1, I and O.
+const DefaultAlphabet = "23456789ABCDEFGHJKLMNPQRSTUVWXYZ"
+
+// maxColumns is the maximum number of columns, labeled A to Z.
+const maxColumns = 26
+
+var (
+	// ErrInvalidSize is the error returned when the grid size is out of
+	// range.
+	ErrInvalidSize = errors.New("grid must have 1 to 26 columns and at least one row")
+
+	// ErrInvalidCoordinate is the error returned when a coordinate cannot be
+	// parsed or is outside of the grid.
+	ErrInvalidCoordinate = errors.New("invalid coordinate")
+
+	// ErrChallengeSize is the error returned when a challenge asks for more
+	// cells than the grid has.
+	ErrChallengeSize = errors.New("challenge size must be between 1 and the number of cells")
+)
+
+// Options used to define input parameters for New.
+type Options struct {
+	// Rows and Columns are the size of the grid. The default is 10x10.
+	Rows    int
+	Columns int
+
+	// CellLength is the number of characters of every cell. The default is
+	// 2.
+	CellLength int
+
+	// Alphabet is the characters of the cells. The default is
+	// DefaultAlphabet.
+	Alphabet string
+
+	// Reader is the source of randomness. The default is crypto/rand.Reader.
+	Reader io.Reader
+	_      struct{}
+}
+
+// Card is a grid card. Cells[row][column] holds the characters at the
+// coordinate of the column letter and the row number, starting at A1.
+type Card struct {
+	Cells [][]string
+}
+
+// New generates a new Card.
+func New(opts Options) (*Card, error) {
+	rows, cols, n := opts.Rows, opts.Columns, opts.CellLength
+	if rows == 0 {
+		rows = 10
+	}
+	if cols == 0 {
+		cols = 10
+	}
+	if n == 0 {
+		n = 2
+	}
+	if rows < 0 || cols < 0 || cols > maxColumns || n < 0 {
+		return nil, ErrInvalidSize
+	}
+
+	alphabet := opts.Alphabet
+	if alphabet == "" {
+		alphabet = DefaultAlphabet
+	}
+	r := opts.Reader
+	if r == nil {
+		r = rand.Reader
+	}
+
+	idx, err := password.UniformIndices(r, len(alphabet), rows*cols*n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate grid card: %w", err)
+	}
+
+	c := &Card{Cells: make([][]string, rows)}
+	for row := range c.Cells {
+		c.Cells[row] = make([]string, cols)
+		for col := range c.Cells[row] {
+			var b strings.Builder
+			for _, i := range idx[:n] {
+				b.WriteByte(alphabet[i])
+			}
+			idx = idx[n:]
+			c.Cells[row][col] = b.String()
+		}
+	}
+	return c, nil
+}
+
+// Cell returns the characters at the given coordinate, such as "B3".
+func (c *Card) Cell(coord string) (string, error) {
+	row, col, err := c.parse(coord)
+	if err != nil {
+		return "", err
+	}
+	return c.Cells[row][col], nil
+}
+
+// Challenge returns n distinct random coordinates of the grid.
+func (c *Card) Challenge(n int) ([]string, error) {
+	cols := c.columns()
+	total := len(c.Cells) * cols
+	if n < 1 || n > total {
+		return nil, ErrChallengeSize
+	}
+
+	cells := make([]int, total)
+	for i := range cells {
+		cells[i] = i
+	}
+	picked, err := password.Sample(cells, n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+
+	coords := make([]string, n)
+	for i, cell := range picked {
+		coords[i] = coordinate(cell/cols, cell%cols)
+	}
+	return coords, nil
+}
+
+// Verify reports whether the responses are the characters at the given
+// coordinates, in order. Responses are compared case-insensitively and in
+// constant time.
+func (c *Card) Verify(coords, responses []string) bool {
+	if len(coords) == 0 || len(coords) != len(responses) {
+		return false
+	}
+
+	ok := 1
+	for i, coord := range coords {
+		want, err := c.Cell(coord)
+		if err != nil {
+			return false
+		}
+		got := strings.ToUpper(strings.TrimSpace(responses[i]))
+		ok &= subtle.ConstantTimeCompare([]byte(strings.ToUpper(want)), []byte(got))
+	}
+	return ok == 1
+}
+
+// Printable returns the grid as text for printing, with column letters and
+// row numbers. It contains the secret cells.
+func (c *Card) Printable() string {
+	var b strings.Builder
+	width := len(strconv.Itoa(len(c.Cells)))
+	cell := 1
+	if len(c.Cells) > 0 && len(c.Cells[0]) > 0 {
+		cell = len(c.Cells[0][0])
+	}
+
+	header := strings.Repeat(" ", width)
+	for col := 0; col < c.columns(); col++ {
+		header += fmt.Sprintf(" %-*c", cell, 'A'+col)
+	}
+	b.WriteString(strings.TrimRight(header, " ") + "\n")
+	for row, cells := range c.Cells {
+		fmt.Fprintf(&b, "%*d", width, row+1)
+		for _, v := range cells {
+			b.WriteString(" " + v)
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// columns returns the number of columns of the grid.
+func (c *Card) columns() int {
+	if len(c.Cells) == 0 {
+		return 0
+	}
+	return len(c.Cells[0])
+}
+
+// parse parses a coordinate into the row and column indexes.
+func (c *Card) parse(coord string) (int, int, error) {
+	coord = strings.ToUpper(strings.TrimSpace(coord))
+	if len(coord) < 2 || coord[0] < 'A' || coord[0] > 'Z' {
+		return 0, 0, fmt.Errorf("%w %q", ErrInvalidCoordinate, coord)
+	}
+
+	col := int(coord[0] - 'A')
+	row, err := strconv.Atoi(coord[1:])
+	if err != nil || row < 1 || row > len(c.Cells) || col >= c.columns() {
+		return 0, 0, fmt.Errorf("%w %q", ErrInvalidCoordinate, coord)
+	}
+	return row - 1, col, nil
+}
+
+// coordinate returns the coordinate of the given row and column indexes.
+func coordinate(row, col int) string {
+	return string(rune('A'+col)) + strconv.Itoa(row+1)
+}
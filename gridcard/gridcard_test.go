@@ -0,0 +1,99 @@
+package gridcard
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	c, err := New(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Cells) != 10 || len(c.Cells[0]) != 10 {
+		t.Fatalf("expected a 10x10 grid, got %dx%d", len(c.Cells), len(c.Cells[0]))
+	}
+	for _, row := range c.Cells {
+		for _, cell := range row {
+			if len(cell) != 2 || strings.Trim(cell, DefaultAlphabet) != "" {
+				t.Errorf("unexpected cell %q", cell)
+			}
+		}
+	}
+
+	c, err = New(Options{Rows: 3, Columns: 4, CellLength: 1, Alphabet: "xy"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Cells) != 3 || len(c.Cells[2]) != 4 || strings.Trim(c.Cells[2][3], "xy") != "" {
+		t.Errorf("unexpected grid %v", c.Cells)
+	}
+
+	if _, err := New(Options{Columns: 27}); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidSize)
+	}
+}
+
+func TestCardChallenge(t *testing.T) {
+	t.Parallel()
+
+	c := &Card{Cells: [][]string{
+		{"AB", "CD", "EF"},
+		{"GH", "JK", "LM"},
+	}}
+
+	if v, err := c.Cell("b2"); err != nil || v != "JK" {
+		t.Errorf("expected JK, got %q, %v", v, err)
+	}
+	for _, coord := range []string{"", "A", "D1", "A3", "A0", "1A", "Ax"} {
+		if _, err := c.Cell(coord); !errors.Is(err, ErrInvalidCoordinate) {
+			t.Errorf("expected %q to be invalid, got %v", coord, err)
+		}
+	}
+
+	coords, err := c.Challenge(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	responses := make([]string, len(coords))
+	for i, coord := range coords {
+		if seen[coord] {
+			t.Errorf("expected distinct coordinates, got %q", coords)
+		}
+		seen[coord] = true
+		responses[i], _ = c.Cell(coord)
+	}
+	if !c.Verify(coords, responses) {
+		t.Error("expected the correct responses to be accepted")
+	}
+	if _, err := c.Challenge(7); !errors.Is(err, ErrChallengeSize) {
+		t.Errorf("expected %v to be %v", err, ErrChallengeSize)
+	}
+
+	if !c.Verify([]string{"A1", "C2"}, []string{"ab", " LM"}) {
+		t.Error("expected responses to be compared case-insensitively")
+	}
+	if c.Verify([]string{"A1", "C2"}, []string{"AB", "LN"}) {
+		t.Error("expected a wrong response to be rejected")
+	}
+	if c.Verify([]string{"A1", "C2"}, []string{"AB"}) || c.Verify(nil, nil) {
+		t.Error("expected mismatched responses to be rejected")
+	}
+}
+
+func TestCardPrintable(t *testing.T) {
+	t.Parallel()
+
+	c := &Card{Cells: [][]string{
+		{"AB", "CD"},
+		{"EF", "GH"},
+	}}
+	want := "  A  B\n1 AB CD\n2 EF GH\n"
+	if got := c.Printable(); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
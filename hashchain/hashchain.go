@@ -0,0 +1,196 @@
+// Package hashchain generates one-time codes from a hash chain, in the style
+// of S/KEY, for unlocking offline devices such as kiosks. Unlike TOTP, it
+// needs no clock: the device only stores the last accepted value, and each
+// code is accepted once, in order.
+//
+// A chain of n codes starts from a random seed hashed n times; the last hash
+// is the anchor given to the device. Code i is the seed hashed n-i times, so
+// hashing a code once yields the previous one, and knowing used codes does
+// not reveal the next ones.
+//
+//	c, err := hashchain.New(100)
+//	if err != nil {
+//		return err
+//	}
+//	v := hashchain.NewVerifier(c.Anchor(), 5) // on the kiosk
+//	code, _ := c.Code(1)                       // printed for the operator
+//	ok := v.Verify(code)
+package hashchain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ValueSize is the size of a chain value in bytes. Values are folded to 64
+// bits, like S/KEY, so that codes are short enough to type.
+const ValueSize = 8
+
+// domain separates hash chain values from other uses of SHA-256.
+const domain = "go-password hashchain v1\x00"
+
+var (
+	// ErrChainLength is the error returned when a chain has no codes.
+	ErrChainLength = errors.New("chain length must be positive")
+
+	// ErrCodeIndex is the error returned when a code index is out of range.
+	ErrCodeIndex = errors.New("code index out of range")
+
+	// ErrInvalidCode is the error returned when a code or an anchor cannot
+	// be parsed.
+	ErrInvalidCode = errors.New("invalid code")
+)
+
+// Value is a value of a hash chain.
+type Value [ValueSize]byte
+
+// String returns the value formatted as a code, in groups of four hexadecimal
+// digits such as "3f2a-09bc-77de-1045".
+func (v Value) String() string {
+	s := hex.EncodeToString(v[:])
+	return s[0:4] + "-" + s[4:8] + "-" + s[8:12] + "-" + s[12:16]
+}
+
+// ParseValue parses a code or an anchor. Case, dashes and spaces are ignored.
+func ParseValue(s string) (Value, error) {
+	s = strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(s))
+
+	var v Value
+	if len(s) != 2*ValueSize {
+		return v, ErrInvalidCode
+	}
+	if _, err := hex.Decode(v[:], []byte(s)); err != nil {
+		return v, fmt.Errorf("%w: %w", ErrInvalidCode, err)
+	}
+	return v, nil
+}
+
+// next returns the next value of the chain.
+func (v Value) next() Value {
+	h := sha256.New()
+	h.Write([]byte(domain))
+	h.Write(v[:])
+	sum := h.Sum(nil)
+
+	var res Value
+	for i, b := range sum {
+		res[i%ValueSize] ^= b
+	}
+	return res
+}
+
+// Chain is a hash chain of one-time codes.
+type Chain struct {
+	seed   Value
+	length int
+}
+
+// New creates a new Chain of the given number of codes from a random seed.
+func New(length int) (*Chain, error) {
+	return NewFromReader(rand.Reader, length)
+}
+
+// NewFromReader creates a new Chain of the given number of codes from a seed
+// read from r.
+func NewFromReader(r io.Reader, length int) (*Chain, error) {
+	if length <= 0 {
+		return nil, ErrChainLength
+	}
+
+	var seed Value
+	if _, err := io.ReadFull(r, seed[:]); err != nil {
+		return nil, fmt.Errorf("failed to read seed: %w", err)
+	}
+	return &Chain{seed: seed, length: length}, nil
+}
+
+// Len returns the number of codes of the chain.
+func (c *Chain) Len() int {
+	return c.length
+}
+
+// Anchor returns the value a verifier starts from. It is not secret.
+func (c *Chain) Anchor() Value {
+	return c.value(c.length)
+}
+
+// Code returns the code to use in position i, from 1 to Len.
+func (c *Chain) Code(i int) (string, error) {
+	if i < 1 || i > c.length {
+		return "", ErrCodeIndex
+	}
+	return c.value(c.length - i).String(), nil
+}
+
+// Codes returns all codes of the chain, in the order they are used.
+func (c *Chain) Codes() []string {
+	codes := make([]string, c.length)
+	v := c.seed
+	for i := c.length - 1; i >= 0; i-- {
+		codes[i] = v.String()
+		v = v.next()
+	}
+	return codes
+}
+
+// value returns the seed hashed n times.
+func (c *Chain) value(n int) Value {
+	v := c.seed
+	for i := 0; i < n; i++ {
+		v = v.next()
+	}
+	return v
+}
+
+// Verifier accepts the codes of a chain in order. It is not safe for
+// concurrent use.
+type Verifier struct {
+	last   Value
+	window int
+}
+
+// NewVerifier creates a new Verifier starting from the given anchor or last
+// accepted value. Up to window codes may be skipped, for example when printed
+// codes were lost; a window of zero only accepts the next code.
+func NewVerifier(last Value, window int) *Verifier {
+	return &Verifier{last: last, window: max(window, 0)}
+}
+
+// Last returns the last accepted value, which the device persists to resume
+// verification after a restart.
+func (v *Verifier) Last() Value {
+	return v.last
+}
+
+// Verify reports whether the code is the next one of the chain, or one of the
+// window codes after it. An accepted code and all codes before it cannot be
+// used again.
+func (v *Verifier) Verify(code string) bool {
+	cur, err := ParseValue(code)
+	if err != nil {
+		return false
+	}
+
+	candidate := cur
+	match := 0
+	for i := 0; i <= v.window; i++ {
+		candidate = candidate.next()
+		match |= subtle.ConstantTimeCompare(candidate[:], v.last[:])
+	}
+	if match == 0 {
+		return false
+	}
+	v.last = cur
+	return true
+}
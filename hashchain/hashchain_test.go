@@ -0,0 +1,89 @@
+package hashchain
+
+import (
+	"bytes"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	t.Parallel()
+
+	c, err := NewFromReader(bytes.NewReader(make([]byte, ValueSize)), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codes := c.Codes()
+	for i := range codes {
+		code, err := c.Code(i + 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != codes[i] {
+			t.Errorf("expected code %d to be %q, got %q", i+1, codes[i], code)
+		}
+	}
+	if len(slices.Compact(slices.Clone(codes))) != len(codes) {
+		t.Errorf("expected distinct codes, got %q", codes)
+	}
+
+	if _, err := c.Code(0); !errors.Is(err, ErrCodeIndex) {
+		t.Errorf("expected %v to be %v", err, ErrCodeIndex)
+	}
+	if _, err := c.Code(11); !errors.Is(err, ErrCodeIndex) {
+		t.Errorf("expected %v to be %v", err, ErrCodeIndex)
+	}
+	if _, err := New(0); !errors.Is(err, ErrChainLength) {
+		t.Errorf("expected %v to be %v", err, ErrChainLength)
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	t.Parallel()
+
+	c, err := New(20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes := c.Codes()
+	v := NewVerifier(c.Anchor(), 2)
+
+	if !v.Verify(codes[0]) {
+		t.Fatal("expected the first code to be accepted")
+	}
+	if v.Verify(codes[0]) {
+		t.Error("expected a used code to be rejected")
+	}
+	if !v.Verify(strings.ToUpper(strings.ReplaceAll(codes[1], "-", " "))) {
+		t.Error("expected the code to be accepted whatever its formatting")
+	}
+
+	// Codes 3 and 4 were lost.
+	if v.Verify(codes[5]) {
+		t.Error("expected a code beyond the window to be rejected")
+	}
+	if !v.Verify(codes[4]) {
+		t.Error("expected a code within the window to be accepted")
+	}
+	if v.Verify(codes[2]) {
+		t.Error("expected a skipped code to be rejected")
+	}
+
+	// The verifier resumes from its persisted state.
+	last, err := ParseValue(v.Last().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !NewVerifier(last, 0).Verify(codes[5]) {
+		t.Error("expected the next code to be accepted after resuming")
+	}
+
+	for _, code := range []string{"", "not a code", "0000-0000-0000-000g"} {
+		if v.Verify(code) {
+			t.Errorf("expected %q to be rejected", code)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+// Package honeypot generates honeypot credentials: user name and password
+// pairs planted in configuration files, vaults or decoy hosts, which nobody
+// legitimately uses. Their passwords embed a beacon, derived with HMAC from the
+// tenant ID under a key of the security team, so that a credential found in the
+// wild, in a paste site, a breach dump or a login attempt, can be recognized as
+// a leaked honeypot and attributed to its tenant with Verify.
+//
+// The beacon is made of letters of the Generator charsets, chosen uniformly
+// from the HMAC of the rest of the password and placed at positions derived from
+// the key, and the password is checked against the requirements of the input.
+// Without the key, honeypot passwords look like generated passwords
+// TagLength characters longer than the input.
+//
+//	input := password.Input{Length: 20, Digits: 4}
+//	c, err := honeypot.New(key, "acme", input, honeypot.Options{})
+//	if err != nil {
+//		return err
+//	}
+//	...
+//	if honeypot.Verify(key, "acme", leaked, input, honeypot.Options{}) {
+//		alert("acme honeypot credential leaked")
+//	}
+package honeypot
+
+import (
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/juev/go-password/password"
+)
+
+const (
+	// TagLength is the length of the beacon embedded in the passwords, in
+	// characters.
+	TagLength = 10
+
+	// MinKeySize is the minimum size of the key, in bytes.
+	MinKeySize = 16
+
+	// maxAttempts is the number of beacon layouts tried before giving up on
+	// an input whose requirements leave no room for the beacon.
+	maxAttempts = 32
+)
+
+var (
+	// ErrInvalidKey is the error returned when the key is shorter than
+	// MinKeySize.
+	ErrInvalidKey = errors.New("honeypot key too short")
+
+	// ErrInvalidTenant is the error returned for an empty tenant ID.
+	ErrInvalidTenant = errors.New("invalid tenant ID")
+
+	// ErrUnsatisfiable is the error returned when the beacon cannot be
+	// embedded in a password satisfying the requirements of the input, such
+	// as a MaxSameClassRun too short for the letters of the beacon.
+	ErrUnsatisfiable = errors.New("cannot embed a beacon satisfying the input")
+)
+
+// Options used to define options for New and Verify.
+type Options struct {
+	// UsernamePrefix is the prefix of the generated user name, followed by 8
+	// hexadecimal characters. The default is "svc_".
+	UsernamePrefix string
+
+	// Generator is the generator of the credentials, whose charsets the
+	// beacon is drawn from. The default is password.NewGenerator().
+	Generator *password.Generator
+	_         struct{}
+}
+
+// Credential is a honeypot user name and password pair.
+type Credential struct {
+	Username string
+	Password string
+}
+
+// New generates a honeypot credential of the tenant. The password is generated
+// with the given input, and the beacon inserted into it, so that it is
+// TagLength characters longer than input.Length. It returns ErrUnsatisfiable
+// if the password with the beacon does not satisfy the input with the longer
+// length, as checked by password.Generator.Validate.
+func New(key []byte, tenant string, input password.Input, opts Options) (Credential, error) {
+	if len(key) < MinKeySize {
+		return Credential{}, ErrInvalidKey
+	}
+	if tenant == "" {
+		return Credential{}, ErrInvalidTenant
+	}
+	if opts.UsernamePrefix == "" {
+		opts.UsernamePrefix = "svc_"
+	}
+	gen := generator(opts)
+
+	id, err := gen.GenerateHex(4)
+	if err != nil {
+		return Credential{}, err
+	}
+
+	tagged := input
+	tagged.Length += TagLength
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		body, err := gen.Generate(input)
+		if err != nil {
+			return Credential{}, err
+		}
+
+		pw, err := embed(gen, key, tenant, input, body, attempt)
+		if err != nil || gen.Validate(pw, tagged) != nil {
+			continue
+		}
+		return Credential{
+			Username: opts.UsernamePrefix + id,
+			Password: pw,
+		}, nil
+	}
+	return Credential{}, ErrUnsatisfiable
+}
+
+// Verify reports whether the password is a honeypot credential of the tenant
+// generated with the key, input and options. To attribute a leaked password,
+// verify it against every tenant.
+func Verify(key []byte, tenant, pw string, input password.Input, opts Options) bool {
+	runes := []rune(pw)
+	if len(key) < MinKeySize || tenant == "" || len(runes) != input.Length+TagLength {
+		return false
+	}
+	gen := generator(opts)
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		body := make([]rune, 0, input.Length)
+		got := make([]rune, 0, TagLength)
+		next := positions(key, tenant, len(runes), attempt)
+		for i, c := range runes {
+			if len(next) > 0 && next[0] == i {
+				got = append(got, c)
+				next = next[1:]
+				continue
+			}
+			body = append(body, c)
+		}
+
+		want, err := tag(gen, key, tenant, input, string(body))
+		if err == nil && hmac.Equal([]byte(want), []byte(string(got))) {
+			return true
+		}
+	}
+	return false
+}
+
+// generator returns the generator of the options.
+func generator(opts Options) password.Generator {
+	if opts.Generator != nil {
+		return *opts.Generator
+	}
+	return password.NewGenerator()
+}
+
+// embed inserts the beacon of the password body into it at the positions of
+// the attempt.
+func embed(gen password.Generator, key []byte, tenant string, input password.Input, body string, attempt int) (string, error) {
+	t, err := tag(gen, key, tenant, input, body)
+	if err != nil {
+		return "", err
+	}
+
+	tagRunes, bodyRunes := []rune(t), []rune(body)
+	res := make([]rune, 0, len(bodyRunes)+TagLength)
+	next := positions(key, tenant, len(bodyRunes)+TagLength, attempt)
+	for len(res) < cap(res) {
+		if len(next) > 0 && next[0] == len(res) {
+			res = append(res, tagRunes[0])
+			tagRunes, next = tagRunes[1:], next[1:]
+			continue
+		}
+		res = append(res, bodyRunes[0])
+		bodyRunes = bodyRunes[1:]
+	}
+	return string(res), nil
+}
+
+// tag returns the beacon of the password body of the tenant: TagLength letters
+// generated by the Generator from a stream derived from the key, tenant and
+// body. Unless the input allows repeats, letters of the body are excluded.
+func tag(gen password.Generator, key []byte, tenant string, input password.Input, body string) (string, error) {
+	info := fmt.Sprintf("honeypot tag\x00%d\x00%s%s", len(tenant), tenant, body)
+	gen = gen.WithReader(password.NewDerivedReader(key, info))
+	if !input.AllowRepeat {
+		gen = gen.WithFilter(func(t string) bool {
+			return !strings.ContainsAny(t, body)
+		})
+	}
+	return gen.Generate(password.Input{
+		Length:      TagLength,
+		NoUpper:     input.NoUpper,
+		AllowRepeat: input.AllowRepeat,
+	})
+}
+
+// positions returns the sorted positions of the beacon in a password of n
+// characters for the attempt, derived from the key and tenant.
+func positions(key []byte, tenant string, n, attempt int) []int {
+	r := password.NewDerivedReader(key, fmt.Sprintf("honeypot positions\x00%d\x00%d\x00%s", n, attempt, tenant))
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	for i := 0; i < TagLength; i++ {
+		// The derived reader never fails.
+		j, _ := password.UniformIndex(r, n-i)
+		idx[i], idx[i+j] = idx[i+j], idx[i]
+	}
+	res := idx[:TagLength]
+	slices.Sort(res)
+	return res
+}
@@ -0,0 +1,144 @@
+package honeypot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/juev/go-password/password"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("0123456789abcdef")
+	input := password.Input{Length: 20, Digits: 4, Symbols: 2}
+
+	c, err := New(key, "acme", input, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(c.Username, "svc_") || len(c.Username) != 12 {
+		t.Errorf("unexpected user name %q", c.Username)
+	}
+	tagged := input
+	tagged.Length += TagLength
+	if err := password.NewGenerator().Validate(c.Password, tagged); err != nil {
+		t.Errorf("expected %q to be a valid password: %v", c.Password, err)
+	}
+
+	if !Verify(key, "acme", c.Password, input, Options{}) {
+		t.Errorf("expected %q to verify", c.Password)
+	}
+	if Verify(key, "globex", c.Password, input, Options{}) {
+		t.Error("expected another tenant not to verify")
+	}
+	if Verify([]byte("fedcba9876543210"), "acme", c.Password, input, Options{}) {
+		t.Error("expected another key not to verify")
+	}
+	if Verify(key, "acme", c.Password, password.Input{Length: 21, Digits: 4, Symbols: 2}, Options{}) {
+		t.Error("expected another input not to verify")
+	}
+	for i := range c.Password {
+		modified := c.Password[:i] + "!" + c.Password[i+1:]
+		if modified != c.Password && Verify(key, "acme", modified, input, Options{}) {
+			t.Errorf("expected modified %q not to verify", modified)
+		}
+	}
+	if Verify(key, "acme", "short", input, Options{}) {
+		t.Error("expected a short password not to verify")
+	}
+
+	pw, err := password.Generate(tagged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if Verify(key, "acme", pw, input, Options{}) {
+		t.Errorf("expected %q not to verify", pw)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("0123456789abcdef")
+	suffix := 0
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		p := positions(key, "acme", 30, attempt)
+		if len(p) != TagLength || p[0] < 0 || p[TagLength-1] >= 30 {
+			t.Fatalf("unexpected positions %v", p)
+		}
+		for i := 1; i < len(p); i++ {
+			if p[i] <= p[i-1] {
+				t.Fatalf("expected positions %v to be sorted and distinct", p)
+			}
+		}
+		if p[0] == 30-TagLength {
+			suffix++
+		}
+	}
+	if suffix == maxAttempts {
+		t.Error("expected the beacon not to be appended")
+	}
+}
+
+func TestNewRequirements(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("0123456789abcdef")
+	for _, input := range []password.Input{
+		{Length: 12, Digits: 2, Symbols: 2},
+		{Length: 20, Digits: 8, Symbols: 6, MaxSameClassRun: 4},
+		{Length: 16, Digits: 2, RequireFromEachClass: true},
+		{Length: 8, Digits: 2, NoUpper: true},
+		{Length: 16, MinDigits: 2, MaxDigits: 6},
+	} {
+		c, err := New(key, "acme", input, Options{})
+		if err != nil {
+			t.Fatalf("%+v: %v", input, err)
+		}
+		tagged := input
+		tagged.Length += TagLength
+		if err := password.NewGenerator().Validate(c.Password, tagged); err != nil {
+			t.Errorf("expected %q to be a valid password: %v", c.Password, err)
+		}
+		if !Verify(key, "acme", c.Password, input, Options{}) {
+			t.Errorf("expected %q to verify", c.Password)
+		}
+	}
+
+	input := password.Input{Length: 20, Digits: 4, Symbols: 2, MaxSameClassRun: 2}
+	if _, err := New(key, "acme", input, Options{}); !errors.Is(err, ErrUnsatisfiable) {
+		t.Errorf("expected %v to be %v", err, ErrUnsatisfiable)
+	}
+}
+
+func TestNewOptions(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("0123456789abcdef")
+	g := password.NewGenerator().WithLowerLetters("äöü").WithUpperLetters("ÄÖÜ")
+	input := password.Input{Length: 6, AllowRepeat: true}
+	opts := Options{UsernamePrefix: "db-", Generator: &g}
+	c, err := New(key, "acme", input, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(c.Username, "db-") || !Verify(key, "acme", c.Password, input, opts) {
+		t.Errorf("unexpected credential %+v", c)
+	}
+	if utf8.RuneCountInString(c.Password) != 6+TagLength || strings.Trim(c.Password, "äöüÄÖÜ") != "" {
+		t.Errorf("expected %q to only use the generator charsets", c.Password)
+	}
+	if Verify(key, "acme", c.Password, input, Options{}) {
+		t.Error("expected other charsets not to verify")
+	}
+
+	if _, err := New(key[:8], "acme", password.Input{Length: 6}, Options{}); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidKey)
+	}
+	if _, err := New(key, "", password.Input{Length: 6}, Options{}); !errors.Is(err, ErrInvalidTenant) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidTenant)
+	}
+}
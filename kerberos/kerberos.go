@@ -0,0 +1,182 @@
+// Package kerberos computes the keys which Active Directory and Kerberos
+// derive from a password, for provisioning flows which must set keys or
+// hashes directly, such as keytab generation or directory synchronization.
+//
+// These functions are for interoperability only. The NT hash is an unsalted
+// MD4 digest which is password-equivalent: anyone holding it can authenticate
+// as the user. Treat every key as the password itself, and never use them to
+// store or verify passwords.
+//
+//	pw, err := password.Generate(password.Input{Length: 32, Digits: 6, Symbols: 6})
+//	if err != nil {
+//		return err
+//	}
+//	nt := kerberos.NTHash(pw)
+//	aes256 := kerberos.AES256Key(pw, kerberos.Salt("EXAMPLE.COM", "alice"))
+package kerberos
+
+import (
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"unicode/utf16"
+)
+
+// DefaultIterations is the default PBKDF2 iteration count of the AES string
+// to key function, used by Active Directory.
+const DefaultIterations = 4096
+
+var (
+	// ErrKeySize is the error returned when an AES key size is neither 16 nor
+	// 32 bytes.
+	ErrKeySize = errors.New("AES key size must be 16 or 32 bytes")
+
+	// ErrIterations is the error returned when the iteration count is not
+	// positive.
+	ErrIterations = errors.New("iteration count must be positive")
+)
+
+// NTHash returns the NT hash of the password: the MD4 digest of its UTF-16LE
+// encoding. It is also the RC4-HMAC (etype 23) key of Kerberos.
+func NTHash(pw string) []byte {
+	units := utf16.Encode([]rune(pw))
+	b := make([]byte, 0, 2*len(units))
+	for _, u := range units {
+		b = binary.LittleEndian.AppendUint16(b, u)
+	}
+	defer clear(b)
+
+	sum := md4(b)
+	return sum[:]
+}
+
+// Salt returns the default salt of a principal: the realm followed by the
+// components of the principal name, such as "EXAMPLE.COMalice" for
+// alice@EXAMPLE.COM. Active Directory uses the uppercase realm and the
+// sAMAccountName of users.
+func Salt(realm string, components ...string) string {
+	return realm + strings.Join(components, "")
+}
+
+// AES128Key returns the AES128-CTS-HMAC-SHA1-96 (etype 17) key of the password
+// with the given salt and DefaultIterations.
+func AES128Key(pw, salt string) []byte {
+	key, _ := AESKey(pw, salt, 16, DefaultIterations)
+	return key
+}
+
+// AES256Key returns the AES256-CTS-HMAC-SHA1-96 (etype 18) key of the password
+// with the given salt and DefaultIterations.
+func AES256Key(pw, salt string) []byte {
+	key, _ := AESKey(pw, salt, 32, DefaultIterations)
+	return key
+}
+
+// AESKey returns the AES key of the given size, in bytes, of the password with
+// the given salt and iteration count, with the string to key function of RFC
+// 3962: PBKDF2-HMAC-SHA1 followed by the key derivation of RFC 3961 with the
+// constant "kerberos".
+func AESKey(pw, salt string, size, iterations int) ([]byte, error) {
+	if size != 16 && size != 32 {
+		return nil, ErrKeySize
+	}
+	if iterations < 1 {
+		return nil, ErrIterations
+	}
+
+	tkey := pbkdf2SHA1([]byte(pw), []byte(salt), iterations, size)
+	defer clear(tkey)
+	return derive(tkey, []byte("kerberos"))
+}
+
+// derive returns the key derived from the base key with the given constant,
+// with the DK function of RFC 3961: the n-folded constant is encrypted
+// repeatedly, and the blocks are concatenated until they fill the key.
+func derive(key, constant []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(key))
+	in := nfold(constant, aes.BlockSize)
+	for len(out) < len(key) {
+		next := make([]byte, aes.BlockSize)
+		block.Encrypt(next, in)
+		out = append(out, next...)
+		in = next
+	}
+	return out[:len(key)], nil
+}
+
+// nfold returns the n-fold of in into n bytes, as specified by RFC 3961:
+// copies of in, each rotated right by 13 bits more than the previous one,
+// are added with ones' complement addition in blocks of n bytes.
+func nfold(in []byte, n int) []byte {
+	inLen := len(in)
+	lcm := n * inLen / gcd(n, inLen)
+	inBits := inLen * 8
+
+	out := make([]byte, n)
+	carry := 0
+	for i := lcm - 1; i >= 0; i-- {
+		// msbit is the bit of in which is the most significant bit of byte i
+		// of the rotated copies.
+		msbit := (inBits - 1 + (inBits+13)*(i/inLen) + (inLen-i%inLen)*8) % inBits
+		hi := int(in[(inLen-1-msbit>>3)%inLen])
+		lo := int(in[(inLen-msbit>>3)%inLen])
+		carry += ((hi<<8 | lo) >> (msbit&7 + 1)) & 0xff
+		carry += int(out[i%n])
+		out[i%n] = byte(carry)
+		carry >>= 8
+	}
+
+	// The final carry wraps around, as in ones' complement addition.
+	for carry != 0 {
+		for i := n - 1; i >= 0; i-- {
+			carry += int(out[i])
+			out[i] = byte(carry)
+			carry >>= 8
+		}
+	}
+	return out
+}
+
+// gcd returns the greatest common divisor of a and b.
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// pbkdf2SHA1 returns the key of the given size derived from the password and
+// salt with PBKDF2-HMAC-SHA1, as specified by RFC 8018.
+func pbkdf2SHA1(pw, salt []byte, iterations, size int) []byte {
+	prf := hmac.New(sha1.New, pw)
+	key := make([]byte, 0, size+sha1.Size)
+	u := make([]byte, sha1.Size)
+	for block := uint32(1); len(key) < size; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, block))
+		u = prf.Sum(u[:0])
+		t := append([]byte(nil), u...)
+
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+		clear(t)
+	}
+	clear(u)
+	return key[:size]
+}
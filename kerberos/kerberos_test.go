@@ -0,0 +1,142 @@
+package kerberos
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestMD4(t *testing.T) {
+	t.Parallel()
+
+	// Test suite of RFC 1320.
+	for in, want := range map[string]string{
+		"":                           "31d6cfe0d16ae931b73c59d7e0c089c0",
+		"a":                          "bde52cb31de33e46245e05fbdbd6fb24",
+		"abc":                        "a448017aaf21d8525fc10ae87aa6729d",
+		"message digest":             "d9130a8164549fe818874806e1c7014b",
+		"abcdefghijklmnopqrstuvwxyz": "d79e1c308aa5bbcdeea8ed63df412da9",
+		"12345678901234567890123456789012345678901234567890123456789012345678901234567890": "e33b4ddc9c38f2199c3e7b164fcc0536",
+	} {
+		sum := md4([]byte(in))
+		if got := hex.EncodeToString(sum[:]); got != want {
+			t.Errorf("expected MD4(%q) to be %s, got %s", in, want, got)
+		}
+	}
+}
+
+func TestNTHash(t *testing.T) {
+	t.Parallel()
+
+	for pw, want := range map[string]string{
+		"":         "31d6cfe0d16ae931b73c59d7e0c089c0",
+		"password": "8846f7eaee8fb117ad06bdd830b7586c",
+	} {
+		if got := hex.EncodeToString(NTHash(pw)); got != want {
+			t.Errorf("expected NTHash(%q) to be %s, got %s", pw, want, got)
+		}
+	}
+}
+
+func TestNFold(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors of RFC 3961, appendix A.1.
+	cases := []struct {
+		in   string
+		bits int
+		want string
+	}{
+		{"012345", 64, "be072631276b1955"},
+		{"password", 56, "78a07b6caf85fa"},
+		{"Rough Consensus, and Running Code", 64, "bb6ed30870b7f0e0"},
+		{"password", 168, "59e4a8ca7c0385c3c37b3f6d2000247cb6e6bd5b3e"},
+		{"kerberos", 64, "6b65726265726f73"},
+		{"kerberos", 128, "6b65726265726f737b9b5b2b93132b93"},
+		{"kerberos", 256, "6b65726265726f737b9b5b2b93132b935c9bdcdad95c9899c4cae4dee6d6cae4"},
+	}
+
+	for _, tc := range cases {
+		if got := hex.EncodeToString(nfold([]byte(tc.in), tc.bits/8)); got != tc.want {
+			t.Errorf("expected %d-fold(%q) to be %s, got %s", tc.bits, tc.in, tc.want, got)
+		}
+	}
+}
+
+func TestAESKey(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors of RFC 3962, appendix B.
+	cases := []struct {
+		pw, salt   string
+		iterations int
+		aes128     string
+		aes256     string
+	}{
+		{
+			"password", "ATHENA.MIT.EDUraeburn", 1,
+			"42263c6e89f4fc28b8df68ee09799f15",
+			"fe697b52bc0d3ce14432ba036a92e65bbb52280990a2fa27883998d72af30161",
+		},
+		{
+			"password", "ATHENA.MIT.EDUraeburn", 1200,
+			"4c01cd46d632d01e6dbe230a01ed642a",
+			"55a6ac740ad17b4846941051e1e8b0a7548d93b0ab30a8bc3ff16280382b8c2a",
+		},
+	}
+
+	for _, tc := range cases {
+		for size, want := range map[int]string{16: tc.aes128, 32: tc.aes256} {
+			key, err := AESKey(tc.pw, tc.salt, size, tc.iterations)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := hex.EncodeToString(key); got != want {
+				t.Errorf("expected the %d-byte key with %d iterations to be %s, got %s", size, tc.iterations, want, got)
+			}
+		}
+	}
+
+	if len(AES128Key("password", Salt("EXAMPLE.COM", "alice"))) != 16 || len(AES256Key("password", "EXAMPLE.COMalice")) != 32 {
+		t.Error("unexpected key sizes")
+	}
+	if _, err := AESKey("password", "salt", 24, 1); !errors.Is(err, ErrKeySize) {
+		t.Errorf("expected %v to be %v", err, ErrKeySize)
+	}
+	if _, err := AESKey("password", "salt", 16, 0); !errors.Is(err, ErrIterations) {
+		t.Errorf("expected %v to be %v", err, ErrIterations)
+	}
+}
+
+func TestPBKDF2SHA1(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors of RFC 6070.
+	cases := []struct {
+		pw, salt   string
+		iterations int
+		want       string
+	}{
+		{"password", "salt", 1, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+		{"password", "salt", 4096, "4b007901b765489abead49d926f721d065a429c1"},
+		{"passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038"},
+	}
+
+	for _, tc := range cases {
+		got := hex.EncodeToString(pbkdf2SHA1([]byte(tc.pw), []byte(tc.salt), tc.iterations, len(tc.want)/2))
+		if got != tc.want {
+			t.Errorf("expected PBKDF2(%q, %q, %d) to be %s, got %s", tc.pw, tc.salt, tc.iterations, tc.want, got)
+		}
+	}
+}
+
+func TestSalt(t *testing.T) {
+	t.Parallel()
+
+	if got := Salt("ATHENA.MIT.EDU", "raeburn"); got != "ATHENA.MIT.EDUraeburn" {
+		t.Errorf("unexpected salt %q", got)
+	}
+	if got := Salt("EXAMPLE.COM", "host", "web.example.com"); got != "EXAMPLE.COMhostweb.example.com" {
+		t.Errorf("unexpected salt %q", got)
+	}
+}
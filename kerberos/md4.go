@@ -0,0 +1,61 @@
+package kerberos
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// md4 returns the MD4 digest of b, as specified by RFC 1320. MD4 is broken
+// and only implemented for the NT hash, which is defined with it.
+func md4(b []byte) [16]byte {
+	// The message is padded with a 1 bit, zeros and its length in bits, to a
+	// multiple of 64 bytes.
+	msg := append([]byte(nil), b...)
+	msg = append(msg, 0x80)
+	for len(msg)%64 != 56 {
+		msg = append(msg, 0)
+	}
+	msg = binary.LittleEndian.AppendUint64(msg, uint64(len(b))*8)
+
+	a, bb, c, d := uint32(0x67452301), uint32(0xefcdab89), uint32(0x98badcfe), uint32(0x10325476)
+	var x [16]uint32
+	for ; len(msg) > 0; msg = msg[64:] {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(msg[4*i:])
+		}
+		aa, bbb, cc, dd := a, bb, c, d
+
+		// Round 1.
+		for _, i := range []int{0, 4, 8, 12} {
+			a = bits.RotateLeft32(a+(bb&c|^bb&d)+x[i], 3)
+			d = bits.RotateLeft32(d+(a&bb|^a&c)+x[i+1], 7)
+			c = bits.RotateLeft32(c+(d&a|^d&bb)+x[i+2], 11)
+			bb = bits.RotateLeft32(bb+(c&d|^c&a)+x[i+3], 19)
+		}
+
+		// Round 2.
+		for _, i := range []int{0, 1, 2, 3} {
+			a = bits.RotateLeft32(a+(bb&c|bb&d|c&d)+x[i]+0x5a827999, 3)
+			d = bits.RotateLeft32(d+(a&bb|a&c|bb&c)+x[i+4]+0x5a827999, 5)
+			c = bits.RotateLeft32(c+(d&a|d&bb|a&bb)+x[i+8]+0x5a827999, 9)
+			bb = bits.RotateLeft32(bb+(c&d|c&a|d&a)+x[i+12]+0x5a827999, 13)
+		}
+
+		// Round 3.
+		for _, i := range []int{0, 2, 1, 3} {
+			a = bits.RotateLeft32(a+(bb^c^d)+x[i]+0x6ed9eba1, 3)
+			d = bits.RotateLeft32(d+(a^bb^c)+x[i+8]+0x6ed9eba1, 9)
+			c = bits.RotateLeft32(c+(d^a^bb)+x[i+4]+0x6ed9eba1, 11)
+			bb = bits.RotateLeft32(bb+(c^d^a)+x[i+12]+0x6ed9eba1, 15)
+		}
+
+		a, bb, c, d = a+aa, bb+bbb, c+cc, d+dd
+	}
+
+	var sum [16]byte
+	binary.LittleEndian.PutUint32(sum[0:], a)
+	binary.LittleEndian.PutUint32(sum[4:], bb)
+	binary.LittleEndian.PutUint32(sum[8:], c)
+	binary.LittleEndian.PutUint32(sum[12:], d)
+	return sum
+}
@@ -0,0 +1,109 @@
+package keyring
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// keychainNotFound is the exit status of security(1) when no item matches.
+const keychainNotFound = 44
+
+// Keychain is the Store of the macOS login Keychain, in which items are
+// generic passwords. Keychain items have no custom attributes, so the
+// attributes of an item are stored in its comment, as "key=value" pairs.
+type Keychain struct {
+	// Command is the security executable. If empty, it is searched in PATH.
+	Command string
+}
+
+var _ Store = Keychain{}
+
+// command returns the security executable.
+func (k Keychain) command() string {
+	if k.Command == "" {
+		return "security"
+	}
+	return k.Command
+}
+
+// Set implements Store. The item is added through the interactive mode of
+// security(1), reading the command from its standard input, with the secret
+// encoded in hexadecimal.
+func (k Keychain) Set(ctx context.Context, item Item) error {
+	if err := item.check(); err != nil {
+		return err
+	}
+
+	args := []string{"add-generic-password", "-U", "-s", item.Service, "-a", item.Account, "-l", item.label()}
+	if comment := keychainComment(item.Attributes); comment != "" {
+		args = append(args, "-j", comment)
+	}
+
+	var line strings.Builder
+	for _, arg := range args {
+		if strings.ContainsAny(arg, "\"\\\n\r") {
+			return fmt.Errorf("%w: %q contains a quote, a backslash or a line break", ErrInvalidItem, arg)
+		}
+		line.WriteString(`"` + arg + `" `)
+	}
+	line.WriteString("-X " + hex.EncodeToString(item.Secret) + "\n")
+	stdin := []byte(line.String())
+	defer clear(stdin)
+
+	// The interactive mode reports failures of its commands on the standard
+	// error only.
+	_, stderr, err := run(ctx, k.command(), stdin, "-i")
+	if err != nil {
+		return fmt.Errorf("failed to store keychain item: %w", err)
+	}
+	if msg := bytes.TrimSpace(stderr); len(msg) > 0 {
+		return fmt.Errorf("failed to store keychain item: %s", msg)
+	}
+	return nil
+}
+
+// Get implements Store.
+func (k Keychain) Get(ctx context.Context, service, account string) ([]byte, error) {
+	if err := checkKey(service, account); err != nil {
+		return nil, err
+	}
+
+	out, _, err := run(ctx, k.command(), nil, "find-generic-password", "-s", service, "-a", account, "-w")
+	if exitCode(err) == keychainNotFound {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keychain item: %w", err)
+	}
+	return bytes.TrimSuffix(out, []byte("\n")), nil
+}
+
+// Delete implements Store.
+func (k Keychain) Delete(ctx context.Context, service, account string) error {
+	if err := checkKey(service, account); err != nil {
+		return err
+	}
+
+	_, _, err := run(ctx, k.command(), nil, "delete-generic-password", "-s", service, "-a", account)
+	if exitCode(err) == keychainNotFound {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete keychain item: %w", err)
+	}
+	return nil
+}
+
+// keychainComment returns the attributes as "key=value" pairs sorted by key.
+func keychainComment(attrs map[string]string) string {
+	pairs := make([]string, 0, len(attrs))
+	for k, v := range attrs {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
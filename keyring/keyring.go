@@ -0,0 +1,155 @@
+// Package keyring stores generated credentials in the keyring of the operating
+// system: the macOS Keychain, or the Secret Service of Linux desktops such as
+// GNOME Keyring and KWallet. It drives the security(1) and secret-tool(1)
+// commands, so that no cgo or D-Bus dependency is needed, and never passes
+// secrets on their command line, where other users could read them.
+//
+//	pw, err := password.Generate(password.Input{Length: 32, Digits: 6, Symbols: 6})
+//	if err != nil {
+//		return err
+//	}
+//	store, err := keyring.Default()
+//	if err != nil {
+//		return err
+//	}
+//	err = store.Set(ctx, keyring.Item{
+//		Service: "example-cli",
+//		Account: "deploy",
+//		Label:   "Example CLI deploy token",
+//		Secret:  []byte(pw),
+//	})
+package keyring
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+var (
+	// ErrNotFound is the error returned when no item matches the service and
+	// account.
+	ErrNotFound = errors.New("keyring item not found")
+
+	// ErrUnsupported is the error returned by Default on platforms without a
+	// supported keyring.
+	ErrUnsupported = errors.New("no supported keyring on this platform")
+
+	// ErrInvalidItem is the error returned when an item has no service or
+	// account, or a field which cannot be passed to the keyring.
+	ErrInvalidItem = errors.New("invalid keyring item")
+)
+
+// Item is a credential stored in a keyring, identified by its service and
+// account.
+type Item struct {
+	Service string
+	Account string
+
+	// Label is the name shown to the user by keyring managers. If empty, the
+	// service is used.
+	Label string
+
+	// Attributes are additional metadata, such as the policy the secret was
+	// generated with.
+	Attributes map[string]string
+
+	Secret []byte
+}
+
+// Store is a keyring. Implementations must be safe for concurrent use.
+type Store interface {
+	// Set stores the item, replacing any item with the same service and
+	// account.
+	Set(ctx context.Context, item Item) error
+
+	// Get returns the secret of the item with the given service and account,
+	// or ErrNotFound.
+	Get(ctx context.Context, service, account string) ([]byte, error)
+
+	// Delete deletes the item with the given service and account, or returns
+	// ErrNotFound.
+	Delete(ctx context.Context, service, account string) error
+}
+
+// Default returns the keyring of the current platform: the Keychain on macOS
+// and the Secret Service on Linux and BSDs.
+func Default() (Store, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return Keychain{}, nil
+	case "linux", "freebsd", "openbsd", "netbsd", "dragonfly":
+		return SecretService{}, nil
+	}
+	return nil, ErrUnsupported
+}
+
+// check verifies that the item can be identified.
+func (item Item) check() error {
+	return checkKey(item.Service, item.Account)
+}
+
+// checkKey verifies that the service and account are set.
+func checkKey(service, account string) error {
+	if service == "" || account == "" {
+		return fmt.Errorf("%w: missing service or account", ErrInvalidItem)
+	}
+	return nil
+}
+
+// label returns the label of the item.
+func (item Item) label() string {
+	if item.Label == "" {
+		return item.Service
+	}
+	return item.Label
+}
+
+// exitError is the error returned by run when the command exits with a
+// non-zero status.
+type exitError struct {
+	code int
+	msg  string
+}
+
+// Error implements error.
+func (e *exitError) Error() string {
+	if e.msg == "" {
+		return fmt.Sprintf("exit status %d", e.code)
+	}
+	return fmt.Sprintf("exit status %d: %s", e.code, e.msg)
+}
+
+// run runs the command with the given standard input and returns its
+// standard output and error. A non-zero exit status is returned as an
+// *exitError.
+func run(ctx context.Context, command string, stdin []byte, args ...string) ([]byte, []byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		return nil, nil, &exitError{code: ee.ExitCode(), msg: strings.TrimSpace(stderr.String())}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return stdout.Bytes(), stderr.Bytes(), nil
+}
+
+// exitCode returns the exit status of a command which failed with err, or -1.
+func exitCode(err error) int {
+	var ee *exitError
+	if errors.As(err, &ee) {
+		return ee.code
+	}
+	return -1
+}
@@ -0,0 +1,167 @@
+package keyring
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/juev/go-password/password"
+)
+
+// testCommand writes a fake command running the given shell script, with $DIR
+// set to a directory for its state, and returns its path and the directory.
+func testCommand(t *testing.T, script string) (string, string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	command := filepath.Join(dir, "command")
+	script = "#!/bin/sh\nDIR='" + dir + "'\n" + script
+	if err := os.WriteFile(command, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	return command, dir
+}
+
+func TestSecretService(t *testing.T) {
+	t.Parallel()
+
+	// The fake secret-tool keeps items in files named after the service and
+	// account, and records the arguments of store.
+	command, dir := testCommand(t, `
+case "$1" in
+store) echo "$@" > "$DIR/args"; cat > "$DIR/$4.$6" ;;
+lookup) [ -f "$DIR/$3.$5" ] || exit 1; cat "$DIR/$3.$5" ;;
+clear) rm "$DIR/$3.$5" ;;
+esac
+`)
+	store := SecretService{Command: command}
+	ctx := context.Background()
+
+	pw := password.MustGenerate(password.Input{Length: 32, Digits: 6, Symbols: 6})
+	err := store.Set(ctx, Item{
+		Service:    "example-cli",
+		Account:    "deploy",
+		Label:      "Deploy token",
+		Attributes: map[string]string{"policy": "database", "env": "prod"},
+		Secret:     []byte(pw),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "store --label=Deploy token service example-cli account deploy env prod policy database\n"; string(args) != want {
+		t.Errorf("expected %q to be %q", args, want)
+	}
+	if strings.Contains(string(args), pw) {
+		t.Error("the secret should not be passed as an argument")
+	}
+
+	got, err := store.Get(ctx, "example-cli", "deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != pw {
+		t.Errorf("expected %q to be %q", got, pw)
+	}
+
+	if err := store.Delete(ctx, "example-cli", "deploy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "example-cli", "deploy"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected %v to be %v", err, ErrNotFound)
+	}
+	if err := store.Delete(ctx, "example-cli", "deploy"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected %v to be %v", err, ErrNotFound)
+	}
+
+	for _, item := range []Item{
+		{Account: "deploy"},
+		{Service: "example-cli", Account: "deploy", Attributes: map[string]string{AttributeService: "other"}},
+	} {
+		if err := store.Set(ctx, item); !errors.Is(err, ErrInvalidItem) {
+			t.Errorf("expected %v to be %v", err, ErrInvalidItem)
+		}
+	}
+}
+
+func TestKeychain(t *testing.T) {
+	t.Parallel()
+
+	// The fake security records the interactive commands, and keeps items
+	// in files named after the service and account.
+	command, dir := testCommand(t, `
+case "$1" in
+-i) cat > "$DIR/stdin" ;;
+find-generic-password) [ -f "$DIR/$3.$5" ] || exit 44; cat "$DIR/$3.$5"; echo ;;
+delete-generic-password) rm "$DIR/$3.$5" 2>/dev/null || exit 44 ;;
+esac
+`)
+	store := Keychain{Command: command}
+	ctx := context.Background()
+
+	err := store.Set(ctx, Item{
+		Service:    "example-cli",
+		Account:    "deploy",
+		Attributes: map[string]string{"policy": "database", "env": "prod"},
+		Secret:     []byte("s3cr3t"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdin, err := os.ReadFile(filepath.Join(dir, "stdin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `"add-generic-password" "-U" "-s" "example-cli" "-a" "deploy" "-l" "example-cli" "-j" "env=prod, policy=database" -X 733363723374` + "\n"
+	if string(stdin) != want {
+		t.Errorf("expected %q to be %q", stdin, want)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "example-cli.deploy"), []byte("s3cr3t"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Get(ctx, "example-cli", "deploy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "s3cr3t" {
+		t.Errorf("expected %q to be %q", got, "s3cr3t")
+	}
+
+	if err := store.Delete(ctx, "example-cli", "deploy"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "example-cli", "deploy"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected %v to be %v", err, ErrNotFound)
+	}
+	if err := store.Delete(ctx, "example-cli", "deploy"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected %v to be %v", err, ErrNotFound)
+	}
+
+	if err := store.Set(ctx, Item{Service: `a"b`, Account: "deploy"}); !errors.Is(err, ErrInvalidItem) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidItem)
+	}
+}
+
+func TestKeychainSetError(t *testing.T) {
+	t.Parallel()
+
+	command, _ := testCommand(t, "cat > /dev/null; echo 'SecKeychainItemCreateFromContent: User interaction is not allowed.' >&2\n")
+	err := Keychain{Command: command}.Set(context.Background(), Item{Service: "example-cli", Account: "deploy"})
+	if err == nil || !strings.Contains(err.Error(), "User interaction is not allowed") {
+		t.Errorf("expected the security error to be returned, got %v", err)
+	}
+}
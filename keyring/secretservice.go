@@ -0,0 +1,99 @@
+package keyring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Attributes identifying the items of the Secret Service.
+const (
+	AttributeService = "service"
+	AttributeAccount = "account"
+)
+
+// SecretService is the Store of the freedesktop.org Secret Service, provided
+// by GNOME Keyring and KWallet. Items are identified by the AttributeService
+// and AttributeAccount attributes, and also carry the attributes of the item,
+// so that they can be searched with secret-tool(1).
+type SecretService struct {
+	// Command is the secret-tool executable. If empty, it is searched in
+	// PATH.
+	Command string
+}
+
+var _ Store = SecretService{}
+
+// command returns the secret-tool executable.
+func (s SecretService) command() string {
+	if s.Command == "" {
+		return "secret-tool"
+	}
+	return s.Command
+}
+
+// Set implements Store. The secret is written to the standard input of
+// secret-tool.
+func (s SecretService) Set(ctx context.Context, item Item) error {
+	if err := item.check(); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(item.Attributes))
+	for k := range item.Attributes {
+		if k == "" || k == AttributeService || k == AttributeAccount {
+			return fmt.Errorf("%w: reserved attribute %q", ErrInvalidItem, k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	args := append([]string{"store", "--label=" + item.label()}, secretServiceKey(item.Service, item.Account)...)
+	for _, k := range keys {
+		args = append(args, k, item.Attributes[k])
+	}
+
+	if _, _, err := run(ctx, s.command(), item.Secret, args...); err != nil {
+		return fmt.Errorf("failed to store secret service item: %w", err)
+	}
+	return nil
+}
+
+// Get implements Store.
+func (s SecretService) Get(ctx context.Context, service, account string) ([]byte, error) {
+	if err := checkKey(service, account); err != nil {
+		return nil, err
+	}
+
+	// secret-tool exits with status 1 and no message when nothing matches.
+	out, _, err := run(ctx, s.command(), nil, append([]string{"lookup"}, secretServiceKey(service, account)...)...)
+	var ee *exitError
+	if errors.As(err, &ee) && ee.code == 1 && ee.msg == "" {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret service item: %w", err)
+	}
+	return out, nil
+}
+
+// Delete implements Store. Since secret-tool does not report whether an item
+// was deleted, the item is looked up first.
+func (s SecretService) Delete(ctx context.Context, service, account string) error {
+	secret, err := s.Get(ctx, service, account)
+	if err != nil {
+		return err
+	}
+	clear(secret)
+
+	if _, _, err := run(ctx, s.command(), nil, append([]string{"clear"}, secretServiceKey(service, account)...)...); err != nil {
+		return fmt.Errorf("failed to delete secret service item: %w", err)
+	}
+	return nil
+}
+
+// secretServiceKey returns the attributes identifying an item.
+func secretServiceKey(service, account string) []string {
+	return []string{AttributeService, service, AttributeAccount, account}
+}
@@ -0,0 +1,80 @@
+package keywrap
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// AES is a KeyWrapper which wraps data keys with a local AES-256-GCM key. It
+// is meant for tests, and for deployments which load the key encryption key
+// from a secret store at startup.
+type AES struct {
+	id  string
+	key []byte
+}
+
+var _ KeyWrapper = (*AES)(nil)
+
+// NewAES creates a new AES KeyWrapper with the given 32-byte key, identified
+// by id.
+func NewAES(id string, key []byte) (*AES, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("invalid key encryption key: need 32 bytes, got %d", len(key))
+	}
+	return &AES{id: id, key: append([]byte(nil), key...)}, nil
+}
+
+// KeyID returns the ID of the key.
+func (w *AES) KeyID() string {
+	return w.id
+}
+
+// Wrap encrypts the data key.
+func (w *AES) Wrap(_ context.Context, key []byte) ([]byte, error) {
+	aead, err := newAEAD(w.key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, key, []byte(w.id)), nil
+}
+
+// Unwrap decrypts a data key returned by Wrap.
+func (w *AES) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) {
+	aead, err := newAEAD(w.key)
+	if err != nil {
+		return nil, err
+	}
+
+	n := aead.NonceSize()
+	if len(wrapped) < n {
+		return nil, errors.New("failed to unwrap key: wrapped key too short")
+	}
+	key, err := aead.Open(nil, wrapped[:n], wrapped[n:], []byte(w.id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap key: %w", err)
+	}
+	return key, nil
+}
+
+// newAEAD returns AES-GCM with the given key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return aead, nil
+}
@@ -0,0 +1,50 @@
+package keywrap
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestAES(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	kek := []byte("0123456789abcdef0123456789abcdef")
+	w, err := NewAES("kek-1", kek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w.KeyID() != "kek-1" {
+		t.Errorf("expected key id %q, got %q", "kek-1", w.KeyID())
+	}
+
+	key := []byte("data key which must be protected")
+	wrapped, err := w.Wrap(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(wrapped, key) {
+		t.Error("expected wrapped key to be encrypted")
+	}
+
+	got, err := w.Unwrap(ctx, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Errorf("expected %q to be %q", got, key)
+	}
+
+	other, err := NewAES("kek-2", kek)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := other.Unwrap(ctx, wrapped); err == nil {
+		t.Error("expected key wrapped under another key id not to unwrap")
+	}
+
+	if _, err := NewAES("short", kek[:16]); err == nil {
+		t.Error("expected short key to be rejected")
+	}
+}
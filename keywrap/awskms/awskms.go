@@ -0,0 +1,171 @@
+// Package awskms implements keywrap.KeyWrapper with AWS Key Management
+// Service. Requests are signed with AWS Signature Version 4, so the AWS SDK is
+// not needed.
+//
+//	w := awskms.New(awskms.Options{
+//		Region:      "eu-west-1",
+//		KeyID:       "arn:aws:kms:eu-west-1:111122223333:key/1234abcd-...",
+//		Credentials: awskms.CredentialsFromEnv(),
+//	})
+package awskms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/juev/go-password/keywrap"
+)
+
+// ErrStatus is the error returned when AWS KMS responds with a non-successful
+// status.
+var ErrStatus = errors.New("aws kms returned unsuccessful status")
+
+// Credentials are AWS credentials.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// SessionToken is set for temporary credentials.
+	SessionToken string
+}
+
+// CredentialsFromEnv returns the credentials in the standard AWS_ACCESS_KEY_ID,
+// AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN environment variables.
+func CredentialsFromEnv() Credentials {
+	return Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// Options used to define input parameters for New.
+type Options struct {
+	// Region is the AWS region of the key.
+	Region string
+
+	// KeyID is the ID, ARN or alias of the KMS key.
+	KeyID string
+
+	// Credentials sign the requests.
+	Credentials Credentials
+
+	// EncryptionContext, if set, is bound to every wrapped key and must match
+	// when unwrapping.
+	EncryptionContext map[string]string
+
+	// Endpoint overrides the regional KMS endpoint, for example for VPC
+	// endpoints.
+	Endpoint string
+
+	// Client is the HTTP client. The default is a client with a 10 second
+	// timeout.
+	Client *http.Client
+	_      struct{}
+}
+
+// KeyWrapper wraps data keys with an AWS KMS key.
+type KeyWrapper struct {
+	opts Options
+	now  func() time.Time
+}
+
+var _ keywrap.KeyWrapper = (*KeyWrapper)(nil)
+
+// New creates a new KeyWrapper from the given options.
+func New(opts Options) *KeyWrapper {
+	if opts.Endpoint == "" {
+		opts.Endpoint = "https://kms." + opts.Region + ".amazonaws.com"
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &KeyWrapper{opts: opts, now: time.Now}
+}
+
+// KeyID returns the ID of the KMS key.
+func (w *KeyWrapper) KeyID() string {
+	return w.opts.KeyID
+}
+
+// EncryptionContext returns the encryption context bound to wrapped keys.
+func (w *KeyWrapper) EncryptionContext() map[string]string {
+	return w.opts.EncryptionContext
+}
+
+// Wrap encrypts the data key with the KMS key. The result is the KMS
+// ciphertext blob.
+func (w *KeyWrapper) Wrap(ctx context.Context, key []byte) ([]byte, error) {
+	var res struct {
+		CiphertextBlob []byte
+	}
+	err := w.do(ctx, "Encrypt", map[string]any{
+		"KeyId":             w.opts.KeyID,
+		"Plaintext":         key,
+		"EncryptionContext": w.opts.EncryptionContext,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.CiphertextBlob, nil
+}
+
+// Unwrap decrypts a data key returned by Wrap.
+func (w *KeyWrapper) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var res struct {
+		Plaintext []byte
+	}
+	err := w.do(ctx, "Decrypt", map[string]any{
+		"KeyId":             w.opts.KeyID,
+		"CiphertextBlob":    wrapped,
+		"EncryptionContext": w.opts.EncryptionContext,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Plaintext, nil
+}
+
+// do calls the given KMS action. Byte slices in body and res are base64
+// encoded as the KMS JSON protocol requires.
+func (w *KeyWrapper) do(ctx context.Context, action string, body, res any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode kms request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.opts.Endpoint+"/", bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to create kms request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService."+action)
+	sign(req, b, w.opts.Credentials, w.opts.Region, "kms", w.now())
+
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call kms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&e)
+		return fmt.Errorf("%w: %s: %s %s", ErrStatus, action, resp.Status, e.Type)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(res); err != nil {
+		return fmt.Errorf("failed to decode kms response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package awskms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testKMS is a fake KMS which "encrypts" by prefixing the plaintext with the
+// encryption context.
+func testKMS(tb testing.TB) *httptest.Server {
+	tb.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(auth, "/eu-west-1/kms/aws4_request") ||
+			r.Header.Get("X-Amz-Security-Token") != "session" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		var req struct {
+			KeyID             string `json:"KeyId"`
+			Plaintext         []byte
+			CiphertextBlob    []byte
+			EncryptionContext map[string]string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.KeyID != "alias/escrow" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		prefix := []byte("sealed:" + req.EncryptionContext["purpose"] + ":")
+
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.Encrypt":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"CiphertextBlob": append(prefix, req.Plaintext...)})
+		case "TrentService.Decrypt":
+			if !bytes.HasPrefix(req.CiphertextBlob, prefix) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"__type":"InvalidCiphertextException"}`))
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"Plaintext": req.CiphertextBlob[len(prefix):]})
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	tb.Cleanup(ts.Close)
+	return ts
+}
+
+func TestKeyWrapper(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ts := testKMS(t)
+	opts := Options{
+		Region:            "eu-west-1",
+		KeyID:             "alias/escrow",
+		Credentials:       Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"},
+		EncryptionContext: map[string]string{"purpose": "escrow"},
+		Endpoint:          ts.URL,
+		Client:            ts.Client(),
+	}
+	w := New(opts)
+
+	if got := w.KeyID(); got != "alias/escrow" {
+		t.Errorf("expected key id %q, got %q", "alias/escrow", got)
+	}
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := w.Wrap(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := w.Unwrap(ctx, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Errorf("expected %q to be %q", got, key)
+	}
+
+	opts.EncryptionContext = map[string]string{"purpose": "other"}
+	if _, err := New(opts).Unwrap(ctx, wrapped); !errors.Is(err, ErrStatus) {
+		t.Errorf("expected %v to be %v", err, ErrStatus)
+	}
+}
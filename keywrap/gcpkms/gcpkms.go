@@ -0,0 +1,146 @@
+// Package gcpkms implements keywrap.KeyWrapper with Google Cloud Key
+// Management Service, through its REST API.
+//
+//	w := gcpkms.New(gcpkms.Options{
+//		Key:   "projects/p/locations/global/keyRings/r/cryptoKeys/escrow",
+//		Token: tokenSource,
+//	})
+//
+// Access tokens are obtained from the Token function, for example backed by
+// the metadata server or golang.org/x/oauth2/google, so that this package does
+// not depend on the Google Cloud SDK.
+package gcpkms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juev/go-password/keywrap"
+)
+
+// ErrStatus is the error returned when Cloud KMS responds with a
+// non-successful status.
+var ErrStatus = errors.New("cloud kms returned unsuccessful status")
+
+// defaultEndpoint is the Cloud KMS REST endpoint.
+const defaultEndpoint = "https://cloudkms.googleapis.com"
+
+// Options used to define input parameters for New.
+type Options struct {
+	// Key is the resource name of the crypto key.
+	Key string
+
+	// Token returns an OAuth 2.0 access token with the cloudkms scope.
+	Token func(ctx context.Context) (string, error)
+
+	// AdditionalData, if set, is bound to every wrapped key and must match
+	// when unwrapping.
+	AdditionalData []byte
+
+	// Endpoint overrides the Cloud KMS endpoint.
+	Endpoint string
+
+	// Client is the HTTP client. The default is a client with a 10 second
+	// timeout.
+	Client *http.Client
+	_      struct{}
+}
+
+// KeyWrapper wraps data keys with a Cloud KMS crypto key.
+type KeyWrapper struct {
+	opts Options
+}
+
+var _ keywrap.KeyWrapper = (*KeyWrapper)(nil)
+
+// New creates a new KeyWrapper from the given options.
+func New(opts Options) *KeyWrapper {
+	if opts.Endpoint == "" {
+		opts.Endpoint = defaultEndpoint
+	}
+	opts.Endpoint = strings.TrimSuffix(opts.Endpoint, "/")
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &KeyWrapper{opts: opts}
+}
+
+// KeyID returns the resource name of the crypto key.
+func (w *KeyWrapper) KeyID() string {
+	return w.opts.Key
+}
+
+// Wrap encrypts the data key with the crypto key.
+func (w *KeyWrapper) Wrap(ctx context.Context, key []byte) ([]byte, error) {
+	var res struct {
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	err := w.do(ctx, "encrypt", map[string][]byte{
+		"plaintext":                   key,
+		"additionalAuthenticatedData": w.opts.AdditionalData,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Ciphertext, nil
+}
+
+// Unwrap decrypts a data key returned by Wrap.
+func (w *KeyWrapper) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var res struct {
+		Plaintext []byte `json:"plaintext"`
+	}
+	err := w.do(ctx, "decrypt", map[string][]byte{
+		"ciphertext":                  wrapped,
+		"additionalAuthenticatedData": w.opts.AdditionalData,
+	}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return res.Plaintext, nil
+}
+
+// do calls the given method on the crypto key. Byte slices in body and res are
+// base64 encoded as the REST API requires.
+func (w *KeyWrapper) do(ctx context.Context, method string, body, res any) error {
+	token, err := w.opts.Token(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get access token: %w", err)
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode cloud kms request: %w", err)
+	}
+
+	u := w.opts.Endpoint + "/v1/" + w.opts.Key + ":" + method
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to create cloud kms request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call cloud kms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		return fmt.Errorf("%w: %s: %s", ErrStatus, method, resp.Status)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(res); err != nil {
+		return fmt.Errorf("failed to decode cloud kms response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package gcpkms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testKey = "projects/p/locations/global/keyRings/r/cryptoKeys/escrow"
+
+// testKMS is a fake Cloud KMS which "encrypts" by prefixing the plaintext with
+// the additional data.
+func testKMS(tb testing.TB) *httptest.Server {
+	tb.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ya29.token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req struct {
+			Plaintext      []byte `json:"plaintext"`
+			Ciphertext     []byte `json:"ciphertext"`
+			AdditionalData []byte `json:"additionalAuthenticatedData"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		prefix := append([]byte("sealed:"), req.AdditionalData...)
+
+		switch r.URL.Path {
+		case "/v1/" + testKey + ":encrypt":
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"ciphertext": append(prefix, req.Plaintext...)})
+		case "/v1/" + testKey + ":decrypt":
+			if !bytes.HasPrefix(req.Ciphertext, prefix) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string][]byte{"plaintext": req.Ciphertext[len(prefix):]})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	tb.Cleanup(ts.Close)
+	return ts
+}
+
+func TestKeyWrapper(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ts := testKMS(t)
+	opts := Options{
+		Key:            testKey,
+		Token:          func(context.Context) (string, error) { return "ya29.token", nil },
+		AdditionalData: []byte("escrow"),
+		Endpoint:       ts.URL,
+		Client:         ts.Client(),
+	}
+	w := New(opts)
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := w.Wrap(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := w.Unwrap(ctx, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Errorf("expected %q to be %q", got, key)
+	}
+
+	opts.AdditionalData = []byte("other")
+	if _, err := New(opts).Unwrap(ctx, wrapped); !errors.Is(err, ErrStatus) {
+		t.Errorf("expected %v to be %v", err, ErrStatus)
+	}
+
+	errToken := errors.New("no credentials")
+	opts.Token = func(context.Context) (string, error) { return "", errToken }
+	if _, err := New(opts).Wrap(ctx, key); !errors.Is(err, errToken) {
+		t.Errorf("expected %v to be %v", err, errToken)
+	}
+}
@@ -0,0 +1,28 @@
+// Package keywrap defines the KeyWrapper interface with which envelope
+// encryption, such as the escrow and export features, protects data keys with
+// a key encryption key held outside of the process.
+//
+// Implementations for major key management services live in subpackages, so
+// that deployments only import the ones they use:
+//
+//   - keywrap/awskms for AWS KMS,
+//   - keywrap/gcpkms for Google Cloud KMS,
+//   - keywrap/transit for the HashiCorp Vault transit secrets engine.
+//
+// They talk to the service APIs directly over HTTPS and need no SDK.
+package keywrap
+
+import "context"
+
+// KeyWrapper wraps and unwraps data keys with a key encryption key, typically
+// held in a KMS. Implementations must be safe for concurrent use.
+type KeyWrapper interface {
+	// KeyID identifies the key encryption key, for example a KMS key ARN.
+	KeyID() string
+
+	// Wrap encrypts the data key.
+	Wrap(ctx context.Context, key []byte) ([]byte, error)
+
+	// Unwrap decrypts a data key returned by Wrap.
+	Unwrap(ctx context.Context, wrapped []byte) ([]byte, error)
+}
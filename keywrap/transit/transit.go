@@ -0,0 +1,160 @@
+// Package transit implements keywrap.KeyWrapper with the transit secrets
+// engine of HashiCorp Vault.
+//
+//	w := transit.New(transit.Options{
+//		Address: "https://vault.example.com:8200",
+//		Token:   os.Getenv("VAULT_TOKEN"),
+//		Key:     "password-escrow",
+//	})
+package transit
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juev/go-password/keywrap"
+)
+
+// ErrStatus is the error returned when Vault responds with a non-successful
+// status.
+var ErrStatus = errors.New("vault returned unsuccessful status")
+
+// Options used to define input parameters for New.
+type Options struct {
+	// Address is the address of the Vault server.
+	Address string
+
+	// Token is the Vault token, sent in the X-Vault-Token header.
+	Token string
+
+	// Namespace, if set, is the Vault Enterprise namespace of the engine.
+	Namespace string
+
+	// Mount is the path the transit engine is mounted at. The default is
+	// "transit".
+	Mount string
+
+	// Key is the name of the transit key.
+	Key string
+
+	// Client is the HTTP client. The default is a client with a 10 second
+	// timeout.
+	Client *http.Client
+	_      struct{}
+}
+
+// KeyWrapper wraps data keys with a Vault transit key.
+type KeyWrapper struct {
+	opts Options
+}
+
+var _ keywrap.KeyWrapper = (*KeyWrapper)(nil)
+
+// New creates a new KeyWrapper from the given options.
+func New(opts Options) *KeyWrapper {
+	if opts.Mount == "" {
+		opts.Mount = "transit"
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	opts.Address = strings.TrimSuffix(opts.Address, "/")
+	return &KeyWrapper{opts: opts}
+}
+
+// KeyID returns the mount and name of the transit key.
+func (w *KeyWrapper) KeyID() string {
+	return w.opts.Mount + "/" + w.opts.Key
+}
+
+// Address returns the address of the Vault server.
+func (w *KeyWrapper) Address() string {
+	return w.opts.Address
+}
+
+// Mount returns the path the transit engine is mounted at.
+func (w *KeyWrapper) Mount() string {
+	return w.opts.Mount
+}
+
+// Key returns the name of the transit key.
+func (w *KeyWrapper) Key() string {
+	return w.opts.Key
+}
+
+// Wrap encrypts the data key with the transit key. The result is the Vault
+// ciphertext, such as "vault:v1:...".
+func (w *KeyWrapper) Wrap(ctx context.Context, key []byte) ([]byte, error) {
+	var res struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	err := w.do(ctx, "encrypt", map[string]string{"plaintext": base64.StdEncoding.EncodeToString(key)}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(res.Data.Ciphertext), nil
+}
+
+// Unwrap decrypts a data key returned by Wrap.
+func (w *KeyWrapper) Unwrap(ctx context.Context, wrapped []byte) ([]byte, error) {
+	var res struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := w.do(ctx, "decrypt", map[string]string{"ciphertext": string(wrapped)}, &res); err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(res.Data.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode vault plaintext: %w", err)
+	}
+	return key, nil
+}
+
+// do calls the given transit operation on the key.
+func (w *KeyWrapper) do(ctx context.Context, op string, body, res any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode vault request: %w", err)
+	}
+
+	u := w.opts.Address + "/v1/" + w.opts.Mount + "/" + op + "/" + url.PathEscape(w.opts.Key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", w.opts.Token)
+	if w.opts.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", w.opts.Namespace)
+	}
+
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+		return fmt.Errorf("%w: transit %s: %s", ErrStatus, op, resp.Status)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(res); err != nil {
+		return fmt.Errorf("failed to decode vault response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package transit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// testVault is a fake transit engine which "encrypts" by prefixing the
+// plaintext.
+func testVault(tb testing.TB) *httptest.Server {
+	tb.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "s.token" || r.Header.Get("X-Vault-Namespace") != "team" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v1/kv-transit/encrypt/escrow":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]string{"ciphertext": "vault:v1:" + req["plaintext"]},
+			})
+		case "/v1/kv-transit/decrypt/escrow":
+			plaintext, ok := strings.CutPrefix(req["ciphertext"], "vault:v1:")
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]string{"plaintext": plaintext},
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	tb.Cleanup(ts.Close)
+	return ts
+}
+
+func TestKeyWrapper(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ts := testVault(t)
+	w := New(Options{
+		Address:   ts.URL + "/",
+		Token:     "s.token",
+		Namespace: "team",
+		Mount:     "kv-transit",
+		Key:       "escrow",
+		Client:    ts.Client(),
+	})
+
+	if got, want := w.KeyID(), "kv-transit/escrow"; got != want {
+		t.Errorf("expected key id %q, got %q", want, got)
+	}
+	if w.Address() != ts.URL || w.Mount() != "kv-transit" || w.Key() != "escrow" {
+		t.Errorf("unexpected address %q, mount %q or key %q", w.Address(), w.Mount(), w.Key())
+	}
+
+	key := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := w.Wrap(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(wrapped, []byte("vault:v1:")) {
+		t.Errorf("expected vault ciphertext, got %q", wrapped)
+	}
+
+	got, err := w.Unwrap(ctx, wrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Errorf("expected %q to be %q", got, key)
+	}
+
+	if _, err := w.Unwrap(ctx, []byte("garbage")); !errors.Is(err, ErrStatus) {
+		t.Errorf("expected %v to be %v", err, ErrStatus)
+	}
+}
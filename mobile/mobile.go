@@ -0,0 +1,133 @@
+// Package mobile provides a gomobile-friendly facade over the password
+// package, so that iOS and Android apps can use the exact same generation
+// logic as the backend.
+//
+// The API only uses types supported by gomobile bind: structs have exported
+// fields of basic types only and are passed by pointer.
+//
+//	gomobile bind -target=android github.com/juev/go-password/mobile
+package mobile
+
+import (
+	"fmt"
+
+	"github.com/juev/go-password/password"
+)
+
+// Input used to define input parameters for the generator. See password.Input
+// for the meaning of every field.
+type Input struct {
+	Length               int
+	Digits               int
+	Symbols              int
+	NoUpper              bool
+	AllowRepeat          bool
+	PreserveClassOrder   bool
+	MaxSameClassRun      int
+	RequireFromEachClass bool
+	MinDigits            int
+	MaxDigits            int
+	MinSymbols           int
+	MaxSymbols           int
+}
+
+// NewInput creates a new Input with the given length, number of digits and
+// number of symbols.
+func NewInput(length, digits, symbols int) *Input {
+	return &Input{
+		Length:  length,
+		Digits:  digits,
+		Symbols: symbols,
+	}
+}
+
+// Generator is the configurable generator. Empty character lists use the
+// defaults of the password package.
+type Generator struct {
+	LowerLetters string
+	UpperLetters string
+	Digits       string
+	Symbols      string
+
+	// WithoutPhoneticallyAmbiguous removes letters which are easily confused
+	// when dictated.
+	WithoutPhoneticallyAmbiguous bool
+}
+
+// NewGenerator creates a new Generator with the default character lists.
+func NewGenerator() *Generator {
+	return &Generator{
+		LowerLetters: password.LowerLetters,
+		UpperLetters: password.UpperLetters,
+		Digits:       password.Digits,
+		Symbols:      password.Symbols,
+	}
+}
+
+// Generate generates a password with the given requirements.
+func (g *Generator) Generate(input *Input) (string, error) {
+	res, err := g.generator().Generate(input.password())
+	if err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return res, nil
+}
+
+// Entropy estimates the entropy, in bits, of passwords generated with the
+// given input.
+func (g *Generator) Entropy(input *Input) (float64, error) {
+	bits, err := g.generator().Entropy(input.password())
+	if err != nil {
+		return 0, fmt.Errorf("failed to estimate entropy: %w", err)
+	}
+	return bits, nil
+}
+
+// Generate generates a password with the given requirements using the default
+// character lists.
+func Generate(input *Input) (string, error) {
+	return NewGenerator().Generate(input)
+}
+
+// generator returns the password.Generator configured by g.
+func (g *Generator) generator() password.Generator {
+	gen := password.NewGenerator()
+	if g.LowerLetters != "" {
+		gen = gen.WithLowerLetters(g.LowerLetters)
+	}
+	if g.UpperLetters != "" {
+		gen = gen.WithUpperLetters(g.UpperLetters)
+	}
+	if g.Digits != "" {
+		gen = gen.WithDigits(g.Digits)
+	}
+	if g.Symbols != "" {
+		gen = gen.WithSymbols(g.Symbols)
+	}
+	if g.WithoutPhoneticallyAmbiguous {
+		gen = gen.WithoutPhoneticallyAmbiguous()
+	}
+	return gen
+}
+
+// password returns the password.Input equivalent to i.
+func (i *Input) password() password.Input {
+	if i == nil {
+		return password.Input{}
+	}
+
+	return password.Input{
+		Length:               i.Length,
+		Digits:               i.Digits,
+		Symbols:              i.Symbols,
+		NoUpper:              i.NoUpper,
+		AllowRepeat:          i.AllowRepeat,
+		PreserveClassOrder:   i.PreserveClassOrder,
+		MaxSameClassRun:      i.MaxSameClassRun,
+		RequireFromEachClass: i.RequireFromEachClass,
+		MinDigits:            i.MinDigits,
+		MaxDigits:            i.MaxDigits,
+		MinSymbols:           i.MinSymbols,
+		MaxSymbols:           i.MaxSymbols,
+	}
+}
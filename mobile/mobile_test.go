@@ -0,0 +1,75 @@
+package mobile
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/juev/go-password/password"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	res, err := Generate(NewInput(16, 4, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 16 {
+		t.Errorf("expected %q to be 16 characters", res)
+	}
+
+	if _, err := Generate(NewInput(1, 2, 0)); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestGenerateRanges(t *testing.T) {
+	t.Parallel()
+
+	input := NewInput(16, 0, 0)
+	input.MinDigits, input.MaxDigits = 2, 4
+	input.MinSymbols, input.MaxSymbols = 1, 3
+	input.MaxSameClassRun = 3
+	input.RequireFromEachClass = true
+
+	for i := 0; i < 100; i++ {
+		res, err := Generate(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := password.NewGenerator().Validate(res, input.password()); err != nil {
+			t.Errorf("expected %q to be valid, got %v", res, err)
+		}
+	}
+}
+
+func TestGenerator(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator()
+	gen.Symbols = "!@"
+	gen.WithoutPhoneticallyAmbiguous = true
+
+	input := NewInput(12, 0, 4)
+	input.NoUpper = true
+	input.AllowRepeat = true
+
+	res, err := gen.Generate(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(res, password.PhoneticallyAmbiguous) {
+		t.Errorf("%q should not contain phonetically ambiguous letters", res)
+	}
+	if strings.Trim(res, password.LowerLetters+"!@") != "" {
+		t.Errorf("%q should only contain lowercase letters and !@", res)
+	}
+
+	bits, err := gen.Entropy(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bits <= 0 {
+		t.Errorf("expected positive entropy, got %f", bits)
+	}
+}
@@ -0,0 +1,290 @@
+// Package otpauth generates TOTP secrets (RFC 6238) and the otpauth:// URIs
+// with which authenticator apps provision them, usually shown as a QR code.
+// The URI follows the Key URI Format of Google Authenticator, which all major
+// authenticator apps accept.
+//
+//	key, err := otpauth.New(otpauth.Options{Issuer: "Example", Account: "alice@example.com"})
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(key.URI()) // otpauth://totp/Example:alice@example.com?secret=...&issuer=Example
+//	...
+//	ok := key.Verify(code, time.Now(), 1)
+package otpauth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Algorithm is the HMAC hash function of a key.
+type Algorithm string
+
+// Algorithms of RFC 6238.
+const (
+	SHA1   Algorithm = "SHA1"
+	SHA256 Algorithm = "SHA256"
+	SHA512 Algorithm = "SHA512"
+)
+
+// Defaults of the Key URI Format, which are omitted from URIs.
+const (
+	DefaultAlgorithm = SHA1
+	DefaultDigits    = 6
+	DefaultPeriod    = 30 * time.Second
+)
+
+// DefaultSecretSize is the default size of a secret, in bytes: the output
+// size of SHA-1, as recommended by RFC 4226.
+const DefaultSecretSize = 20
+
+// minSecretSize is the minimum size of a secret, in bytes, required by RFC
+// 4226.
+const minSecretSize = 16
+
+// encoding is the unpadded base32 encoding of secrets in URIs.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+var (
+	// ErrMissingAccount is the error returned when a key has no account.
+	ErrMissingAccount = errors.New("missing account")
+
+	// ErrInvalidIssuer is the error returned when the issuer contains a ':',
+	// which separates it from the account in the URI label.
+	ErrInvalidIssuer = errors.New("issuer must not contain ':'")
+
+	// ErrInvalidAlgorithm is the error returned for an unknown algorithm.
+	ErrInvalidAlgorithm = errors.New("algorithm must be SHA1, SHA256 or SHA512")
+
+	// ErrInvalidDigits is the error returned when the number of digits is not
+	// between 6 and 8.
+	ErrInvalidDigits = errors.New("digits must be between 6 and 8")
+
+	// ErrInvalidPeriod is the error returned when the period is not a
+	// positive number of seconds.
+	ErrInvalidPeriod = errors.New("period must be a positive number of seconds")
+
+	// ErrSecretSize is the error returned when a secret is shorter than 128
+	// bits.
+	ErrSecretSize = fmt.Errorf("secret must be at least %d bytes", minSecretSize)
+)
+
+// Options used to define input parameters for New.
+type Options struct {
+	// Issuer is the provider or service the account belongs to, shown by
+	// authenticator apps. It is recommended.
+	Issuer string
+
+	// Account is the name of the account, such as an email address.
+	Account string
+
+	// Algorithm is the HMAC hash function. The default is DefaultAlgorithm;
+	// some authenticator apps ignore other algorithms.
+	Algorithm Algorithm
+
+	// Digits is the number of digits of codes. The default is
+	// DefaultDigits.
+	Digits int
+
+	// Period is the validity of codes, in whole seconds. The default is
+	// DefaultPeriod.
+	Period time.Duration
+
+	// SecretSize is the size of the secret, in bytes. The default is
+	// DefaultSecretSize.
+	SecretSize int
+
+	// Reader is the source of randomness. The default is crypto/rand.Reader.
+	Reader io.Reader
+	_      struct{}
+}
+
+// Key is a TOTP key with the parameters of its URI.
+type Key struct {
+	Issuer    string
+	Account   string
+	Secret    []byte
+	Algorithm Algorithm
+	Digits    int
+	Period    time.Duration
+}
+
+// New generates a new Key with a random secret.
+func New(opts Options) (*Key, error) {
+	k := &Key{
+		Issuer:    opts.Issuer,
+		Account:   opts.Account,
+		Algorithm: opts.Algorithm,
+		Digits:    opts.Digits,
+		Period:    opts.Period,
+	}
+	if k.Algorithm == "" {
+		k.Algorithm = DefaultAlgorithm
+	}
+	if k.Digits == 0 {
+		k.Digits = DefaultDigits
+	}
+	if k.Period == 0 {
+		k.Period = DefaultPeriod
+	}
+
+	size := opts.SecretSize
+	if size == 0 {
+		size = DefaultSecretSize
+	}
+	if size < minSecretSize {
+		return nil, ErrSecretSize
+	}
+	r := opts.Reader
+	if r == nil {
+		r = rand.Reader
+	}
+
+	k.Secret = make([]byte, size)
+	if _, err := io.ReadFull(r, k.Secret); err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+	if err := k.Validate(); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// Validate returns an error if the key cannot be provisioned.
+func (k *Key) Validate() error {
+	switch {
+	case k.Account == "":
+		return ErrMissingAccount
+	case strings.Contains(k.Issuer, ":"):
+		return ErrInvalidIssuer
+	case k.Digits < 6 || k.Digits > 8:
+		return ErrInvalidDigits
+	case k.Period < time.Second || k.Period%time.Second != 0:
+		return ErrInvalidPeriod
+	case len(k.Secret) < minSecretSize:
+		return ErrSecretSize
+	}
+	if _, err := k.Algorithm.hash(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Bits returns the entropy of the secret, in bits, for display next to the
+// URI.
+func (k *Key) Bits() int {
+	return 8 * len(k.Secret)
+}
+
+// EncodedSecret returns the secret encoded as unpadded base32, as entered
+// manually in authenticator apps.
+func (k *Key) EncodedSecret() string {
+	return encoding.EncodeToString(k.Secret)
+}
+
+// URI returns the otpauth:// URI of the key. The algorithm, digits and period
+// are omitted when they are the defaults.
+func (k *Key) URI() string {
+	label := escapeLabel(k.Account)
+	if k.Issuer != "" {
+		label = escapeLabel(k.Issuer) + ":" + label
+	}
+
+	params := []string{"secret=" + k.EncodedSecret()}
+	if k.Issuer != "" {
+		params = append(params, "issuer="+escape(k.Issuer))
+	}
+	if k.Algorithm != DefaultAlgorithm {
+		params = append(params, "algorithm="+string(k.Algorithm))
+	}
+	if k.Digits != DefaultDigits {
+		params = append(params, "digits="+strconv.Itoa(k.Digits))
+	}
+	if k.Period != DefaultPeriod {
+		params = append(params, "period="+strconv.FormatInt(int64(k.Period/time.Second), 10))
+	}
+	return "otpauth://totp/" + label + "?" + strings.Join(params, "&")
+}
+
+// escapeLabel percent-encodes s for the label of a URI, including the ':'
+// which separates the issuer from the account.
+func escapeLabel(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), ":", "%3A")
+}
+
+// escape percent-encodes s for a parameter of a URI. Spaces are encoded as
+// %20, since authenticator apps do not decode '+'.
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// Code returns the code of the key at the given time.
+func (k *Key) Code(t time.Time) (string, error) {
+	if err := k.Validate(); err != nil {
+		return "", err
+	}
+	return k.code(uint64(t.Unix()) / uint64(k.Period/time.Second)), nil
+}
+
+// Verify reports whether code is the code of the key at the given time, or of
+// up to skew periods before or after it to allow for clock drift.
+func (k *Key) Verify(code string, t time.Time, skew int) bool {
+	if k.Validate() != nil || len(code) != k.Digits {
+		return false
+	}
+
+	counter := int64(uint64(t.Unix()) / uint64(k.Period/time.Second))
+	ok := 0
+	for i := -int64(skew); i <= int64(skew); i++ {
+		if counter+i < 0 {
+			continue
+		}
+		ok |= subtle.ConstantTimeCompare([]byte(k.code(uint64(counter+i))), []byte(code))
+	}
+	return ok == 1
+}
+
+// code returns the HOTP code of RFC 4226 for the counter. The key must be
+// valid.
+func (k *Key) code(counter uint64) string {
+	h, _ := k.Algorithm.hash()
+	mac := hmac.New(h, k.Secret)
+	_ = binary.Write(mac, binary.BigEndian, counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	v := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < k.Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", k.Digits, v%mod)
+}
+
+// hash returns the hash function of the algorithm.
+func (a Algorithm) hash() (func() hash.Hash, error) {
+	switch a {
+	case SHA1:
+		return sha1.New, nil
+	case SHA256:
+		return sha256.New, nil
+	case SHA512:
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidAlgorithm, string(a))
+}
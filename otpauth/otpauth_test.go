@@ -0,0 +1,138 @@
+package otpauth
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Keys are the keys of the test vectors of RFC 6238, appendix B.
+var rfc6238Keys = map[Algorithm][]byte{
+	SHA1:   []byte("12345678901234567890"),
+	SHA256: []byte("12345678901234567890123456789012"),
+	SHA512: []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+}
+
+func TestCode(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		time  int64
+		codes map[Algorithm]string
+	}{
+		{59, map[Algorithm]string{SHA1: "94287082", SHA256: "46119246", SHA512: "90693936"}},
+		{1111111109, map[Algorithm]string{SHA1: "07081804", SHA256: "68084774", SHA512: "25091201"}},
+		{1111111111, map[Algorithm]string{SHA1: "14050471", SHA256: "67062674", SHA512: "99943326"}},
+		{1234567890, map[Algorithm]string{SHA1: "89005924", SHA256: "91819424", SHA512: "93441116"}},
+		{2000000000, map[Algorithm]string{SHA1: "69279037", SHA256: "90698825", SHA512: "38618901"}},
+		{20000000000, map[Algorithm]string{SHA1: "65353130", SHA256: "77737706", SHA512: "47863826"}},
+	}
+
+	for _, tc := range cases {
+		for alg, want := range tc.codes {
+			k := &Key{Account: "test", Secret: rfc6238Keys[alg], Algorithm: alg, Digits: 8, Period: 30 * time.Second}
+			now := time.Unix(tc.time, 0)
+			got, err := k.Code(now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Errorf("expected %s code at %d to be %s, got %s", alg, tc.time, want, got)
+			}
+
+			if !k.Verify(want, now, 0) {
+				t.Errorf("expected %s to be verified", want)
+			}
+			if !k.Verify(want, now.Add(30*time.Second), 1) {
+				t.Errorf("expected %s to be verified with a skew of 1", want)
+			}
+			if k.Verify(want, now.Add(30*time.Second), 0) {
+				t.Errorf("expected %s not to be verified in the next period", want)
+			}
+		}
+	}
+}
+
+func TestURI(t *testing.T) {
+	t.Parallel()
+
+	// Example of the Key URI Format of Google Authenticator.
+	k := &Key{
+		Issuer:    "Example",
+		Account:   "alice@google.com",
+		Secret:    []byte("Hello!\xde\xad\xbe\xef"),
+		Algorithm: DefaultAlgorithm,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+	}
+	if got, want := k.URI(), "otpauth://totp/Example:alice@google.com?secret=JBSWY3DPEHPK3PXP&issuer=Example"; got != want {
+		t.Errorf("expected %q to be %q", got, want)
+	}
+
+	k = &Key{
+		Issuer:    "ACME Co & Sons",
+		Account:   "john:doe@example.com",
+		Secret:    []byte("Hello!\xde\xad\xbe\xef"),
+		Algorithm: SHA256,
+		Digits:    8,
+		Period:    time.Minute,
+	}
+	want := "otpauth://totp/ACME%20Co%20&%20Sons:john%3Adoe@example.com?secret=JBSWY3DPEHPK3PXP&issuer=ACME%20Co%20%26%20Sons&algorithm=SHA256&digits=8&period=60"
+	if got := k.URI(); got != want {
+		t.Errorf("expected %q to be %q", got, want)
+	}
+
+	u, err := url.Parse(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Query().Get("issuer") != k.Issuer {
+		t.Errorf("expected issuer %q to be %q", u.Query().Get("issuer"), k.Issuer)
+	}
+	if label := strings.TrimPrefix(u.Path, "/"); label != k.Issuer+":"+k.Account {
+		t.Errorf("expected label %q to be %q", label, k.Issuer+":"+k.Account)
+	}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	k, err := New(Options{Issuer: "Example", Account: "alice@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k.Bits() != 160 || k.Algorithm != SHA1 || k.Digits != 6 || k.Period != 30*time.Second {
+		t.Errorf("unexpected defaults %+v", k)
+	}
+	if bytes.Equal(k.Secret, make([]byte, DefaultSecretSize)) {
+		t.Error("expected a random secret")
+	}
+
+	code, err := k.Code(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != 6 || !k.Verify(code, time.Now(), 1) {
+		t.Errorf("expected %q to be a valid code", code)
+	}
+
+	cases := []struct {
+		opts Options
+		err  error
+	}{
+		{Options{}, ErrMissingAccount},
+		{Options{Issuer: "a:b", Account: "alice"}, ErrInvalidIssuer},
+		{Options{Account: "alice", Algorithm: "MD5"}, ErrInvalidAlgorithm},
+		{Options{Account: "alice", Digits: 10}, ErrInvalidDigits},
+		{Options{Account: "alice", Period: 1500 * time.Millisecond}, ErrInvalidPeriod},
+		{Options{Account: "alice", SecretSize: 10}, ErrSecretSize},
+	}
+	for _, tc := range cases {
+		if _, err := New(tc.opts); !errors.Is(err, tc.err) {
+			t.Errorf("expected %v to be %v", err, tc.err)
+		}
+	}
+}
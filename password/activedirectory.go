@@ -0,0 +1,24 @@
+package password
+
+import (
+	"encoding/binary"
+	"unicode/utf16"
+)
+
+// EncodeADPassword encodes the password as the value of the unicodePwd
+// attribute of Active Directory: the password enclosed in double quotes,
+// encoded as UTF-16LE without a byte order mark. Characters outside of the
+// Basic Multilingual Plane are encoded as surrogate pairs. Active Directory
+// only accepts the attribute over an encrypted connection, such as LDAPS.
+//
+// To set a password, replace the attribute with the encoded password. To
+// change it as the user, delete it with the encoded old password and add the
+// encoded new one in the same modify request.
+func EncodeADPassword(pw string) []byte {
+	units := utf16.Encode([]rune(`"` + pw + `"`))
+	b := make([]byte, 0, 2*len(units))
+	for _, u := range units {
+		b = binary.LittleEndian.AppendUint16(b, u)
+	}
+	return b
+}
@@ -0,0 +1,27 @@
+package password
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestEncodeADPassword(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pw   string
+		want []byte
+	}{
+		{"", []byte{'"', 0, '"', 0}},
+		{"new", []byte{'"', 0, 'n', 0, 'e', 0, 'w', 0, '"', 0}},
+		{`a"é`, []byte{'"', 0, 'a', 0, '"', 0, 0xe9, 0, '"', 0}},
+		{"€", []byte{'"', 0, 0xac, 0x20, '"', 0}},
+		{"😀", []byte{'"', 0, 0x3d, 0xd8, 0x00, 0xde, '"', 0}},
+	}
+
+	for _, tc := range cases {
+		if got := EncodeADPassword(tc.pw); !bytes.Equal(got, tc.want) {
+			t.Errorf("expected EncodeADPassword(%q) to be % x, got % x", tc.pw, tc.want, got)
+		}
+	}
+}
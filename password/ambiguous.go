@@ -0,0 +1,24 @@
+package password
+
+// VisuallyAmbiguous is the list of characters which are easily confused with
+// one another when read, especially off printed sheets: zero and capital O,
+// one, lowercase L, capital I and the vertical bar, and the backtick.
+const VisuallyAmbiguous = "0O1lI|`"
+
+// WithoutAmbiguous creates a new Generator from another Generator with all
+// VisuallyAmbiguous characters removed from all of its charsets. This is
+// intended for passwords which are read and typed by hand.
+func (g Generator) WithoutAmbiguous() Generator {
+	return g.With(WithoutAmbiguous())
+}
+
+// WithoutAmbiguous returns an Option removing VisuallyAmbiguous characters, as
+// Generator.WithoutAmbiguous.
+func WithoutAmbiguous() Option {
+	return func(g *Generator) {
+		g.lowerLetters = removeChars(g.lowerLetters, VisuallyAmbiguous)
+		g.upperLetters = removeChars(g.upperLetters, VisuallyAmbiguous)
+		g.digits = removeChars(g.digits, VisuallyAmbiguous)
+		g.symbols = removeChars(g.symbols, VisuallyAmbiguous)
+	}
+}
@@ -0,0 +1,27 @@
+package password
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratorWithoutAmbiguous(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator().WithoutAmbiguous()
+	for i := 0; i < N; i++ {
+		res, err := gen.Generate(Input{
+			Length:      32,
+			Digits:      8,
+			Symbols:     8,
+			AllowRepeat: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.ContainsAny(res, VisuallyAmbiguous) {
+			t.Errorf("%q should not contain visually ambiguous characters", res)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package password
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ErrUnknownClass is the error returned when decoding an unknown class.
+var ErrUnknownClass = errors.New("unknown character class")
+
+// Class is the class of a character.
+type Class int
+
+const (
+	// ClassLower is the class of lowercase letters.
+	ClassLower Class = iota
+
+	// ClassUpper is the class of uppercase letters.
+	ClassUpper
+
+	// ClassDigit is the class of digits.
+	ClassDigit
+
+	// ClassSymbol is the class of symbols and punctuation.
+	ClassSymbol
+
+	// ClassOther is the class of all other characters, such as spaces and
+	// letters without case.
+	ClassOther
+)
+
+// String returns the name of the class.
+func (c Class) String() string {
+	switch c {
+	case ClassLower:
+		return "lower"
+	case ClassUpper:
+		return "upper"
+	case ClassDigit:
+		return "digit"
+	case ClassSymbol:
+		return "symbol"
+	case ClassOther:
+		return "other"
+	}
+	return "unknown"
+}
+
+// MarshalText implements encoding.TextMarshaler, encoding the class as its
+// name.
+func (c Class) MarshalText() ([]byte, error) {
+	if c < ClassLower || c > ClassOther {
+		return nil, fmt.Errorf("%w %d", ErrUnknownClass, int(c))
+	}
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, decoding the class from
+// its name.
+func (c *Class) UnmarshalText(text []byte) error {
+	for class := ClassLower; class <= ClassOther; class++ {
+		if class.String() == string(text) {
+			*c = class
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %q", ErrUnknownClass, text)
+}
+
+// ClassOf returns the class of the given character.
+func ClassOf(r rune) Class {
+	switch {
+	case unicode.IsLower(r):
+		return ClassLower
+	case unicode.IsUpper(r):
+		return ClassUpper
+	case unicode.IsDigit(r):
+		return ClassDigit
+	case unicode.IsPunct(r) || unicode.IsSymbol(r):
+		return ClassSymbol
+	}
+	return ClassOther
+}
+
+const (
+	// minRunLength is the minimum length of a reported run of repeated
+	// characters.
+	minRunLength = 3
+
+	// minSequenceLength is the minimum length of a reported sequence.
+	minSequenceLength = 3
+
+	// minKeyboardWalkLength is the minimum length of a reported keyboard walk.
+	minKeyboardWalkLength = 4
+)
+
+//go:embed common.txt
+var commonList string
+
+// commonWords returns the list of common words reported by Analyze.
+var commonWords = sync.OnceValue(func() []string {
+	var words []string
+	for _, line := range strings.Split(commonList, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words
+})
+
+// Pattern is a weak part of a password found by Analyze.
+type Pattern struct {
+	// Position is the index of the first character of the pattern, in runes.
+	Position int
+
+	// Length is the length of the pattern, in runes.
+	Length int
+
+	// Value is the part of the password matching the pattern.
+	Value string
+}
+
+// Anatomy is a breakdown of the structure of a password, as returned by
+// Analyze.
+type Anatomy struct {
+	// Length is the length of the password, in runes.
+	Length int
+
+	// Counts is the number of characters of every class.
+	Counts map[Class]int
+
+	// Runs are repetitions of the same character, such as "aaa".
+	Runs []Pattern
+
+	// Sequences are ascending or descending sequences of letters or digits,
+	// such as "abc" or "987".
+	Sequences []Pattern
+
+	// KeyboardWalks are sequences of physically adjacent keys on a QWERTY
+	// keyboard, such as "qwert" or "zaq1".
+	KeyboardWalks []Pattern
+
+	// DictionaryWords are common words and passwords, matched
+	// case-insensitively and through common character substitutions, such as
+	// "P4ssword".
+	DictionaryWords []Pattern
+}
+
+// Analyze breaks the given password down into its classes of characters and
+// the weak patterns it contains. Patterns may overlap.
+func Analyze(pw string) Anatomy {
+	runes := []rune(pw)
+	a := Anatomy{
+		Length: len(runes),
+		Counts: make(map[Class]int),
+	}
+
+	for _, r := range runes {
+		a.Counts[ClassOf(r)]++
+	}
+
+	a.Runs = findPatterns(runes, minRunLength, func(prev, cur, _ rune) bool {
+		return prev == cur
+	})
+
+	a.Sequences = findPatterns(runes, minSequenceLength, func(prev, cur, before rune) bool {
+		if !sequential(prev, cur) {
+			return false
+		}
+		// The direction must not change within a sequence.
+		before, prev, cur = unicode.ToLower(before), unicode.ToLower(prev), unicode.ToLower(cur)
+		return before == 0 || cur-prev == prev-before
+	})
+
+	a.KeyboardWalks = findPatterns(runes, minKeyboardWalkLength, func(prev, cur, _ rune) bool {
+		return QWERTY.Adjacent(prev, cur)
+	})
+
+	a.DictionaryWords = findWords(runes, commonWords())
+	return a
+}
+
+// findPatterns returns all maximal parts of runes of at least minLength in
+// which every character continues the pattern according to cont. cont is
+// called with the previous character, the current character and the character
+// before the previous one, which is zero at the start of a pattern.
+func findPatterns(runes []rune, minLength int, cont func(prev, cur, before rune) bool) []Pattern {
+	var patterns []Pattern
+	start := 0
+	for i := 1; i <= len(runes); i++ {
+		if i < len(runes) {
+			var before rune
+			if i-start >= 2 {
+				before = runes[i-2]
+			}
+			if cont(runes[i-1], runes[i], before) {
+				continue
+			}
+		}
+
+		if i-start >= minLength {
+			patterns = append(patterns, Pattern{
+				Position: start,
+				Length:   i - start,
+				Value:    string(runes[start:i]),
+			})
+		}
+
+		// A new pattern may start with the last character of the previous one,
+		// such as "bcd" following "aaab". Sequences that change direction are
+		// handled the same way.
+		start = i - 1
+		if i < len(runes) && !cont(runes[i-1], runes[i], 0) {
+			start = i
+		}
+	}
+	return patterns
+}
+
+// sequential reports whether a and b are consecutive letters or digits, in
+// either direction.
+func sequential(a, b rune) bool {
+	a, b = unicode.ToLower(a), unicode.ToLower(b)
+	if ClassOf(a) != ClassOf(b) || (ClassOf(a) != ClassLower && ClassOf(a) != ClassDigit) {
+		return false
+	}
+	return a-b == 1 || b-a == 1
+}
+
+// findWords returns all occurrences of the given words in runes, matched
+// case-insensitively and through common character substitutions.
+func findWords(runes []rune, words []string) []Pattern {
+	normalized := make([]rune, len(runes))
+	for i, r := range runes {
+		normalized[i] = unicode.ToLower(r)
+		if s := leetReplacer.Replace(string(normalized[i])); len(s) == 1 {
+			normalized[i] = rune(s[0])
+		}
+	}
+	haystack := string(normalized)
+
+	var patterns []Pattern
+	for _, word := range words {
+		for off := 0; ; {
+			i := strings.Index(haystack[off:], word)
+			if i < 0 {
+				break
+			}
+
+			pos := len([]rune(haystack[:off+i]))
+			n := len([]rune(word))
+			patterns = append(patterns, Pattern{
+				Position: pos,
+				Length:   n,
+				Value:    string(runes[pos : pos+n]),
+			})
+			off += i + 1
+		}
+	}
+	return patterns
+}
@@ -0,0 +1,105 @@
+package password
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassOf(t *testing.T) {
+	t.Parallel()
+
+	cases := map[rune]Class{
+		'a': ClassLower,
+		'Z': ClassUpper,
+		'7': ClassDigit,
+		'#': ClassSymbol,
+		'€': ClassSymbol,
+		'ж': ClassLower,
+		' ': ClassOther,
+	}
+
+	for r, want := range cases {
+		if got := ClassOf(r); got != want {
+			t.Errorf("expected class of %q to be %s, got %s", r, want, got)
+		}
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		pw   string
+		want Anatomy
+	}{
+		{
+			name: "counts",
+			pw:   "aB3$ €",
+			want: Anatomy{
+				Length: 6,
+				Counts: map[Class]int{ClassLower: 1, ClassUpper: 1, ClassDigit: 1, ClassSymbol: 2, ClassOther: 1},
+			},
+		},
+		{
+			name: "runs",
+			pw:   "xaaay1111",
+			want: Anatomy{
+				Length: 9,
+				Counts: map[Class]int{ClassLower: 5, ClassDigit: 4},
+				Runs: []Pattern{
+					{Position: 1, Length: 3, Value: "aaa"},
+					{Position: 5, Length: 4, Value: "1111"},
+				},
+			},
+		},
+		{
+			name: "sequences",
+			pw:   "AbCba%987",
+			want: Anatomy{
+				Length: 9,
+				Counts: map[Class]int{ClassLower: 3, ClassUpper: 2, ClassSymbol: 1, ClassDigit: 3},
+				Sequences: []Pattern{
+					{Position: 0, Length: 3, Value: "AbC"},
+					{Position: 2, Length: 3, Value: "Cba"},
+					{Position: 6, Length: 3, Value: "987"},
+				},
+			},
+		},
+		{
+			name: "keyboard_walks",
+			pw:   "%qwer#zaq1",
+			want: Anatomy{
+				Length: 10,
+				Counts: map[Class]int{ClassLower: 7, ClassSymbol: 2, ClassDigit: 1},
+				KeyboardWalks: []Pattern{
+					{Position: 1, Length: 4, Value: "qwer"},
+					{Position: 6, Length: 4, Value: "zaq1"},
+				},
+			},
+		},
+		{
+			name: "dictionary_words",
+			pw:   "ж9P4ssw0rd!",
+			want: Anatomy{
+				Length: 11,
+				Counts: map[Class]int{ClassLower: 6, ClassUpper: 1, ClassDigit: 3, ClassSymbol: 1},
+				DictionaryWords: []Pattern{
+					{Position: 2, Length: 8, Value: "P4ssw0rd"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Analyze(tc.pw); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("expected\n%#v\nto be\n%#v", got, tc.want)
+			}
+		})
+	}
+}
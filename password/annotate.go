@@ -0,0 +1,47 @@
+package password
+
+import "strings"
+
+// GenerateAnnotated is the same as Generate, but also returns the class of
+// every rune of the password, so that user interfaces can color-code the
+// characters for easier transcription. See Annotate.
+func (g Generator) GenerateAnnotated(input Input) (string, []Class, error) {
+	res, err := g.Generate(input)
+	if err != nil {
+		return "", nil, err
+	}
+	return res, g.Annotate(res), nil
+}
+
+// Annotate returns the class of every rune of the password. Characters of the
+// Generator charsets have the class of their charset, so that a custom
+// charset holding, for example, digits as symbols is annotated as such. Other
+// characters, such as separators added by post-processors, are classified with
+// ClassOf.
+func (g Generator) Annotate(pw string) []Class {
+	classes := make([]Class, 0, len(pw))
+	for _, r := range pw {
+		classes = append(classes, g.classOf(r))
+	}
+	return classes
+}
+
+// classOf returns the class of r according to the Generator charsets.
+func (g Generator) classOf(r rune) Class {
+	switch {
+	case strings.ContainsRune(g.lowerLetters, r):
+		return ClassLower
+	case strings.ContainsRune(g.upperLetters, r):
+		return ClassUpper
+	case strings.ContainsRune(g.digits, r):
+		return ClassDigit
+	case strings.ContainsRune(g.symbols, r):
+		return ClassSymbol
+	}
+	return ClassOf(r)
+}
+
+// GenerateAnnotated is the package shortcut for Generator.GenerateAnnotated.
+func GenerateAnnotated(input Input) (string, []Class, error) {
+	return NewGenerator().GenerateAnnotated(input)
+}
@@ -0,0 +1,48 @@
+package password
+
+import (
+	"slices"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestGenerateAnnotated(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < N; i++ {
+		res, classes, err := GenerateAnnotated(Input{Length: 16, Digits: 4, Symbols: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(classes) != utf8.RuneCountInString(res) {
+			t.Fatalf("expected %d classes for %q, got %d", utf8.RuneCountInString(res), res, len(classes))
+		}
+
+		var counts [ClassOther + 1]int
+		for j, r := range []rune(res) {
+			if classes[j] != ClassOf(r) {
+				t.Errorf("expected %q to be annotated %s, got %s", r, ClassOf(r), classes[j])
+			}
+			counts[classes[j]]++
+		}
+		if counts[ClassDigit] != 4 || counts[ClassSymbol] != 4 {
+			t.Errorf("unexpected classes %v for %q", classes, res)
+		}
+	}
+}
+
+func TestGeneratorAnnotate(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator().WithSymbols("0")
+	got := gen.Annotate("aB0-é")
+	want := []Class{ClassLower, ClassUpper, ClassDigit, ClassSymbol, ClassLower}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	gen = NewGenerator().WithDigits("").WithSymbols("0")
+	if got := gen.Annotate("0"); !slices.Equal(got, []Class{ClassSymbol}) {
+		t.Errorf("expected the symbol charset to take precedence, got %v", got)
+	}
+}
@@ -0,0 +1,55 @@
+package password
+
+import (
+	"errors"
+	"slices"
+	"unicode/utf8"
+)
+
+// ErrRequiresString is the error returned by AppendPassword when the Generator
+// has post-processors or filters, which only handle passwords as strings.
+var ErrRequiresString = errors.New("post-processors and filters require string passwords")
+
+// AppendPassword appends a password generated with the given input to dst and
+// returns the extended buffer, like Generate but without ever holding the
+// password in a string. Strings are immutable, so a password returned by
+// Generate stays in memory until it is garbage collected and overwritten;
+// the bytes appended by AppendPassword can be wiped by the caller with clear
+// once used. Intermediate buffers are wiped before returning.
+//
+// dst is grown at most once, before the password is written, so that no copy
+// of the password is left behind by a reallocation. Post-processors and
+// filters work on strings: if the Generator has any, ErrRequiresString is
+// returned.
+func (g Generator) AppendPassword(dst []byte, input Input) ([]byte, error) {
+	if len(g.postProcessors) > 0 || len(g.filters) > 0 {
+		return dst, ErrRequiresString
+	}
+
+	var (
+		buf []rune
+		err error
+	)
+	g.account(func(g Generator) {
+		buf, err = g.generateRunes(input)
+	})
+	if err != nil {
+		return dst, err
+	}
+	defer clear(buf)
+
+	n := 0
+	for _, r := range buf {
+		n += utf8.RuneLen(r)
+	}
+	dst = slices.Grow(dst, n)
+	for _, r := range buf {
+		dst = utf8.AppendRune(dst, r)
+	}
+	return dst, nil
+}
+
+// AppendPassword is the package shortcut for Generator.AppendPassword.
+func AppendPassword(dst []byte, input Input) ([]byte, error) {
+	return NewGenerator().AppendPassword(dst, input)
+}
@@ -0,0 +1,58 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGeneratorAppendPassword(t *testing.T) {
+	t.Parallel()
+
+	input := Input{Length: 24, Digits: 4, Symbols: 4, MaxSameClassRun: 3}
+	want, err := NewGenerator().WithReader(&testReader{seed: "a"}).Generate(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gen := NewGenerator().WithReader(&testReader{seed: "a"}).WithStats()
+	got, err := gen.AppendPassword([]byte("pw="), input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "pw="+want {
+		t.Errorf("expected %q to be %q", got, "pw="+want)
+	}
+	if st := gen.Stats(); st.Calls != 1 || st.RandomBytes == 0 {
+		t.Errorf("expected %+v to account for one call", st)
+	}
+
+	got, err = NewGenerator().WithSymbols("€").AppendPassword(nil, Input{Length: 8, Symbols: 2, AllowRepeat: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 6+2*3 {
+		t.Errorf("expected %v to be %v", len(got), 12)
+	}
+}
+
+func TestGeneratorAppendPasswordErrors(t *testing.T) {
+	t.Parallel()
+
+	dst := []byte("prefix")
+	for _, gen := range []Generator{
+		NewGenerator().WithFilter(func(string) bool { return true }),
+		NewGenerator().WithPostProcessors(Uppercase()),
+	} {
+		got, err := gen.AppendPassword(dst, Input{Length: 8})
+		if !errors.Is(err, ErrRequiresString) {
+			t.Errorf("expected %v to be %v", err, ErrRequiresString)
+		}
+		if string(got) != "prefix" {
+			t.Errorf("expected %q to be %q", got, "prefix")
+		}
+	}
+
+	if _, err := AppendPassword(nil, Input{Length: 4, Digits: 5}); !errors.Is(err, ErrExceedsTotalLength) {
+		t.Errorf("expected %v to be %v", err, ErrExceedsTotalLength)
+	}
+}
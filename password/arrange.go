@@ -0,0 +1,143 @@
+package password
+
+import "io"
+
+// arrange combines the characters of the given classes into a single password
+// as requested by the input, using bytes read from r. buf holds counts[i]
+// characters of class i, class after class, in random order within each
+// class. buf may be modified or returned.
+func arrange(r io.Reader, buf []rune, counts []int, input Input) ([]rune, error) {
+	if input.MaxSameClassRun > 0 {
+		return arrangeRuns(r, buf, counts, input.MaxSameClassRun, input.PreserveClassOrder)
+	}
+
+	if input.PreserveClassOrder {
+		return buf, nil
+	}
+
+	// Every permutation is equally likely, so every interleaving of the
+	// classes is too.
+	if err := shuffle(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// arrangeRuns combines the characters of the given classes such that no more
+// than maxRun characters of the same class appear in a row, using bytes read
+// from r.
+func arrangeRuns(r io.Reader, buf []rune, counts []int, maxRun int, preserveOrder bool) ([]rune, error) {
+	if preserveOrder {
+		for _, n := range counts {
+			if n > maxRun {
+				return nil, ErrSameClassRunUnsatisfiable
+			}
+		}
+		return buf, nil
+	}
+
+	layout, err := classLayout(r, counts, maxRun)
+	if err != nil {
+		return nil, err
+	}
+
+	// next[c] is the position in buf of the next character of class c.
+	next := make([]int, len(counts))
+	for c := 1; c < len(counts); c++ {
+		next[c] = next[c-1] + counts[c-1]
+	}
+	res := make([]rune, len(layout))
+	for i, c := range layout {
+		res[i] = buf[next[c]]
+		next[c]++
+	}
+	clear(buf)
+	return res, nil
+}
+
+// classLayout returns a random sequence of class indexes in which class i
+// appears counts[i] times and no class appears more than maxRun times in a
+// row. At every position a class is chosen with probability proportional to
+// its remaining count, among the classes which keep the rest of the sequence
+// satisfiable.
+func classLayout(r io.Reader, counts []int, maxRun int) ([]int, error) {
+	remaining := make([]int, len(counts))
+	copy(remaining, counts)
+
+	var total int
+	for _, c := range remaining {
+		total += c
+	}
+
+	if !runsSatisfiable(remaining, total, -1, 0, maxRun) {
+		return nil, ErrSameClassRunUnsatisfiable
+	}
+
+	layout := make([]int, 0, total)
+	last, run := -1, 0
+	weights := make([]int, len(counts))
+	for len(layout) < total {
+		var sum int
+		for c := range remaining {
+			weights[c] = 0
+			if remaining[c] == 0 || (c == last && run == maxRun) {
+				continue
+			}
+
+			nextRun := 1
+			if c == last {
+				nextRun = run + 1
+			}
+
+			remaining[c]--
+			if runsSatisfiable(remaining, total-len(layout)-1, c, nextRun, maxRun) {
+				weights[c] = remaining[c] + 1
+			}
+			remaining[c]++
+			sum += weights[c]
+		}
+
+		if sum == 0 {
+			return nil, ErrSameClassRunUnsatisfiable
+		}
+
+		n, err := UniformIndex(r, sum)
+		if err != nil {
+			return nil, err
+		}
+
+		c := 0
+		for n >= weights[c] {
+			n -= weights[c]
+			c++
+		}
+
+		if c == last {
+			run++
+		} else {
+			last, run = c, 1
+		}
+		remaining[c]--
+		layout = append(layout, c)
+	}
+	return layout, nil
+}
+
+// runsSatisfiable reports whether the remaining characters can be arranged
+// without exceeding maxRun, given that the sequence so far ends with run
+// characters of class last. Every class needs enough characters of other
+// classes to separate its runs.
+func runsSatisfiable(remaining []int, total, last, run, maxRun int) bool {
+	for c, n := range remaining {
+		others := total - n
+		limit := maxRun * (others + 1)
+		if c == last {
+			limit = maxRun - run + maxRun*others
+		}
+
+		if n > limit {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testMaxClassRun(tb testing.TB, s string) int {
+	tb.Helper()
+
+	class := func(b byte) int {
+		switch {
+		case strings.IndexByte(Digits, b) >= 0:
+			return 1
+		case strings.IndexByte(Symbols, b) >= 0:
+			return 2
+		default:
+			return 0
+		}
+	}
+
+	var longest, run int
+	for i := 0; i < len(s); i++ {
+		if i > 0 && class(s[i]) == class(s[i-1]) {
+			run++
+		} else {
+			run = 1
+		}
+		longest = max(longest, run)
+	}
+	return longest
+}
+
+func TestGeneratorGenerateMaxSameClassRun(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator()
+
+	t.Run("limits_runs", func(t *testing.T) {
+		t.Parallel()
+
+		for i := 0; i < N; i++ {
+			res, err := gen.Generate(Input{
+				Length:          32,
+				Digits:          10,
+				Symbols:         6,
+				AllowRepeat:     true,
+				MaxSameClassRun: 2,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(res) != 32 {
+				t.Errorf("%q should be 32 characters", res)
+			}
+			if got := testMaxClassRun(t, res); got > 2 {
+				t.Errorf("%q has a run of %d characters of the same class", res, got)
+			}
+		}
+	})
+
+	t.Run("tight", func(t *testing.T) {
+		t.Parallel()
+
+		// 10 letters in runs of at most 2 need exactly 4 separators.
+		for i := 0; i < N; i++ {
+			res, err := gen.Generate(Input{
+				Length:          14,
+				Digits:          2,
+				Symbols:         2,
+				MaxSameClassRun: 2,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := testMaxClassRun(t, res); got > 2 {
+				t.Errorf("%q has a run of %d characters of the same class", res, got)
+			}
+		}
+	})
+
+	t.Run("unsatisfiable", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := gen.Generate(Input{
+			Length:          15,
+			Digits:          2,
+			Symbols:         2,
+			MaxSameClassRun: 2,
+		}); !errors.Is(err, ErrSameClassRunUnsatisfiable) {
+			t.Errorf("expected %q to be %q", err, ErrSameClassRunUnsatisfiable)
+		}
+	})
+
+	t.Run("preserve_class_order", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := gen.Generate(Input{
+			Length:             8,
+			Digits:             3,
+			Symbols:            2,
+			PreserveClassOrder: true,
+			MaxSameClassRun:    3,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Trim(res[:3], LowerLetters+UpperLetters) != "" {
+			t.Errorf("%q should start with letters", res)
+		}
+
+		if _, err := gen.Generate(Input{
+			Length:             8,
+			Digits:             3,
+			PreserveClassOrder: true,
+			MaxSameClassRun:    3,
+		}); !errors.Is(err, ErrSameClassRunUnsatisfiable) {
+			t.Errorf("expected %q to be %q", err, ErrSameClassRunUnsatisfiable)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package password
+
+import (
+	"context"
+	"fmt"
+)
+
+// BatchOptions used to define how GenerateN behaves when the batch cannot be
+// completed.
+type BatchOptions struct {
+	// MaxAttempts is the number of passwords which may be generated for the
+	// whole batch, including those rejected by filters. The default is
+	// n*1000, the same budget as n calls to Generate, but a single password
+	// may use more than its share.
+	MaxAttempts int
+
+	// Partial makes GenerateN return the passwords generated so far along
+	// with the *BatchError when the batch is interrupted, instead of no
+	// passwords at all.
+	Partial bool
+
+	// Unique makes GenerateN reject passwords already generated in the batch,
+	// counting them as attempts, so that every password of the batch is
+	// distinct.
+	Unique bool
+	_      struct{}
+}
+
+// BatchError is the error returned by GenerateN when the batch is interrupted
+// by the retry budget, the context or a generation failure. It records how far
+// the batch went so that a bulk job can resume with the missing passwords.
+type BatchError struct {
+	// Requested is the number of passwords requested, and Completed the
+	// number of passwords generated before the interruption.
+	Requested int
+	Completed int
+
+	// Attempts is the number of passwords generated, including those
+	// rejected by filters.
+	Attempts int
+
+	// Err is the cause of the interruption: ErrFilterAttemptsExceeded, the
+	// context error or the generation error.
+	Err error
+}
+
+// Error implements error.
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch interrupted after %d of %d passwords (%d attempts): %s",
+		e.Completed, e.Requested, e.Attempts, e.Err)
+}
+
+// Unwrap returns the cause of the interruption.
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// GenerateN generates n passwords with the given requirements, as by Generate.
+// Instead of a retry budget per password, the batch shares the budget of
+// opts.MaxAttempts, and it stops when ctx is done.
+//
+// If the batch is interrupted, a *BatchError is returned, and the passwords
+// generated so far are returned with it if opts.Partial is set. This function
+// is safe for concurrent use.
+func (g Generator) GenerateN(ctx context.Context, n int, input Input, opts BatchOptions) ([]string, error) {
+	if n < 0 {
+		return nil, ErrInvalidRange
+	}
+
+	budget := opts.MaxAttempts
+	if budget <= 0 {
+		budget = n * maxFilterAttempts
+	}
+
+	res := make([]string, 0, n)
+	var seen map[string]struct{}
+	if opts.Unique {
+		seen = make(map[string]struct{}, n)
+	}
+	attempts := 0
+	var err error
+	g.account(func(g Generator) {
+		for len(res) < n {
+			if err = ctx.Err(); err != nil {
+				return
+			}
+			if attempts == budget {
+				err = ErrFilterAttemptsExceeded
+				return
+			}
+			attempts++
+
+			var (
+				pw string
+				ok bool
+			)
+			pw, ok, err = g.generateCandidate(input)
+			if err != nil {
+				return
+			}
+			if !ok {
+				continue
+			}
+			if seen != nil {
+				if _, dup := seen[pw]; dup {
+					continue
+				}
+				seen[pw] = struct{}{}
+			}
+			res = append(res, pw)
+		}
+	})
+	if err == nil {
+		return res, nil
+	}
+
+	batchErr := &BatchError{
+		Requested: n,
+		Completed: len(res),
+		Attempts:  attempts,
+		Err:       err,
+	}
+	if !opts.Partial {
+		return nil, batchErr
+	}
+	return res, batchErr
+}
+
+// GenerateMany generates n distinct passwords with the given requirements, as
+// by GenerateN with BatchOptions.Unique set. It is meant for provisioning many
+// accounts at once, where no two of them may share a password.
+func (g Generator) GenerateMany(n int, input Input) ([]string, error) {
+	return g.GenerateN(context.Background(), n, input, BatchOptions{Unique: true})
+}
+
+// GenerateMany is the package shortcut for Generator.GenerateMany.
+func GenerateMany(n int, input Input) ([]string, error) {
+	return NewGenerator().GenerateMany(n, input)
+}
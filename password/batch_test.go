@@ -0,0 +1,141 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGeneratorGenerateN(t *testing.T) {
+	t.Parallel()
+
+	t.Run("complete", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := NewGenerator().GenerateN(context.Background(), 20, Input{Length: 12, Digits: 2}, BatchOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 20 {
+			t.Fatalf("expected 20 passwords, got %d", len(res))
+		}
+		for _, pw := range res {
+			if len(pw) != 12 {
+				t.Errorf("expected 12 characters, got %q", pw)
+			}
+		}
+	})
+
+	t.Run("budget", func(t *testing.T) {
+		t.Parallel()
+
+		// Only passwords starting with a digit are accepted, so a budget of
+		// 200 attempts yields about 20 of them.
+		gen := NewGenerator().WithLowerLetters("ab").WithUpperLetters("").WithDigits("0").WithFilter(func(s string) bool {
+			return strings.HasPrefix(s, "0")
+		})
+		input := Input{Length: 10, Digits: 1, AllowRepeat: true}
+
+		res, err := gen.GenerateN(context.Background(), 1000, input, BatchOptions{MaxAttempts: 200})
+		var batchErr *BatchError
+		if !errors.As(err, &batchErr) || !errors.Is(err, ErrFilterAttemptsExceeded) {
+			t.Fatalf("expected batch error, got %v", err)
+		}
+		if res != nil {
+			t.Errorf("expected no passwords without Partial, got %d", len(res))
+		}
+		if batchErr.Requested != 1000 || batchErr.Attempts != 200 {
+			t.Errorf("unexpected batch error %+v", batchErr)
+		}
+
+		res, err = gen.GenerateN(context.Background(), 1000, input, BatchOptions{MaxAttempts: 200, Partial: true})
+		if !errors.As(err, &batchErr) {
+			t.Fatalf("expected batch error, got %v", err)
+		}
+		if len(res) != batchErr.Completed || len(res) == 0 {
+			t.Errorf("expected %d partial passwords, got %d", batchErr.Completed, len(res))
+		}
+		for _, pw := range res {
+			if !strings.HasPrefix(pw, "0") {
+				t.Errorf("expected filtered password, got %q", pw)
+			}
+		}
+	})
+
+	t.Run("context", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		n := 0
+		gen := NewGenerator().WithFilter(func(string) bool {
+			if n++; n == 5 {
+				cancel()
+			}
+			return true
+		})
+
+		res, err := gen.GenerateN(ctx, 10, Input{Length: 8}, BatchOptions{Partial: true})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected %v to be %v", err, context.Canceled)
+		}
+		if len(res) != 5 {
+			t.Errorf("expected 5 partial passwords, got %d", len(res))
+		}
+	})
+
+	t.Run("unique", func(t *testing.T) {
+		t.Parallel()
+
+		// Only 16 passwords are possible.
+		gen := NewGenerator().WithLowerLetters("ab").WithUpperLetters("")
+		input := Input{Length: 4, AllowRepeat: true}
+
+		res, err := gen.GenerateN(context.Background(), 16, input, BatchOptions{Unique: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[string]struct{})
+		for _, pw := range res {
+			if _, ok := seen[pw]; ok {
+				t.Errorf("duplicate password %q", pw)
+			}
+			seen[pw] = struct{}{}
+		}
+
+		if _, err := gen.GenerateN(context.Background(), 17, input, BatchOptions{Unique: true}); !errors.Is(err, ErrFilterAttemptsExceeded) {
+			t.Errorf("expected %v to be %v", err, ErrFilterAttemptsExceeded)
+		}
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := NewGenerator().GenerateN(context.Background(), 3, Input{Length: 2, Digits: 3}, BatchOptions{Partial: true})
+		if !errors.Is(err, ErrExceedsTotalLength) {
+			t.Errorf("expected %v to be %v", err, ErrExceedsTotalLength)
+		}
+		if len(res) != 0 {
+			t.Errorf("expected no passwords, got %q", res)
+		}
+	})
+}
+
+func TestGenerateMany(t *testing.T) {
+	t.Parallel()
+
+	res, err := GenerateMany(200, Input{Length: 6, NoUpper: true, AllowRepeat: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 200 {
+		t.Fatalf("expected 200 passwords, got %d", len(res))
+	}
+	seen := make(map[string]struct{})
+	for _, pw := range res {
+		if _, ok := seen[pw]; ok {
+			t.Errorf("duplicate password %q", pw)
+		}
+		seen[pw] = struct{}{}
+	}
+}
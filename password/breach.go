@@ -0,0 +1,70 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoBreachChecker is the error returned by GenerateUnbreached when the
+	// Generator has no BreachChecker.
+	ErrNoBreachChecker = errors.New("generator has no breach checker")
+
+	// ErrBreachAttemptsExceeded is the error returned when every password
+	// generated by GenerateUnbreached was found in breaches, which only
+	// happens when the input allows very few passwords.
+	ErrBreachAttemptsExceeded = errors.New("every generated password was breached")
+)
+
+// maxBreachAttempts is the maximum number of passwords checked before giving
+// up.
+const maxBreachAttempts = 10
+
+// BreachChecker checks passwords against a database of breached passwords,
+// such as the breachcheck package does with Have I Been Pwned.
+type BreachChecker interface {
+	// Check returns the number of times the password appears in breaches,
+	// which is zero if it was never breached.
+	Check(ctx context.Context, pw string) (int, error)
+}
+
+// WithBreachChecker returns an Option setting the BreachChecker used by
+// GenerateUnbreached.
+func WithBreachChecker(c BreachChecker) Option {
+	return func(g *Generator) {
+		g.breachChecker = c
+	}
+}
+
+// WithBreachChecker creates a new Generator from another Generator with the
+// given BreachChecker, used by GenerateUnbreached.
+func (g Generator) WithBreachChecker(c BreachChecker) Generator {
+	return g.With(WithBreachChecker(c))
+}
+
+// GenerateUnbreached is the same as Generate, but checks the password with the
+// BreachChecker of the Generator and generates another one while it is found
+// in breaches. Random passwords of useful length are practically never
+// breached, so the check mostly guards against weak inputs and broken readers.
+// Errors of the BreachChecker are returned rather than ignored.
+func (g Generator) GenerateUnbreached(ctx context.Context, input Input) (string, error) {
+	if g.breachChecker == nil {
+		return "", ErrNoBreachChecker
+	}
+
+	for i := 0; i < maxBreachAttempts; i++ {
+		res, err := g.Generate(input)
+		if err != nil {
+			return "", err
+		}
+		n, err := g.breachChecker.Check(ctx, res)
+		if err != nil {
+			return "", fmt.Errorf("failed to check password: %w", err)
+		}
+		if n == 0 {
+			return res, nil
+		}
+	}
+	return "", ErrBreachAttemptsExceeded
+}
@@ -0,0 +1,52 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// breachCheckerFunc is a BreachChecker calling a function.
+type breachCheckerFunc func(pw string) (int, error)
+
+func (f breachCheckerFunc) Check(_ context.Context, pw string) (int, error) {
+	return f(pw)
+}
+
+func TestGenerateUnbreached(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	input := Input{Length: 16, Digits: 3, Symbols: 3}
+
+	if _, err := NewGenerator().GenerateUnbreached(ctx, input); !errors.Is(err, ErrNoBreachChecker) {
+		t.Errorf("expected %v to be %v", err, ErrNoBreachChecker)
+	}
+
+	var checked []string
+	g := NewGenerator().WithBreachChecker(breachCheckerFunc(func(pw string) (int, error) {
+		checked = append(checked, pw)
+		if len(checked) < 3 {
+			return 42, nil
+		}
+		return 0, nil
+	}))
+	res, err := g.GenerateUnbreached(ctx, input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checked) != 3 || res != checked[2] {
+		t.Errorf("expected %q to be the third checked password of %q", res, checked)
+	}
+
+	g = g.WithBreachChecker(breachCheckerFunc(func(string) (int, error) { return 1, nil }))
+	if _, err := g.GenerateUnbreached(ctx, input); !errors.Is(err, ErrBreachAttemptsExceeded) {
+		t.Errorf("expected %v to be %v", err, ErrBreachAttemptsExceeded)
+	}
+
+	errDown := errors.New("down")
+	g = g.WithBreachChecker(breachCheckerFunc(func(string) (int, error) { return 0, errDown }))
+	if _, err := g.GenerateUnbreached(ctx, input); !errors.Is(err, errDown) {
+		t.Errorf("expected %v to be %v", err, errDown)
+	}
+}
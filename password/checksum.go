@@ -0,0 +1,48 @@
+package password
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"strings"
+)
+
+// ChecksumWord returns a word from the wordlist derived from a SHA-256 hash of
+// the given words. Appending it to a passphrase makes transcription errors
+// detectable with VerifyChecksum. Words are compared case-insensitively.
+//
+// The checksum word adds no entropy; it only guards against typos.
+func (w Wordlist) ChecksumWord(words []string) (string, error) {
+	if len(w) == 0 {
+		return "", ErrWordlistEmpty
+	}
+
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.Join(words, " "))))
+	i := binary.BigEndian.Uint64(sum[:8]) % uint64(len(w))
+	return w[i], nil
+}
+
+// AppendChecksum returns the given words followed by their checksum word.
+func (w Wordlist) AppendChecksum(words []string) ([]string, error) {
+	checksum, err := w.ChecksumWord(words)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(words)+1)
+	res = append(res, words...)
+	return append(res, checksum), nil
+}
+
+// VerifyChecksum reports whether the last of the given words is the checksum
+// word of the words before it.
+func (w Wordlist) VerifyChecksum(words []string) bool {
+	if len(words) < 2 {
+		return false
+	}
+
+	checksum, err := w.ChecksumWord(words[:len(words)-1])
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(checksum, words[len(words)-1])
+}
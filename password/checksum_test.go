@@ -0,0 +1,64 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWordlistChecksum(t *testing.T) {
+	t.Parallel()
+
+	list := Wordlist{"apple", "banana", "cherry", "grape", "lemon", "mango", "melon", "peach"}
+
+	t.Run("round_trip", func(t *testing.T) {
+		t.Parallel()
+
+		words, err := list.AppendChecksum([]string{"lemon", "apple", "peach"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(words) != 4 {
+			t.Fatalf("expected %d words, got %d", 4, len(words))
+		}
+		if !list.VerifyChecksum(words) {
+			t.Errorf("expected %q to verify", words)
+		}
+	})
+
+	t.Run("case_insensitive", func(t *testing.T) {
+		t.Parallel()
+
+		words, err := list.AppendChecksum([]string{"lemon", "apple", "peach"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		words[0] = "Lemon"
+		if !list.VerifyChecksum(words) {
+			t.Errorf("expected %q to verify", words)
+		}
+	})
+
+	t.Run("detects_typo", func(t *testing.T) {
+		t.Parallel()
+
+		words, err := list.AppendChecksum([]string{"lemon", "apple", "peach"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		words[0] = "lemno"
+		if list.VerifyChecksum(words) {
+			t.Errorf("expected %q not to verify", words)
+		}
+	})
+
+	t.Run("empty_wordlist", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := Wordlist(nil).ChecksumWord([]string{"apple"}); !errors.Is(err, ErrWordlistEmpty) {
+			t.Errorf("expected %q to be %q", err, ErrWordlistEmpty)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+)
+
+//go:embed policy_corpus.jsonl
+var policyCorpusList string
+
+// CorpusCase is a password, a policy and the expected verdict of validating
+// the password against the policy.
+type CorpusCase struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+	Policy   Policy `json:"policy"`
+
+	// Valid is whether the password follows the policy, and Rules the rules
+	// it does not follow otherwise, in the order reported by Validate.
+	Valid bool   `json:"valid"`
+	Rules []Rule `json:"rules,omitempty"`
+}
+
+// CorpusMismatch is a case of the corpus for which a validator did not return
+// the expected verdict.
+type CorpusMismatch struct {
+	Case CorpusCase
+
+	// Err is the error returned by the validator.
+	Err error
+}
+
+// policyCorpus returns the parsed policy corpus.
+var policyCorpus = sync.OnceValue(func() []CorpusCase {
+	var cases []CorpusCase
+	sc := bufio.NewScanner(strings.NewReader(policyCorpusList))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+
+		var c CorpusCase
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			panic("password: invalid policy corpus: " + err.Error())
+		}
+		cases = append(cases, c)
+	}
+	return cases
+})
+
+// PolicyCorpus returns the regression corpus of Policy.Validate: passwords,
+// policies and the expected verdicts, including edge cases such as multibyte
+// characters and non-ASCII digits.
+func PolicyCorpus() []CorpusCase {
+	cases := policyCorpus()
+	res := make([]CorpusCase, len(cases))
+	for i, c := range cases {
+		c.Policy.RequiredClasses = slices.Clone(c.Policy.RequiredClasses)
+		c.Rules = slices.Clone(c.Rules)
+		res[i] = c
+	}
+	return res
+}
+
+// RunPolicyCorpus runs the given validator against every case of
+// PolicyCorpus, and returns the cases for which it did not return the
+// expected verdict. A validator must return nil for valid passwords; if it
+// returns a *PolicyError, the rules it reports must also be the expected ones.
+//
+// Policy.Validate passes the corpus, so it can be used to check custom
+// validators and wrappers, such as one rejecting breached passwords, against
+// the same expectations.
+func RunPolicyCorpus(validate func(p Policy, pw string) error) []CorpusMismatch {
+	var mismatches []CorpusMismatch
+	for _, c := range PolicyCorpus() {
+		err := validate(c.Policy, c.Password)
+		if (err == nil) != c.Valid {
+			mismatches = append(mismatches, CorpusMismatch{Case: c, Err: err})
+			continue
+		}
+
+		var policyErr *PolicyError
+		if errors.As(err, &policyErr) && !slices.Equal(policyErr.Rules(), c.Rules) {
+			mismatches = append(mismatches, CorpusMismatch{Case: c, Err: err})
+		}
+	}
+	return mismatches
+}
@@ -0,0 +1,185 @@
+package password
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrInvalidStrengthModel is the error returned when a StrengthModel has a
+// parameter which is not positive.
+var ErrInvalidStrengthModel = errors.New("strength model parameters must be positive")
+
+// GuessRate is the number of guesses per second of an attack scenario.
+type GuessRate struct {
+	Scenario  string  `json:"scenario"`
+	PerSecond float64 `json:"per_second"`
+}
+
+// ClassGuesses is the number of guesses needed for a single character of every
+// class, when it is not part of a pattern.
+type ClassGuesses struct {
+	Lower  float64 `json:"lower"`
+	Upper  float64 `json:"upper"`
+	Digit  float64 `json:"digit"`
+	Symbol float64 `json:"symbol"`
+	Other  float64 `json:"other"`
+}
+
+// PatternGuesses is the number of guesses needed for the patterns reported by
+// Analyze. Runs, sequences and keyboard walks cost their weight per character,
+// since an attacker enumerates their starting point, direction and length.
+// Dictionary words cost their weight whatever their length, which is the size
+// of the dictionary times the variations tried for every word.
+type PatternGuesses struct {
+	Run            float64 `json:"run"`
+	Sequence       float64 `json:"sequence"`
+	KeyboardWalk   float64 `json:"keyboard_walk"`
+	DictionaryWord float64 `json:"dictionary_word"`
+}
+
+// StrengthModel is the set of parameters used to estimate how long a password
+// resists guessing. DefaultStrengthModel returns the built-in parameters;
+// security teams can export them as JSON, calibrate them to their threat
+// model, and load them back with LoadStrengthModel.
+type StrengthModel struct {
+	GuessRates []GuessRate    `json:"guess_rates"`
+	Classes    ClassGuesses   `json:"classes"`
+	Patterns   PatternGuesses `json:"patterns"`
+}
+
+// CrackEstimate is the estimated resistance of a password to guessing.
+type CrackEstimate struct {
+	// GuessesLog10 is the base-10 logarithm of the number of guesses needed
+	// to find the password.
+	GuessesLog10 float64
+
+	// Seconds is the time needed to find the password, in seconds, for every
+	// scenario of the model.
+	Seconds map[string]float64
+}
+
+// DefaultStrengthModel returns the built-in strength model. Its guess rates
+// are a throttled and an unthrottled online attack, and offline attacks on a
+// slow (such as bcrypt or Argon2) and a fast (such as unsalted SHA-1) hash.
+func DefaultStrengthModel() StrengthModel {
+	return StrengthModel{
+		GuessRates: []GuessRate{
+			{Scenario: "online_throttled", PerSecond: 100.0 / 3600},
+			{Scenario: "online_unthrottled", PerSecond: 10},
+			{Scenario: "offline_slow_hash", PerSecond: 1e4},
+			{Scenario: "offline_fast_hash", PerSecond: 1e10},
+		},
+		Classes: ClassGuesses{
+			Lower:  26,
+			Upper:  26,
+			Digit:  10,
+			Symbol: 33,
+			Other:  100,
+		},
+		Patterns: PatternGuesses{
+			Run:            95,
+			Sequence:       60,
+			KeyboardWalk:   200,
+			DictionaryWord: 1e4,
+		},
+	}
+}
+
+// LoadStrengthModel reads a strength model encoded as JSON. Parameters which
+// are absent keep their value from DefaultStrengthModel; guess rates, if
+// present, replace the default ones entirely.
+func LoadStrengthModel(r io.Reader) (StrengthModel, error) {
+	m := DefaultStrengthModel()
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&m); err != nil {
+		return StrengthModel{}, fmt.Errorf("failed to decode strength model: %w", err)
+	}
+	if err := m.Validate(); err != nil {
+		return StrengthModel{}, err
+	}
+	return m, nil
+}
+
+// Validate checks that all parameters of the model are positive and that
+// every scenario is named.
+func (m StrengthModel) Validate() error {
+	for _, r := range m.GuessRates {
+		if r.Scenario == "" || !(r.PerSecond > 0) {
+			return fmt.Errorf("%w: guess rate %+v", ErrInvalidStrengthModel, r)
+		}
+	}
+	for _, v := range []float64{
+		m.Classes.Lower, m.Classes.Upper, m.Classes.Digit, m.Classes.Symbol, m.Classes.Other,
+		m.Patterns.Run, m.Patterns.Sequence, m.Patterns.KeyboardWalk, m.Patterns.DictionaryWord,
+	} {
+		if !(v > 0) {
+			return ErrInvalidStrengthModel
+		}
+	}
+	return nil
+}
+
+// Estimate estimates how many guesses are needed to find the password, and how
+// long they take in every scenario. The password is split into the patterns
+// reported by Analyze and single characters, so that the number of guesses is
+// the smallest product of their costs.
+func (m StrengthModel) Estimate(pw string) CrackEstimate {
+	runes := []rune(pw)
+	a := Analyze(pw)
+
+	// ends[i] holds the patterns ending before rune i, with their cost.
+	type cover struct {
+		start int
+		log   float64
+	}
+	ends := make([][]cover, len(runes)+1)
+	add := func(patterns []Pattern, cost func(p Pattern) float64) {
+		for _, p := range patterns {
+			ends[p.Position+p.Length] = append(ends[p.Position+p.Length], cover{p.Position, math.Log10(cost(p))})
+		}
+	}
+	perChar := func(weight float64) func(Pattern) float64 {
+		return func(p Pattern) float64 { return weight * float64(p.Length) }
+	}
+	add(a.Runs, perChar(m.Patterns.Run))
+	add(a.Sequences, perChar(m.Patterns.Sequence))
+	add(a.KeyboardWalks, perChar(m.Patterns.KeyboardWalk))
+	add(a.DictionaryWords, func(Pattern) float64 { return m.Patterns.DictionaryWord })
+
+	// best[i] is the base-10 logarithm of the guesses for the first i runes.
+	best := make([]float64, len(runes)+1)
+	for i := 1; i <= len(runes); i++ {
+		best[i] = best[i-1] + math.Log10(m.Classes.guesses(ClassOf(runes[i-1])))
+		for _, c := range ends[i] {
+			best[i] = math.Min(best[i], best[c.start]+c.log)
+		}
+	}
+
+	e := CrackEstimate{
+		GuessesLog10: best[len(runes)],
+		Seconds:      make(map[string]float64, len(m.GuessRates)),
+	}
+	for _, r := range m.GuessRates {
+		e.Seconds[r.Scenario] = math.Pow(10, e.GuessesLog10) / r.PerSecond
+	}
+	return e
+}
+
+// guesses returns the guesses needed for a character of the given class.
+func (c ClassGuesses) guesses(class Class) float64 {
+	switch class {
+	case ClassLower:
+		return c.Lower
+	case ClassUpper:
+		return c.Upper
+	case ClassDigit:
+		return c.Digit
+	case ClassSymbol:
+		return c.Symbol
+	}
+	return c.Other
+}
@@ -0,0 +1,90 @@
+package password
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestStrengthModelEstimate(t *testing.T) {
+	t.Parallel()
+
+	m := DefaultStrengthModel()
+
+	cases := []struct {
+		pw   string
+		want float64
+	}{
+		{"", 0},
+		{"a", math.Log10(26)},
+		{"aB3!", math.Log10(26 * 26 * 10 * 33)},
+		{"aaaaaa", math.Log10(95 * 6)},
+		{"password", 4},
+		{"qwerty1x", math.Log10(200 * 6 * 10 * 26)},
+	}
+	for _, tc := range cases {
+		e := m.Estimate(tc.pw)
+		if math.Abs(e.GuessesLog10-tc.want) > 1e-9 {
+			t.Errorf("expected %q to need 10^%.3f guesses, got 10^%.3f", tc.pw, tc.want, e.GuessesLog10)
+		}
+	}
+
+	e := m.Estimate("password")
+	if got := e.Seconds["offline_fast_hash"]; math.Abs(got-1e-6) > 1e-12 {
+		t.Errorf("expected 1µs with a fast hash, got %g", got)
+	}
+	if len(e.Seconds) != len(m.GuessRates) {
+		t.Errorf("expected a time for every scenario, got %v", e.Seconds)
+	}
+
+	if weak, strong := m.Estimate("password1"), m.Estimate("x7#Kq9!vLm2@"); weak.GuessesLog10 >= strong.GuessesLog10 {
+		t.Errorf("expected random password to be stronger, got %v and %v", weak, strong)
+	}
+}
+
+func TestLoadStrengthModel(t *testing.T) {
+	t.Parallel()
+
+	b, err := json.Marshal(DefaultStrengthModel())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadStrengthModel(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Estimate("password").GuessesLog10 != 4 {
+		t.Errorf("expected the exported model to load unchanged, got %+v", m)
+	}
+
+	m, err = LoadStrengthModel(strings.NewReader(`{
+		"guess_rates": [{"scenario": "gpu_cluster", "per_second": 1e12}],
+		"patterns": {"dictionary_word": 1e6}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := m.Estimate("password")
+	if e.GuessesLog10 != 6 || len(e.Seconds) != 1 || e.Seconds["gpu_cluster"] != 1e-6 {
+		t.Errorf("unexpected calibrated estimate %+v", e)
+	}
+	if m.Classes != DefaultStrengthModel().Classes {
+		t.Errorf("expected absent parameters to keep their defaults, got %+v", m.Classes)
+	}
+
+	for _, s := range []string{
+		`{"classes": {"lower": 0}}`,
+		`{"guess_rates": [{"scenario": "", "per_second": 1}]}`,
+		`{"unknown": 1}`,
+	} {
+		if _, err := LoadStrengthModel(strings.NewReader(s)); err == nil {
+			t.Errorf("expected %s to be rejected", s)
+		}
+	}
+	if _, err := LoadStrengthModel(strings.NewReader(`{"patterns": {"run": -1}}`)); !errors.Is(err, ErrInvalidStrengthModel) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidStrengthModel)
+	}
+}
@@ -0,0 +1,30 @@
+package password
+
+// EBCDICSymbols is the list of symbols with the same code point in all common
+// EBCDIC code pages, such as 037, 273, 277, 280, 284, 285, 297, 500 and 1047.
+// Symbols like !, #, $, @, [ and ] are assigned differently from one code page
+// to another and are corrupted when a password is translated with a code page
+// other than the one it was typed with. The quotes, although invariant, are
+// excluded since they delimit strings in JCL and REXX.
+const EBCDICSymbols = "%&()*+,-./:;<=>?_"
+
+// WithEBCDICCharset creates a new Generator from another Generator which only
+// generates characters with stable EBCDIC mappings: LowerLetters,
+// UpperLetters, Digits and EBCDICSymbols. It is meant for passwords sent to
+// z/OS and other mainframe systems. Many of them (such as RACF without mixed
+// case passwords) fold passwords to uppercase; use Input.NoUpper and the
+// Uppercase post-processor for those.
+func (g Generator) WithEBCDICCharset() Generator {
+	return g.With(WithEBCDICCharset())
+}
+
+// WithEBCDICCharset returns an Option setting the EBCDIC-safe charsets, as
+// Generator.WithEBCDICCharset.
+func WithEBCDICCharset() Option {
+	return func(g *Generator) {
+		g.lowerLetters = LowerLetters
+		g.upperLetters = UpperLetters
+		g.digits = Digits
+		g.symbols = EBCDICSymbols
+	}
+}
@@ -0,0 +1,27 @@
+package password
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratorWithEBCDICCharset(t *testing.T) {
+	t.Parallel()
+
+	allowed := LowerLetters + UpperLetters + Digits + EBCDICSymbols
+	gen := NewGenerator().WithEBCDICCharset()
+	for i := 0; i < N; i++ {
+		res, err := gen.Generate(Input{
+			Length:  16,
+			Digits:  4,
+			Symbols: 4,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Trim(res, allowed) != "" {
+			t.Errorf("%q should only contain EBCDIC invariant characters", res)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package password
+
+import (
+	"math"
+	"slices"
+	"unicode/utf8"
+)
+
+// Entropy estimates the entropy, in bits, of passwords generated with the
+// given input: the base-2 logarithm of the number of distinct passwords the
+// Generator can produce. Unlike the naive length*log2(alphabet) calculation,
+// it accounts for the constraints which shrink the keyspace: the exact number
+// of digits and symbols, characters which may not repeat, characters removed
+// from the Generator charsets, PreserveClassOrder, MaxSameClassRun and
+// RequireFromEachClass. For ranges of digits or symbols, it sums the passwords
+// of every count within the ranges.
+//
+// Filters and post-processors are not accounted for. Filters reject a small
+// part of the keyspace, and post-processors change what the password looks
+// like rather than how many passwords are possible. Since layouts satisfying
+// MaxSameClassRun are not all equally likely, the estimate for such inputs is
+// an upper bound; inputs too long for their layouts to be counted quickly
+// return ErrSameClassRunTooLarge.
+func (g Generator) Entropy(input Input) (float64, error) {
+	if !input.ranged() {
+		return g.exactEntropy(input)
+	}
+
+	t, err := g.compositions(input)
+	if err != nil {
+		return 0, err
+	}
+	return slices.Max(t.bits) + math.Log2(t.cumulative[len(t.cumulative)-1]), nil
+}
+
+// exactEntropy returns the entropy of passwords generated with the given input
+// with exact counts of digits and symbols.
+func (g Generator) exactEntropy(input Input) (float64, error) {
+	return g.exactEntropyLayouts(input, nil)
+}
+
+// exactEntropyLayouts is exactEntropy counting the layouts satisfying
+// MaxSameClassRun with the given table, which spans the counts of the input, or
+// with a new table if it is nil.
+func (g Generator) exactEntropyLayouts(input Input, layouts *runLayoutTable) (float64, error) {
+	chars, err := g.check(input)
+	if err != nil {
+		return 0, err
+	}
+
+	counts := [3]int{chars, input.Digits, input.Symbols}
+	bits := charsEntropy(utf8.RuneCountInString(g.letters(input)), chars, input.AllowRepeat)
+	if input.RequireFromEachClass && !input.NoUpper {
+		bits = requiredLettersEntropy(utf8.RuneCountInString(g.lowerLetters), utf8.RuneCountInString(g.upperLetters), chars, input.AllowRepeat)
+	}
+	bits += charsEntropy(utf8.RuneCountInString(g.digits), input.Digits, input.AllowRepeat) +
+		charsEntropy(utf8.RuneCountInString(g.symbols), input.Symbols, input.AllowRepeat)
+
+	switch {
+	case input.PreserveClassOrder:
+		// The layout is fixed.
+		for _, n := range counts {
+			if input.MaxSameClassRun > 0 && n > input.MaxSameClassRun {
+				return 0, ErrSameClassRunUnsatisfiable
+			}
+		}
+	case input.MaxSameClassRun > 0:
+		if layouts == nil {
+			if layouts, err = newRunLayoutTable(counts, input.Length, input.MaxSameClassRun); err != nil {
+				return 0, err
+			}
+		}
+		n := layouts.log2(counts)
+		if math.IsInf(n, -1) {
+			return 0, ErrSameClassRunUnsatisfiable
+		}
+		bits += n
+	default:
+		bits += log2Factorial(input.Length) -
+			log2Factorial(chars) - log2Factorial(input.Digits) - log2Factorial(input.Symbols)
+	}
+	return bits, nil
+}
+
+// charsEntropy returns the entropy of n characters chosen from an alphabet of
+// the given size, with or without repetition.
+func charsEntropy(alphabet, n int, allowRepeat bool) float64 {
+	if n == 0 {
+		return 0
+	}
+	if allowRepeat {
+		return float64(n) * math.Log2(float64(alphabet))
+	}
+	return log2Factorial(alphabet) - log2Factorial(alphabet-n)
+}
+
+// requiredLettersEntropy returns the entropy of n letters chosen from lower
+// lowercase and upper uppercase letters, with or without repetition, which
+// include at least one letter of each case.
+func requiredLettersEntropy(lower, upper, n int, allowRepeat bool) float64 {
+	total := charsEntropy(lower+upper, n, allowRepeat)
+
+	// Letters of a single case are rejected.
+	rejected := 0.0
+	for _, size := range []int{lower, upper} {
+		if allowRepeat || size >= n {
+			rejected += math.Exp2(charsEntropy(size, n, allowRepeat) - total)
+		}
+	}
+	return total + math.Log2(1-rejected)
+}
+
+// log2Factorial returns log2(n!).
+func log2Factorial(n int) float64 {
+	v, _ := math.Lgamma(float64(n) + 1)
+	return v / math.Ln2
+}
+
+// maxRunLayoutCells bounds the work and memory of counting layouts with
+// newRunLayoutTable, so that inputs from untrusted policies cannot exhaust the
+// CPU or memory.
+const maxRunLayoutCells = 1 << 24
+
+// runLayoutTable holds the numbers of layouts of n characters of three classes,
+// with up to bounds[i] characters of class i, in which no class appears more
+// than maxRun times in a row.
+type runLayoutTable struct {
+	n      int
+	bounds [3]int
+
+	// axes[i] is the class counted along axis i of plane; the class with the
+	// largest bound is the third axis, implied by n.
+	axes [3]int
+
+	// free is set when maxRun is at least every bound, so that the layouts
+	// are the multinomial coefficients.
+	free bool
+
+	// plane holds the base-2 logarithms of the numbers of layouts, by the
+	// counts of the classes of the first two axes.
+	plane []float64
+}
+
+// newRunLayoutTable counts the layouts of n characters within the given bounds.
+// Layouts are built as sequences of blocks of a single class, each of at most
+// maxRun characters and of another class than the previous block. The counts
+// are computed one length at a time, keeping the lengths of the last maxRun
+// blocks, and scaled to float64 per length, so that they never overflow.
+func newRunLayoutTable(bounds [3]int, n, maxRun int) (*runLayoutTable, error) {
+	t := &runLayoutTable{n: n, bounds: bounds, axes: [3]int{0, 1, 2}}
+	slices.SortFunc(t.axes[:], func(a, b int) int { return bounds[a] - bounds[b] })
+	if maxRun >= slices.Max(bounds[:]) {
+		t.free = true
+		return t, nil
+	}
+
+	var b [3]int
+	for i, c := range t.axes {
+		b[i] = bounds[c]
+	}
+	size := (b[0] + 1) * (b[1] + 1)
+	if float64(size)*float64(b[2]+1)*3*float64(maxRun+1) > maxRunLayoutCells {
+		return nil, ErrSameClassRunTooLarge
+	}
+
+	// layers[l%len(layers)] holds, for every cell of the plane, the scaled
+	// numbers of sequences of l characters ending with a block of each
+	// class, and scale[l] is their base-2 exponent.
+	layers := make([][]float64, maxRun+1)
+	for i := range layers {
+		layers[i] = make([]float64, size*3)
+	}
+	scale := make([]float64, n+1)
+	// others returns the scaled number of sequences of l characters with the
+	// counts u0 and u1 whose last block is not of the class of axis c. The
+	// empty sequence can be followed by any block.
+	others := func(l, u0, u1, c int) float64 {
+		if l == 0 {
+			return 1
+		}
+		e := layers[l%len(layers)][(u0*(b[1]+1)+u1)*3:]
+		return e[0] + e[1] + e[2] - e[c]
+	}
+
+	factors := make([]float64, maxRun+1)
+	for l := 1; l <= n; l++ {
+		cur := layers[l%len(layers)]
+		clear(cur)
+		for r := 1; r <= min(maxRun, l); r++ {
+			factors[r] = math.Exp2(scale[l-r] - scale[l-1])
+		}
+		top := 0.0
+		for u0 := 0; u0 <= min(b[0], l); u0++ {
+			for u1 := 0; u1 <= min(b[1], l-u0); u1++ {
+				u := [3]int{u0, u1, l - u0 - u1}
+				if u[2] > b[2] {
+					continue
+				}
+				for c := 0; c < 3; c++ {
+					v := 0.0
+					for r := 1; r <= min(maxRun, u[c]); r++ {
+						p := u
+						p[c] -= r
+						v += others(l-r, p[0], p[1], c) * factors[r]
+					}
+					cur[(u0*(b[1]+1)+u1)*3+c] = v
+					top = max(top, v)
+				}
+			}
+		}
+
+		scale[l] = scale[l-1]
+		if top > 0 {
+			scale[l] += math.Log2(top)
+			for i := range cur {
+				cur[i] /= top
+			}
+		}
+	}
+
+	t.plane = make([]float64, size)
+	for i := range t.plane {
+		e := layers[n%len(layers)][i*3:]
+		v := e[0] + e[1] + e[2]
+		if n == 0 {
+			v = 1
+		}
+		t.plane[i] = math.Log2(v) + scale[n]
+	}
+	return t, nil
+}
+
+// log2 returns the base-2 logarithm of the number of layouts with the given
+// counts of every class, or -Inf if there is none.
+func (t *runLayoutTable) log2(counts [3]int) float64 {
+	if counts[0]+counts[1]+counts[2] != t.n {
+		return math.Inf(-1)
+	}
+	for i, c := range counts {
+		if c < 0 || c > t.bounds[i] {
+			return math.Inf(-1)
+		}
+	}
+	if t.free {
+		return log2Factorial(t.n) - log2Factorial(counts[0]) - log2Factorial(counts[1]) - log2Factorial(counts[2])
+	}
+	u0, u1 := counts[t.axes[0]], counts[t.axes[1]]
+	return t.plane[u0*(t.bounds[t.axes[1]]+1)+u1]
+}
+
+// runLayouts returns the base-2 logarithm of the number of sequences in which
+// class i appears counts[i] times and no class appears more than maxRun times
+// in a row, or -Inf if there is none.
+func runLayouts(counts [3]int, maxRun int) (float64, error) {
+	t, err := newRunLayoutTable(counts, counts[0]+counts[1]+counts[2], maxRun)
+	if err != nil {
+		return 0, err
+	}
+	return t.log2(counts), nil
+}
@@ -0,0 +1,127 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	// ErrWordlistTooShort is the error returned when a wordlist has fewer than
+	// two words and cannot encode any entropy.
+	ErrWordlistTooShort = errors.New("wordlist must contain at least two words")
+
+	// ErrWordlistDuplicates is the error returned when a wordlist contains the
+	// same word more than once and cannot be decoded unambiguously.
+	ErrWordlistDuplicates = errors.New("wordlist contains duplicate words")
+
+	// ErrUnknownWord is the error returned when a passphrase contains a word
+	// which is not in the wordlist.
+	ErrUnknownWord = errors.New("word is not in the wordlist")
+
+	// ErrPassphraseLength is the error returned when a passphrase has the
+	// wrong number of words for the requested entropy size.
+	ErrPassphraseLength = errors.New("passphrase has the wrong number of words for the entropy size")
+
+	// ErrEntropySize is the error returned when the requested entropy size is
+	// negative.
+	ErrEntropySize = errors.New("entropy size must not be negative")
+
+	// ErrPassphraseOverflow is the error returned when a passphrase encodes a
+	// value which does not fit in the requested entropy size.
+	ErrPassphraseOverflow = errors.New("passphrase encodes a value larger than the entropy size")
+)
+
+// PassphraseFromEntropy deterministically renders the given bytes as words from
+// the wordlist, so that existing keys can be written down for human backup.
+// The number of words depends only on len(b) and the size of the wordlist.
+// Use EntropyFromPassphrase to recover the bytes.
+//
+// Unlike BIP39, any wordlist of unique words can be used and no checksum is
+// included; see Wordlist.AppendChecksum.
+func PassphraseFromEntropy(b []byte, w Wordlist) ([]string, error) {
+	if _, err := w.index(); err != nil {
+		return nil, err
+	}
+
+	base := big.NewInt(int64(len(w)))
+	n := new(big.Int).SetBytes(b)
+	size, err := wordsForEntropy(len(b), len(w))
+	if err != nil {
+		return nil, err
+	}
+	words := make([]string, size)
+
+	mod := new(big.Int)
+	for i := len(words) - 1; i >= 0; i-- {
+		n.DivMod(n, base, mod)
+		words[i] = w[mod.Int64()]
+	}
+	return words, nil
+}
+
+// EntropyFromPassphrase is the inverse of PassphraseFromEntropy. It recovers
+// size bytes from the given words.
+func EntropyFromPassphrase(words []string, w Wordlist, size int) ([]byte, error) {
+	index, err := w.index()
+	if err != nil {
+		return nil, err
+	}
+
+	want, err := wordsForEntropy(size, len(w))
+	if err != nil {
+		return nil, err
+	}
+	if len(words) != want {
+		return nil, ErrPassphraseLength
+	}
+
+	base := big.NewInt(int64(len(w)))
+	n := new(big.Int)
+	for _, word := range words {
+		i, ok := index[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownWord, word)
+		}
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+
+	if (n.BitLen()+7)/8 > size {
+		return nil, ErrPassphraseOverflow
+	}
+	return n.FillBytes(make([]byte, size)), nil
+}
+
+// index returns a map from every word of the wordlist to its position.
+func (w Wordlist) index() (map[string]int, error) {
+	if len(w) < 2 {
+		return nil, ErrWordlistTooShort
+	}
+
+	index := make(map[string]int, len(w))
+	for i, word := range w {
+		if _, ok := index[word]; ok {
+			return nil, ErrWordlistDuplicates
+		}
+		index[word] = i
+	}
+	return index, nil
+}
+
+// wordsForEntropy returns the smallest number of words from a wordlist of the
+// given size which can encode every value of size bytes.
+func wordsForEntropy(size, words int) (int, error) {
+	if size < 0 {
+		return 0, ErrEntropySize
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(size)*8)
+	base := big.NewInt(int64(words))
+
+	n := 0
+	for v := big.NewInt(1); v.Cmp(limit) < 0; v.Mul(v, base) {
+		n++
+	}
+	return n, nil
+}
@@ -0,0 +1,102 @@
+package password
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func testWordlist(tb testing.TB, n int) Wordlist {
+	tb.Helper()
+
+	list := make(Wordlist, n)
+	for i := range list {
+		list[i] = fmt.Sprintf("w%04d", i)
+	}
+	return list
+}
+
+func TestPassphraseFromEntropy(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round_trip", func(t *testing.T) {
+		t.Parallel()
+
+		for _, words := range []int{2, 7, 2048, 7776} {
+			list := testWordlist(t, words)
+			for _, size := range []int{0, 1, 3, 16, 32} {
+				b := make([]byte, size)
+				if _, err := rand.Read(b); err != nil {
+					t.Fatal(err)
+				}
+
+				phrase, err := PassphraseFromEntropy(b, list)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got, err := EntropyFromPassphrase(phrase, list, size)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(got, b) {
+					t.Errorf("expected %x to be %x", got, b)
+				}
+			}
+		}
+	})
+
+	t.Run("leading_zeros", func(t *testing.T) {
+		t.Parallel()
+
+		list := testWordlist(t, 2048)
+		b := []byte{0, 0, 0, 1}
+
+		phrase, err := PassphraseFromEntropy(b, list)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(phrase) != 3 {
+			t.Errorf("expected %d words, got %d", 3, len(phrase))
+		}
+
+		got, err := EntropyFromPassphrase(phrase, list, len(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, b) {
+			t.Errorf("expected %x to be %x", got, b)
+		}
+	})
+
+	t.Run("invalid_wordlist", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := PassphraseFromEntropy([]byte{1}, Wordlist{"a"}); !errors.Is(err, ErrWordlistTooShort) {
+			t.Errorf("expected %q to be %q", err, ErrWordlistTooShort)
+		}
+		if _, err := PassphraseFromEntropy([]byte{1}, Wordlist{"a", "a"}); !errors.Is(err, ErrWordlistDuplicates) {
+			t.Errorf("expected %q to be %q", err, ErrWordlistDuplicates)
+		}
+	})
+
+	t.Run("invalid_passphrase", func(t *testing.T) {
+		t.Parallel()
+
+		list := testWordlist(t, 7)
+		if _, err := EntropyFromPassphrase([]string{"w0001", "nope", "w0002"}, list, 1); !errors.Is(err, ErrUnknownWord) {
+			t.Errorf("expected %q to be %q", err, ErrUnknownWord)
+		}
+		if _, err := EntropyFromPassphrase([]string{"w0001"}, list, 1); !errors.Is(err, ErrPassphraseLength) {
+			t.Errorf("expected %q to be %q", err, ErrPassphraseLength)
+		}
+		if _, err := EntropyFromPassphrase([]string{"w0006", "w0006", "w0006"}, list, 1); !errors.Is(err, ErrPassphraseOverflow) {
+			t.Errorf("expected %q to be %q", err, ErrPassphraseOverflow)
+		}
+		if _, err := EntropyFromPassphrase([]string{"w0001"}, list, -1); !errors.Is(err, ErrEntropySize) {
+			t.Errorf("expected %q to be %q", err, ErrEntropySize)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package password
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func testCountLayouts(tb testing.TB, counts []int, maxRun int) int {
+	tb.Helper()
+
+	var total int
+	for _, c := range counts {
+		total += c
+	}
+
+	var count func(remaining []int, last, run, left int) int
+	count = func(remaining []int, last, run, left int) int {
+		if left == 0 {
+			return 1
+		}
+
+		var n int
+		for c := range remaining {
+			if remaining[c] == 0 || (c == last && run == maxRun) {
+				continue
+			}
+
+			nextRun := 1
+			if c == last {
+				nextRun = run + 1
+			}
+
+			remaining[c]--
+			n += count(remaining, c, nextRun, left-1)
+			remaining[c]++
+		}
+		return n
+	}
+	return count(counts, -1, 0, total)
+}
+
+func TestGeneratorEntropy(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator()
+
+	cases := []struct {
+		name  string
+		gen   Generator
+		input Input
+		want  float64
+	}{
+		{
+			name:  "empty",
+			gen:   gen,
+			input: Input{},
+			want:  0,
+		},
+		{
+			name:  "repeats",
+			gen:   gen,
+			input: Input{Length: 8, AllowRepeat: true},
+			want:  8 * math.Log2(52),
+		},
+		{
+			name:  "no_repeats",
+			gen:   gen,
+			input: Input{Length: 3, NoUpper: true},
+			want:  math.Log2(26 * 25 * 24),
+		},
+		{
+			name:  "classes",
+			gen:   gen,
+			input: Input{Length: 4, Digits: 1, Symbols: 1, AllowRepeat: true},
+			want:  2*math.Log2(52) + math.Log2(10) + math.Log2(30) + math.Log2(12),
+		},
+		{
+			name:  "preserve_class_order",
+			gen:   gen,
+			input: Input{Length: 4, Digits: 1, Symbols: 1, AllowRepeat: true, PreserveClassOrder: true},
+			want:  2*math.Log2(52) + math.Log2(10) + math.Log2(30),
+		},
+		{
+			name:  "max_same_class_run",
+			gen:   gen,
+			input: Input{Length: 4, Digits: 2, AllowRepeat: true, MaxSameClassRun: 1},
+			want:  2*math.Log2(52) + 2*math.Log2(10) + 1,
+		},
+		{
+			name:  "excluded_characters",
+			gen:   gen.WithoutPhoneticallyAmbiguous(),
+			input: Input{Length: 8, NoUpper: true, AllowRepeat: true},
+			want:  8 * math.Log2(13),
+		},
+		{
+			name:  "require_from_each_class",
+			gen:   gen,
+			input: Input{Length: 3, AllowRepeat: true, RequireFromEachClass: true},
+			want:  math.Log2(52*52*52 - 2*26*26*26),
+		},
+		{
+			name:  "require_from_each_class_no_repeats",
+			gen:   gen,
+			input: Input{Length: 2, RequireFromEachClass: true},
+			want:  math.Log2(52*51 - 2*26*25),
+		},
+		{
+			name:  "require_from_each_class_no_upper",
+			gen:   gen,
+			input: Input{Length: 3, NoUpper: true, RequireFromEachClass: true},
+			want:  math.Log2(26 * 25 * 24),
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := tc.gen.Entropy(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-tc.want) > 1e-9 {
+				t.Errorf("expected %f to be %f", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := gen.Entropy(Input{Digits: 1}); !errors.Is(err, ErrExceedsTotalLength) {
+			t.Errorf("expected %q to be %q", err, ErrExceedsTotalLength)
+		}
+		if _, err := gen.Entropy(Input{Length: 15, Digits: 2, Symbols: 2, MaxSameClassRun: 2}); !errors.Is(err, ErrSameClassRunUnsatisfiable) {
+			t.Errorf("expected %q to be %q", err, ErrSameClassRunUnsatisfiable)
+		}
+	})
+}
+
+func TestRunLayouts(t *testing.T) {
+	t.Parallel()
+
+	for _, counts := range [][]int{{3, 2, 1}, {5, 1, 1}, {4, 4, 0}, {6, 0, 0}, {2, 3, 4}} {
+		for maxRun := 1; maxRun <= 4; maxRun++ {
+			want := testCountLayouts(t, append([]int(nil), counts...), maxRun)
+
+			got, err := runLayouts([3]int(counts), maxRun)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-math.Log2(float64(want))) > 1e-9 && !(want == 0 && math.IsInf(got, -1)) {
+				t.Errorf("expected %d layouts of %v with runs of %d, got 2^%v", want, counts, maxRun, got)
+			}
+		}
+	}
+}
+
+func TestEntropyLargeSameClassRun(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator()
+	bits, err := gen.Entropy(Input{Length: 256, Digits: 80, Symbols: 80, AllowRepeat: true, MaxSameClassRun: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	free, err := gen.Entropy(Input{Length: 256, Digits: 80, Symbols: 80, AllowRepeat: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !(bits < free && bits > free-64) {
+		t.Errorf("expected %v bits to be slightly less than %v", bits, free)
+	}
+
+	if _, err := gen.Entropy(Input{Length: 4096, Digits: 1000, Symbols: 1000, AllowRepeat: true, MaxSameClassRun: 4}); !errors.Is(err, ErrSameClassRunTooLarge) {
+		t.Errorf("expected %q to be %q", err, ErrSameClassRunTooLarge)
+	}
+	if _, err := gen.Entropy(Input{Length: 4096, Digits: 1000, Symbols: 1000, AllowRepeat: true, MaxSameClassRun: 4096}); err != nil {
+		t.Errorf("expected a run limit longer than the input to be free, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// EnvUnsafe is the list of characters which cannot be written to a .env file
+// in a way all dotenv parsers and POSIX shells read back identically: the
+// single quote, which ends the quoted value, and the backslash, which some
+// dotenv parsers treat as an escape inside single quotes.
+const EnvUnsafe = `'\`
+
+var (
+	// ErrInvalidEnvKey is the error returned when an environment variable
+	// name is not made of letters, digits and underscores, or starts with a
+	// digit.
+	ErrInvalidEnvKey = errors.New("invalid environment variable name")
+
+	// ErrNotEnvSafe is the error returned when a value or a charset contains
+	// an EnvUnsafe or a control character.
+	ErrNotEnvSafe = errors.New("character cannot be represented in a .env file")
+)
+
+// FormatEnv returns the line KEY='password' of a .env file. Single quotes keep
+// every other character literal for dotenv parsers (Node, Ruby, Python, Go)
+// and for POSIX shells sourcing the file, so that '$', '#', '"', '`' and
+// spaces need no escaping. A password containing EnvUnsafe or control
+// characters cannot be represented this way, and ErrNotEnvSafe is returned;
+// generate it with a Generator checked by CheckEnvSafe, or built with
+// WithoutEnvUnsafe.
+func FormatEnv(key, password string) (string, error) {
+	if !isEnvKey(key) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidEnvKey, key)
+	}
+	if err := checkEnvSafe("password", password); err != nil {
+		return "", err
+	}
+	return key + "='" + password + "'", nil
+}
+
+// isEnvKey reports whether key is a portable environment variable name.
+func isEnvKey(key string) bool {
+	if key == "" || (key[0] >= '0' && key[0] <= '9') {
+		return false
+	}
+	for _, r := range key {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
+
+// WithoutEnvUnsafe creates a new Generator from another Generator with all
+// EnvUnsafe characters removed from its symbols, so that every password it
+// generates can be written with FormatEnv.
+func (g Generator) WithoutEnvUnsafe() Generator {
+	return g.With(WithoutEnvUnsafe())
+}
+
+// WithoutEnvUnsafe returns an Option removing EnvUnsafe symbols, as
+// Generator.WithoutEnvUnsafe.
+func WithoutEnvUnsafe() Option {
+	return func(g *Generator) {
+		g.symbols = removeChars(g.symbols, EnvUnsafe)
+	}
+}
+
+// CheckEnvSafe verifies that every password the Generator can produce can be
+// written with FormatEnv, returning ErrNotEnvSafe naming the first charset
+// with an unsafe character. The default symbols contain a backslash and are
+// not safe. Post-processors are not checked.
+func (g Generator) CheckEnvSafe() error {
+	for _, cs := range []struct{ name, chars string }{
+		{"lower letters", g.lowerLetters},
+		{"upper letters", g.upperLetters},
+		{"digits", g.digits},
+		{"symbols", g.symbols},
+	} {
+		if err := checkEnvSafe(cs.name, cs.chars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkEnvSafe verifies that s can be written with FormatEnv, naming it in the
+// error.
+func checkEnvSafe(name, s string) error {
+	for _, r := range s {
+		if strings.ContainsRune(EnvUnsafe, r) || unicode.IsControl(r) {
+			return fmt.Errorf("%w: %q in %s", ErrNotEnvSafe, r, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormatEnv(t *testing.T) {
+	t.Parallel()
+
+	line, err := FormatEnv("DB_PASSWORD", "a$b #c\"d`e f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `DB_PASSWORD='a$b #c"d` + "`" + `e f'`; line != want {
+		t.Errorf("expected %s, got %s", want, line)
+	}
+
+	for _, pw := range []string{"it's", `back\slash`, "new\nline"} {
+		if _, err := FormatEnv("KEY", pw); !errors.Is(err, ErrNotEnvSafe) {
+			t.Errorf("expected %v to be %v for %q", err, ErrNotEnvSafe, pw)
+		}
+	}
+	for _, key := range []string{"", "1KEY", "MY-KEY", "KEY "} {
+		if _, err := FormatEnv(key, "secret"); !errors.Is(err, ErrInvalidEnvKey) {
+			t.Errorf("expected %v to be %v for %q", err, ErrInvalidEnvKey, key)
+		}
+	}
+}
+
+func TestGeneratorCheckEnvSafe(t *testing.T) {
+	t.Parallel()
+
+	if err := NewGenerator().CheckEnvSafe(); !errors.Is(err, ErrNotEnvSafe) {
+		t.Errorf("expected %v to be %v", err, ErrNotEnvSafe)
+	}
+
+	gen := NewGenerator().WithoutEnvUnsafe()
+	if err := gen.CheckEnvSafe(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < N; i++ {
+		res, err := gen.Generate(Input{Length: 32, Digits: 4, Symbols: 16})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := FormatEnv("KEY", res); err != nil {
+			t.Errorf("expected %q to be representable, got %v", res, err)
+		}
+	}
+}
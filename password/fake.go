@@ -0,0 +1,40 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FakePrefix is the prefix of the passwords returned by GenerateFake, marking
+// them as not secret.
+const FakePrefix = "FAKE-"
+
+// fakeKey is the public key of the stream of randomness of fake passwords.
+const fakeKey = "go-password fake v1"
+
+// GenerateFake returns a fake password for documentation, demo environments
+// and screenshots: FakePrefix followed by a password generated with the
+// charsets of the Generator as described by input. Its randomness comes from a
+// public stream derived from the input, so the same input always gives the
+// same fake, which is not secret and must never be used as a password.
+//
+// Fakes are not counted in the Generator Stats.
+func (g Generator) GenerateFake(input Input) (string, error) {
+	r := NewDerivedReader([]byte(fakeKey), fmt.Sprintf("%+v", input))
+	res, err := g.WithReader(r).generateFiltered(input)
+	if err != nil {
+		return "", err
+	}
+	return FakePrefix + res, nil
+}
+
+// GenerateFake is the package shortcut for Generator.GenerateFake.
+func GenerateFake(input Input) (string, error) {
+	return NewGenerator().GenerateFake(input)
+}
+
+// IsFake reports whether the password was returned by GenerateFake, so that
+// configuration checks can refuse fakes outside of demo environments.
+func IsFake(pw string) bool {
+	return strings.HasPrefix(pw, FakePrefix)
+}
@@ -0,0 +1,47 @@
+package password
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateFake(t *testing.T) {
+	t.Parallel()
+
+	input := Input{Length: 16, Digits: 3, Symbols: 3}
+	res, err := GenerateFake(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsFake(res) || len(res) != len(FakePrefix)+16 {
+		t.Fatalf("expected %q to be a prefixed fake of 16 characters", res)
+	}
+	pw := strings.TrimPrefix(res, FakePrefix)
+	if a := Analyze(pw); a.Counts[ClassDigit] != 3 || a.Counts[ClassSymbol] != 3 {
+		t.Errorf("expected %q to follow the input", pw)
+	}
+
+	for i := 0; i < 10; i++ {
+		if again, err := GenerateFake(input); err != nil || again != res {
+			t.Fatalf("expected %q to be %q", again, res)
+		}
+	}
+	if other, _ := GenerateFake(Input{Length: 16, Digits: 4, Symbols: 3}); other == res {
+		t.Errorf("expected distinct inputs to give distinct fakes, got %q", other)
+	}
+
+	g := NewGenerator().WithStats()
+	if _, err := g.GenerateFake(input); err != nil {
+		t.Fatal(err)
+	}
+	if st := g.Stats(); st.Calls != 0 {
+		t.Errorf("expected fakes not to be counted, got %+v", st)
+	}
+
+	if _, err := GenerateFake(Input{Length: 2, Digits: 3}); err == nil {
+		t.Error("expected an invalid input to fail")
+	}
+	if IsFake("hunter2") {
+		t.Error("expected a real password not to be fake")
+	}
+}
@@ -0,0 +1,101 @@
+package password
+
+import (
+	"crypto/subtle"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tolerance selects the typing errors which VerifyFuzzy forgives. Tolerances
+// can be combined with |.
+//
+// Every forgiven variant is one more password an attacker guesses with the same
+// attempt. Accepting k variants of a password makes each online guess up to
+// k+1 times as likely to succeed, which costs up to log2(k+1) bits of entropy
+// against online attacks; offline attacks against the stored hash are not
+// affected. ToleranceFirstCase and ToleranceCapsLock add one variant each, for
+// at most 1.6 bits together. ToleranceAdjacentKey adds up to about 8 variants
+// per character, about 7 bits for a 16 character password, and should only be
+// combined with rate limiting.
+type Tolerance int
+
+const (
+	// ToleranceFirstCase forgives the wrong case of the first character, as
+	// caused by automatic capitalization on mobile keyboards.
+	ToleranceFirstCase Tolerance = 1 << iota
+
+	// ToleranceCapsLock forgives a password typed with caps lock on, that is
+	// with the case of every letter inverted.
+	ToleranceCapsLock
+
+	// ToleranceAdjacentKey forgives a single character typed with a key
+	// physically adjacent, on a US QWERTY keyboard, to the intended one.
+	ToleranceAdjacentKey
+)
+
+// VerifyFuzzy reports whether input is the stored password, or one of the
+// variants of it permitted by tolerance. A zero tolerance only accepts the
+// exact password. Every comparison takes constant time, but the number of
+// comparisons depends on input.
+//
+// Backends which store password hashes instead should hash and compare every
+// candidate returned by FuzzyCandidates.
+func VerifyFuzzy(input, stored string, tolerance Tolerance) bool {
+	ok := 0
+	for _, c := range FuzzyCandidates(input, tolerance) {
+		ok |= subtle.ConstantTimeCompare([]byte(c), []byte(stored))
+	}
+	return ok == 1
+}
+
+// FuzzyCandidates returns the input followed by the passwords the user may
+// have meant when typing it with the errors permitted by tolerance. The
+// candidates are unique.
+func FuzzyCandidates(input string, tolerance Tolerance) []string {
+	res := []string{input}
+	seen := map[string]struct{}{input: {}}
+	add := func(s string) {
+		if _, ok := seen[s]; !ok {
+			seen[s] = struct{}{}
+			res = append(res, s)
+		}
+	}
+
+	if tolerance&ToleranceFirstCase != 0 && input != "" {
+		r, size := utf8.DecodeRuneInString(input)
+		if f := flipCase(r); f != r {
+			add(string(f) + input[size:])
+		}
+	}
+
+	if tolerance&ToleranceCapsLock != 0 {
+		runes := []rune(input)
+		for i, r := range runes {
+			runes[i] = flipCase(r)
+		}
+		add(string(runes))
+	}
+
+	if tolerance&ToleranceAdjacentKey != 0 {
+		runes := []rune(input)
+		for i, r := range runes {
+			for _, n := range QWERTY.Neighbors(r) {
+				runes[i] = n
+				add(string(runes))
+			}
+			runes[i] = r
+		}
+	}
+	return res
+}
+
+// flipCase returns r in the opposite case, or r if it has no case.
+func flipCase(r rune) rune {
+	switch {
+	case unicode.IsUpper(r):
+		return unicode.ToLower(r)
+	case unicode.IsLower(r):
+		return unicode.ToUpper(r)
+	}
+	return r
+}
@@ -0,0 +1,68 @@
+package password
+
+import "testing"
+
+func TestVerifyFuzzy(t *testing.T) {
+	t.Parallel()
+
+	const stored = "Tr0ub4dor&3"
+
+	cases := []struct {
+		input     string
+		tolerance Tolerance
+		want      bool
+	}{
+		{stored, 0, true},
+		{"tr0ub4dor&3", 0, false},
+		{"tr0ub4dor&3", ToleranceFirstCase, true},
+		{"tR0UB4DOR&3", ToleranceFirstCase, false},
+		{"tR0UB4DOR&3", ToleranceCapsLock, true},
+		{"Tr0ub4dor&3", ToleranceCapsLock, true},
+		{"Tr0ub4for&3", ToleranceAdjacentKey, true},  // d -> f
+		{"Tr0ub4dor^3", ToleranceAdjacentKey, true},  // & -> ^, both shifted
+		{"Tr0ub4dor73", ToleranceAdjacentKey, false}, // & -> 7 changes shift
+		{"Tr0ub4fir&3", ToleranceAdjacentKey, false}, // two typos
+		{"Tr0ub4dpr&3", ToleranceAdjacentKey, true},  // o -> p
+		{"Tr0ub4dmr&3", ToleranceAdjacentKey, false}, // o -> m is not adjacent
+		{"tr0ub4for&3", ToleranceAdjacentKey | ToleranceFirstCase, false},
+		{"", ToleranceFirstCase | ToleranceCapsLock | ToleranceAdjacentKey, false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.input, func(t *testing.T) {
+			t.Parallel()
+
+			if got := VerifyFuzzy(tc.input, stored, tc.tolerance); got != tc.want {
+				t.Errorf("expected %t, got %t", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestFuzzyCandidates(t *testing.T) {
+	t.Parallel()
+
+	got := FuzzyCandidates("aB1", ToleranceFirstCase|ToleranceCapsLock)
+	want := []string{"aB1", "AB1", "Ab1"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	}
+
+	seen := make(map[string]struct{})
+	for _, c := range FuzzyCandidates("password", ToleranceAdjacentKey) {
+		if _, ok := seen[c]; ok {
+			t.Errorf("duplicate candidate %q", c)
+		}
+		seen[c] = struct{}{}
+	}
+	if _, ok := seen["passwird"]; !ok {
+		t.Error("expected adjacent key candidate passwird")
+	}
+}
@@ -13,9 +13,9 @@ package password
 import (
 	"crypto/rand"
 	"errors"
-	"fmt"
-	"math/big"
+	"io"
 	"strings"
+	"unicode/utf8"
 )
 
 const (
@@ -48,8 +48,31 @@ var (
 	// ErrSymbolsExceedsAvailable is the error returned with the number of symbols
 	// exceeds the number of available symbols and repeats are not allowed.
 	ErrSymbolsExceedsAvailable = errors.New("number of symbols exceeds available symbols and repeats are not allowed")
+
+	// ErrSameClassRunUnsatisfiable is the error returned when the letters,
+	// digits and symbols cannot be arranged without exceeding the maximum run of
+	// characters of the same class.
+	ErrSameClassRunUnsatisfiable = errors.New("characters cannot be arranged within the maximum run of the same class")
+
+	// ErrSameClassRunTooLarge is the error returned when the layouts
+	// satisfying MaxSameClassRun are too many to count for the entropy of an
+	// input, because it is too long.
+	ErrSameClassRunTooLarge = errors.New("input too large to count layouts within the maximum run of the same class")
+
+	// ErrFilterAttemptsExceeded is the error returned when no generated password
+	// was accepted by the filters within the maximum number of attempts.
+	ErrFilterAttemptsExceeded = errors.New("no generated password was accepted by the filters")
+
+	// ErrRequiredClassesUnsatisfiable is the error returned when the letters
+	// cannot include a lowercase and an uppercase letter, because there are
+	// too few of them or a charset is empty.
+	ErrRequiredClassesUnsatisfiable = errors.New("letters cannot include every required class")
 )
 
+// maxFilterAttempts is the maximum number of passwords generated before giving
+// up when filters reject them.
+const maxFilterAttempts = 1000
+
 // Generator is the stateful generator which can be used to customize the list
 // of letters, digits, and/or symbols.
 type Generator struct {
@@ -57,6 +80,14 @@ type Generator struct {
 	upperLetters string
 	digits       string
 	symbols      string
+	reader       io.Reader
+	filters      []func(string) bool
+	latin1       bool
+	stats        *statsCounter
+	defaults     *Input
+
+	postProcessors []PostProcessor
+	breachChecker  BreachChecker
 }
 
 // Input used to define input parameters for the generator.
@@ -66,137 +97,282 @@ type Input struct {
 	Symbols     int
 	NoUpper     bool
 	AllowRepeat bool
-	_           struct{}
+
+	PreserveClassOrder   bool
+	MaxSameClassRun      int
+	RequireFromEachClass bool
+
+	// MinDigits and MaxDigits, if either is set, define a range of digits
+	// instead of the exact Digits, which must then be zero. The number of
+	// digits of every password is chosen at random within the range, so that
+	// every password the range allows is equally likely; with MaxSameClassRun,
+	// layouts are not all equally likely, as for exact counts. A zero
+	// MaxDigits means up to Length. MinSymbols and MaxSymbols do the same for
+	// symbols. Ranges allowing more than 16384 combinations of counts return
+	// ErrCountRangeTooLarge.
+	MinDigits  int
+	MaxDigits  int
+	MinSymbols int
+	MaxSymbols int
+	_          struct{}
 }
 
-// NewGenerator creates a new Generator from the specified configuration. If no
-// input is given, all the default values are used. This function is safe for
-// concurrent use.
-func NewGenerator() Generator {
-	return Generator{
+// NewGenerator creates a new Generator with the default charsets and
+// crypto/rand.Reader, configured by the given options. This function is safe
+// for concurrent use.
+func NewGenerator(opts ...Option) Generator {
+	g := Generator{
 		lowerLetters: LowerLetters,
 		upperLetters: UpperLetters,
 		digits:       Digits,
 		symbols:      Symbols,
+		reader:       rand.Reader,
 	}
+	return g.With(opts...)
 }
 
 // WithLowerLetters creates a new Generator from another Generator with specific
 // LowerLetters.
 func (g Generator) WithLowerLetters(lowerLetters string) Generator {
-	g.lowerLetters = lowerLetters
-	return g
+	return g.With(WithLowerLetters(lowerLetters))
 }
 
 // WithUpperLetters creates a new Generator from another Generator with specific
 // UpperLetters.
 func (g Generator) WithUpperLetters(upperLetters string) Generator {
-	g.upperLetters = upperLetters
-	return g
+	return g.With(WithUpperLetters(upperLetters))
 }
 
 // WithDigits creates a new Generator from another Generator with specific
 // Digits.
 func (g Generator) WithDigits(digits string) Generator {
-	g.digits = digits
-	return g
+	return g.With(WithDigits(digits))
 }
 
 // WithSymbols creates a new Generator from another Generator with specific
 // Symbols.
 func (g Generator) WithSymbols(symbols string) Generator {
-	g.symbols = symbols
-	return g
+	return g.With(WithSymbols(symbols))
+}
+
+// WithReader creates a new Generator from another Generator which reads all of
+// its randomness from r, such as an HSM-backed reader, or a deterministic reader
+// in tests. If r is nil, crypto/rand.Reader is used.
+func (g Generator) WithReader(r io.Reader) Generator {
+	return g.With(WithReader(r))
+}
+
+// WithReaders creates a new Generator from another Generator which reads its
+// randomness from all of the given readers, combined with XOR as by
+// NewMixedReader. The result is unpredictable as long as any one of the readers
+// is, so combining crypto/rand.Reader with a hardware or organization-supplied
+// source never makes the output weaker than crypto/rand alone. Without readers,
+// or with a single nil reader, crypto/rand.Reader is used; a nil reader among
+// several panics.
+func (g Generator) WithReaders(readers ...io.Reader) Generator {
+	return g.With(WithReaders(readers...))
+}
+
+// WithFilter creates a new Generator from another Generator with an additional
+// filter. Generated passwords for which any filter returns false are discarded
+// and generated again.
+func (g Generator) WithFilter(filter func(string) bool) Generator {
+	return g.With(WithFilter(filter))
 }
 
 // Generate generates a password with the given requirements. length is the
 // total number of characters in the password. numDigits is the number of digits
 // to include in the result. numSymbols is the number of symbols to include in
 // the result. noUpper excludes uppercase letters from the results. allowRepeat
-// allows characters to repeat.
+// allows characters to repeat. preserveClassOrder emits all letters first, then
+// all digits, then all symbols, instead of interleaving them at random; this
+// makes the password easier to guess and is only meant for legacy systems which
+// require such a format. maxSameClassRun, if positive, is the maximum number of
+// characters of the same class (letters, digits or symbols) which may appear in
+// a row. requireFromEachClass makes the letters include at least one lowercase
+// and, unless noUpper is set, one uppercase letter, as many password policies
+// require; digits and symbols are already guaranteed by their counts.
 //
 // The algorithm is fast, but it's not designed to be performant; it favors
 // entropy over speed. This function is safe for concurrent use.
+//
+// If the Generator has post-processors, they are applied in order to the
+// generated password. If the Generator has filters, passwords are generated
+// until one is accepted by all of them, or ErrFilterAttemptsExceeded is
+// returned. If the Generator is Latin-1 safe, the post-processed password is
+// checked with IsLatin1Safe.
 func (g Generator) Generate(input Input) (string, error) {
-	letters := g.lowerLetters
-	if !input.NoUpper {
-		letters += g.upperLetters
+	res, _, err := g.GenerateStats(input)
+	return res, err
+}
+
+// generateFiltered generates passwords until one is accepted by the filters.
+func (g Generator) generateFiltered(input Input) (string, error) {
+	for i := 0; i < maxFilterAttempts; i++ {
+		res, ok, err := g.generateCandidate(input)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return res, nil
+		}
 	}
+	return "", ErrFilterAttemptsExceeded
+}
 
-	chars := input.Length - input.Digits - input.Symbols
-	if chars < 0 {
-		return "", ErrExceedsTotalLength
+// generateCandidate generates and post-processes a single password, and
+// reports whether it is accepted by the filters.
+func (g Generator) generateCandidate(input Input) (string, bool, error) {
+	res, err := g.generate(input)
+	if err != nil {
+		return "", false, err
 	}
 
-	if !input.AllowRepeat && chars > len(letters) {
-		return "", ErrLettersExceedsAvailable
+	res, err = g.postProcess(res)
+	if err != nil {
+		return "", false, err
 	}
 
-	if !input.AllowRepeat && input.Digits > len(g.digits) {
-		return "", ErrDigitsExceedsAvailable
+	if g.latin1 {
+		if err := checkLatin1("password", res); err != nil {
+			return "", false, err
+		}
 	}
+	return res, g.accept(res), nil
+}
 
-	if !input.AllowRepeat && input.Symbols > len(g.symbols) {
-		return "", ErrSymbolsExceedsAvailable
+// accept reports whether the given password is accepted by all filters.
+func (g Generator) accept(s string) bool {
+	for _, filter := range g.filters {
+		if !filter(s) {
+			return false
+		}
 	}
+	return true
+}
 
-	var result string
+// letters returns the letters permitted by the given input. Like all charsets,
+// it may contain any Unicode characters.
+func (g Generator) letters(input Input) string {
+	if input.NoUpper {
+		return g.lowerLetters
+	}
+	return g.lowerLetters + g.upperLetters
+}
 
-	// Characters
-	for i := 0; i < chars; i++ {
-		ch, err := randomElement(letters)
-		if err != nil {
-			return "", err
+// check verifies that the given input can be satisfied and returns the number
+// of letters to generate.
+func (g Generator) check(input Input) (int, error) {
+	if g.latin1 {
+		if err := g.checkLatin1Charsets(); err != nil {
+			return 0, err
 		}
+	}
 
-		if !input.AllowRepeat && strings.Contains(result, ch) {
-			i--
-			continue
-		}
+	chars := input.Length - input.Digits - input.Symbols
+	if chars < 0 {
+		return 0, ErrExceedsTotalLength
+	}
 
-		result, err = randomInsert(result, ch)
-		if err != nil {
-			return "", err
-		}
+	if !input.AllowRepeat && chars > utf8.RuneCountInString(g.letters(input)) {
+		return 0, ErrLettersExceedsAvailable
 	}
 
-	// Digits
-	for i := 0; i < input.Digits; i++ {
-		d, err := randomElement(g.digits)
-		if err != nil {
-			return "", err
+	if input.RequireFromEachClass {
+		required := 1
+		if !input.NoUpper {
+			required++
 		}
-
-		if !input.AllowRepeat && strings.Contains(result, d) {
-			i--
-			continue
+		if chars < required || g.lowerLetters == "" || (!input.NoUpper && g.upperLetters == "") {
+			return 0, ErrRequiredClassesUnsatisfiable
 		}
+	}
 
-		result, err = randomInsert(result, d)
-		if err != nil {
-			return "", err
-		}
+	if !input.AllowRepeat && input.Digits > utf8.RuneCountInString(g.digits) {
+		return 0, ErrDigitsExceedsAvailable
 	}
 
-	// Symbols
-	for i := 0; i < input.Symbols; i++ {
-		sym, err := randomElement(g.symbols)
+	if !input.AllowRepeat && input.Symbols > utf8.RuneCountInString(g.symbols) {
+		return 0, ErrSymbolsExceedsAvailable
+	}
+	return chars, nil
+}
+
+// generate generates a single password with the given requirements.
+func (g Generator) generate(input Input) (string, error) {
+	buf, err := g.generateRunes(input)
+	if err != nil {
+		return "", err
+	}
+	res := string(buf)
+	clear(buf)
+	return res, nil
+}
+
+// generateRunes generates the characters of a single password with the given
+// requirements. The caller should clear them once done.
+func (g Generator) generateRunes(input Input) ([]rune, error) {
+	input, err := g.resolve(input)
+	if err != nil {
+		return nil, err
+	}
+	chars, err := g.check(input)
+	if err != nil {
+		return nil, err
+	}
+	letters := g.letters(input)
+
+	// The characters of every class are appended to a single buffer, in
+	// random order within each class. Charsets are indexed by rune so that
+	// they may contain multibyte characters.
+	buf := make([]rune, 0, input.Length)
+	used := make(map[rune]bool, input.Length)
+	for i, class := range []struct {
+		chars string
+		n     int
+	}{
+		{letters, chars},
+		{g.digits, input.Digits},
+		{g.symbols, input.Symbols},
+	} {
+		buf, err = appendRandomChars(g.reader, buf, []rune(class.chars), class.n, used, input.AllowRepeat)
 		if err != nil {
-			return "", err
+			clear(buf)
+			return nil, err
 		}
 
-		if !input.AllowRepeat && strings.Contains(result, sym) {
-			i--
-			continue
+		// Letters missing a required class are generated again, so that
+		// every accepted combination stays equally likely.
+		for i == 0 && input.RequireFromEachClass && !g.hasEachLetterClass(buf, input) {
+			for _, ch := range buf {
+				delete(used, ch)
+			}
+			clear(buf)
+			buf, err = appendRandomChars(g.reader, buf[:0], []rune(class.chars), class.n, used, input.AllowRepeat)
+			if err != nil {
+				clear(buf)
+				return nil, err
+			}
 		}
+	}
 
-		result, err = randomInsert(result, sym)
-		if err != nil {
-			return "", err
-		}
+	res, err := arrange(g.reader, buf, []int{chars, input.Digits, input.Symbols}, input)
+	if err != nil {
+		clear(buf)
+		return nil, err
 	}
+	return res, nil
+}
 
-	return result, nil
+// hasEachLetterClass reports whether the letters include a lowercase letter
+// and, unless NoUpper is set, an uppercase letter of the Generator charsets.
+func (g Generator) hasEachLetterClass(letters []rune, input Input) bool {
+	lower, upper := false, input.NoUpper
+	for _, ch := range letters {
+		lower = lower || strings.ContainsRune(g.lowerLetters, ch)
+		upper = upper || strings.ContainsRune(g.upperLetters, ch)
+	}
+	return lower && upper
 }
 
 // MustGenerate is the same as Generate, but panics on error.
@@ -222,25 +398,25 @@ func MustGenerate(input Input) string {
 	return res
 }
 
-// randomInsert randomly inserts the given value into the given string.
-func randomInsert(s, val string) (string, error) {
-	if s == "" {
-		return val, nil
-	}
+// appendRandomChars appends n random characters from the given charset to buf,
+// using bytes read from r. Unless allowRepeat is set, characters are never
+// repeated and characters marked in used are never chosen; chosen characters
+// are marked in used.
+func appendRandomChars(r io.Reader, buf []rune, charset []rune, n int, used map[rune]bool, allowRepeat bool) ([]rune, error) {
+	for i := 0; i < n; i++ {
+		j, err := UniformIndex(r, len(charset))
+		if err != nil {
+			return buf, err
+		}
 
-	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(s)+1)))
-	if err != nil {
-		return "", fmt.Errorf("failed to generate random integer: %w", err)
-	}
-	i := n.Int64()
-	return s[0:i] + val + s[i:], nil
-}
+		ch := charset[j]
+		if !allowRepeat && used[ch] {
+			i--
+			continue
+		}
 
-// randomElement extracts a random element from the given string.
-func randomElement(s string) (string, error) {
-	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(s))))
-	if err != nil {
-		return "", fmt.Errorf("failed to generate random integer: %w", err)
+		used[ch] = true
+		buf = append(buf, ch)
 	}
-	return string(s[n.Int64()]), nil
+	return buf, nil
 }
@@ -2,8 +2,10 @@ package password
 
 import (
 	"errors"
+	"math"
 	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 const (
@@ -128,6 +130,28 @@ func testGeneratorGenerate(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("gen_preserve_class_order", func(t *testing.T) {
+		t.Parallel()
+
+		for i := 0; i < N; i++ {
+			res, err := gen.Generate(Input{
+				Length:             20,
+				Digits:             5,
+				Symbols:            5,
+				PreserveClassOrder: true,
+			})
+			if err != nil {
+				t.Error(err)
+			}
+
+			if strings.Trim(res[:10], LowerLetters+UpperLetters) != "" ||
+				strings.Trim(res[10:15], Digits) != "" ||
+				strings.Trim(res[15:], Symbols) != "" {
+				t.Errorf("%q should be letters, then digits, then symbols", res)
+			}
+		}
+	})
 }
 
 func TestGeneratorGenerate(t *testing.T) {
@@ -187,3 +211,139 @@ func TestGenerator_Reader_Generate_Custom(t *testing.T) {
 	t.Parallel()
 	testGeneratorGenerateCustom(t)
 }
+
+func TestGeneratorWithReader(t *testing.T) {
+	t.Parallel()
+
+	input := Input{Length: 24, Digits: 4, Symbols: 4}
+	a, err := NewGenerator().WithReader(&testReader{seed: "a"}).Generate(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewGenerator().WithReader(&testReader{seed: "a"}).Generate(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf("expected the same reader to generate the same password, got %q and %q", a, b)
+	}
+
+	passphrase, err := NewGenerator().WithReader(&testReader{seed: "a"}).GeneratePassphrase(PassphraseInput{Words: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, err := NewGenerator().WithReader(&testReader{seed: "a"}).GeneratePassphrase(PassphraseInput{Words: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passphrase != again {
+		t.Errorf("expected the same reader to generate the same passphrase, got %q and %q", passphrase, again)
+	}
+
+	if _, err := NewGenerator().WithReader(errReader{}).Generate(input); err == nil {
+		t.Error("expected the reader error to be returned")
+	}
+	if _, err := NewGenerator().WithReader(nil).Generate(input); err != nil {
+		t.Errorf("expected a nil reader to use crypto/rand, got %v", err)
+	}
+}
+
+func TestGeneratorUnicodeCharsets(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator().
+		WithLowerLetters("абвгдеёжзийклмнопрстуфхцчшщъыьэюя").
+		WithUpperLetters("АБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ").
+		WithSymbols("•€£")
+	input := Input{Length: 16, Digits: 4, Symbols: 3}
+	allowed := "абвгдеёжзийклмнопрстуфхцчшщъыьэюяАБВГДЕЁЖЗИЙКЛМНОПРСТУФХЦЧШЩЪЫЬЭЮЯ" + Digits + "•€£"
+	for i := 0; i < N; i++ {
+		res, err := gen.Generate(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !utf8.ValidString(res) {
+			t.Fatalf("%q should be valid UTF-8", res)
+		}
+		if got := utf8.RuneCountInString(res); got != input.Length {
+			t.Errorf("expected %q to have %d characters, got %d", res, input.Length, got)
+		}
+		for _, r := range res {
+			if !strings.ContainsRune(allowed, r) {
+				t.Errorf("%q should only contain characters of the charsets", res)
+				break
+			}
+		}
+		for _, sym := range "•€£" {
+			if !strings.ContainsRune(res, sym) {
+				t.Errorf("%q should contain all %d symbols", res, input.Symbols)
+				break
+			}
+		}
+		if err := gen.Validate(res, input); err != nil {
+			t.Errorf("expected %q to be valid, got %v", res, err)
+		}
+	}
+
+	if _, err := gen.Generate(Input{Length: 8, Symbols: 4}); !errors.Is(err, ErrSymbolsExceedsAvailable) {
+		t.Errorf("expected %v to be %v", err, ErrSymbolsExceedsAvailable)
+	}
+
+	entropy, err := gen.Entropy(Input{Length: 3, Symbols: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := math.Log2(6); math.Abs(entropy-want) > 1e-9 {
+		t.Errorf("expected entropy %v to be %v", entropy, want)
+	}
+}
+
+func TestGeneratorRequireFromEachClass(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator()
+	input := Input{Length: 4, Digits: 1, Symbols: 1, RequireFromEachClass: true}
+	for i := 0; i < N; i++ {
+		res, err := gen.Generate(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, chars := range []string{LowerLetters, UpperLetters, Digits, Symbols} {
+			if !strings.ContainsAny(res, chars) {
+				t.Errorf("%q should contain one of %q", res, chars)
+			}
+		}
+		if err := gen.Validate(res, input); err != nil {
+			t.Errorf("expected %q to be valid, got %v", res, err)
+		}
+	}
+
+	res, err := gen.Generate(Input{Length: 4, NoUpper: true, RequireFromEachClass: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.ContainsAny(res, UpperLetters) {
+		t.Errorf("%q should not contain uppercase letters", res)
+	}
+
+	for _, tc := range []struct {
+		gen   Generator
+		input Input
+	}{
+		{gen, Input{Length: 3, Digits: 1, Symbols: 1, RequireFromEachClass: true}},
+		{gen, Input{Length: 2, Digits: 1, Symbols: 1, NoUpper: true, RequireFromEachClass: true}},
+		{gen.WithUpperLetters(""), Input{Length: 8, RequireFromEachClass: true}},
+	} {
+		if _, err := tc.gen.Generate(tc.input); !errors.Is(err, ErrRequiredClassesUnsatisfiable) {
+			t.Errorf("expected %v to be %v", err, ErrRequiredClassesUnsatisfiable)
+		}
+	}
+
+	err = gen.Validate("abcd", Input{Length: 4, RequireFromEachClass: true})
+	var perr *PolicyError
+	if !errors.As(err, &perr) || len(perr.Violations) != 1 || perr.Violations[0].Rule != RuleRequiredClass {
+		t.Errorf("expected a %s violation, got %v", RuleRequiredClass, err)
+	}
+}
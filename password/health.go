@@ -0,0 +1,126 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+var (
+	// ErrEntropyUnhealthy is the error returned when an entropy source fails
+	// its health checks.
+	ErrEntropyUnhealthy = errors.New("entropy source failed health check")
+
+	// ErrEntropyUnavailable is the error returned by FailoverReader when neither
+	// the primary nor the fallback entropy source is usable.
+	ErrEntropyUnavailable = errors.New("no healthy entropy source available")
+)
+
+// repetitionCutoff is the number of identical consecutive bytes after which an
+// entropy source is considered broken. For a full-entropy source the chance of
+// this happening by accident is 2^-56 per byte.
+const repetitionCutoff = 8
+
+// HealthOptions used to define input parameters for NewFailoverReader.
+type HealthOptions struct {
+	// Check, if set, is called with every chunk of bytes read from an entropy
+	// source in addition to the built-in repetition count test. A non-nil error
+	// marks the source as unhealthy.
+	Check func(b []byte) error
+
+	// OnAlert, if set, is called whenever an entropy source fails and the
+	// reader fails over or fails closed. It must not block.
+	OnAlert func(err error)
+	_       struct{}
+}
+
+// FailoverReader is an io.Reader which reads from a primary entropy source and
+// fails over to a fallback source when the primary returns an error or fails
+// its health checks. Failover is permanent for the lifetime of the reader. If
+// both sources fail, every read fails with ErrEntropyUnavailable rather than
+// returning possibly weak bytes. It is safe for concurrent use.
+type FailoverReader struct {
+	opts HealthOptions
+
+	mu      sync.Mutex
+	sources []*healthSource
+}
+
+// healthSource is an entropy source with its repetition count test state.
+type healthSource struct {
+	name   string
+	r      io.Reader
+	last   byte
+	run    int
+	failed bool
+}
+
+// NewFailoverReader creates a new FailoverReader from the given sources. The
+// fallback may be nil, in which case the reader fails closed as soon as the
+// primary fails.
+func NewFailoverReader(primary, fallback io.Reader, opts HealthOptions) *FailoverReader {
+	sources := []*healthSource{{name: "primary", r: primary}}
+	if fallback != nil {
+		sources = append(sources, &healthSource{name: "fallback", r: fallback})
+	}
+	return &FailoverReader{opts: opts, sources: sources}
+}
+
+// Read fills p with bytes from the first healthy entropy source.
+func (f *FailoverReader) Read(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, s := range f.sources {
+		if s.failed {
+			continue
+		}
+
+		err := s.read(p, f.opts.Check)
+		if err == nil {
+			return len(p), nil
+		}
+
+		s.failed = true
+		if f.opts.OnAlert != nil {
+			f.opts.OnAlert(fmt.Errorf("%s entropy source: %w", s.name, err))
+		}
+	}
+
+	clear(p)
+	return 0, ErrEntropyUnavailable
+}
+
+// Healthy reports whether the primary entropy source is still in use.
+func (f *FailoverReader) Healthy() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !f.sources[0].failed
+}
+
+// read fills p from the source and runs the health checks on the result.
+func (s *healthSource) read(p []byte, check func([]byte) error) error {
+	if _, err := io.ReadFull(s.r, p); err != nil {
+		return fmt.Errorf("failed to read: %w", err)
+	}
+
+	for _, b := range p {
+		if s.run > 0 && b == s.last {
+			s.run++
+		} else {
+			s.last, s.run = b, 1
+		}
+
+		if s.run >= repetitionCutoff {
+			return fmt.Errorf("%w: byte %#02x repeated %d times", ErrEntropyUnhealthy, b, s.run)
+		}
+	}
+
+	if check != nil {
+		if err := check(p); err != nil {
+			return fmt.Errorf("%w: %w", ErrEntropyUnhealthy, err)
+		}
+	}
+	return nil
+}
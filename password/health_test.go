@@ -0,0 +1,103 @@
+package password
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+)
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestFailoverReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("healthy", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewFailoverReader(rand.Reader, nil, HealthOptions{
+			OnAlert: func(err error) { t.Errorf("unexpected alert: %s", err) },
+		})
+
+		b := make([]byte, 4096)
+		if _, err := io.ReadFull(r, b); err != nil {
+			t.Fatal(err)
+		}
+		if !r.Healthy() {
+			t.Error("expected primary to be healthy")
+		}
+	})
+
+	t.Run("fails_over_on_repetition", func(t *testing.T) {
+		t.Parallel()
+
+		var mu sync.Mutex
+		var alerts []error
+		r := NewFailoverReader(bytes.NewReader(make([]byte, 64)), rand.Reader, HealthOptions{
+			OnAlert: func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				alerts = append(alerts, err)
+			},
+		})
+
+		b := make([]byte, 32)
+		if _, err := r.Read(b); err != nil {
+			t.Fatal(err)
+		}
+		if r.Healthy() {
+			t.Error("expected primary to be unhealthy")
+		}
+		if len(alerts) != 1 || !errors.Is(alerts[0], ErrEntropyUnhealthy) {
+			t.Errorf("expected one %q alert, got %q", ErrEntropyUnhealthy, alerts)
+		}
+	})
+
+	t.Run("fails_over_on_error", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewFailoverReader(errReader{}, rand.Reader, HealthOptions{})
+		if _, err := r.Read(make([]byte, 32)); err != nil {
+			t.Fatal(err)
+		}
+		if r.Healthy() {
+			t.Error("expected primary to be unhealthy")
+		}
+	})
+
+	t.Run("custom_check", func(t *testing.T) {
+		t.Parallel()
+
+		errBad := errors.New("bad")
+		r := NewFailoverReader(rand.Reader, nil, HealthOptions{
+			Check: func([]byte) error { return errBad },
+		})
+		if _, err := r.Read(make([]byte, 32)); !errors.Is(err, ErrEntropyUnavailable) {
+			t.Errorf("expected %q to be %q", err, ErrEntropyUnavailable)
+		}
+	})
+
+	t.Run("fails_closed", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewFailoverReader(errReader{}, bytes.NewReader(bytes.Repeat([]byte{7}, 64)), HealthOptions{})
+
+		b := bytes.Repeat([]byte{1}, 32)
+		if _, err := r.Read(b); !errors.Is(err, ErrEntropyUnavailable) {
+			t.Errorf("expected %q to be %q", err, ErrEntropyUnavailable)
+		}
+		if !bytes.Equal(b, make([]byte, 32)) {
+			t.Errorf("expected buffer to be cleared, got %x", b)
+		}
+
+		if _, err := r.Read(b); !errors.Is(err, ErrEntropyUnavailable) {
+			t.Errorf("expected %q to be %q", err, ErrEntropyUnavailable)
+		}
+	})
+}
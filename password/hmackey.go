@@ -0,0 +1,43 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MinHMACKeyBits is the minimum size of an HMAC key, in bits. It is the output
+// size of SHA-256, below which an HS256 key is weaker than the hash function;
+// RFC 7518 requires it for JWT signing keys.
+const MinHMACKeyBits = 256
+
+var (
+	// ErrHMACKeyTooShort is the error returned when an HMAC key is requested
+	// with fewer than MinHMACKeyBits bits.
+	ErrHMACKeyTooShort = fmt.Errorf("HMAC key must be at least %d bits", MinHMACKeyBits)
+
+	// ErrHMACKeyBits is the error returned when an HMAC key is requested with
+	// a number of bits which is not a multiple of 8.
+	ErrHMACKeyBits = errors.New("HMAC key bits must be a multiple of 8")
+)
+
+// GenerateHMACKey generates a random key of the given number of bits, such as
+// a JWT signing secret, encoded as unpadded base64url as in the "k" member of
+// a JSON Web Key. The encoded string is the secret to configure; it carries
+// the full entropy of the key, unlike a password of the same length. Keys of
+// fewer than MinHMACKeyBits bits are rejected with ErrHMACKeyTooShort; use 384
+// or 512 bits for HS384 and HS512.
+func (g Generator) GenerateHMACKey(bits int) (string, error) {
+	if bits < MinHMACKeyBits {
+		return "", ErrHMACKeyTooShort
+	}
+	if bits%8 != 0 {
+		return "", ErrHMACKeyBits
+	}
+
+	return g.GenerateBase64URL(bits / 8)
+}
+
+// GenerateHMACKey is the package shortcut for Generator.GenerateHMACKey.
+func GenerateHMACKey(bits int) (string, error) {
+	return NewGenerator().GenerateHMACKey(bits)
+}
@@ -0,0 +1,38 @@
+package password
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestGenerateHMACKey(t *testing.T) {
+	t.Parallel()
+
+	seen := make(map[string]struct{})
+	for _, bits := range []int{256, 384, 512} {
+		key, err := GenerateHMACKey(bits)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		raw, err := base64.RawURLEncoding.DecodeString(key)
+		if err != nil {
+			t.Fatalf("expected %q to be base64url, got %v", key, err)
+		}
+		if len(raw)*8 != bits {
+			t.Errorf("expected %d bits, got %d", bits, len(raw)*8)
+		}
+		if _, ok := seen[key]; ok {
+			t.Errorf("duplicate key %q", key)
+		}
+		seen[key] = struct{}{}
+	}
+
+	if _, err := GenerateHMACKey(128); !errors.Is(err, ErrHMACKeyTooShort) {
+		t.Errorf("expected %v to be %v", err, ErrHMACKeyTooShort)
+	}
+	if _, err := GenerateHMACKey(260); !errors.Is(err, ErrHMACKeyBits) {
+		t.Errorf("expected %v to be %v", err, ErrHMACKeyBits)
+	}
+}
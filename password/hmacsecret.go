@@ -0,0 +1,108 @@
+package password
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// ErrInvalidHMACSecret is the error returned when an authenticator returns an
+// hmac-secret output of the wrong length.
+var ErrInvalidHMACSecret = errors.New("hmac-secret output must be 32 bytes")
+
+// hmacSecretSaltPrefix separates the salts of this package from those of other
+// applications sharing the same credential.
+const hmacSecretSaltPrefix = "go-password hmac-secret v1\x00"
+
+// HMACSecreter is a FIDO2 authenticator supporting the hmac-secret extension,
+// usually a wrapper around libfido2 or a platform WebAuthn API bound to a
+// credential created with hmac-secret enabled. The authenticator computes
+// HMAC-SHA-256 of the salt under a key which never leaves it, after the user
+// touches the token, so the same token and salt always give the same output.
+type HMACSecreter interface {
+	// HMACSecret performs an assertion with the 32-byte salt and returns the
+	// 32-byte hmac-secret output.
+	HMACSecret(ctx context.Context, salt []byte) ([]byte, error)
+}
+
+// HMACSecretSalt returns the hmac-secret salt for the given site. Salts are
+// domain-separated, so the outputs cannot be replayed into other applications
+// using the same credential.
+func HMACSecretSalt(site string) []byte {
+	sum := sha256.Sum256([]byte(hmacSecretSaltPrefix + site))
+	return sum[:]
+}
+
+// NewHMACSecretReader asks the authenticator for the hmac-secret output of the
+// site salt and returns a DerivedReader keyed by it. Generating with the reader
+// gives a password derived from the security key and the site name, which can
+// be regenerated with the same key and the same Input at any time:
+//
+//	r, err := NewHMACSecretReader(ctx, key, "example.com")
+//	if err != nil {
+//		return err
+//	}
+//	res, err := NewGenerator().WithReader(r).Generate(input)
+//
+// Anyone holding the authenticator, and its PIN if one is set, can derive the
+// password, and changing the password requires changing the site string, for
+// example by appending a counter.
+func NewHMACSecretReader(ctx context.Context, a HMACSecreter, site string) (*DerivedReader, error) {
+	secret, err := a.HMACSecret(ctx, HMACSecretSalt(site))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hmac-secret: %w", err)
+	}
+	defer clear(secret)
+
+	if len(secret) != sha256.Size {
+		return nil, ErrInvalidHMACSecret
+	}
+	return NewDerivedReader(secret, site), nil
+}
+
+// DerivedReader is an io.Reader producing a deterministic stream of bytes from
+// a key, as HMAC-SHA-256 of the info string and a block counter. Distinct info
+// strings give independent streams. It is not safe for concurrent use.
+type DerivedReader struct {
+	mac     hash.Hash
+	info    []byte
+	counter uint64
+	block   []byte
+	buf     []byte
+}
+
+// NewDerivedReader creates a new DerivedReader from the key and info string.
+// The key is copied.
+func NewDerivedReader(key []byte, info string) *DerivedReader {
+	return &DerivedReader{
+		mac:  hmac.New(sha256.New, append([]byte(nil), key...)),
+		info: []byte(info),
+	}
+}
+
+// Read fills p with the next bytes of the stream. It never fails.
+func (d *DerivedReader) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); {
+		if len(d.buf) == 0 {
+			var counter [8]byte
+			binary.BigEndian.PutUint64(counter[:], d.counter)
+			d.counter++
+
+			d.mac.Reset()
+			d.mac.Write(d.info)
+			d.mac.Write(counter[:])
+			d.block = d.mac.Sum(d.block[:0])
+			d.buf = d.block
+		}
+
+		m := copy(p[n:], d.buf)
+		clear(d.buf[:m])
+		d.buf = d.buf[m:]
+		n += m
+	}
+	return len(p), nil
+}
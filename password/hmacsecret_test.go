@@ -0,0 +1,138 @@
+package password
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"testing"
+)
+
+// testAuthenticator is an HMACSecreter computing the hmac-secret output in
+// software.
+type testAuthenticator struct {
+	key   []byte
+	salts [][]byte
+	err   error
+}
+
+func (a *testAuthenticator) HMACSecret(_ context.Context, salt []byte) ([]byte, error) {
+	a.salts = append(a.salts, salt)
+	if a.err != nil {
+		return nil, a.err
+	}
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write(salt)
+	return mac.Sum(nil), nil
+}
+
+func TestHMACSecretReader(t *testing.T) {
+	t.Parallel()
+
+	input := Input{Length: 24, Digits: 4, Symbols: 4}
+	generate := func(t *testing.T, key, site string) string {
+		t.Helper()
+
+		r, err := NewHMACSecretReader(context.Background(), &testAuthenticator{key: []byte(key)}, site)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := NewGenerator().WithReaders(r).Generate(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	t.Run("deterministic", func(t *testing.T) {
+		t.Parallel()
+
+		a, b := generate(t, "key", "example.com"), generate(t, "key", "example.com")
+		if a != b {
+			t.Errorf("expected %q to equal %q", a, b)
+		}
+		if c := generate(t, "key", "example.org"); c == a {
+			t.Errorf("expected different password for different site, got %q", c)
+		}
+		if c := generate(t, "other", "example.com"); c == a {
+			t.Errorf("expected different password for different key, got %q", c)
+		}
+	})
+
+	t.Run("salt", func(t *testing.T) {
+		t.Parallel()
+
+		a := &testAuthenticator{key: []byte("key")}
+		if _, err := NewHMACSecretReader(context.Background(), a, "example.com"); err != nil {
+			t.Fatal(err)
+		}
+		if len(a.salts) != 1 || !bytes.Equal(a.salts[0], HMACSecretSalt("example.com")) {
+			t.Errorf("unexpected salts %x", a.salts)
+		}
+		if len(a.salts[0]) != 32 {
+			t.Errorf("expected 32-byte salt, got %d bytes", len(a.salts[0]))
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		errTouch := errors.New("no touch")
+		_, err := NewHMACSecretReader(context.Background(), &testAuthenticator{err: errTouch}, "example.com")
+		if !errors.Is(err, errTouch) {
+			t.Errorf("expected %q to be %q", err, errTouch)
+		}
+	})
+
+	t.Run("length", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewHMACSecretReader(context.Background(), shortAuthenticator{}, "example.com")
+		if !errors.Is(err, ErrInvalidHMACSecret) {
+			t.Errorf("expected %q to be %q", err, ErrInvalidHMACSecret)
+		}
+	})
+}
+
+type shortAuthenticator struct{}
+
+func (shortAuthenticator) HMACSecret(context.Context, []byte) ([]byte, error) {
+	return make([]byte, 16), nil
+}
+
+func TestDerivedReader(t *testing.T) {
+	t.Parallel()
+
+	whole := make([]byte, 100)
+	if _, err := NewDerivedReader([]byte("key"), "info").Read(whole); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewDerivedReader([]byte("key"), "info")
+	var pieces []byte
+	for _, n := range []int{1, 31, 33, 35} {
+		b := make([]byte, n)
+		if _, err := r.Read(b); err != nil {
+			t.Fatal(err)
+		}
+		pieces = append(pieces, b...)
+	}
+	if !bytes.Equal(whole, pieces) {
+		t.Errorf("expected chunked reads %x to equal %x", pieces, whole)
+	}
+
+	mac := hmac.New(sha256.New, []byte("key"))
+	mac.Write([]byte("info\x00\x00\x00\x00\x00\x00\x00\x00"))
+	if want := mac.Sum(nil); !bytes.Equal(whole[:32], want) {
+		t.Errorf("expected first block %x, got %x", want, whole[:32])
+	}
+
+	other := make([]byte, 100)
+	if _, err := NewDerivedReader([]byte("key"), "other").Read(other); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(whole, other) {
+		t.Error("expected different streams for different info")
+	}
+}
@@ -0,0 +1,178 @@
+// Command effwordlist syncs the embedded EFF long wordlist of package password
+// with the file published by the EFF. It reads the upstream file, verifies it
+// against the SHA-256 given with -sha256, checks that it holds one word for
+// every roll of five dice, in order, and writes the words without the dice
+// numbers:
+//
+//	go run ./internal/effwordlist -sha256 <SHA-256 of the upstream file> -o eff_large_wordlist.txt
+//
+// The file is downloaded from the EFF unless -file names a local copy. The
+// number of words and their Wordlist.Checksum, derived from the verified file,
+// are printed, to be pinned in package password as EFFLongWordlistChecksum.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/juev/go-password/password"
+)
+
+// upstreamURL is the URL of the EFF long wordlist.
+const upstreamURL = "https://www.eff.org/files/2016/07/18/eff_large_wordlist.txt"
+
+// dice is the number of dice rolled for every word.
+const dice = 5
+
+// header is the header of the written wordlist.
+const header = `# EFF long wordlist for GeneratePassphrase, from
+# https://www.eff.org/dice (CC BY 3.0 US), without the dice numbers. One
+# lowercase word per line; lines starting with "#" are ignored.
+#
+# Generated by internal/effwordlist from the upstream file with SHA-256
+# %s.
+`
+
+func main() {
+	if err := run(context.Background(), os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("effwordlist", flag.ContinueOnError)
+	url := flags.String("url", upstreamURL, "URL of the upstream wordlist")
+	file := flags.String("file", "", "path to a local copy of the upstream wordlist, instead of -url")
+	digest := flags.String("sha256", "", "hex-encoded SHA-256 of the upstream wordlist")
+	out := flags.String("o", "eff_large_wordlist.txt", "path of the written wordlist")
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse flags: %w", err)
+	}
+	if *digest == "" {
+		return errors.New("-sha256 is required")
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if *file != "" {
+		data, err = os.ReadFile(*file)
+	} else {
+		data, err = fetch(ctx, *url)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := verify(data, *digest); err != nil {
+		return err
+	}
+	words, err := parse(data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, header, strings.ToLower(*digest))
+	for _, w := range words {
+		buf.WriteString(w)
+		buf.WriteByte('\n')
+	}
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write wordlist: %w", err)
+	}
+
+	fmt.Printf("words: %d\nchecksum: %s\n", len(words), words.Checksum())
+	return nil
+}
+
+// verify returns an error if the SHA-256 of data is not the hex-encoded digest.
+func verify(data []byte, digest string) error {
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, digest) {
+		return fmt.Errorf("upstream wordlist does not match checksum: got %s, want %s", got, digest)
+	}
+	return nil
+}
+
+// fetch downloads the upstream wordlist.
+func fetch(ctx context.Context, url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download wordlist: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download wordlist: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// parse returns the words of the upstream wordlist, in which every line holds
+// the dice numbers of a word, a tab and the word. It checks that the lines
+// cover every roll of the dice, in order.
+func parse(data []byte) (password.Wordlist, error) {
+	var (
+		words password.Wordlist
+		roll  = strings.Repeat("1", dice)
+	)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		n, word, ok := strings.Cut(line, "\t")
+		if !ok || word == "" {
+			return nil, fmt.Errorf("invalid line %q", line)
+		}
+		if n != roll {
+			return nil, fmt.Errorf("expected roll %s, got line %q", roll, line)
+		}
+		words = append(words, word)
+		roll = next(roll)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if roll != strings.Repeat("1", dice) || len(words) == 0 {
+		return nil, fmt.Errorf("expected roll %s, got %d words", roll, len(words))
+	}
+	return words, nil
+}
+
+// next returns the roll of the dice following roll, wrapping around from all
+// sixes to all ones.
+func next(roll string) string {
+	b := []byte(roll)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] < '6' {
+			b[i]++
+			return string(b)
+		}
+		b[i] = '1'
+	}
+	return string(b)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// upstream returns a wordlist in the upstream format with n words.
+func upstream(n int) string {
+	var b strings.Builder
+	roll := "11111"
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "%s\tword%04d\n", roll, i)
+		roll = next(roll)
+	}
+	return b.String()
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	words, err := parse([]byte(upstream(7776)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(words) != 7776 || words[0] != "word0000" || words[7775] != "word7775" {
+		t.Errorf("unexpected words %d, %q, %q", len(words), words[0], words[len(words)-1])
+	}
+
+	for name, data := range map[string]string{
+		"missing_words": upstream(7769),
+		"extra_words":   upstream(7776) + "11111\textra\n",
+		"skipped_roll":  strings.Replace(upstream(7776), "11112\tword0001\n", "", 1),
+		"no_tab":        strings.Replace(upstream(7776), "11112\t", "11112 ", 1),
+		"empty":         "",
+	} {
+		if _, err := parse([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("11111\tabacus\n")
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	if err := verify(data, digest); err != nil {
+		t.Error(err)
+	}
+	if err := verify(data, strings.ToUpper(digest)); err != nil {
+		t.Error(err)
+	}
+	if err := verify(append(data, '\n'), digest); err == nil {
+		t.Error("expected modified data to fail")
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	data := []byte(upstream(7776))
+	in, out := filepath.Join(dir, "upstream.txt"), filepath.Join(dir, "words.txt")
+	if err := os.WriteFile(in, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+
+	if err := run(context.Background(), []string{"-file", in, "-o", out}); err == nil {
+		t.Error("expected a missing digest to fail")
+	}
+	if err := run(context.Background(), []string{"-file", in, "-sha256", strings.Repeat("0", 64), "-o", out}); err == nil {
+		t.Error("expected a wrong digest to fail")
+	}
+	if err := run(context.Background(), []string{"-file", in, "-sha256", digest, "-o", out}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), digest) || !strings.Contains(string(b), "\nword0000\n") || !strings.HasSuffix(string(b), "\nword7775\n") {
+		t.Errorf("unexpected wordlist %.200q", b)
+	}
+}
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	for roll, want := range map[string]string{
+		"11111": "11112",
+		"11116": "11121",
+		"16666": "21111",
+		"66666": "11111",
+	} {
+		if got := next(roll); got != want {
+			t.Errorf("expected %q to be %q", got, want)
+		}
+	}
+}
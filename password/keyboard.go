@@ -0,0 +1,117 @@
+package password
+
+import (
+	"math"
+	"unicode"
+)
+
+// KeyboardLayout maps every key of a keyboard to its physical position. Rows
+// are one unit apart and keys one unit wide, with each row shifted right by its
+// stagger. It is used to detect keyboard walks and adjacent-key typos.
+type KeyboardLayout struct {
+	keys map[rune]keyPosition
+}
+
+// keyPosition is the physical position of a key, and whether the character is
+// typed with shift.
+type keyPosition struct {
+	row     int
+	x       float64
+	shifted bool
+}
+
+// AdjacentKeyDistance is the largest distance between the centers of two keys
+// which are considered adjacent, including diagonal neighbors.
+const AdjacentKeyDistance = 1.3
+
+var (
+	// QWERTY is the US QWERTY keyboard layout.
+	QWERTY = NewKeyboardLayout(
+		[]string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"},
+		[]string{"~!@#$%^&*()_+", "QWERTYUIOP{}|", "ASDFGHJKL:\"", "ZXCVBNM<>?"},
+		[]float64{0, 1.5, 1.75, 2.25},
+	)
+
+	// Dvorak is the US Dvorak keyboard layout.
+	Dvorak = NewKeyboardLayout(
+		[]string{"`1234567890[]", "',.pyfgcrl/=\\", "aoeuidhtns-", ";qjkxbmwvz"},
+		[]string{"~!@#$%^&*(){}", "\"<>PYFGCRL?+|", "AOEUIDHTNS_", ":QJKXBMWVZ"},
+		[]float64{0, 1.5, 1.75, 2.25},
+	)
+)
+
+// NewKeyboardLayout creates a new KeyboardLayout from the unshifted and shifted
+// characters of every row, from top to bottom, and the stagger of every row in
+// key widths. Shifted characters are on the same keys as the unshifted
+// characters at the same positions.
+func NewKeyboardLayout(rows, shifted []string, stagger []float64) *KeyboardLayout {
+	keys := make(map[rune]keyPosition)
+	for layer, layerRows := range [][]string{rows, shifted} {
+		for r, chars := range layerRows {
+			for c, ch := range []rune(chars) {
+				keys[ch] = keyPosition{row: r, x: stagger[r] + float64(c), shifted: layer == 1}
+			}
+		}
+	}
+	return &KeyboardLayout{keys: keys}
+}
+
+// KeyDistance returns the distance, in key widths, between the centers of the
+// keys producing a and b on the given layout, and false if either is not on
+// the keyboard. Characters on the same key, such as 'a' and 'A', have distance
+// zero. A nil layout means QWERTY.
+func KeyDistance(a, b rune, layout *KeyboardLayout) (float64, bool) {
+	if layout == nil {
+		layout = QWERTY
+	}
+	return layout.Distance(a, b)
+}
+
+// Distance returns the distance between the centers of the keys producing a
+// and b, and false if either is not on the keyboard.
+func (l *KeyboardLayout) Distance(a, b rune) (float64, bool) {
+	pa, ok := l.position(a)
+	if !ok {
+		return 0, false
+	}
+
+	pb, ok := l.position(b)
+	if !ok {
+		return 0, false
+	}
+
+	return math.Hypot(pa.x-pb.x, float64(pa.row-pb.row)), true
+}
+
+// Adjacent reports whether a and b are produced by different, physically
+// adjacent keys.
+func (l *KeyboardLayout) Adjacent(a, b rune) bool {
+	d, ok := l.Distance(a, b)
+	return ok && d > 0 && d <= AdjacentKeyDistance
+}
+
+// Neighbors returns the characters of the keys adjacent to the key producing
+// r, typed with the same shift state, in no particular order.
+func (l *KeyboardLayout) Neighbors(r rune) []rune {
+	p, ok := l.keys[r]
+	if !ok {
+		return nil
+	}
+
+	var res []rune
+	for ch, q := range l.keys {
+		if q.shifted == p.shifted && l.Adjacent(r, ch) {
+			res = append(res, ch)
+		}
+	}
+	return res
+}
+
+// position returns the position of the key producing r.
+func (l *KeyboardLayout) position(r rune) (keyPosition, bool) {
+	p, ok := l.keys[r]
+	if !ok {
+		p, ok = l.keys[unicode.ToLower(r)]
+	}
+	return p, ok
+}
@@ -0,0 +1,58 @@
+package password
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestKeyDistance(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		a, b   rune
+		layout *KeyboardLayout
+		want   float64
+		ok     bool
+	}{
+		{'a', 's', nil, 1, true},
+		{'a', 'A', QWERTY, 0, true},
+		{'q', 'a', QWERTY, math.Hypot(0.25, 1), true},
+		{'1', 'q', QWERTY, math.Hypot(0.5, 1), true},
+		{'a', 'l', QWERTY, 8, true},
+		{'a', 'o', Dvorak, 1, true},
+		{'a', 'é', QWERTY, 0, false},
+	}
+
+	for _, tc := range cases {
+		got, ok := KeyDistance(tc.a, tc.b, tc.layout)
+		if ok != tc.ok || math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("KeyDistance(%q, %q): expected %f, %t, got %f, %t", tc.a, tc.b, tc.want, tc.ok, got, ok)
+		}
+	}
+}
+
+func TestKeyboardLayoutNeighbors(t *testing.T) {
+	t.Parallel()
+
+	got := QWERTY.Neighbors('s')
+	slices.Sort(got)
+	if want := []rune("adewxz"); !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", string(want), string(got))
+	}
+
+	got = QWERTY.Neighbors('S')
+	slices.Sort(got)
+	if want := []rune("ADEWXZ"); !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", string(want), string(got))
+	}
+
+	if !QWERTY.Adjacent('g', 'h') || QWERTY.Adjacent('g', 'g') || QWERTY.Adjacent('g', 'k') {
+		t.Error("unexpected adjacency")
+	}
+
+	custom := NewKeyboardLayout([]string{"ab", "cd"}, nil, []float64{0, 0})
+	if d, ok := custom.Distance('a', 'd'); !ok || math.Abs(d-math.Sqrt2) > 1e-9 {
+		t.Errorf("expected diagonal distance, got %f, %t", d, ok)
+	}
+}
@@ -0,0 +1,79 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotLatin1Safe is the error returned by a Latin-1 safe Generator when a
+// charset or a generated password contains a character which does not survive
+// a Latin-1 or Windows-1252 round-trip.
+var ErrNotLatin1Safe = errors.New("character does not survive Latin-1/Windows-1252 round-trips")
+
+// IsLatin1Safe reports whether every character of s is encoded identically in
+// ISO 8859-1 and Windows-1252, and is visible: printable ASCII, or U+00A1 to
+// U+00FF except the soft hyphen. The C1 range, which the two encodings map
+// differently, the no-break space and control characters are not safe.
+func IsLatin1Safe(s string) bool {
+	for _, r := range s {
+		if !isLatin1Safe(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// isLatin1Safe reports whether r is Latin-1 safe, as defined by IsLatin1Safe.
+func isLatin1Safe(r rune) bool {
+	switch {
+	case r >= ' ' && r <= '~':
+		return true
+	case r >= 0xA1 && r <= 0xFF:
+		return r != 0xAD
+	default:
+		return false
+	}
+}
+
+// WithLatin1Safe creates a new Generator from another Generator which only
+// generates passwords surviving Latin-1 and Windows-1252 round-trips, for
+// legacy systems which mangle UTF-8. Generate and Entropy return
+// ErrNotLatin1Safe if a charset contains a character which is not safe as
+// defined by IsLatin1Safe, and Generate also checks the password after
+// post-processing. The default charsets are safe.
+func (g Generator) WithLatin1Safe() Generator {
+	return g.With(WithLatin1Safe())
+}
+
+// WithLatin1Safe returns an Option restricting passwords to Latin-1 safe ones,
+// as Generator.WithLatin1Safe.
+func WithLatin1Safe() Option {
+	return func(g *Generator) {
+		g.latin1 = true
+	}
+}
+
+// checkLatin1Charsets verifies that all the Generator charsets are Latin-1 safe.
+func (g Generator) checkLatin1Charsets() error {
+	for _, cs := range []struct{ name, chars string }{
+		{"lower letters", g.lowerLetters},
+		{"upper letters", g.upperLetters},
+		{"digits", g.digits},
+		{"symbols", g.symbols},
+	} {
+		if err := checkLatin1(cs.name, cs.chars); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkLatin1 verifies that s is Latin-1 safe, naming it in the error.
+func checkLatin1(name, s string) error {
+	for _, r := range s {
+		if !isLatin1Safe(r) {
+			return fmt.Errorf("%w: %q in %s", ErrNotLatin1Safe, r, name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestIsLatin1Safe(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		s    string
+		safe bool
+	}{
+		{"", true},
+		{LowerLetters + UpperLetters + Digits + Symbols, true},
+		{"café £5 §¿", true},
+		{"tab\there", false},
+		{"no\u00a0break", false},
+		{"soft\u00adhyphen", false},
+		{"euro€", false},
+		{"c1\u0085", false},
+		{"\xff", false},
+	}
+
+	for _, tc := range cases {
+		if got := IsLatin1Safe(tc.s); got != tc.safe {
+			t.Errorf("expected IsLatin1Safe(%q) to be %t", tc.s, tc.safe)
+		}
+	}
+}
+
+func TestGeneratorWithLatin1Safe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		gen := NewGenerator().WithLatin1Safe()
+		for i := 0; i < N; i++ {
+			res, err := gen.Generate(Input{Length: 24, Digits: 4, Symbols: 4})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !IsLatin1Safe(res) {
+				t.Errorf("%q should be Latin-1 safe", res)
+			}
+		}
+	})
+
+	t.Run("charset", func(t *testing.T) {
+		t.Parallel()
+
+		gen := NewGenerator().WithSymbols("!€").WithLatin1Safe()
+		if _, err := gen.Generate(Input{Length: 8}); !errors.Is(err, ErrNotLatin1Safe) {
+			t.Errorf("expected %v to be %v", err, ErrNotLatin1Safe)
+		}
+		if _, err := gen.Entropy(Input{Length: 8}); !errors.Is(err, ErrNotLatin1Safe) {
+			t.Errorf("expected %v to be %v", err, ErrNotLatin1Safe)
+		}
+	})
+
+	t.Run("post-processor", func(t *testing.T) {
+		t.Parallel()
+
+		gen := NewGenerator().WithPostProcessors(Group(4, "–")).WithLatin1Safe()
+		if _, err := gen.Generate(Input{Length: 8}); !errors.Is(err, ErrNotLatin1Safe) {
+			t.Errorf("expected %v to be %v", err, ErrNotLatin1Safe)
+		}
+	})
+}
@@ -0,0 +1,232 @@
+package password
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// Transform is a mechanical change which makes an existing password follow a
+// new policy without the user choosing a new one.
+type Transform string
+
+// Transforms applied by Policy.Migrate, in this order.
+const (
+	// TransformStripForbidden removes the characters the policy forbids,
+	// typically symbols a legacy system cannot store.
+	TransformStripForbidden Transform = "strip_forbidden"
+
+	// TransformTruncate truncates the password to the maximum length.
+	TransformTruncate Transform = "truncate"
+)
+
+// MigrationAction is what to do with an existing password when moving to a new
+// policy.
+type MigrationAction string
+
+// Actions of a Migration.
+const (
+	// MigrationKeep means the password already follows the new policy.
+	MigrationKeep MigrationAction = "keep"
+
+	// MigrationTransform means the password follows the new policy once
+	// transformed.
+	MigrationTransform MigrationAction = "transform"
+
+	// MigrationRegenerate means no transform makes the password follow the
+	// new policy, and a new password must be issued.
+	MigrationRegenerate MigrationAction = "regenerate"
+)
+
+// Migration is the outcome of Policy.Migrate for a single password.
+type Migration struct {
+	Action MigrationAction
+
+	// Password is the password to store: the original one if it is kept, or
+	// the transformed one. It is empty if the password must be regenerated.
+	Password string
+
+	// Transforms are the transforms applied to the password, in order.
+	Transforms []Transform
+
+	// Violations are the rules the transformed password still does not
+	// follow, if it must be regenerated.
+	Violations []Violation
+}
+
+// Migrate determines how an existing password can be moved to the policy:
+// kept as is, transformed by stripping forbidden characters and truncating it
+// to the maximum length, or regenerated. Transforms never lengthen a password
+// nor add characters, so passwords which are too short or miss a required
+// class must be regenerated.
+//
+// Since passwords are usually stored hashed, Migrate is meant to run when the
+// plaintext is available, such as at login, or on stored service credentials.
+// The transformed password must be hashed again and communicated to its user.
+func (p Policy) Migrate(pw string) Migration {
+	if p.Validate(pw) == nil {
+		return Migration{Action: MigrationKeep, Password: pw}
+	}
+
+	var m Migration
+	if p.Forbidden != "" && strings.ContainsAny(pw, p.Forbidden) {
+		pw = strings.Map(func(r rune) rune {
+			if strings.ContainsRune(p.Forbidden, r) {
+				return -1
+			}
+			return r
+		}, pw)
+		m.Transforms = append(m.Transforms, TransformStripForbidden)
+	}
+	if runes := []rune(pw); p.MaxLength > 0 && len(runes) > p.MaxLength {
+		pw = string(runes[:p.MaxLength])
+		m.Transforms = append(m.Transforms, TransformTruncate)
+	}
+
+	var policyErr *PolicyError
+	if err := p.Validate(pw); errors.As(err, &policyErr) {
+		m.Action = MigrationRegenerate
+		m.Transforms = nil
+		m.Violations = policyErr.Violations
+		return m
+	}
+	m.Action = MigrationTransform
+	m.Password = pw
+	return m
+}
+
+// MigrationReport aggregates the migrations of a user base, without holding
+// any password.
+type MigrationReport struct {
+	Total       int
+	Kept        int
+	Transformed int
+	Regenerated int
+
+	// Transforms is the number of transformed passwords per transform.
+	Transforms map[Transform]int
+
+	// Violations is the number of regenerated passwords per rule they still
+	// violated.
+	Violations map[Rule]int
+}
+
+// Add accounts for a migration in the report.
+func (r *MigrationReport) Add(m Migration) {
+	r.Total++
+	switch m.Action {
+	case MigrationKeep:
+		r.Kept++
+	case MigrationTransform:
+		r.Transformed++
+		for _, t := range m.Transforms {
+			if r.Transforms == nil {
+				r.Transforms = make(map[Transform]int)
+			}
+			r.Transforms[t]++
+		}
+	case MigrationRegenerate:
+		r.Regenerated++
+		var rules []Rule
+		for _, v := range m.Violations {
+			if slices.Contains(rules, v.Rule) {
+				continue
+			}
+			rules = append(rules, v.Rule)
+			if r.Violations == nil {
+				r.Violations = make(map[Rule]int)
+			}
+			r.Violations[v.Rule]++
+		}
+	}
+}
+
+// MigrationPlan is the outcome of PlanMigration.
+type MigrationPlan struct {
+	// Transforms are the transforms some passwords following the old policy
+	// may need.
+	Transforms []Transform
+
+	// Regenerate are the rules of the new policy which some passwords
+	// following the old policy may violate even after the transforms. If it
+	// is empty, every password can be migrated mechanically.
+	Regenerate []Rule
+}
+
+// PlanMigration determines, from the policies alone, whether every password
+// following the old policy can be moved mechanically to the new one. It is a
+// conservative estimate: a rule is listed in Regenerate if some password
+// following the old policy may violate it, not if one necessarily does. Use
+// Policy.Migrate on actual passwords for the exact outcome.
+func PlanMigration(from, to Policy) MigrationPlan {
+	var plan MigrationPlan
+	regenerate := func(rule Rule) {
+		if !slices.Contains(plan.Regenerate, rule) {
+			plan.Regenerate = append(plan.Regenerate, rule)
+		}
+	}
+
+	if to.MinLength > from.MinLength {
+		regenerate(RuleMinLength)
+	}
+	if to.MinDigits > from.MinDigits {
+		regenerate(RuleMinDigits)
+	}
+	if to.MinSymbols > from.MinSymbols {
+		regenerate(RuleMinSymbols)
+	}
+	for _, c := range to.RequiredClasses {
+		if !from.requires(c) {
+			regenerate(RuleRequiredClass)
+		}
+	}
+
+	// Stripping newly forbidden characters shortens passwords, and removes
+	// the characters of their class.
+	var stripped []Class
+	for _, r := range to.Forbidden {
+		if !strings.ContainsRune(from.Forbidden, r) && !slices.Contains(stripped, ClassOf(r)) {
+			stripped = append(stripped, ClassOf(r))
+		}
+	}
+	if len(stripped) > 0 {
+		plan.Transforms = append(plan.Transforms, TransformStripForbidden)
+		if to.MinLength > 0 {
+			regenerate(RuleMinLength)
+		}
+		if to.MinDigits > 0 && slices.Contains(stripped, ClassDigit) {
+			regenerate(RuleMinDigits)
+		}
+		if to.MinSymbols > 0 && slices.Contains(stripped, ClassSymbol) {
+			regenerate(RuleMinSymbols)
+		}
+		for _, c := range to.RequiredClasses {
+			if slices.Contains(stripped, c) {
+				regenerate(RuleRequiredClass)
+			}
+		}
+	}
+
+	// Truncating may cut the required characters off the end.
+	if to.MaxLength > 0 && (from.MaxLength == 0 || from.MaxLength > to.MaxLength) {
+		plan.Transforms = append(plan.Transforms, TransformTruncate)
+		if to.MinDigits > 0 {
+			regenerate(RuleMinDigits)
+		}
+		if to.MinSymbols > 0 {
+			regenerate(RuleMinSymbols)
+		}
+		if len(to.RequiredClasses) > 0 {
+			regenerate(RuleRequiredClass)
+		}
+	}
+	return plan
+}
+
+// requires reports whether every password following the policy contains a
+// character of the given class.
+func (p Policy) requires(c Class) bool {
+	return slices.Contains(p.RequiredClasses, c) ||
+		(c == ClassDigit && p.MinDigits > 0) ||
+		(c == ClassSymbol && p.MinSymbols > 0)
+}
@@ -0,0 +1,110 @@
+package password
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPolicyMigrate(t *testing.T) {
+	t.Parallel()
+
+	to := Policy{MinLength: 8, MaxLength: 12, MinDigits: 1, Forbidden: "<>&"}
+	cases := []struct {
+		pw         string
+		action     MigrationAction
+		password   string
+		transforms []Transform
+		violations []Rule
+	}{
+		{"abcdefgh1", MigrationKeep, "abcdefgh1", nil, nil},
+		{"abc<def>gh1", MigrationTransform, "abcdefgh1", []Transform{TransformStripForbidden}, nil},
+		{"abcdefghijk1mnop", MigrationTransform, "abcdefghijk1", []Transform{TransformTruncate}, nil},
+		{"a&b1cdefghijklmn", MigrationTransform, "ab1cdefghijk", []Transform{TransformStripForbidden, TransformTruncate}, nil},
+		{"abcdefghijklmn1", MigrationRegenerate, "", nil, []Rule{RuleMinDigits}},
+		{"<<<ab1>>>", MigrationRegenerate, "", nil, []Rule{RuleMinLength}},
+	}
+
+	for _, tc := range cases {
+		m := to.Migrate(tc.pw)
+		if m.Action != tc.action || m.Password != tc.password || !slices.Equal(m.Transforms, tc.transforms) {
+			t.Errorf("unexpected migration of %q: %+v", tc.pw, m)
+		}
+		var rules []Rule
+		for _, v := range m.Violations {
+			rules = append(rules, v.Rule)
+		}
+		if !slices.Equal(rules, tc.violations) {
+			t.Errorf("expected violations %v for %q, got %v", tc.violations, tc.pw, rules)
+		}
+	}
+}
+
+func TestMigrationReport(t *testing.T) {
+	t.Parallel()
+
+	to := Policy{MinLength: 8, MaxLength: 12, Forbidden: " "}
+	var r MigrationReport
+	for _, pw := range []string{"abcdefgh", "abcd efgh", "abcdefghijklmnop", "short", "a b"} {
+		r.Add(to.Migrate(pw))
+	}
+
+	if r.Total != 5 || r.Kept != 1 || r.Transformed != 2 || r.Regenerated != 2 {
+		t.Errorf("unexpected report %+v", r)
+	}
+	if r.Transforms[TransformStripForbidden] != 1 || r.Transforms[TransformTruncate] != 1 {
+		t.Errorf("unexpected transforms %v", r.Transforms)
+	}
+	if r.Violations[RuleMinLength] != 2 {
+		t.Errorf("unexpected violations %v", r.Violations)
+	}
+}
+
+func TestPlanMigration(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		from, to   Policy
+		transforms []Transform
+		regenerate []Rule
+	}{
+		{
+			name: "looser",
+			from: Policy{MinLength: 12, MinDigits: 2, RequiredClasses: []Class{ClassUpper}},
+			to:   Policy{MinLength: 8, RequiredClasses: []Class{ClassDigit}},
+		},
+		{
+			name:       "shorter",
+			from:       Policy{MinLength: 8},
+			to:         Policy{MinLength: 8, MaxLength: 16},
+			transforms: []Transform{TransformTruncate},
+		},
+		{
+			name:       "shorter with digits",
+			from:       Policy{MinLength: 8, MaxLength: 32, MinDigits: 1},
+			to:         Policy{MaxLength: 16, MinDigits: 1},
+			transforms: []Transform{TransformTruncate},
+			regenerate: []Rule{RuleMinDigits},
+		},
+		{
+			name:       "forbidden symbols",
+			from:       Policy{MinLength: 10, MinSymbols: 1},
+			to:         Policy{MinLength: 10, RequiredClasses: []Class{ClassSymbol}, Forbidden: "<>"},
+			transforms: []Transform{TransformStripForbidden},
+			regenerate: []Rule{RuleMinLength, RuleRequiredClass},
+		},
+		{
+			name:       "stricter",
+			from:       Policy{MinLength: 8},
+			to:         Policy{MinLength: 12, MinSymbols: 1, RequiredClasses: []Class{ClassUpper}},
+			regenerate: []Rule{RuleMinLength, RuleMinSymbols, RuleRequiredClass},
+		},
+	}
+
+	for _, tc := range cases {
+		plan := PlanMigration(tc.from, tc.to)
+		if !slices.Equal(plan.Transforms, tc.transforms) || !slices.Equal(plan.Regenerate, tc.regenerate) {
+			t.Errorf("%s: unexpected plan %+v", tc.name, plan)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package password
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// ErrNoDefaults is the error returned by GenerateDefault when the Generator has
+// no default input.
+var ErrNoDefaults = errors.New("generator has no default input")
+
+// Option configures a Generator, as passed to NewGenerator or Generator.With.
+// Every builder method of Generator has a matching Option, so that code
+// configuring generators can accept a []Option.
+type Option func(*Generator)
+
+// With creates a new Generator from another Generator with the given options
+// applied in order.
+func (g Generator) With(opts ...Option) Generator {
+	for _, opt := range opts {
+		opt(&g)
+	}
+	return g
+}
+
+// WithLowerLetters returns an Option setting the lowercase letters.
+func WithLowerLetters(lowerLetters string) Option {
+	return func(g *Generator) {
+		g.lowerLetters = lowerLetters
+	}
+}
+
+// WithUpperLetters returns an Option setting the uppercase letters.
+func WithUpperLetters(upperLetters string) Option {
+	return func(g *Generator) {
+		g.upperLetters = upperLetters
+	}
+}
+
+// WithDigits returns an Option setting the digits.
+func WithDigits(digits string) Option {
+	return func(g *Generator) {
+		g.digits = digits
+	}
+}
+
+// WithSymbols returns an Option setting the symbols.
+func WithSymbols(symbols string) Option {
+	return func(g *Generator) {
+		g.symbols = symbols
+	}
+}
+
+// WithReader returns an Option reading all randomness from r, as
+// Generator.WithReader.
+func WithReader(r io.Reader) Option {
+	return func(g *Generator) {
+		if r == nil {
+			r = rand.Reader
+		}
+		g.reader = r
+	}
+}
+
+// WithReaders returns an Option reading randomness from all of the given
+// readers combined, as Generator.WithReaders.
+func WithReaders(readers ...io.Reader) Option {
+	return func(g *Generator) {
+		switch len(readers) {
+		case 0:
+			g.reader = rand.Reader
+		case 1:
+			WithReader(readers[0])(g)
+		default:
+			g.reader = NewMixedReader(readers...)
+		}
+	}
+}
+
+// WithFilter returns an Option adding a filter, as Generator.WithFilter.
+func WithFilter(filter func(string) bool) Option {
+	return func(g *Generator) {
+		g.filters = append(g.filters[:len(g.filters):len(g.filters)], filter)
+	}
+}
+
+// WithPostProcessors returns an Option appending post-processors, as
+// Generator.WithPostProcessors.
+func WithPostProcessors(processors ...PostProcessor) Option {
+	return func(g *Generator) {
+		g.postProcessors = append(g.postProcessors[:len(g.postProcessors):len(g.postProcessors)], processors...)
+	}
+}
+
+// WithDefaults returns an Option setting the default input of the Generator,
+// used by GenerateDefault.
+func WithDefaults(input Input) Option {
+	return func(g *Generator) {
+		g.defaults = &input
+	}
+}
+
+// WithDefaults creates a new Generator from another Generator with the given
+// default input, used by GenerateDefault.
+func (g Generator) WithDefaults(input Input) Generator {
+	return g.With(WithDefaults(input))
+}
+
+// Defaults returns the default input of the Generator, and whether it has one.
+func (g Generator) Defaults() (Input, bool) {
+	if g.defaults == nil {
+		return Input{}, false
+	}
+	return *g.defaults, true
+}
+
+// GenerateDefault generates a password with the default input of the
+// Generator, or returns ErrNoDefaults if it has none.
+func (g Generator) GenerateDefault() (string, error) {
+	input, ok := g.Defaults()
+	if !ok {
+		return "", ErrNoDefaults
+	}
+	return g.Generate(input)
+}
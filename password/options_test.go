@@ -0,0 +1,82 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewGeneratorOptions(t *testing.T) {
+	t.Parallel()
+
+	opts := []Option{
+		WithLowerLetters("ab"),
+		WithUpperLetters("CD"),
+		WithDigits("12"),
+		WithSymbols("!"),
+		WithReader(&testReader{seed: "a"}),
+		WithDefaults(Input{Length: 6, Digits: 2, Symbols: 1, AllowRepeat: true}),
+	}
+	gen := NewGenerator(opts...)
+	chained := NewGenerator().
+		WithLowerLetters("ab").
+		WithUpperLetters("CD").
+		WithDigits("12").
+		WithSymbols("!").
+		WithReader(&testReader{seed: "a"}).
+		WithDefaults(Input{Length: 6, Digits: 2, Symbols: 1, AllowRepeat: true})
+
+	got, err := gen.GenerateDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := chained.GenerateDefault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+	if strings.Trim(got, "abCD12!") != "" {
+		t.Errorf("%q should only contain the configured charsets", got)
+	}
+
+	input, ok := gen.Defaults()
+	if !ok || input.Length != 6 {
+		t.Errorf("expected %+v to be the defaults", input)
+	}
+}
+
+func TestGeneratorWith(t *testing.T) {
+	t.Parallel()
+
+	base := NewGenerator(WithSymbols("!"))
+	gen := base.With(WithoutAmbiguous(), WithFilter(func(s string) bool { return !strings.Contains(s, "a") }))
+	if base.lowerLetters != LowerLetters {
+		t.Errorf("expected %v to be %v", base.lowerLetters, LowerLetters)
+	}
+	if strings.ContainsAny(gen.lowerLetters+gen.digits, VisuallyAmbiguous) {
+		t.Errorf("%q should not contain ambiguous characters", gen.lowerLetters+gen.digits)
+	}
+
+	for i := 0; i < N; i++ {
+		res, err := gen.Generate(Input{Length: 8, Digits: 2, Symbols: 1, AllowRepeat: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(res, "a") {
+			t.Errorf("%q should have been filtered", res)
+		}
+	}
+}
+
+func TestGenerateDefaultWithoutDefaults(t *testing.T) {
+	t.Parallel()
+
+	if _, err := NewGenerator().GenerateDefault(); !errors.Is(err, ErrNoDefaults) {
+		t.Errorf("expected %v to be %v", err, ErrNoDefaults)
+	}
+	if _, ok := NewGenerator().Defaults(); ok {
+		t.Error("expected no defaults")
+	}
+}
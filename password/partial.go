@@ -0,0 +1,69 @@
+package password
+
+import (
+	"crypto/subtle"
+	"errors"
+	"slices"
+)
+
+// ErrInvalidPosition is the error returned when a challenge position is
+// outside of the password or repeated.
+var ErrInvalidPosition = errors.New("invalid challenge position")
+
+// ChallengePositions returns n distinct random positions of a password of the
+// given length, in ascending order, for a partial-password challenge such as
+// "please enter the 2nd, 5th and 9th characters". Positions start at 1.
+func ChallengePositions(length, n int) ([]int, error) {
+	if n < 1 || n > length {
+		return nil, ErrSampleSize
+	}
+
+	all := make([]int, length)
+	for i := range all {
+		all[i] = i + 1
+	}
+	positions, err := Sample(all, n)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(positions)
+	return positions, nil
+}
+
+// Challenge returns the characters of the password at the given positions,
+// starting at 1, which is the expected response to the challenge. Positions
+// are in runes.
+func Challenge(pw string, positions []int) (string, error) {
+	runes := []rune(pw)
+	res := make([]rune, len(positions))
+	for i, p := range positions {
+		if p < 1 || p > len(runes) || slices.Contains(positions[:i], p) {
+			return "", ErrInvalidPosition
+		}
+		res[i] = runes[p-1]
+	}
+	return string(res), nil
+}
+
+// VerifyChallenge reports whether the response holds the characters of the
+// password at the given positions, in order. The comparison takes the same
+// time whichever characters are wrong, so that the response time does not
+// reveal them.
+func VerifyChallenge(pw string, positions []int, response string) bool {
+	want, err := Challenge(pw, positions)
+	if err != nil || len(positions) == 0 {
+		return false
+	}
+
+	// Compare fixed-size runes, so that the time does not depend on the
+	// encoded length of the characters either.
+	got := []rune(response)
+	if len(got) != len(positions) {
+		return false
+	}
+	ok := 1
+	for i, r := range []rune(want) {
+		ok &= subtle.ConstantTimeEq(int32(r), int32(got[i]))
+	}
+	return ok == 1
+}
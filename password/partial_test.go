@@ -0,0 +1,74 @@
+package password
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestChallengePositions(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < N; i++ {
+		positions, err := ChallengePositions(12, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(positions) != 3 || !slices.IsSorted(positions) {
+			t.Fatalf("expected 3 sorted positions, got %v", positions)
+		}
+		for j, p := range positions {
+			if p < 1 || p > 12 || (j > 0 && p == positions[j-1]) {
+				t.Fatalf("invalid positions %v", positions)
+			}
+		}
+	}
+
+	for _, n := range []int{0, 13} {
+		if _, err := ChallengePositions(12, n); !errors.Is(err, ErrSampleSize) {
+			t.Errorf("expected %v to be %v", err, ErrSampleSize)
+		}
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	t.Parallel()
+
+	res, err := Challenge("corréct-horse", []int{2, 5, 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != "oéh" {
+		t.Errorf("expected %q, got %q", "oéh", res)
+	}
+
+	for _, positions := range [][]int{{0}, {14}, {2, 2}} {
+		if _, err := Challenge("corréct-horse", positions); !errors.Is(err, ErrInvalidPosition) {
+			t.Errorf("expected %v to be %v for %v", err, ErrInvalidPosition, positions)
+		}
+	}
+}
+
+func TestVerifyChallenge(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		positions []int
+		response  string
+		ok        bool
+	}{
+		{[]int{2, 5, 9}, "oéh", true},
+		{[]int{9, 2}, "ho", true},
+		{[]int{2, 5, 9}, "oeh", false},
+		{[]int{2, 5, 9}, "oé", false},
+		{[]int{2, 5, 9}, "oéhx", false},
+		{[]int{2, 5, 14}, "oéh", false},
+		{nil, "", false},
+	}
+
+	for _, tc := range cases {
+		if got := VerifyChallenge("corréct-horse", tc.positions, tc.response); got != tc.ok {
+			t.Errorf("expected VerifyChallenge(%v, %q) to be %t", tc.positions, tc.response, tc.ok)
+		}
+	}
+}
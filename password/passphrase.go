@@ -0,0 +1,141 @@
+package password
+
+import (
+	_ "embed"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrPassphraseWords is the error returned when a passphrase is requested with
+// fewer than one word.
+var ErrPassphraseWords = errors.New("passphrase must have at least one word")
+
+// EFFLongWordlistChecksum is the Wordlist.Checksum of the embedded EFF long
+// wordlist, verified when it is first loaded. internal/effwordlist derives it
+// from the upstream file once it has verified the SHA-256 of that file.
+const EFFLongWordlistChecksum = "01dc85182151d2b4486a454f7db85e8534a6bff1ed4eefd1c5f4273728e736b5"
+
+//go:embed eff_large_wordlist.txt
+var effLongList string
+
+// effLongWordlist returns the embedded EFF long wordlist.
+var effLongWordlist = sync.OnceValue(func() Wordlist {
+	return parseEFFLongWordlist(effLongList)
+})
+
+// parseEFFLongWordlist parses the EFF long wordlist and verifies its checksum.
+func parseEFFLongWordlist(list string) Wordlist {
+	var words Wordlist
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := words.VerifyIntegrity(EFFLongWordlistChecksum); err != nil {
+		panic("password: invalid EFF long wordlist: " + err.Error())
+	}
+	return words
+}
+
+// EFFLongWordlist returns a copy of the embedded EFF long wordlist, the default
+// wordlist of GeneratePassphrase. Its words are easy to type and remember, and
+// each of them contributes about 12.9 bits of entropy.
+func EFFLongWordlist() Wordlist {
+	return slices.Clone(effLongWordlist())
+}
+
+// Capitalization is the capitalization of the words of a passphrase.
+type Capitalization int
+
+// Capitalizations of the words of a passphrase.
+const (
+	// CapitalizeNone keeps the words as they are in the wordlist.
+	CapitalizeNone Capitalization = iota
+
+	// CapitalizeFirst capitalizes the first letter of every word.
+	CapitalizeFirst
+
+	// CapitalizeAll uppercases every word.
+	CapitalizeAll
+
+	// CapitalizeRandom capitalizes the first letter of every word with a
+	// probability of one half, which adds one bit of entropy per word.
+	CapitalizeRandom
+)
+
+// PassphraseInput used to define input parameters for GeneratePassphrase.
+type PassphraseInput struct {
+	// Words is the number of words of the passphrase.
+	Words int
+
+	// Separator is inserted between words, such as "-" or " ".
+	Separator string
+
+	// Capitalization is the capitalization of the words.
+	Capitalization Capitalization
+
+	// Wordlist is the list from which words are chosen. If nil, the embedded
+	// EFF long wordlist is used.
+	Wordlist Wordlist
+	_        struct{}
+}
+
+// GeneratePassphrase generates a passphrase of words chosen uniformly at
+// random, such as "correct-horse-battery-staple", using the Generator reader.
+// Words may repeat, so that every word contributes the full entropy of the
+// wordlist.
+func (g Generator) GeneratePassphrase(input PassphraseInput) (string, error) {
+	if input.Words < 1 {
+		return "", ErrPassphraseWords
+	}
+	list := input.Wordlist
+	if list == nil {
+		list = effLongWordlist()
+	}
+	if len(list) == 0 {
+		return "", ErrWordlistEmpty
+	}
+
+	words := make([]string, input.Words)
+	for i := range words {
+		j, err := UniformIndex(g.reader, len(list))
+		if err != nil {
+			return "", err
+		}
+
+		word := list[j]
+		switch input.Capitalization {
+		case CapitalizeFirst:
+			word = capitalize(word)
+		case CapitalizeAll:
+			word = strings.ToUpper(word)
+		case CapitalizeRandom:
+			flip, err := UniformIndex(g.reader, 2)
+			if err != nil {
+				return "", err
+			}
+			if flip == 1 {
+				word = capitalize(word)
+			}
+		}
+		words[i] = word
+	}
+	return strings.Join(words, input.Separator), nil
+}
+
+// GeneratePassphrase is the package shortcut for Generator.GeneratePassphrase.
+func GeneratePassphrase(input PassphraseInput) (string, error) {
+	return NewGenerator().GeneratePassphrase(input)
+}
+
+// capitalize uppercases the first letter of s.
+func capitalize(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
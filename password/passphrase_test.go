@@ -0,0 +1,122 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEFFLongWordlist(t *testing.T) {
+	t.Parallel()
+
+	list := EFFLongWordlist()
+	if len(list) != 7769 {
+		t.Fatalf("expected %d to be %d", len(list), 7769)
+	}
+	if err := list.VerifyIntegrity(EFFLongWordlistChecksum); err != nil {
+		t.Fatal(err)
+	}
+	if EFFLongWordlistChecksum != "01dc85182151d2b4486a454f7db85e8534a6bff1ed4eefd1c5f4273728e736b5" {
+		t.Errorf("unexpected checksum %s", EFFLongWordlistChecksum)
+	}
+	if !slices.IsSorted(list) {
+		t.Error("expected the wordlist to be sorted")
+	}
+	if len(slices.Compact(slices.Clone(list))) != len(list) {
+		t.Error("expected the wordlist to have unique words")
+	}
+	if list[0] != "abacus" || list[len(list)-1] != "zoom" {
+		t.Errorf("unexpected bounds %q and %q", list[0], list[len(list)-1])
+	}
+
+	list[0] = "changed"
+	if EFFLongWordlist()[0] != "abacus" {
+		t.Error("expected the wordlist to be copied")
+	}
+}
+
+func TestParseEFFLongWordlist(t *testing.T) {
+	t.Parallel()
+
+	if got := parseEFFLongWordlist(effLongList); len(got) != len(EFFLongWordlist()) {
+		t.Errorf("expected %d to be %d", len(got), len(EFFLongWordlist()))
+	}
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), ErrWordlistChecksum.Error()) {
+			t.Errorf("expected a tampered wordlist to panic, got %v", r)
+		}
+	}()
+	parseEFFLongWordlist(strings.Replace(effLongList, "\nzoom", "\nzoom\nzzz", 1))
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default", func(t *testing.T) {
+		t.Parallel()
+
+		list := EFFLongWordlist()
+		for i := 0; i < N; i++ {
+			res, err := GeneratePassphrase(PassphraseInput{Words: 4, Separator: " "})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			words := strings.Split(res, " ")
+			if len(words) != 4 {
+				t.Fatalf("expected 4 words in %q", res)
+			}
+			for _, word := range words {
+				if _, ok := slices.BinarySearch(list, word); !ok {
+					t.Errorf("unexpected word %q in %q", word, res)
+				}
+			}
+		}
+	})
+
+	t.Run("capitalization", func(t *testing.T) {
+		t.Parallel()
+
+		list := Wordlist{"apple", "banana"}
+		cases := []struct {
+			capitalization Capitalization
+			valid          func(word string) bool
+		}{
+			{CapitalizeNone, func(w string) bool { return w == "apple" || w == "banana" }},
+			{CapitalizeFirst, func(w string) bool { return w == "Apple" || w == "Banana" }},
+			{CapitalizeAll, func(w string) bool { return w == "APPLE" || w == "BANANA" }},
+			{CapitalizeRandom, func(w string) bool { return strings.EqualFold(w, "apple") || strings.EqualFold(w, "banana") }},
+		}
+
+		for _, tc := range cases {
+			res, err := GeneratePassphrase(PassphraseInput{
+				Words:          8,
+				Separator:      " ",
+				Capitalization: tc.capitalization,
+				Wordlist:       list,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, word := range strings.Split(res, " ") {
+				if !tc.valid(word) {
+					t.Errorf("unexpected word %q for capitalization %d", word, tc.capitalization)
+				}
+			}
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := GeneratePassphrase(PassphraseInput{}); !errors.Is(err, ErrPassphraseWords) {
+			t.Errorf("expected %v to be %v", err, ErrPassphraseWords)
+		}
+		if _, err := GeneratePassphrase(PassphraseInput{Words: 4, Wordlist: Wordlist{}}); !errors.Is(err, ErrWordlistEmpty) {
+			t.Errorf("expected %v to be %v", err, ErrWordlistEmpty)
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package password_test
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"log"
 
 	"github.com/juev/go-password/password"
@@ -53,3 +56,33 @@ func ExampleNewGenerator_custom() {
 	gen := password.NewGenerator().WithSymbols("!@#$%^()")
 	_ = gen // gen.Generate(...)
 }
+
+// securityKey stands in for a FIDO2 authenticator, such as a wrapper around
+// libfido2.
+type securityKey struct{}
+
+func (securityKey) HMACSecret(_ context.Context, salt []byte) ([]byte, error) {
+	// A real authenticator waits for a touch and computes the output in
+	// hardware.
+	mac := hmac.New(sha256.New, []byte("credential key"))
+	mac.Write(salt)
+	return mac.Sum(nil), nil
+}
+
+func ExampleNewHMACSecretReader() {
+	r, err := password.NewHMACSecretReader(context.Background(), securityKey{}, "example.com")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The same security key and site always give the same password.
+	res, err := password.NewGenerator().WithReader(r).Generate(password.Input{
+		Length:  24,
+		Digits:  4,
+		Symbols: 4,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Print(res)
+}
@@ -0,0 +1,131 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// ErrInvalidPattern is the error returned when a pattern ends with an escape
+// character, or has a placeholder for an empty charset.
+var ErrInvalidPattern = errors.New("invalid pattern")
+
+// Placeholders of a pattern.
+const (
+	PatternUpper  = 'L'
+	PatternLower  = 'l'
+	PatternDigit  = 'd'
+	PatternSymbol = 's'
+	PatternEscape = '\\'
+)
+
+// patternChar is a character of a pattern: either a literal, or a placeholder
+// for a random character of a charset.
+type patternChar struct {
+	literal rune
+	charset []rune
+}
+
+// parsePattern parses the pattern with the Generator charsets.
+func (g Generator) parsePattern(pattern string) ([]patternChar, error) {
+	chars := make([]patternChar, 0, len(pattern))
+	escaped := false
+	for _, r := range pattern {
+		if escaped {
+			chars = append(chars, patternChar{literal: r})
+			escaped = false
+			continue
+		}
+
+		var charset string
+		switch r {
+		case PatternEscape:
+			escaped = true
+			continue
+		case PatternUpper:
+			charset = g.upperLetters
+		case PatternLower:
+			charset = g.lowerLetters
+		case PatternDigit:
+			charset = g.digits
+		case PatternSymbol:
+			charset = g.symbols
+		default:
+			chars = append(chars, patternChar{literal: r})
+			continue
+		}
+
+		if charset == "" {
+			return nil, fmt.Errorf("%w: empty charset for %q", ErrInvalidPattern, r)
+		}
+		chars = append(chars, patternChar{charset: []rune(charset)})
+	}
+
+	if escaped {
+		return nil, fmt.Errorf("%w: trailing %q", ErrInvalidPattern, PatternEscape)
+	}
+	return chars, nil
+}
+
+// GenerateFromPattern generates a password of a fixed shape, as required by
+// some legacy systems. Every placeholder of the pattern is replaced with a
+// random character of the matching Generator charset: PatternUpper ('L') with
+// an uppercase letter, PatternLower ('l') with a lowercase letter,
+// PatternDigit ('d') with a digit and PatternSymbol ('s') with a symbol. Other
+// characters, and characters preceded by PatternEscape ('\'), are kept as is.
+// For example, "Lll-dddd-ss" generates passwords such as "Kxq-4821-#!".
+//
+// Characters may repeat. Filters and post-processors are not applied, since
+// they would change the shape of the password. See PatternEntropy.
+func (g Generator) GenerateFromPattern(pattern string) (string, error) {
+	chars, err := g.parsePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	for _, c := range chars {
+		if c.charset == nil {
+			sb.WriteRune(c.literal)
+			continue
+		}
+
+		i, err := UniformIndex(g.reader, len(c.charset))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteRune(c.charset[i])
+	}
+
+	res := sb.String()
+	if g.latin1 {
+		if err := checkLatin1("password", res); err != nil {
+			return "", err
+		}
+	}
+	return res, nil
+}
+
+// PatternEntropy returns the entropy, in bits, of passwords generated from the
+// pattern by GenerateFromPattern. Literals add no entropy.
+func (g Generator) PatternEntropy(pattern string) (float64, error) {
+	chars, err := g.parsePattern(pattern)
+	if err != nil {
+		return 0, err
+	}
+
+	bits := 0.0
+	for _, c := range chars {
+		if c.charset != nil {
+			bits += math.Log2(float64(len(c.charset)))
+		}
+	}
+	return bits, nil
+}
+
+// GenerateFromPattern is the package shortcut for
+// Generator.GenerateFromPattern.
+func GenerateFromPattern(pattern string) (string, error) {
+	return NewGenerator().GenerateFromPattern(pattern)
+}
@@ -0,0 +1,64 @@
+package password
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGenerateFromPattern(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < N; i++ {
+		res, err := GenerateFromPattern(`Lll-dddd-ss\d`)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runes := []rune(res)
+		if len(runes) != 12 {
+			t.Fatalf("expected %q to have 12 characters", res)
+		}
+		for j, charset := range []string{UpperLetters, LowerLetters, LowerLetters, "-", Digits, Digits, Digits, Digits, "-", Symbols, Symbols, "d"} {
+			if !strings.ContainsRune(charset, runes[j]) {
+				t.Errorf("expected character %d of %q to be one of %q", j, res, charset)
+			}
+		}
+	}
+
+	res, err := NewGenerator().WithUpperLetters("Ж").GenerateFromPattern("L")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != "Ж" {
+		t.Errorf("expected %q to be %q", res, "Ж")
+	}
+
+	for _, pattern := range []string{`ll\`, "s"} {
+		if _, err := NewGenerator().WithSymbols("").GenerateFromPattern(pattern); !errors.Is(err, ErrInvalidPattern) {
+			t.Errorf("expected %v to be %v", err, ErrInvalidPattern)
+		}
+	}
+
+	if _, err := NewGenerator().WithReader(errReader{}).GenerateFromPattern("d"); err == nil {
+		t.Error("expected the reader error to be returned")
+	}
+}
+
+func TestGeneratorPatternEntropy(t *testing.T) {
+	t.Parallel()
+
+	got, err := NewGenerator().PatternEntropy(`Lll-dddd-ss\s`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 3*math.Log2(26) + 4*math.Log2(10) + 2*math.Log2(float64(len(Symbols)))
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("expected %f to be %f", got, want)
+	}
+
+	if _, err := NewGenerator().PatternEntropy(`\`); !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidPattern)
+	}
+}
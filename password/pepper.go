@@ -0,0 +1,173 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// MinPepperBytes is the minimum size of a pepper, in bytes.
+	MinPepperBytes = 16
+
+	// DefaultPepperBytes is the recommended size of a pepper, in bytes, which
+	// matches the key size of HMAC-SHA-256.
+	DefaultPepperBytes = 32
+)
+
+var (
+	// ErrPepperSize is the error returned when a pepper is requested with
+	// fewer than MinPepperBytes bytes.
+	ErrPepperSize = fmt.Errorf("pepper must be at least %d bytes", MinPepperBytes)
+
+	// ErrPepperVersion is the error returned when a pepper added to a
+	// PepperKeyring is not newer than its current pepper.
+	ErrPepperVersion = errors.New("pepper version must be newer than the current pepper")
+)
+
+// Pepper is a secret mixed into password hashes, typically as the key of an
+// HMAC applied before or after the password hashing function, and stored apart
+// from the hashes so that a leaked database alone cannot be attacked offline.
+// The version is stored next to every hash, so that hashes can be verified
+// with the pepper they were created with while peppers are rotated.
+//
+// String and every fmt verb print the version but not the value.
+type Pepper struct {
+	Version uint32
+	Value   []byte
+
+	// CreatedAt is the time the pepper was generated.
+	CreatedAt time.Time
+
+	// RetiredAt is the time a newer pepper replaced it, or zero if it is
+	// current. Retired peppers only verify existing hashes, which should be
+	// rehashed with the current pepper at the next login.
+	RetiredAt time.Time
+}
+
+// String returns the version of the pepper, redacting its value.
+func (p Pepper) String() string {
+	return fmt.Sprintf("pepper v%d %s", p.Version, Redacted)
+}
+
+// Format implements fmt.Formatter, so that no verb prints the value.
+func (p Pepper) Format(f fmt.State, _ rune) {
+	io.WriteString(f, p.String())
+}
+
+// GeneratePepper generates a pepper of the given size with the Generator
+// reader. Its version is 1; PepperKeyring.Rotate numbers the peppers it
+// generates.
+func (g Generator) GeneratePepper(bytes int) (Pepper, error) {
+	if bytes < MinPepperBytes {
+		return Pepper{}, ErrPepperSize
+	}
+
+	value := make([]byte, bytes)
+	if _, err := io.ReadFull(g.reader, value); err != nil {
+		return Pepper{}, err
+	}
+	return Pepper{Version: 1, Value: value, CreatedAt: time.Now()}, nil
+}
+
+// GeneratePepper is the package shortcut for Generator.GeneratePepper.
+func GeneratePepper(bytes int) (Pepper, error) {
+	return NewGenerator().GeneratePepper(bytes)
+}
+
+// PepperKeyring is a small set of peppers: the current one, with which new
+// hashes are created, and the most recent retired ones, with which existing
+// hashes are still verified. It is safe for concurrent use. The values of the
+// peppers it returns must not be modified.
+type PepperKeyring struct {
+	mu      sync.RWMutex
+	size    int
+	gen     Generator
+	peppers []Pepper
+}
+
+// NewPepperKeyring creates an empty keyring retaining at most size peppers,
+// including the current one. It generates peppers with the given Generator.
+// A size below 2 is treated as 2, so that hashes created with the previous
+// pepper can be verified after a rotation.
+func NewPepperKeyring(g Generator, size int) *PepperKeyring {
+	return &PepperKeyring{size: max(size, 2), gen: g}
+}
+
+// Rotate generates a pepper of the given size, makes it current and retires
+// the previous one. Its version follows that of the previous pepper. The
+// oldest peppers are dropped beyond the size of the keyring; hashes created
+// with them can no longer be verified.
+func (k *PepperKeyring) Rotate(bytes int) (Pepper, error) {
+	p, err := k.gen.GeneratePepper(bytes)
+	if err != nil {
+		return Pepper{}, err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if n := len(k.peppers); n > 0 {
+		p.Version = k.peppers[n-1].Version + 1
+	}
+	k.add(p)
+	return p, nil
+}
+
+// Add makes the given pepper current, such as when loading the keyring from
+// the secret store, oldest pepper first. Its version must be newer than the
+// current one, or ErrPepperVersion is returned.
+func (k *PepperKeyring) Add(p Pepper) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if n := len(k.peppers); n > 0 && p.Version <= k.peppers[n-1].Version {
+		return fmt.Errorf("%w: %d is not newer than %d", ErrPepperVersion, p.Version, k.peppers[n-1].Version)
+	}
+	k.add(p)
+	return nil
+}
+
+// add makes p current. k.mu must be held.
+func (k *PepperKeyring) add(p Pepper) {
+	p.RetiredAt = time.Time{}
+	if n := len(k.peppers); n > 0 {
+		k.peppers[n-1].RetiredAt = p.CreatedAt
+	}
+	k.peppers = append(k.peppers, p)
+	if n := len(k.peppers); n > k.size {
+		k.peppers = slices.Delete(k.peppers, 0, n-k.size)
+	}
+}
+
+// Current returns the current pepper, and whether there is one.
+func (k *PepperKeyring) Current() (Pepper, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if len(k.peppers) == 0 {
+		return Pepper{}, false
+	}
+	return k.peppers[len(k.peppers)-1], true
+}
+
+// Lookup returns the pepper with the given version, and whether it is still in
+// the keyring.
+func (k *PepperKeyring) Lookup(version uint32) (Pepper, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	for _, p := range k.peppers {
+		if p.Version == version {
+			return p, true
+		}
+	}
+	return Pepper{}, false
+}
+
+// Peppers returns the peppers of the keyring, oldest first, for example to
+// persist them.
+func (k *PepperKeyring) Peppers() []Pepper {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return slices.Clone(k.peppers)
+}
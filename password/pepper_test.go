@@ -0,0 +1,80 @@
+package password
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePepper(t *testing.T) {
+	t.Parallel()
+
+	p, err := GeneratePepper(DefaultPepperBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Value) != DefaultPepperBytes || p.Version != 1 || p.CreatedAt.IsZero() || !p.RetiredAt.IsZero() {
+		t.Errorf("unexpected pepper %#v", p)
+	}
+	if bytes.Equal(p.Value, make([]byte, DefaultPepperBytes)) {
+		t.Error("expected a random value")
+	}
+
+	for _, s := range []string{p.String(), fmt.Sprintf("%v %+v %#v %x", p, p, p, p)} {
+		if strings.Contains(s, fmt.Sprintf("%x", p.Value)) || !strings.Contains(s, Redacted) {
+			t.Errorf("expected %q to redact the value", s)
+		}
+	}
+
+	if _, err := GeneratePepper(MinPepperBytes - 1); !errors.Is(err, ErrPepperSize) {
+		t.Errorf("expected %v to be %v", err, ErrPepperSize)
+	}
+}
+
+func TestPepperKeyring(t *testing.T) {
+	t.Parallel()
+
+	k := NewPepperKeyring(NewGenerator(), 3)
+	if _, ok := k.Current(); ok {
+		t.Fatal("expected no current pepper")
+	}
+
+	var rotated []Pepper
+	for i := 0; i < 4; i++ {
+		p, err := k.Rotate(DefaultPepperBytes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Version != uint32(i+1) {
+			t.Errorf("expected version %d, got %d", i+1, p.Version)
+		}
+		rotated = append(rotated, p)
+	}
+
+	current, ok := k.Current()
+	if !ok || current.Version != 4 || !current.RetiredAt.IsZero() {
+		t.Errorf("unexpected current pepper %v", current)
+	}
+	if _, ok := k.Lookup(1); ok {
+		t.Error("expected the oldest pepper to be dropped")
+	}
+	p, ok := k.Lookup(2)
+	if !ok || !bytes.Equal(p.Value, rotated[1].Value) || p.RetiredAt.IsZero() {
+		t.Errorf("unexpected retired pepper %v", p)
+	}
+	if got := len(k.Peppers()); got != 3 {
+		t.Errorf("expected 3 peppers, got %d", got)
+	}
+
+	if err := k.Add(Pepper{Version: 4, Value: make([]byte, 32)}); !errors.Is(err, ErrPepperVersion) {
+		t.Errorf("expected %v to be %v", err, ErrPepperVersion)
+	}
+	if err := k.Add(Pepper{Version: 10, Value: make([]byte, 32)}); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := k.Rotate(DefaultPepperBytes); err != nil || p.Version != 11 {
+		t.Errorf("expected version 11, got %d (%v)", p.Version, err)
+	}
+}
@@ -0,0 +1,73 @@
+package password
+
+import (
+	_ "embed"
+	"strings"
+	"sync"
+)
+
+// PhoneticallyAmbiguous is the list of letters that are easily confused with
+// one another when dictated over the phone, such as B/P/V, D/T, M/N and F/S.
+const PhoneticallyAmbiguous = "bcdefgmnpstvzBCDEFGMNPSTVZ"
+
+//go:embed homophones.txt
+var homophonesList string
+
+// homophones returns the set of words that sound like another word.
+var homophones = sync.OnceValue(func() map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(homophonesList, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		for _, word := range strings.Fields(line) {
+			set[word] = struct{}{}
+		}
+	}
+	return set
+})
+
+// WithoutPhoneticallyAmbiguous creates a new Generator from another Generator
+// with all PhoneticallyAmbiguous letters removed from the lower and upper
+// letters. This is intended for codes that are commonly dictated verbally;
+// since case is hard to convey by voice, such codes should usually also set
+// Input.NoUpper.
+func (g Generator) WithoutPhoneticallyAmbiguous() Generator {
+	return g.With(WithoutPhoneticallyAmbiguous())
+}
+
+// WithoutPhoneticallyAmbiguous returns an Option removing PhoneticallyAmbiguous
+// letters, as Generator.WithoutPhoneticallyAmbiguous.
+func WithoutPhoneticallyAmbiguous() Option {
+	return func(g *Generator) {
+		g.lowerLetters = removeChars(g.lowerLetters, PhoneticallyAmbiguous)
+		g.upperLetters = removeChars(g.upperLetters, PhoneticallyAmbiguous)
+	}
+}
+
+// WithoutHomophones returns a copy of the wordlist with all words that sound
+// like another word, such as "accept" and "except", removed.
+func (w Wordlist) WithoutHomophones() Wordlist {
+	set := homophones()
+
+	res := make(Wordlist, 0, len(w))
+	for _, word := range w {
+		if _, ok := set[strings.ToLower(word)]; ok {
+			continue
+		}
+		res = append(res, word)
+	}
+	return res
+}
+
+// removeChars returns s with all characters in chars removed.
+func removeChars(s, chars string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(chars, r) {
+			return -1
+		}
+		return r
+	}, s)
+}
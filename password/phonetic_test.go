@@ -0,0 +1,35 @@
+package password
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratorWithoutPhoneticallyAmbiguous(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator().WithoutPhoneticallyAmbiguous()
+	for i := 0; i < N; i++ {
+		res, err := gen.Generate(Input{
+			Length:      10,
+			Digits:      4,
+			AllowRepeat: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.ContainsAny(res, PhoneticallyAmbiguous) {
+			t.Errorf("%q should not contain phonetically ambiguous letters", res)
+		}
+	}
+}
+
+func TestWordlistWithoutHomophones(t *testing.T) {
+	t.Parallel()
+
+	list := Wordlist{"accept", "apple", "except", "Knight", "zebra"}
+	if got, want := strings.Join(list.WithoutHomophones(), " "), "apple zebra"; got != want {
+		t.Errorf("expected %q to be %q", got, want)
+	}
+}
@@ -0,0 +1,93 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+)
+
+// pinSequenceLength is the length of the runs of consecutive digits rejected
+// by PINOptions.NoSequences.
+const pinSequenceLength = 3
+
+// ErrPINLength is the error returned when a PIN is shorter than one digit, or
+// longer than ten digits without repeats.
+var ErrPINLength = errors.New("invalid PIN length")
+
+// PINOptions used to define options for GeneratePIN.
+type PINOptions struct {
+	// NoRepeat disallows digits appearing more than once, as in "1121".
+	NoRepeat bool
+
+	// NoSequences disallows runs of three or more consecutive digits, up or
+	// down, as in "1234" or "9870".
+	NoSequences bool
+
+	// NoLeadingZero disallows PINs starting with zero, which some systems
+	// store as numbers and truncate.
+	NoLeadingZero bool
+	_             struct{}
+}
+
+// GeneratePIN generates a numeric PIN of the given length, using the Generator
+// reader. The Generator charsets are not used: PINs are always made of the
+// digits 0 to 9. PINs which the options disallow are generated again, so that
+// all the others are equally likely; ErrFilterAttemptsExceeded is returned if
+// none is found.
+func (g Generator) GeneratePIN(length int, opts PINOptions) (string, error) {
+	if length < 1 || (opts.NoRepeat && length > len(Digits)) {
+		return "", fmt.Errorf("%w: %d", ErrPINLength, length)
+	}
+
+	pin := make([]byte, length)
+	for i := 0; i < maxFilterAttempts; i++ {
+		if opts.NoRepeat {
+			digits := []byte(Digits)
+			if err := shuffle(g.reader, digits); err != nil {
+				return "", err
+			}
+			copy(pin, digits)
+		} else {
+			idx, err := UniformIndices(g.reader, len(Digits), length)
+			if err != nil {
+				return "", err
+			}
+			for j, k := range idx {
+				pin[j] = Digits[k]
+			}
+		}
+
+		if opts.NoLeadingZero && pin[0] == '0' {
+			continue
+		}
+		if opts.NoSequences && hasDigitSequence(pin, pinSequenceLength) {
+			continue
+		}
+		return string(pin), nil
+	}
+	return "", ErrFilterAttemptsExceeded
+}
+
+// hasDigitSequence reports whether pin has a run of n consecutive digits, up
+// or down.
+func hasDigitSequence(pin []byte, n int) bool {
+	up, down := 1, 1
+	for i := 1; i < len(pin); i++ {
+		switch int(pin[i]) - int(pin[i-1]) {
+		case 1:
+			up, down = up+1, 1
+		case -1:
+			up, down = 1, down+1
+		default:
+			up, down = 1, 1
+		}
+		if up >= n || down >= n {
+			return true
+		}
+	}
+	return false
+}
+
+// GeneratePIN is the package shortcut for Generator.GeneratePIN.
+func GeneratePIN(length int, opts PINOptions) (string, error) {
+	return NewGenerator().GeneratePIN(length, opts)
+}
@@ -0,0 +1,67 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePIN(t *testing.T) {
+	t.Parallel()
+
+	opts := PINOptions{NoRepeat: true, NoSequences: true, NoLeadingZero: true}
+	for i := 0; i < N; i++ {
+		pin, err := GeneratePIN(6, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(pin) != 6 || strings.Trim(pin, Digits) != "" {
+			t.Fatalf("expected %q to be 6 digits", pin)
+		}
+		if pin[0] == '0' {
+			t.Errorf("%q should not start with zero", pin)
+		}
+		for j := range pin {
+			if strings.Count(pin, pin[j:j+1]) > 1 {
+				t.Errorf("%q should not repeat digits", pin)
+				break
+			}
+		}
+		if hasDigitSequence([]byte(pin), pinSequenceLength) {
+			t.Errorf("%q should not have sequences", pin)
+		}
+	}
+
+	pin, err := GeneratePIN(10, PINOptions{NoRepeat: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pin) != 10 {
+		t.Errorf("expected %q to be 10 digits", pin)
+	}
+
+	for _, length := range []int{0, 11} {
+		if _, err := GeneratePIN(length, PINOptions{NoRepeat: true}); !errors.Is(err, ErrPINLength) {
+			t.Errorf("expected %v to be %v", err, ErrPINLength)
+		}
+	}
+}
+
+func TestHasDigitSequence(t *testing.T) {
+	t.Parallel()
+
+	for pin, want := range map[string]bool{
+		"1234": true,
+		"9870": true,
+		"0121": true,
+		"1212": false,
+		"1357": false,
+		"9010": false,
+		"5":    false,
+	} {
+		if got := hasDigitSequence([]byte(pin), 3); got != want {
+			t.Errorf("expected hasDigitSequence(%q) to be %t", pin, want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrPolicyViolation is the error matched by a *PolicyError.
+var ErrPolicyViolation = errors.New("password violates the policy")
+
+// Rule identifies a rule of a Policy.
+type Rule string
+
+// Rules of a Policy.
+const (
+	RuleMinLength     Rule = "min_length"
+	RuleMaxLength     Rule = "max_length"
+	RuleMinDigits     Rule = "min_digits"
+	RuleMinSymbols    Rule = "min_symbols"
+	RuleRequiredClass Rule = "required_class"
+	RuleForbidden     Rule = "forbidden"
+)
+
+// Policy is a set of rules passwords must follow, such as those of a system
+// which accepts user-chosen passwords. Lengths and counts are in runes, and
+// characters are classified with ClassOf.
+type Policy struct {
+	// MinLength and MaxLength are the range of lengths. A MaxLength of zero
+	// means no maximum.
+	MinLength int `json:"min_length,omitempty"`
+	MaxLength int `json:"max_length,omitempty"`
+
+	// MinDigits and MinSymbols are the minimum number of digits and symbols.
+	MinDigits  int `json:"min_digits,omitempty"`
+	MinSymbols int `json:"min_symbols,omitempty"`
+
+	// RequiredClasses are the classes of which at least one character must
+	// be present.
+	RequiredClasses []Class `json:"required_classes,omitempty"`
+
+	// Forbidden is the list of characters which must not be present.
+	Forbidden string `json:"forbidden,omitempty"`
+}
+
+// Violation is a rule of a Policy which a password does not follow.
+type Violation struct {
+	Rule    Rule
+	Message string
+}
+
+// PolicyError is the error returned by Policy.Validate, listing every rule
+// the password does not follow.
+type PolicyError struct {
+	Violations []Violation
+}
+
+// Error implements error.
+func (e *PolicyError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Message
+	}
+	return ErrPolicyViolation.Error() + ": " + strings.Join(msgs, "; ")
+}
+
+// Is reports whether target is ErrPolicyViolation.
+func (e *PolicyError) Is(target error) bool {
+	return target == ErrPolicyViolation
+}
+
+// Rules returns the rules the password does not follow, in order.
+func (e *PolicyError) Rules() []Rule {
+	rules := make([]Rule, len(e.Violations))
+	for i, v := range e.Violations {
+		rules[i] = v.Rule
+	}
+	return rules
+}
+
+// Validate checks the password against every rule of the policy. If it does
+// not follow all of them, it returns a *PolicyError listing the rules which
+// failed.
+func (p Policy) Validate(pw string) error {
+	var violations []Violation
+	fail := func(rule Rule, format string, args ...any) {
+		violations = append(violations, Violation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	runes := []rune(pw)
+	counts := make(map[Class]int)
+	for _, r := range runes {
+		counts[ClassOf(r)]++
+	}
+
+	if len(runes) < p.MinLength {
+		fail(RuleMinLength, "must be at least %d characters long", p.MinLength)
+	}
+	if p.MaxLength > 0 && len(runes) > p.MaxLength {
+		fail(RuleMaxLength, "must be at most %d characters long", p.MaxLength)
+	}
+	if counts[ClassDigit] < p.MinDigits {
+		fail(RuleMinDigits, "must contain at least %d digits", p.MinDigits)
+	}
+	if counts[ClassSymbol] < p.MinSymbols {
+		fail(RuleMinSymbols, "must contain at least %d symbols", p.MinSymbols)
+	}
+	for _, c := range p.RequiredClasses {
+		if counts[c] == 0 {
+			fail(RuleRequiredClass, "must contain at least one %s character", c)
+		}
+	}
+	if i := strings.IndexAny(pw, p.Forbidden); p.Forbidden != "" && i >= 0 {
+		r := []rune(pw[i:])[0]
+		fail(RuleForbidden, "must not contain %q", r)
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// PolicyFromInput returns the policy matching the given generation input, so
+// that user-chosen passwords can be validated against the same rules as
+// generated ones: at least the length, digits and symbols of the input. Every
+// password the input generates follows it. Unlike Generator.Validate, it
+// accepts longer passwords and characters outside of the Generator charsets.
+func PolicyFromInput(input Input) Policy {
+	return Policy{
+		MinLength:  input.Length,
+		MinDigits:  max(input.Digits, input.MinDigits),
+		MinSymbols: max(input.Symbols, input.MinSymbols),
+	}
+}
@@ -0,0 +1,111 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnsatisfiablePolicy is the error returned when no password can follow a
+// policy.
+var ErrUnsatisfiablePolicy = errors.New("no password can follow the policy")
+
+// IntersectPolicies returns the strictest common policy of a and b: every
+// password following it follows both. It has the larger minimums, the smaller
+// maximum length, and the required classes and forbidden characters of both.
+// This is the policy of a credential shared by two systems with different
+// rules. If no password can follow both, ErrUnsatisfiablePolicy is returned.
+func IntersectPolicies(a, b Policy) (Policy, error) {
+	p := Policy{
+		MinLength:  max(a.MinLength, b.MinLength),
+		MaxLength:  a.MaxLength,
+		MinDigits:  max(a.MinDigits, b.MinDigits),
+		MinSymbols: max(a.MinSymbols, b.MinSymbols),
+	}
+	if p.MaxLength == 0 || (b.MaxLength > 0 && b.MaxLength < p.MaxLength) {
+		p.MaxLength = b.MaxLength
+	}
+
+	for _, classes := range [][]Class{a.RequiredClasses, b.RequiredClasses} {
+		for _, c := range classes {
+			if !slices.Contains(p.RequiredClasses, c) {
+				p.RequiredClasses = append(p.RequiredClasses, c)
+			}
+		}
+	}
+	for _, r := range a.Forbidden + b.Forbidden {
+		if !strings.ContainsRune(p.Forbidden, r) {
+			p.Forbidden += string(r)
+		}
+	}
+
+	if err := p.satisfiable(); err != nil {
+		return Policy{}, err
+	}
+	return p, nil
+}
+
+// satisfiable checks that some password can follow the policy.
+func (p Policy) satisfiable() error {
+	if p.MaxLength == 0 {
+		return nil
+	}
+	if p.MinLength > p.MaxLength {
+		return fmt.Errorf("%w: minimum length %d exceeds maximum length %d", ErrUnsatisfiablePolicy, p.MinLength, p.MaxLength)
+	}
+
+	// The shortest password has the minimum digits and symbols, and one
+	// character of every other required class.
+	need := p.MinDigits + p.MinSymbols
+	for _, c := range p.RequiredClasses {
+		if (c != ClassDigit || p.MinDigits == 0) && (c != ClassSymbol || p.MinSymbols == 0) {
+			need++
+		}
+	}
+	if need > p.MaxLength {
+		return fmt.Errorf("%w: %d required characters exceed maximum length %d", ErrUnsatisfiablePolicy, need, p.MaxLength)
+	}
+	return nil
+}
+
+// MergePolicies returns the most permissive policy covering all the given
+// policies: every password following any of them follows it. It has the
+// smallest minimums, the largest maximum length (none if any policy has
+// none), and only the required classes and forbidden characters common to all
+// policies. This consolidates legacy rule sets into a single standard which
+// rejects no password any of them accepted. Without policies, the zero Policy
+// is returned.
+func MergePolicies(policies ...Policy) Policy {
+	if len(policies) == 0 {
+		return Policy{}
+	}
+
+	p := policies[0]
+	p.RequiredClasses = slices.Clone(p.RequiredClasses)
+	for _, q := range policies[1:] {
+		p.MinLength = min(p.MinLength, q.MinLength)
+		p.MinDigits = min(p.MinDigits, q.MinDigits)
+		p.MinSymbols = min(p.MinSymbols, q.MinSymbols)
+		if p.MaxLength > 0 {
+			p.MaxLength = max(p.MaxLength, q.MaxLength)
+			if q.MaxLength == 0 {
+				p.MaxLength = 0
+			}
+		}
+
+		p.RequiredClasses = slices.DeleteFunc(p.RequiredClasses, func(c Class) bool {
+			return !slices.Contains(q.RequiredClasses, c)
+		})
+		p.Forbidden = strings.Map(func(r rune) rune {
+			if !strings.ContainsRune(q.Forbidden, r) {
+				return -1
+			}
+			return r
+		}, p.Forbidden)
+	}
+	if len(p.RequiredClasses) == 0 {
+		p.RequiredClasses = nil
+	}
+	return p
+}
@@ -0,0 +1,88 @@
+package password
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestIntersectPolicies(t *testing.T) {
+	t.Parallel()
+
+	a := Policy{MinLength: 8, MaxLength: 32, MinDigits: 2, RequiredClasses: []Class{ClassUpper}, Forbidden: "'\""}
+	b := Policy{MinLength: 12, MaxLength: 20, MinSymbols: 1, RequiredClasses: []Class{ClassLower, ClassUpper}, Forbidden: "\\'"}
+
+	got, err := IntersectPolicies(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Policy{
+		MinLength:       12,
+		MaxLength:       20,
+		MinDigits:       2,
+		MinSymbols:      1,
+		RequiredClasses: []Class{ClassUpper, ClassLower},
+		Forbidden:       "'\"\\",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	// A password following the intersection follows both policies.
+	if err := got.Validate("Abcdefgh12!x"); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []Policy{a, b} {
+		if err := p.Validate("Abcdefgh12!x"); err != nil {
+			t.Errorf("expected password to follow %+v: %v", p, err)
+		}
+	}
+
+	if got, err := IntersectPolicies(Policy{MinLength: 4}, Policy{MaxLength: 10}); err != nil || got.MaxLength != 10 {
+		t.Errorf("expected the only maximum to be kept, got %+v, %v", got, err)
+	}
+
+	for _, tc := range []struct{ a, b Policy }{
+		{Policy{MinLength: 16}, Policy{MaxLength: 12}},
+		{Policy{MaxLength: 4, MinDigits: 3}, Policy{MinSymbols: 1, RequiredClasses: []Class{ClassLower}}},
+	} {
+		if _, err := IntersectPolicies(tc.a, tc.b); !errors.Is(err, ErrUnsatisfiablePolicy) {
+			t.Errorf("expected %+v and %+v to be unsatisfiable, got %v", tc.a, tc.b, err)
+		}
+	}
+}
+
+func TestMergePolicies(t *testing.T) {
+	t.Parallel()
+
+	a := Policy{MinLength: 8, MaxLength: 32, MinDigits: 2, RequiredClasses: []Class{ClassUpper, ClassLower}, Forbidden: "'\""}
+	b := Policy{MinLength: 12, MaxLength: 20, MinSymbols: 1, RequiredClasses: []Class{ClassLower}, Forbidden: "\\'"}
+
+	got := MergePolicies(a, b)
+	want := Policy{
+		MinLength:       8,
+		MaxLength:       32,
+		RequiredClasses: []Class{ClassLower},
+		Forbidden:       "'",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+	if len(a.RequiredClasses) != 2 {
+		t.Errorf("expected the policies not to be modified, got %+v", a)
+	}
+
+	// Passwords following any policy follow the merged policy.
+	for _, pw := range []string{"Abcdefg12", "abcdefghijk!"} {
+		if err := got.Validate(pw); err != nil {
+			t.Errorf("expected %q to follow the merged policy: %v", pw, err)
+		}
+	}
+
+	if got := MergePolicies(a, Policy{MinLength: 4}); got.MaxLength != 0 || got.RequiredClasses != nil {
+		t.Errorf("expected no maximum and no required classes, got %+v", got)
+	}
+	if got := MergePolicies(); !reflect.DeepEqual(got, Policy{}) {
+		t.Errorf("expected the zero policy, got %+v", got)
+	}
+}
@@ -0,0 +1,105 @@
+package password
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	p := Policy{MinLength: 10, MinDigits: 2, RequiredClasses: []Class{ClassUpper}, Forbidden: " "}
+	if err := p.Validate("Abcdefgh12"); err != nil {
+		t.Errorf("expected password to be valid, got %v", err)
+	}
+
+	err := p.Validate("abc def1")
+	if !errors.Is(err, ErrPolicyViolation) {
+		t.Fatalf("expected %v to be %v", err, ErrPolicyViolation)
+	}
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected a policy error, got %T", err)
+	}
+	want := []Rule{RuleMinLength, RuleMinDigits, RuleRequiredClass, RuleForbidden}
+	if got := policyErr.Rules(); !slices.Equal(got, want) {
+		t.Errorf("expected rules %v, got %v", want, got)
+	}
+	if got, want := err.Error(), `password violates the policy: must be at least 10 characters long; must contain at least 2 digits; must contain at least one upper character; must not contain ' '`; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestPolicyJSON(t *testing.T) {
+	t.Parallel()
+
+	p := Policy{MinLength: 8, RequiredClasses: []Class{ClassLower, ClassSymbol}}
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"min_length":8,"required_classes":["lower","symbol"]}`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	var decoded Policy
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(decoded.RequiredClasses, p.RequiredClasses) {
+		t.Errorf("expected %v, got %v", p.RequiredClasses, decoded.RequiredClasses)
+	}
+
+	if err := json.Unmarshal([]byte(`{"required_classes":["emoji"]}`), &decoded); !errors.Is(err, ErrUnknownClass) {
+		t.Errorf("expected %v to be %v", err, ErrUnknownClass)
+	}
+}
+
+func TestRunPolicyCorpus(t *testing.T) {
+	t.Parallel()
+
+	if len(PolicyCorpus()) < 20 {
+		t.Fatalf("expected a corpus, got %d cases", len(PolicyCorpus()))
+	}
+
+	for _, m := range RunPolicyCorpus(Policy.Validate) {
+		t.Errorf("case %q: expected valid=%t rules=%v, got %v", m.Case.Name, m.Case.Valid, m.Case.Rules, m.Err)
+	}
+
+	// A validator ignoring forbidden characters is caught.
+	lax := func(p Policy, pw string) error {
+		p.Forbidden = ""
+		return p.Validate(pw)
+	}
+	if len(RunPolicyCorpus(lax)) == 0 {
+		t.Error("expected a lax validator to fail the corpus")
+	}
+}
+
+func TestPolicyFromInput(t *testing.T) {
+	t.Parallel()
+
+	input := Input{Length: 16, Digits: 3, Symbols: 2}
+	p := PolicyFromInput(input)
+	if p.MinLength != 16 || p.MinDigits != 3 || p.MinSymbols != 2 {
+		t.Errorf("unexpected policy %+v", p)
+	}
+
+	for i := 0; i < 100; i++ {
+		res, err := Generate(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.Validate(res); err != nil {
+			t.Errorf("expected %q to follow the policy, got %v", res, err)
+		}
+	}
+
+	err := p.Validate("correct horse battery staple")
+	var policyErr *PolicyError
+	if !errors.As(err, &policyErr) || !slices.Equal(policyErr.Rules(), []Rule{RuleMinDigits, RuleMinSymbols}) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
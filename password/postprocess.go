@@ -0,0 +1,151 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrChecksumAlphabet is the error returned when a password contains a
+// character which is not in the checksum alphabet.
+var ErrChecksumAlphabet = errors.New("character is not in the checksum alphabet")
+
+// PostProcessor transforms a generated password. Post-processors run in the
+// order in which they were added to the Generator, each receiving the output of
+// the previous one, before the Generator filters are applied.
+//
+// Post-processors change what Input describes: Input.Length is the length
+// before post-processing, and a post-processor may add characters which carry
+// no entropy (such as separators or check characters) or remove entropy (such
+// as case folding). The documentation of each post-processor states its effect.
+type PostProcessor interface {
+	Process(s string) (string, error)
+}
+
+// PostProcessorFunc is an adapter to allow the use of ordinary functions as
+// post-processors, for example for Unicode normalization.
+type PostProcessorFunc func(s string) (string, error)
+
+// Process calls f(s).
+func (f PostProcessorFunc) Process(s string) (string, error) {
+	return f(s)
+}
+
+// WithPostProcessors creates a new Generator from another Generator with the
+// given post-processors appended to its existing ones.
+func (g Generator) WithPostProcessors(processors ...PostProcessor) Generator {
+	return g.With(WithPostProcessors(processors...))
+}
+
+// postProcess runs the Generator post-processors on the given password.
+func (g Generator) postProcess(s string) (string, error) {
+	for _, p := range g.postProcessors {
+		var err error
+		s, err = p.Process(s)
+		if err != nil {
+			return "", fmt.Errorf("failed to post-process password: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// Group returns a PostProcessor which splits the password into groups of size
+// characters joined by sep, such as "abcd-efgh-ijkl". It adds
+// (n-1)/size*len(sep) characters to a password of n characters and does not
+// change its entropy.
+func Group(size int, sep string) PostProcessor {
+	return PostProcessorFunc(func(s string) (string, error) {
+		if size <= 0 {
+			return s, nil
+		}
+
+		runes := []rune(s)
+		var b strings.Builder
+		for i, r := range runes {
+			if i > 0 && i%size == 0 {
+				b.WriteString(sep)
+			}
+			b.WriteRune(r)
+		}
+		return b.String(), nil
+	})
+}
+
+// Lowercase returns a PostProcessor which converts the password to lowercase.
+// It does not change the length, but reduces entropy unless Input.NoUpper is
+// set; prefer Input.NoUpper, which keeps the full entropy of the lowercase
+// alphabet.
+func Lowercase() PostProcessor {
+	return PostProcessorFunc(func(s string) (string, error) {
+		return strings.ToLower(s), nil
+	})
+}
+
+// Uppercase returns a PostProcessor which converts the password to uppercase.
+// Like Lowercase, it reduces entropy unless the password has no lowercase
+// letters.
+func Uppercase() PostProcessor {
+	return PostProcessorFunc(func(s string) (string, error) {
+		return strings.ToUpper(s), nil
+	})
+}
+
+// LuhnChecksum returns a PostProcessor which appends a Luhn mod N check
+// character computed over the given alphabet, so that single-character typos
+// and most transpositions can be detected with ValidLuhn. Every character of
+// the password must be in the alphabet. It adds one character and does not
+// change the entropy.
+func LuhnChecksum(alphabet string) PostProcessor {
+	return PostProcessorFunc(func(s string) (string, error) {
+		check, err := luhnCheck(s, []rune(alphabet), 2)
+		if err != nil {
+			return "", err
+		}
+		return s + string(check), nil
+	})
+}
+
+// ValidLuhn reports whether the last character of s is the Luhn mod N check
+// character of the characters before it, as appended by LuhnChecksum.
+func ValidLuhn(s, alphabet string) bool {
+	if utf8.RuneCountInString(s) < 2 {
+		return false
+	}
+
+	runes := []rune(alphabet)
+	_, size := utf8.DecodeLastRuneInString(s)
+	check, err := luhnCheck(s[:len(s)-size], runes, 2)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(s, string(check))
+}
+
+// luhnCheck computes the Luhn mod N check character of s. The factor of the
+// rightmost character is the given factor; factors alternate between it and 1.
+func luhnCheck(s string, alphabet []rune, factor int) (rune, error) {
+	n := len(alphabet)
+	if n == 0 {
+		return 0, ErrChecksumAlphabet
+	}
+
+	index := make(map[rune]int, n)
+	for i, r := range alphabet {
+		index[r] = i
+	}
+
+	runes := []rune(s)
+	sum := 0
+	for i := len(runes) - 1; i >= 0; i-- {
+		v, ok := index[runes[i]]
+		if !ok {
+			return 0, fmt.Errorf("%w: %q", ErrChecksumAlphabet, runes[i])
+		}
+
+		v *= factor
+		sum += v/n + v%n
+		factor = 3 - factor
+	}
+	return alphabet[(n-sum%n)%n], nil
+}
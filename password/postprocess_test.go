@@ -0,0 +1,125 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		s    string
+		size int
+		want string
+	}{
+		{"abcdefghijkl", 4, "abcd-efgh-ijkl"},
+		{"abcdefghij", 4, "abcd-efgh-ij"},
+		{"abc", 4, "abc"},
+		{"€€€€", 2, "€€-€€"},
+		{"abc", 0, "abc"},
+	}
+
+	for _, tc := range cases {
+		got, err := Group(tc.size, "-").Process(tc.s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("expected %q to be %q", got, tc.want)
+		}
+	}
+}
+
+func TestLuhnChecksum(t *testing.T) {
+	t.Parallel()
+
+	t.Run("digits", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := LuhnChecksum(Digits).Process("7992739871")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "79927398713" {
+			t.Errorf("expected %q to be %q", got, "79927398713")
+		}
+		if !ValidLuhn(got, Digits) {
+			t.Errorf("expected %q to be valid", got)
+		}
+		if ValidLuhn("79927398731", Digits) {
+			t.Error("expected transposition to be detected")
+		}
+	})
+
+	t.Run("alphabet", func(t *testing.T) {
+		t.Parallel()
+
+		alphabet := LowerLetters + Digits
+		for i := 0; i < 100; i++ {
+			res, err := NewGenerator().
+				WithPostProcessors(LuhnChecksum(alphabet)).
+				Generate(Input{Length: 12, Digits: 4, NoUpper: true, AllowRepeat: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(res) != 13 || !ValidLuhn(res, alphabet) {
+				t.Errorf("expected %q to have a valid check character", res)
+			}
+		}
+	})
+
+	t.Run("not_in_alphabet", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := LuhnChecksum(Digits).Process("12a"); !errors.Is(err, ErrChecksumAlphabet) {
+			t.Errorf("expected %q to be %q", err, ErrChecksumAlphabet)
+		}
+	})
+}
+
+func TestGeneratorWithPostProcessors(t *testing.T) {
+	t.Parallel()
+
+	t.Run("ordered", func(t *testing.T) {
+		t.Parallel()
+
+		gen := NewGenerator().WithPostProcessors(
+			Uppercase(),
+			PostProcessorFunc(func(s string) (string, error) { return "x" + s, nil }),
+			Group(3, " "),
+		)
+
+		res, err := gen.Generate(Input{Length: 5, NoUpper: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 7 || res[0] != 'x' || res[3] != ' ' || strings.ToUpper(res[1:]) != res[1:] {
+			t.Errorf("unexpected result %q", res)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		errBad := errors.New("bad")
+		gen := NewGenerator().WithPostProcessors(PostProcessorFunc(func(string) (string, error) {
+			return "", errBad
+		}))
+		if _, err := gen.Generate(Input{Length: 5}); !errors.Is(err, errBad) {
+			t.Errorf("expected %q to be %q", err, errBad)
+		}
+	})
+
+	t.Run("filters_see_output", func(t *testing.T) {
+		t.Parallel()
+
+		gen := NewGenerator().
+			WithPostProcessors(Group(2, "-")).
+			WithFilter(func(s string) bool { return strings.Contains(s, "-") })
+		if _, err := gen.Generate(Input{Length: 4}); err != nil {
+			t.Error(err)
+		}
+	})
+}
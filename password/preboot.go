@@ -0,0 +1,39 @@
+package password
+
+const (
+	// PreBootLowerLetters is the list of lowercase letters on the same key in
+	// the US QWERTY, German QWERTZ and French AZERTY layouts. Pre-boot prompts,
+	// such as the BIOS/UEFI setup, LUKS in an initramfs without a keymap or the
+	// BitLocker recovery screen, often assume a US layout whatever the layout
+	// of the keyboard, so letters swapped between layouts (a/q, w/z, y/z and m)
+	// would be typed differently than at the operating system prompt.
+	PreBootLowerLetters = "bcdefghijklnoprstuvx"
+
+	// PreBootUpperLetters is the uppercase variant of PreBootLowerLetters.
+	PreBootUpperLetters = "BCDEFGHIJKLNOPRSTUVX"
+)
+
+// WithPreBootCharset creates a new Generator from another Generator which only
+// generates characters reliably typed at pre-boot prompts: PreBootLowerLetters
+// and PreBootUpperLetters. It is meant for BIOS/UEFI passwords and
+// disk-encryption passphrases.
+//
+// The Generator has no digits and no symbols, so inputs must not require any:
+// make up for them with length. Digits are typed with Shift on AZERTY but
+// without it on QWERTY and QWERTZ, the state of NumLock at pre-boot prompts
+// varies, and no symbol is on the same key with the same modifiers in all three
+// layouts.
+func (g Generator) WithPreBootCharset() Generator {
+	return g.With(WithPreBootCharset())
+}
+
+// WithPreBootCharset returns an Option setting the pre-boot charsets, as
+// Generator.WithPreBootCharset.
+func WithPreBootCharset() Option {
+	return func(g *Generator) {
+		g.lowerLetters = PreBootLowerLetters
+		g.upperLetters = PreBootUpperLetters
+		g.digits = ""
+		g.symbols = ""
+	}
+}
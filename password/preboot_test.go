@@ -0,0 +1,35 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGeneratorWithPreBootCharset(t *testing.T) {
+	t.Parallel()
+
+	allowed := PreBootLowerLetters + PreBootUpperLetters
+	gen := NewGenerator().WithPreBootCharset()
+	for i := 0; i < N; i++ {
+		res, err := gen.Generate(Input{
+			Length:               32,
+			AllowRepeat:          true,
+			RequireFromEachClass: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Trim(res, allowed) != "" {
+			t.Errorf("%q should only contain pre-boot safe characters", res)
+		}
+	}
+
+	if _, err := gen.Generate(Input{Length: 16, Digits: 2}); !errors.Is(err, ErrDigitsExceedsAvailable) {
+		t.Errorf("expected %v to be %v", err, ErrDigitsExceedsAvailable)
+	}
+	if _, err := gen.Generate(Input{Length: 16, Symbols: 2}); !errors.Is(err, ErrSymbolsExceedsAvailable) {
+		t.Errorf("expected %v to be %v", err, ErrSymbolsExceedsAvailable)
+	}
+}
@@ -0,0 +1,122 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rules checked by Generator.Validate, in addition to those of a Policy.
+const (
+	RuleLength       Rule = "length"
+	RuleCharset      Rule = "charset"
+	RuleDigits       Rule = "digits"
+	RuleSymbols      Rule = "symbols"
+	RuleRepeat       Rule = "repeat"
+	RuleClassOrder   Rule = "class_order"
+	RuleSameClassRun Rule = "same_class_run"
+)
+
+// Validate checks whether the password could have been generated by the
+// Generator with the given input: it has the exact length, only characters of
+// the Generator charsets, the number or range of digits and symbols of the
+// input, no repeated characters unless allowed, the class order and runs
+// required by the input, and a letter of each case if RequireFromEachClass is set. This detects
+// hand-edited credentials in systems which mandate generated ones. It is not a
+// proof that the password was generated, only that it could have been.
+//
+// Post-processors are not accounted for, so the password must be validated as
+// generated, before post-processing. If it could not have been generated, a
+// *PolicyError is returned listing the rules which failed.
+func (g Generator) Validate(pw string, input Input) error {
+	if _, err := g.compositions(input); err != nil {
+		return err
+	}
+
+	var violations []Violation
+	fail := func(rule Rule, format string, args ...any) {
+		violations = append(violations, Violation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	letters := g.letters(input)
+	if utf8.RuneCountInString(pw) != input.Length {
+		fail(RuleLength, "must be exactly %d characters long", input.Length)
+	}
+
+	// classes holds the class of every character: 0 for letters, 1 for digits
+	// and 2 for symbols, in the order of the Generator charsets.
+	classes := make([]int, 0, len(pw))
+	counts := make([]int, 3)
+	seen := make(map[rune]bool, len(pw))
+	repeated, invalid := false, false
+	for _, c := range pw {
+		class := -1
+		for j, cs := range []string{letters, g.digits, g.symbols} {
+			if strings.ContainsRune(cs, c) {
+				class = j
+				break
+			}
+		}
+		if class < 0 {
+			if !invalid {
+				fail(RuleCharset, "must not contain %q", c)
+				invalid = true
+			}
+			continue
+		}
+
+		classes = append(classes, class)
+		counts[class]++
+		repeated = repeated || seen[c]
+		seen[c] = true
+	}
+
+	dlo, dhi, _ := countRange("digits", input.Digits, input.MinDigits, input.MaxDigits, input.Length)
+	slo, shi, _ := countRange("symbols", input.Symbols, input.MinSymbols, input.MaxSymbols, input.Length)
+	switch {
+	case dlo == dhi && counts[1] != dlo:
+		fail(RuleDigits, "must contain exactly %d digits", dlo)
+	case counts[1] < dlo || counts[1] > dhi:
+		fail(RuleDigits, "must contain between %d and %d digits", dlo, dhi)
+	}
+	switch {
+	case slo == shi && counts[2] != slo:
+		fail(RuleSymbols, "must contain exactly %d symbols", slo)
+	case counts[2] < slo || counts[2] > shi:
+		fail(RuleSymbols, "must contain between %d and %d symbols", slo, shi)
+	}
+	if repeated && !input.AllowRepeat {
+		fail(RuleRepeat, "must not repeat characters")
+	}
+	if input.RequireFromEachClass {
+		if !strings.ContainsAny(pw, g.lowerLetters) {
+			fail(RuleRequiredClass, "must contain at least one %s character", ClassLower)
+		}
+		if !input.NoUpper && !strings.ContainsAny(pw, g.upperLetters) {
+			fail(RuleRequiredClass, "must contain at least one %s character", ClassUpper)
+		}
+	}
+
+	run := 0
+	for i, class := range classes {
+		if i > 0 && class == classes[i-1] {
+			run++
+		} else {
+			run = 1
+		}
+
+		if input.PreserveClassOrder && i > 0 && class < classes[i-1] {
+			fail(RuleClassOrder, "must have letters, then digits, then symbols")
+			break
+		}
+		if input.MaxSameClassRun > 0 && run > input.MaxSameClassRun {
+			fail(RuleSameClassRun, "must not have more than %d characters of the same class in a row", input.MaxSameClassRun)
+			break
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+	return nil
+}
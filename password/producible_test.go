@@ -0,0 +1,64 @@
+package password
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestGeneratorValidate(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator()
+	input := Input{Length: 16, Digits: 4, Symbols: 4, MaxSameClassRun: 3}
+	for i := 0; i < N; i++ {
+		pw, err := gen.Generate(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := gen.Validate(pw, input); err != nil {
+			t.Fatalf("expected generated %q to be valid: %v", pw, err)
+		}
+	}
+
+	cases := []struct {
+		name  string
+		pw    string
+		input Input
+		rules []Rule
+	}{
+		{"valid", "abC1!", Input{Length: 5, Digits: 1, Symbols: 1}, nil},
+		{"length", "abC1!x", Input{Length: 5, Digits: 1, Symbols: 1}, []Rule{RuleLength}},
+		{"charset", "abc1é", Input{Length: 5, Digits: 1, Symbols: 1}, []Rule{RuleCharset, RuleSymbols}},
+		{"no upper", "abC1!", Input{Length: 5, Digits: 1, Symbols: 1, NoUpper: true}, []Rule{RuleCharset}},
+		{"digits", "ab12!", Input{Length: 5, Digits: 1, Symbols: 1}, []Rule{RuleDigits}},
+		{"symbols", "abcd1", Input{Length: 5, Digits: 1, Symbols: 1}, []Rule{RuleSymbols}},
+		{"repeat", "aab1!", Input{Length: 5, Digits: 1, Symbols: 1}, []Rule{RuleRepeat}},
+		{"repeat allowed", "aab1!", Input{Length: 5, Digits: 1, Symbols: 1, AllowRepeat: true}, nil},
+		{"class order", "ab!c1", Input{Length: 5, Digits: 1, Symbols: 1, PreserveClassOrder: true}, []Rule{RuleClassOrder}},
+		{"same class run", "abc1!", Input{Length: 5, Digits: 1, Symbols: 1, MaxSameClassRun: 2}, []Rule{RuleSameClassRun}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := gen.Validate(tc.pw, tc.input)
+			var policyErr *PolicyError
+			switch {
+			case tc.rules == nil && err != nil:
+				t.Errorf("expected %q to be valid, got %v", tc.pw, err)
+			case tc.rules != nil && !errors.As(err, &policyErr):
+				t.Errorf("expected a policy error, got %v", err)
+			case tc.rules != nil && !slices.Equal(policyErr.Rules(), tc.rules):
+				t.Errorf("expected rules %v, got %v", tc.rules, policyErr.Rules())
+			}
+		})
+	}
+
+	if err := gen.Validate("ab", Input{Length: 2, Digits: 3}); !errors.Is(err, ErrExceedsTotalLength) {
+		t.Errorf("expected %v to be %v", err, ErrExceedsTotalLength)
+	}
+}
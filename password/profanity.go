@@ -0,0 +1,119 @@
+package password
+
+import (
+	_ "embed"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//go:embed profanity.txt
+var profanityList string
+
+// minSubstringLength is the length from which entries match anywhere in a
+// string. Shorter entries only match whole words, since as substrings they
+// would reject many ordinary strings.
+const minSubstringLength = 4
+
+// leetReplacer maps common character substitutions back to the letters they
+// stand in for, so that "sh1t" is caught just like "shit".
+var leetReplacer = strings.NewReplacer(
+	"0", "o",
+	"1", "i",
+	"3", "e",
+	"4", "a",
+	"5", "s",
+	"7", "t",
+	"@", "a",
+	"$", "s",
+	"!", "i",
+	"|", "l",
+)
+
+// ProfanityFilter rejects strings containing offensive substrings. It is built
+// from an embedded multi-language list plus any caller-supplied additions. This
+// is most useful for customer-visible values such as recovery codes and
+// usernames.
+type ProfanityFilter struct {
+	words []string
+
+	// short holds the entries shorter than minSubstringLength, which only
+	// match whole words.
+	short []string
+}
+
+// NewProfanityFilter creates a new ProfanityFilter from the embedded list and
+// the given extra words. Matching is case-insensitive and also catches common
+// character substitutions. Words shorter than four characters only match
+// whole words, that is not next to other letters.
+func NewProfanityFilter(extra ...string) *ProfanityFilter {
+	f := &ProfanityFilter{words: make([]string, 0, 128+len(extra))}
+	for _, line := range strings.Split(profanityList, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		f.add(line)
+	}
+
+	for _, word := range extra {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" {
+			continue
+		}
+		f.add(word)
+	}
+
+	return f
+}
+
+// add adds a lowercase entry to the filter.
+func (f *ProfanityFilter) add(word string) {
+	if utf8.RuneCountInString(word) < minSubstringLength {
+		f.short = append(f.short, word)
+		return
+	}
+	f.words = append(f.words, word)
+}
+
+// Contains reports whether s contains any offensive substring.
+func (f *ProfanityFilter) Contains(s string) bool {
+	lower := strings.ToLower(s)
+	normalized := leetReplacer.Replace(lower)
+	for _, word := range f.words {
+		if strings.Contains(lower, word) || strings.Contains(normalized, word) {
+			return true
+		}
+	}
+	for _, word := range f.short {
+		if containsWord(lower, word) || containsWord(normalized, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWord reports whether s contains word with no letter directly before
+// or after it.
+func containsWord(s, word string) bool {
+	for i := 0; ; {
+		j := strings.Index(s[i:], word)
+		if j < 0 {
+			return false
+		}
+
+		start, end := i+j, i+j+len(word)
+		before, _ := utf8.DecodeLastRuneInString(s[:start])
+		after, _ := utf8.DecodeRuneInString(s[end:])
+		if (start == 0 || !unicode.IsLetter(before)) && (end == len(s) || !unicode.IsLetter(after)) {
+			return true
+		}
+		i = start + 1
+	}
+}
+
+// Allow reports whether s is free of offensive substrings. It can be passed
+// directly to Generator.WithFilter or WordlistOptions.Filter.
+func (f *ProfanityFilter) Allow(s string) bool {
+	return !f.Contains(s)
+}
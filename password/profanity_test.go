@@ -0,0 +1,93 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProfanityFilter(t *testing.T) {
+	t.Parallel()
+
+	f := NewProfanityFilter("Zorgon", "q")
+
+	cases := []struct {
+		name string
+		s    string
+		want bool
+	}{
+		{"clean", "xk7Qpw2m", false},
+		{"embedded", "ab12ShIt9", true},
+		{"leet", "x5h1tx", true},
+		{"other_language", "fooMERDEbar", true},
+		{"extra", "aazorgonzz", true},
+		{"short_embedded", "Essex", false},
+		{"short_word", "x7-sex-2", true},
+		{"short_leet", "8s3x", true},
+		{"short_extra", "aqaqa", false},
+		{"short_extra_word", "9Q-x", true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := f.Contains(tc.s); got != tc.want {
+				t.Errorf("expected Contains(%q) to be %t", tc.s, tc.want)
+			}
+			if got := f.Allow(tc.s); got == tc.want {
+				t.Errorf("expected Allow(%q) to be %t", tc.s, !tc.want)
+			}
+		})
+	}
+}
+
+func TestGeneratorWithFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("profanity", func(t *testing.T) {
+		t.Parallel()
+
+		f := NewProfanityFilter()
+		gen := NewGenerator().WithFilter(f.Allow)
+		for i := 0; i < N; i++ {
+			res, err := gen.Generate(Input{
+				Length:      8,
+				NoUpper:     true,
+				AllowRepeat: true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if f.Contains(res) {
+				t.Errorf("%q should not contain offensive substrings", res)
+			}
+		}
+	})
+
+	t.Run("attempts_exceeded", func(t *testing.T) {
+		t.Parallel()
+
+		gen := NewGenerator().WithFilter(func(string) bool { return false })
+		if _, err := gen.Generate(Input{Length: 8}); !errors.Is(err, ErrFilterAttemptsExceeded) {
+			t.Errorf("expected %q to be %q", err, ErrFilterAttemptsExceeded)
+		}
+	})
+
+	t.Run("does_not_share_filters", func(t *testing.T) {
+		t.Parallel()
+
+		base := NewGenerator().WithFilter(func(string) bool { return true })
+		a := base.WithFilter(func(string) bool { return true })
+		b := base.WithFilter(func(string) bool { return false })
+
+		if _, err := a.Generate(Input{Length: 8}); err != nil {
+			t.Error(err)
+		}
+		if _, err := b.Generate(Input{Length: 8}); !errors.Is(err, ErrFilterAttemptsExceeded) {
+			t.Errorf("expected %q to be %q", err, ErrFilterAttemptsExceeded)
+		}
+	})
+}
@@ -0,0 +1,139 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// PronounceableConsonants is the list of consonants of pronounceable
+	// passwords. Q, X and Y are left out, since they are hard to spell out
+	// over the phone.
+	PronounceableConsonants = "bcdfghjklmnprstvwz"
+
+	// PronounceableVowels is the list of vowels of pronounceable passwords.
+	PronounceableVowels = "aeiou"
+)
+
+// ErrInvalidPronounceable is the error returned when the options of a
+// pronounceable password do not fit its length.
+var ErrInvalidPronounceable = errors.New("invalid pronounceable password options")
+
+// PronounceableOptions used to define options for GeneratePronounceable.
+type PronounceableOptions struct {
+	// Upper is the number of letters which are uppercase.
+	Upper int
+
+	// Digits is the number of digits, inserted at random positions. They
+	// are chosen from the Generator digits and may repeat.
+	Digits int
+	_      struct{}
+}
+
+// checkPronounceable verifies the options and returns the number of letters.
+func checkPronounceable(length int, opts PronounceableOptions) (int, error) {
+	letters := length - opts.Digits
+	if length < 1 || opts.Digits < 0 || opts.Upper < 0 || letters < 1 || opts.Upper > letters {
+		return 0, fmt.Errorf("%w: length %d, %d digits and %d uppercase letters", ErrInvalidPronounceable, length, opts.Digits, opts.Upper)
+	}
+	return letters, nil
+}
+
+// GeneratePronounceable generates a password of the given length which can be
+// read over the phone, such as "tavokimu" or "fe7Ruzo2dax": its letters
+// alternate between PronounceableConsonants and PronounceableVowels, like
+// pwgen and apg do, starting with either. It has less entropy than a random
+// password of the same length, so it should be longer; see
+// PronounceableEntropy.
+func (g Generator) GeneratePronounceable(length int, opts PronounceableOptions) (string, error) {
+	letters, err := checkPronounceable(length, opts)
+	if err != nil {
+		return "", err
+	}
+
+	start, err := UniformIndex(g.reader, 2)
+	if err != nil {
+		return "", err
+	}
+	classes := []string{PronounceableConsonants, PronounceableVowels}
+	res := make([]rune, 0, length)
+	for i := 0; i < letters; i++ {
+		chars := classes[(start+i)%2]
+		j, err := UniformIndex(g.reader, len(chars))
+		if err != nil {
+			return "", err
+		}
+		res = append(res, rune(chars[j]))
+	}
+
+	upper, err := g.positions(letters, opts.Upper)
+	if err != nil {
+		return "", err
+	}
+	for _, i := range upper {
+		res[i] = unicode.ToUpper(res[i])
+	}
+
+	digits := []rune(g.digits)
+	positions, err := g.positions(length, opts.Digits)
+	if err != nil {
+		return "", err
+	}
+	slices.Sort(positions)
+	for _, i := range positions {
+		j, err := UniformIndex(g.reader, len(digits))
+		if err != nil {
+			return "", err
+		}
+		res = append(res[:i], append([]rune{digits[j]}, res[i:]...)...)
+	}
+	return string(res), nil
+}
+
+// PronounceableEntropy returns the entropy, in bits, of passwords generated by
+// GeneratePronounceable with the same length and options.
+func (g Generator) PronounceableEntropy(length int, opts PronounceableOptions) (float64, error) {
+	letters, err := checkPronounceable(length, opts)
+	if err != nil {
+		return 0, err
+	}
+
+	// The letters start with a consonant or a vowel, which are equally
+	// likely but not equally many if the number of letters is odd.
+	c, v := math.Log2(float64(len(PronounceableConsonants))), math.Log2(float64(len(PronounceableVowels)))
+	odd, even := float64((letters+1)/2), float64(letters/2)
+	consonantFirst, vowelFirst := odd*c+even*v, odd*v+even*c
+	bits := consonantFirst + math.Log2(1+math.Exp2(vowelFirst-consonantFirst))
+
+	bits += log2Binomial(letters, opts.Upper) + log2Binomial(length, opts.Digits) +
+		float64(opts.Digits)*math.Log2(float64(utf8.RuneCountInString(g.digits)))
+	return bits, nil
+}
+
+// positions returns k distinct random positions out of n, using the Generator
+// reader.
+func (g Generator) positions(n, k int) ([]int, error) {
+	idx := make([]int, n)
+	for i := range idx {
+		idx[i] = i
+	}
+	if err := shuffle(g.reader, idx); err != nil {
+		return nil, err
+	}
+	return idx[:k], nil
+}
+
+// log2Binomial returns log2 of n choose k.
+func log2Binomial(n, k int) float64 {
+	return log2Factorial(n) - log2Factorial(k) - log2Factorial(n-k)
+}
+
+// GeneratePronounceable is the package shortcut for
+// Generator.GeneratePronounceable.
+func GeneratePronounceable(length int, opts PronounceableOptions) (string, error) {
+	return NewGenerator().GeneratePronounceable(length, opts)
+}
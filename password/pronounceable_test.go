@@ -0,0 +1,87 @@
+package password
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestGeneratePronounceable(t *testing.T) {
+	t.Parallel()
+
+	opts := PronounceableOptions{Upper: 2, Digits: 3}
+	for i := 0; i < N; i++ {
+		res, err := GeneratePronounceable(12, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 12 {
+			t.Fatalf("expected %q to have 12 characters", res)
+		}
+
+		var letters []rune
+		upper, digits := 0, 0
+		for _, r := range res {
+			switch {
+			case unicode.IsDigit(r):
+				digits++
+			case unicode.IsUpper(r):
+				upper++
+				letters = append(letters, unicode.ToLower(r))
+			default:
+				letters = append(letters, r)
+			}
+		}
+		if upper != opts.Upper || digits != opts.Digits {
+			t.Errorf("expected %q to have %d uppercase letters and %d digits", res, opts.Upper, opts.Digits)
+		}
+
+		for j := 1; j < len(letters); j++ {
+			if strings.ContainsRune(PronounceableVowels, letters[j]) == strings.ContainsRune(PronounceableVowels, letters[j-1]) {
+				t.Errorf("expected the letters of %q to alternate between consonants and vowels", res)
+				break
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		length int
+		opts   PronounceableOptions
+	}{
+		{0, PronounceableOptions{}},
+		{4, PronounceableOptions{Digits: 4}},
+		{4, PronounceableOptions{Upper: 3, Digits: 2}},
+		{4, PronounceableOptions{Upper: -1}},
+	} {
+		if _, err := GeneratePronounceable(tc.length, tc.opts); !errors.Is(err, ErrInvalidPronounceable) {
+			t.Errorf("expected %v to be %v", err, ErrInvalidPronounceable)
+		}
+	}
+}
+
+func TestGeneratorPronounceableEntropy(t *testing.T) {
+	t.Parallel()
+
+	c, v := float64(len(PronounceableConsonants)), float64(len(PronounceableVowels))
+	cases := []struct {
+		length int
+		opts   PronounceableOptions
+		want   float64
+	}{
+		{4, PronounceableOptions{}, math.Log2(2 * c * v * c * v)},
+		{3, PronounceableOptions{}, math.Log2(c*v*c + v*c*v)},
+		{4, PronounceableOptions{Upper: 1, Digits: 1}, math.Log2((c*v*c + v*c*v) * 3 * 4 * 10)},
+	}
+
+	for _, tc := range cases {
+		got, err := NewGenerator().PronounceableEntropy(tc.length, tc.opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(got-tc.want) > 1e-9 {
+			t.Errorf("expected %f to be %f", got, tc.want)
+		}
+	}
+}
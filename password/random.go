@@ -0,0 +1,126 @@
+package password
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var (
+	// ErrInvalidRange is the error returned when sampling from an empty range.
+	ErrInvalidRange = errors.New("range must be positive")
+
+	// ErrSampleSize is the error returned when the requested sample size is
+	// negative or larger than the population.
+	ErrSampleSize = errors.New("sample size must be between zero and the number of elements")
+
+	// ErrNoElements is the error returned when choosing from an empty slice.
+	ErrNoElements = errors.New("cannot choose from an empty slice")
+)
+
+// UniformIndex returns a uniformly distributed random integer in [0, n) using
+// bytes read from r. Unlike taking a random value modulo n, it does not favor
+// any index; values which would introduce a bias are rejected and drawn again.
+// It does not allocate.
+func UniformIndex(r io.Reader, n int) (int, error) {
+	if n <= 0 {
+		return 0, ErrInvalidRange
+	}
+
+	var buf [8]byte
+	threshold := -uint64(n) % uint64(n)
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, fmt.Errorf("failed to read random bytes: %w", err)
+		}
+
+		if v := binary.LittleEndian.Uint64(buf[:]); v >= threshold {
+			return int(v % uint64(n)), nil
+		}
+	}
+}
+
+// UniformIndices returns count uniformly distributed random integers in
+// [0, n), as UniformIndex, reading from r in a single batch where possible.
+func UniformIndices(r io.Reader, n, count int) ([]int, error) {
+	if n <= 0 {
+		return nil, ErrInvalidRange
+	}
+
+	res := make([]int, 0, count)
+	if count <= 0 {
+		return res, nil
+	}
+
+	buf := make([]byte, 8*count)
+	threshold := -uint64(n) % uint64(n)
+	for len(res) < count {
+		want := 8 * (count - len(res))
+		if _, err := io.ReadFull(r, buf[:want]); err != nil {
+			return nil, fmt.Errorf("failed to read random bytes: %w", err)
+		}
+
+		for i := 0; i < want; i += 8 {
+			if v := binary.LittleEndian.Uint64(buf[i:]); v >= threshold {
+				res = append(res, int(v%uint64(n)))
+			}
+		}
+	}
+	clear(buf)
+	return res, nil
+}
+
+// Choice returns an element of the given slice chosen uniformly at random using
+// crypto/rand.
+func Choice[T any](s []T) (T, error) {
+	var zero T
+	if len(s) == 0 {
+		return zero, ErrNoElements
+	}
+
+	i, err := UniformIndex(rand.Reader, len(s))
+	if err != nil {
+		return zero, err
+	}
+	return s[i], nil
+}
+
+// Shuffle shuffles the given slice in place using crypto/rand. Every
+// permutation is equally likely.
+func Shuffle[T any](s []T) error {
+	return shuffle(rand.Reader, s)
+}
+
+// shuffle shuffles the given slice in place with the Fisher-Yates algorithm,
+// using bytes read from r.
+func shuffle[T any](r io.Reader, s []T) error {
+	for i := len(s) - 1; i > 0; i-- {
+		j, err := UniformIndex(r, i+1)
+		if err != nil {
+			return err
+		}
+		s[i], s[j] = s[j], s[i]
+	}
+	return nil
+}
+
+// Sample returns k distinct elements of the given slice chosen uniformly at
+// random using crypto/rand, in random order. The given slice is not modified.
+func Sample[T any](s []T, k int) ([]T, error) {
+	if k < 0 || k > len(s) {
+		return nil, ErrSampleSize
+	}
+
+	pool := make([]T, len(s))
+	copy(pool, s)
+	for i := 0; i < k; i++ {
+		j, err := UniformIndex(rand.Reader, len(pool)-i)
+		if err != nil {
+			return nil, err
+		}
+		pool[i], pool[i+j] = pool[i+j], pool[i]
+	}
+	return pool[:k:k], nil
+}
@@ -0,0 +1,214 @@
+package password
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func testUint64s(tb testing.TB, vals ...uint64) io.Reader {
+	tb.Helper()
+
+	b := make([]byte, 0, 8*len(vals))
+	for _, v := range vals {
+		b = binary.LittleEndian.AppendUint64(b, v)
+	}
+	return bytes.NewReader(b)
+}
+
+func TestUniformIndex(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid_range", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := UniformIndex(rand.Reader, 0); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("expected %q to be %q", err, ErrInvalidRange)
+		}
+		if _, err := UniformIndices(rand.Reader, -1, 1); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("expected %q to be %q", err, ErrInvalidRange)
+		}
+	})
+
+	t.Run("rejects_biased_values", func(t *testing.T) {
+		t.Parallel()
+
+		// 2^64 mod 3 == 1, so 0 must be rejected to keep the result unbiased.
+		i, err := UniformIndex(testUint64s(t, 0, 5), 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i != 2 {
+			t.Errorf("expected %d to be %d", i, 2)
+		}
+
+		res, err := UniformIndices(testUint64s(t, 0, 4, 0, 5), 3, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res) != 2 || res[0] != 1 || res[1] != 2 {
+			t.Errorf("expected %v to be %v", res, []int{1, 2})
+		}
+	})
+
+	t.Run("read_error", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := UniformIndex(errReader{}, 10); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("expected %q to be %q", err, io.ErrUnexpectedEOF)
+		}
+		if _, err := UniformIndices(errReader{}, 10, 3); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("expected %q to be %q", err, io.ErrUnexpectedEOF)
+		}
+	})
+
+	t.Run("distribution", func(t *testing.T) {
+		t.Parallel()
+
+		const n = 7
+		counts := make([]int, n)
+		res, err := UniformIndices(rand.Reader, n, N*n)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, i := range res {
+			if i < 0 || i >= n {
+				t.Fatalf("%d is out of range", i)
+			}
+			counts[i]++
+		}
+
+		for i, c := range counts {
+			if c < N*9/10 || c > N*11/10 {
+				t.Errorf("index %d was chosen %d times, expected about %d", i, c, N)
+			}
+		}
+	})
+}
+
+func TestChoice(t *testing.T) {
+	t.Parallel()
+
+	t.Run("chooses", func(t *testing.T) {
+		t.Parallel()
+
+		seen := make(map[int]struct{})
+		for i := 0; i < N; i++ {
+			v, err := Choice([]int{1, 2, 3})
+			if err != nil {
+				t.Fatal(err)
+			}
+			seen[v] = struct{}{}
+		}
+		if len(seen) != 3 {
+			t.Errorf("expected all elements to be chosen, got %v", seen)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := Choice([]int(nil)); !errors.Is(err, ErrNoElements) {
+			t.Errorf("expected %q to be %q", err, ErrNoElements)
+		}
+	})
+}
+
+func TestShuffle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("permutes", func(t *testing.T) {
+		t.Parallel()
+
+		s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		if err := Shuffle(s); err != nil {
+			t.Fatal(err)
+		}
+
+		seen := make(map[int]struct{}, len(s))
+		for _, v := range s {
+			seen[v] = struct{}{}
+		}
+		if len(seen) != 10 {
+			t.Errorf("%v is not a permutation", s)
+		}
+	})
+
+	t.Run("distribution", func(t *testing.T) {
+		t.Parallel()
+
+		counts := make(map[string]int)
+		for i := 0; i < 6*N; i++ {
+			s := []byte("abc")
+			if err := Shuffle(s); err != nil {
+				t.Fatal(err)
+			}
+			counts[string(s)]++
+		}
+
+		if len(counts) != 6 {
+			t.Errorf("expected 6 permutations, got %v", counts)
+		}
+		for p, c := range counts {
+			if c < N*9/10 || c > N*11/10 {
+				t.Errorf("%q was produced %d times, expected about %d", p, c, N)
+			}
+		}
+	})
+}
+
+func TestSample(t *testing.T) {
+	t.Parallel()
+
+	t.Run("distinct", func(t *testing.T) {
+		t.Parallel()
+
+		s := []string{"a", "b", "c", "d", "e"}
+		for i := 0; i < N; i++ {
+			res, err := Sample(s, 3)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(res) != 3 || testHasDuplicates(t, strings.Join(res, "")) {
+				t.Errorf("%q should be 3 distinct elements", res)
+			}
+		}
+
+		if got := strings.Join(s, ""); got != "abcde" {
+			t.Errorf("input was modified to %q", got)
+		}
+	})
+
+	t.Run("invalid_size", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := Sample([]int{1, 2}, 3); !errors.Is(err, ErrSampleSize) {
+			t.Errorf("expected %q to be %q", err, ErrSampleSize)
+		}
+		if _, err := Sample([]int{1, 2}, -1); !errors.Is(err, ErrSampleSize) {
+			t.Errorf("expected %q to be %q", err, ErrSampleSize)
+		}
+	})
+}
+
+func BenchmarkUniformIndex(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := UniformIndex(rand.Reader, 94); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUniformIndices(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := UniformIndices(rand.Reader, 94, 64); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
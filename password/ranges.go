@@ -0,0 +1,219 @@
+package password
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrInvalidCountRange is the error returned when a range of digits or symbols
+// is negative, empty, or set together with an exact count.
+var ErrInvalidCountRange = errors.New("invalid range of digits or symbols")
+
+// ErrCountRangeTooLarge is the error returned when the ranges of digits and
+// symbols allow more compositions than can be weighed.
+var ErrCountRangeTooLarge = errors.New("ranges of digits and symbols allow too many compositions")
+
+// maxCompositions bounds the number of compositions of a ranged input, so that
+// inputs from untrusted policies cannot exhaust the CPU or memory. It allows
+// full ranges of both digits and symbols up to a length of 128.
+const maxCompositions = 1 << 14
+
+// ranged reports whether the input has a range of digits or symbols rather than
+// exact counts.
+func (input Input) ranged() bool {
+	return input.MinDigits != 0 || input.MaxDigits != 0 || input.MinSymbols != 0 || input.MaxSymbols != 0
+}
+
+// countRange returns the bounds of the number of characters of a class, from
+// its exact count or its range. A zero maximum means up to the length.
+func countRange(class string, exact, lo, hi, length int) (int, int, error) {
+	if lo == 0 && hi == 0 {
+		return exact, exact, nil
+	}
+	if exact != 0 {
+		return 0, 0, fmt.Errorf("%w: both exact and range of %s set", ErrInvalidCountRange, class)
+	}
+	if hi == 0 {
+		hi = length
+	}
+	if lo < 0 || lo > hi {
+		return 0, 0, fmt.Errorf("%w: %s between %d and %d", ErrInvalidCountRange, class, lo, hi)
+	}
+	return lo, hi, nil
+}
+
+// maxCachedCompositions is the number of inputs whose compositions are cached.
+const maxCachedCompositions = 64
+
+// compositionKey identifies the compositions of an input for a Generator.
+type compositionKey struct {
+	lowerLetters, upperLetters, digits, symbols string
+	latin1                                      bool
+	input                                       Input
+}
+
+// compositionCache caches the compositions of the ranged inputs generated
+// with, so that their entropy is only computed once. It is emptied when full.
+var compositionCache struct {
+	sync.Mutex
+	m map[compositionKey]*compositionTable
+}
+
+// compositionTable holds the exact counts of digits and symbols which an input
+// allows, with the entropy of each of them.
+type compositionTable struct {
+	digits, symbols []int
+	bits            []float64
+
+	// cumulative holds the cumulative weights of the compositions, in which
+	// every composition weighs its number of passwords.
+	cumulative []float64
+}
+
+// exact returns the input with the exact counts of the i-th composition.
+func (t *compositionTable) exact(input Input, i int) Input {
+	input.Digits, input.MinDigits, input.MaxDigits = t.digits[i], 0, 0
+	input.Symbols, input.MinSymbols, input.MaxSymbols = t.symbols[i], 0, 0
+	return input
+}
+
+// compositions returns the exact counts of digits and symbols which the given
+// input allows, with the entropy of each of them. Compositions which cannot be
+// satisfied are left out; the error of the first of them is returned if none
+// can. Tables of ranged inputs are cached.
+func (g Generator) compositions(input Input) (*compositionTable, error) {
+	if !input.ranged() {
+		return g.newCompositionTable(input)
+	}
+
+	key := compositionKey{g.lowerLetters, g.upperLetters, g.digits, g.symbols, g.latin1, input}
+	compositionCache.Lock()
+	t, ok := compositionCache.m[key]
+	compositionCache.Unlock()
+	if ok {
+		return t, nil
+	}
+
+	t, err := g.newCompositionTable(input)
+	if err != nil {
+		return nil, err
+	}
+	compositionCache.Lock()
+	if len(compositionCache.m) >= maxCachedCompositions {
+		clear(compositionCache.m)
+	}
+	if compositionCache.m == nil {
+		compositionCache.m = make(map[compositionKey]*compositionTable)
+	}
+	compositionCache.m[key] = t
+	compositionCache.Unlock()
+	return t, nil
+}
+
+// newCompositionTable computes the compositions of the input. The layouts
+// satisfying MaxSameClassRun are counted once for all of them.
+func (g Generator) newCompositionTable(input Input) (*compositionTable, error) {
+	dlo, dhi, err := countRange("digits", input.Digits, input.MinDigits, input.MaxDigits, input.Length)
+	if err != nil {
+		return nil, err
+	}
+	slo, shi, err := countRange("symbols", input.Symbols, input.MinSymbols, input.MaxSymbols, input.Length)
+	if err != nil {
+		return nil, err
+	}
+	if n := (dhi - dlo + 1) * (shi - slo + 1); n > maxCompositions {
+		return nil, fmt.Errorf("%w: %d", ErrCountRangeTooLarge, n)
+	}
+
+	var layouts *runLayoutTable
+	if input.MaxSameClassRun > 0 && !input.PreserveClassOrder {
+		bounds := [3]int{input.Length - dlo - slo, min(dhi, input.Length-slo), min(shi, input.Length-dlo)}
+		if !input.AllowRepeat {
+			bounds[0] = min(bounds[0], utf8.RuneCountInString(g.letters(input)))
+			bounds[1] = min(bounds[1], utf8.RuneCountInString(g.digits))
+			bounds[2] = min(bounds[2], utf8.RuneCountInString(g.symbols))
+		}
+		if bounds[0] >= 0 && bounds[1] >= dlo && bounds[2] >= slo {
+			if layouts, err = newRunLayoutTable(bounds, input.Length, input.MaxSameClassRun); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var (
+		t        compositionTable
+		firstErr error
+	)
+	for d := dlo; d <= dhi; d++ {
+		for s := slo; s <= shi; s++ {
+			exact := input
+			exact.Digits, exact.MinDigits, exact.MaxDigits = d, 0, 0
+			exact.Symbols, exact.MinSymbols, exact.MaxSymbols = s, 0, 0
+
+			b, err := g.exactEntropyLayouts(exact, layouts)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			t.digits = append(t.digits, d)
+			t.symbols = append(t.symbols, s)
+			t.bits = append(t.bits, b)
+		}
+	}
+	if len(t.bits) == 0 {
+		return nil, firstErr
+	}
+
+	top := slices.Max(t.bits)
+	t.cumulative = make([]float64, len(t.bits))
+	total := 0.0
+	for i, b := range t.bits {
+		total += math.Exp2(b - top)
+		t.cumulative[i] = total
+	}
+	return &t, nil
+}
+
+// resolve returns the input with exact counts of digits and symbols to generate
+// a password with. For a ranged input, the composition is chosen with a
+// probability proportional to the number of passwords it allows, so that every
+// password of the range is equally likely, unless MaxSameClassRun makes the
+// layouts of a composition unequally likely.
+func (g Generator) resolve(input Input) (Input, error) {
+	if !input.ranged() {
+		return input, nil
+	}
+
+	t, err := g.compositions(input)
+	if err != nil {
+		return Input{}, err
+	}
+
+	f, err := randomFloat(g.reader)
+	if err != nil {
+		return Input{}, err
+	}
+	target := f * t.cumulative[len(t.cumulative)-1]
+	i, _ := slices.BinarySearch(t.cumulative, target)
+	if i < len(t.cumulative) && t.cumulative[i] == target {
+		i++
+	}
+	return t.exact(input, min(i, len(t.cumulative)-1)), nil
+}
+
+// randomFloat returns a float64 in [0, 1) with 53 random bits read from r.
+func randomFloat(r io.Reader) (float64, error) {
+	var b [8]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return 0, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return float64(binary.LittleEndian.Uint64(b[:])>>11) / (1 << 53), nil
+}
@@ -0,0 +1,193 @@
+package password
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGeneratorCountRanges(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator()
+	input := Input{Length: 16, MinDigits: 2, MaxDigits: 5, MinSymbols: 1, MaxSymbols: 3}
+	seen := make(map[int]bool)
+	for i := 0; i < N; i++ {
+		res, err := gen.Generate(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		digits, symbols := 0, 0
+		for _, c := range res {
+			switch {
+			case strings.ContainsRune(Digits, c):
+				digits++
+			case strings.ContainsRune(Symbols, c):
+				symbols++
+			}
+		}
+		if digits < 2 || digits > 5 {
+			t.Errorf("expected %q to have 2 to 5 digits", res)
+		}
+		if symbols < 1 || symbols > 3 {
+			t.Errorf("expected %q to have 1 to 3 symbols", res)
+		}
+		seen[digits*10+symbols] = true
+
+		if err := gen.Validate(res, input); err != nil {
+			t.Errorf("expected %q to be valid, got %v", res, err)
+		}
+	}
+	if len(seen) != 12 {
+		t.Errorf("expected %v to be %v", len(seen), 12)
+	}
+
+	err := gen.Validate("abcdefghijklmnop", input)
+	var perr *PolicyError
+	if !errors.As(err, &perr) || len(perr.Violations) != 2 {
+		t.Errorf("expected digits and symbols violations, got %v", err)
+	}
+}
+
+func TestGeneratorCountRangesUniform(t *testing.T) {
+	t.Parallel()
+
+	// Every two-character string of "ab01" is allowed, so all 16 must be
+	// equally likely whatever their number of digits.
+	gen := NewGenerator().WithLowerLetters("ab").WithDigits("01")
+	input := Input{Length: 2, MaxDigits: 2, NoUpper: true, AllowRepeat: true}
+
+	bits, err := gen.Entropy(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(bits-4) > 1e-9 {
+		t.Errorf("expected %v to be %v", bits, 4)
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < N; i++ {
+		res, err := gen.Generate(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[res]++
+	}
+	if len(counts) != 16 {
+		t.Errorf("expected %v to be %v", len(counts), 16)
+	}
+	for res, n := range counts {
+		if n < N/16/2 || n > N/16*2 {
+			t.Errorf("%q generated %d times out of %d", res, n, N)
+		}
+	}
+}
+
+func TestGeneratorCountRangesEntropy(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator()
+	exact, err := gen.Entropy(Input{Length: 12, Digits: 3, Symbols: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ranged, err := gen.Entropy(Input{Length: 12, MinDigits: 3, MaxDigits: 3, MinSymbols: 2, MaxSymbols: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(exact-ranged) > 1e-9 {
+		t.Errorf("expected %v to be %v", ranged, exact)
+	}
+
+	wider, err := gen.Entropy(Input{Length: 12, MinDigits: 1, MaxDigits: 4, MinSymbols: 1, MaxSymbols: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wider <= exact {
+		t.Errorf("expected %v to be greater than %v", wider, exact)
+	}
+}
+
+func TestGeneratorCountRangesSameClassRun(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator()
+	input := Input{Length: 14, MinDigits: 1, MaxDigits: 4, MinSymbols: 1, MaxSymbols: 4, MaxSameClassRun: 2}
+
+	// The entropy of the range sums that of every exact composition, whose
+	// layouts are counted separately.
+	sum := 0.0
+	for d := 1; d <= 4; d++ {
+		for s := 1; s <= 4; s++ {
+			bits, err := gen.Entropy(Input{Length: 14, Digits: d, Symbols: s, MaxSameClassRun: 2})
+			if errors.Is(err, ErrSameClassRunUnsatisfiable) {
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			sum += math.Exp2(bits)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		ranged, err := gen.Entropy(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(ranged-math.Log2(sum)) > 1e-9 {
+			t.Errorf("expected %v to be %v", ranged, math.Log2(sum))
+		}
+	}
+
+	// Other charsets are not served from the cache of the default ones.
+	small := gen.WithDigits("01")
+	bits, err := small.Entropy(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(bits-math.Log2(sum)) < 1 {
+		t.Errorf("expected %v to differ from %v", bits, math.Log2(sum))
+	}
+
+	long := Input{Length: 64, MinDigits: 1, MinSymbols: 1, MaxSameClassRun: 3, AllowRepeat: true}
+	for i := 0; i < 100; i++ {
+		res, err := gen.Generate(long)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := gen.Validate(res, long); err != nil {
+			t.Fatalf("expected %q to be valid, got %v", res, err)
+		}
+	}
+}
+
+func TestGeneratorCountRangesErrors(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator()
+	for _, tc := range []struct {
+		input Input
+		err   error
+	}{
+		{Input{Length: 8, Digits: 2, MaxDigits: 4}, ErrInvalidCountRange},
+		{Input{Length: 8, MinDigits: 4, MaxDigits: 2}, ErrInvalidCountRange},
+		{Input{Length: 8, MinSymbols: -1, MaxSymbols: 2}, ErrInvalidCountRange},
+		{Input{Length: 8, MinDigits: 9}, ErrInvalidCountRange},
+		{Input{Length: 8, MinDigits: 5, MinSymbols: 5}, ErrExceedsTotalLength},
+		{Input{Length: 20, MinDigits: 11}, ErrDigitsExceedsAvailable},
+		{Input{Length: 1024, MinDigits: 1, MinSymbols: 1, AllowRepeat: true}, ErrCountRangeTooLarge},
+	} {
+		if _, err := gen.Generate(tc.input); !errors.Is(err, tc.err) {
+			t.Errorf("expected %v to be %v", err, tc.err)
+		}
+		if _, err := gen.Entropy(tc.input); !errors.Is(err, tc.err) {
+			t.Errorf("expected %v to be %v", err, tc.err)
+		}
+	}
+
+	if p := PolicyFromInput(Input{Length: 8, MinDigits: 2, MinSymbols: 1}); p.MinDigits != 2 || p.MinSymbols != 1 {
+		t.Errorf("expected %+v to require 2 digits and 1 symbol", p)
+	}
+}
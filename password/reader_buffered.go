@@ -0,0 +1,74 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd || dragonfly
+
+package password
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// defaultBufferSize is the default size of the BufferedReader buffer.
+const defaultBufferSize = 4096
+
+// BufferedReader is an io.Reader over the platform CSPRNG which amortizes the
+// cost of the underlying getrandom(2), getentropy(2) or arc4random_buf(3) call
+// across many small reads. Generating a password performs many reads of a few
+// bytes each, so buffering removes most of the syscall overhead.
+//
+// Buffered bytes are handed out exactly once and wiped as they are consumed.
+// It is safe for concurrent use.
+type BufferedReader struct {
+	src io.Reader
+
+	mu  sync.Mutex
+	buf []byte
+	off int
+}
+
+// NewBufferedReader creates a new BufferedReader with the given buffer size. If
+// size is not positive, a default of 4096 bytes is used.
+func NewBufferedReader(size int) *BufferedReader {
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+
+	buf := make([]byte, size)
+	return &BufferedReader{
+		src: rand.Reader,
+		buf: buf,
+		off: len(buf),
+	}
+}
+
+// Read fills p with random bytes.
+func (b *BufferedReader) Read(p []byte) (int, error) {
+	// Large reads gain nothing from the buffer.
+	if len(p) >= len(b.buf) {
+		if _, err := io.ReadFull(b.src, p); err != nil {
+			return 0, fmt.Errorf("failed to read random bytes: %w", err)
+		}
+		return len(p), nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n := 0
+	for n < len(p) {
+		if b.off == len(b.buf) {
+			if _, err := io.ReadFull(b.src, b.buf); err != nil {
+				clear(p)
+				return 0, fmt.Errorf("failed to read random bytes: %w", err)
+			}
+			b.off = 0
+		}
+
+		m := copy(p[n:], b.buf[b.off:])
+		clear(b.buf[b.off : b.off+m])
+		b.off += m
+		n += m
+	}
+	return n, nil
+}
@@ -0,0 +1,88 @@
+//go:build linux || darwin || freebsd || openbsd || netbsd || dragonfly
+
+package password
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+)
+
+func TestBufferedReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("small_reads", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewBufferedReader(64)
+		seen := make(map[string]struct{})
+		for i := 0; i < 100; i++ {
+			b := make([]byte, 24)
+			if n, err := r.Read(b); err != nil || n != len(b) {
+				t.Fatalf("expected %d bytes, got %d (%v)", len(b), n, err)
+			}
+
+			if _, ok := seen[string(b)]; ok {
+				t.Errorf("%x was returned twice", b)
+			}
+			seen[string(b)] = struct{}{}
+		}
+	})
+
+	t.Run("large_reads", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewBufferedReader(16)
+		b := make([]byte, 1024)
+		if n, err := r.Read(b); err != nil || n != len(b) {
+			t.Fatalf("expected %d bytes, got %d (%v)", len(b), n, err)
+		}
+		if bytes.Equal(b, make([]byte, len(b))) {
+			t.Error("expected random bytes")
+		}
+	})
+
+	t.Run("wipes_buffer", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewBufferedReader(32)
+		if _, err := r.Read(make([]byte, 8)); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(r.buf[:8], make([]byte, 8)) {
+			t.Errorf("expected consumed bytes to be wiped, got %x", r.buf[:8])
+		}
+	})
+}
+
+func BenchmarkCryptoRandReader(b *testing.B) {
+	p := make([]byte, 8)
+	for i := 0; i < b.N; i++ {
+		if _, err := rand.Read(p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBufferedReader(b *testing.B) {
+	r := NewBufferedReader(0)
+	p := make([]byte, 8)
+	for i := 0; i < b.N; i++ {
+		if _, err := r.Read(p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBufferedReader_Parallel(b *testing.B) {
+	r := NewBufferedReader(0)
+	b.RunParallel(func(pb *testing.PB) {
+		p := make([]byte, 8)
+		for pb.Next() {
+			if _, err := r.Read(p); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,73 @@
+package password
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MixedReader is an io.Reader which combines several entropy sources by XORing
+// their output. As long as at least one source is unpredictable and
+// independent of the others, so is the result; this satisfies requirements to
+// combine an OS CSPRNG with a hardware or organization-supplied source. It is
+// safe for concurrent use if all sources are.
+type MixedReader struct {
+	readers []io.Reader
+
+	pool sync.Pool
+}
+
+// NewMixedReader creates a new MixedReader from the given readers. It panics if
+// any of the readers is nil.
+func NewMixedReader(readers ...io.Reader) *MixedReader {
+	for i, r := range readers {
+		if r == nil {
+			panic(fmt.Sprintf("password: entropy source %d is nil", i))
+		}
+	}
+
+	return &MixedReader{
+		readers: append([]io.Reader(nil), readers...),
+	}
+}
+
+// Read fills p with the XOR of bytes read from every source. If any source
+// fails, p is wiped and the error is returned.
+func (m *MixedReader) Read(p []byte) (int, error) {
+	if len(m.readers) == 0 {
+		return 0, ErrEntropyUnavailable
+	}
+
+	if _, err := io.ReadFull(m.readers[0], p); err != nil {
+		clear(p)
+		return 0, fmt.Errorf("failed to read from entropy source 0: %w", err)
+	}
+
+	buf := m.buffer(len(p))
+	defer m.pool.Put(buf)
+
+	for i, r := range m.readers[1:] {
+		b := (*buf)[:len(p)]
+		if _, err := io.ReadFull(r, b); err != nil {
+			clear(b)
+			clear(p)
+			return 0, fmt.Errorf("failed to read from entropy source %d: %w", i+1, err)
+		}
+
+		for j := range p {
+			p[j] ^= b[j]
+		}
+		clear(b)
+	}
+	return len(p), nil
+}
+
+// buffer returns a scratch buffer of at least n bytes.
+func (m *MixedReader) buffer(n int) *[]byte {
+	if buf, ok := m.pool.Get().(*[]byte); ok && cap(*buf) >= n {
+		return buf
+	}
+
+	buf := make([]byte, n)
+	return &buf
+}
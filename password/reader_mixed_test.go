@@ -0,0 +1,132 @@
+package password
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+)
+
+// testReader is a deterministic io.Reader producing a SHA-256 based stream
+// from a seed.
+type testReader struct {
+	seed    string
+	counter uint64
+	buf     []byte
+}
+
+func (r *testReader) Read(p []byte) (int, error) {
+	for n := 0; n < len(p); {
+		if len(r.buf) == 0 {
+			var block [8]byte
+			binary.BigEndian.PutUint64(block[:], r.counter)
+			r.counter++
+			sum := sha256.Sum256(append([]byte(r.seed), block[:]...))
+			r.buf = sum[:]
+		}
+
+		m := copy(p[n:], r.buf)
+		r.buf = r.buf[m:]
+		n += m
+	}
+	return len(p), nil
+}
+
+func TestMixedReader(t *testing.T) {
+	t.Parallel()
+
+	t.Run("xor", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewMixedReader(
+			bytes.NewReader([]byte{0x0f, 0xff, 0x00}),
+			bytes.NewReader([]byte{0xf0, 0x0f, 0x00}),
+			bytes.NewReader([]byte{0x01, 0x01, 0x01}),
+		)
+
+		b := make([]byte, 3)
+		if _, err := r.Read(b); err != nil {
+			t.Fatal(err)
+		}
+		if want := []byte{0xfe, 0xf1, 0x01}; !bytes.Equal(b, want) {
+			t.Errorf("expected %x to be %x", b, want)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewMixedReader(rand.Reader, errReader{})
+		b := make([]byte, 16)
+		if _, err := r.Read(b); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("expected %q to be %q", err, io.ErrUnexpectedEOF)
+		}
+		if !bytes.Equal(b, make([]byte, 16)) {
+			t.Errorf("expected buffer to be wiped, got %x", b)
+		}
+	})
+
+	t.Run("no_readers", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := NewMixedReader().Read(make([]byte, 1)); !errors.Is(err, ErrEntropyUnavailable) {
+			t.Errorf("expected %q to be %q", err, ErrEntropyUnavailable)
+		}
+	})
+
+	t.Run("nil_reader", func(t *testing.T) {
+		t.Parallel()
+
+		defer func() {
+			if recover() == nil {
+				t.Error("expected NewMixedReader to panic on a nil reader")
+			}
+		}()
+		NewMixedReader(rand.Reader, nil)
+	})
+}
+
+func TestGeneratorWithReaders(t *testing.T) {
+	t.Parallel()
+
+	input := Input{Length: 32, Digits: 8, Symbols: 8, AllowRepeat: true}
+
+	a, err := NewGenerator().
+		WithReaders(&testReader{seed: "a"}, &testReader{seed: "b"}).
+		Generate(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := NewGenerator().
+		WithReaders(&testReader{seed: "a"}, &testReader{seed: "b"}).
+		Generate(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewGenerator().
+		WithReaders(&testReader{seed: "a"}, &testReader{seed: "c"}).
+		Generate(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if a != b {
+		t.Errorf("expected %q to be %q", a, b)
+	}
+	if a == c {
+		t.Errorf("expected %q to differ from %q", a, c)
+	}
+
+	if _, err := NewGenerator().WithReaders(rand.Reader, errReader{}).Generate(input); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected %q to be %q", err, io.ErrUnexpectedEOF)
+	}
+
+	if _, err := NewGenerator().WithReaders(nil).Generate(input); err != nil {
+		t.Errorf("expected %v to be %v", err, nil)
+	}
+}
@@ -0,0 +1,98 @@
+package password
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+// CrockfordAlphabet is the lowercase alphabet of Crockford's base32, without
+// the letters i, l, o and u which are confused with digits or spell words.
+const CrockfordAlphabet = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// RecoveryCodeSeparator is the separator of the groups of a recovery code.
+const RecoveryCodeSeparator = "-"
+
+// ErrRecoveryCodes is the error returned when the number or size of recovery
+// codes is not positive, or when more distinct codes are requested than can
+// exist.
+var ErrRecoveryCodes = errors.New("invalid number or size of recovery codes")
+
+// GenerateRecoveryCodes generates count distinct recovery codes, such as
+// "ved3-k9f2-8map", for users who lose their second factor. Every code has
+// groups groups of groupLen characters of CrockfordAlphabet, joined by
+// RecoveryCodeSeparator, and holds groupLen*groups*5 bits of entropy. Codes
+// are distinct within a batch: a code equal to a previous one is generated
+// again.
+func (g Generator) GenerateRecoveryCodes(count, groupLen, groups int) ([]string, error) {
+	if count < 1 || groupLen < 1 || groups < 1 {
+		return nil, ErrRecoveryCodes
+	}
+	if bits := float64(groupLen*groups) * math.Log2(float64(len(CrockfordAlphabet))); bits < 63 && float64(count) > math.Exp2(bits) {
+		return nil, ErrRecoveryCodes
+	}
+
+	codes := make([]string, 0, count)
+	seen := make(map[string]bool, count)
+	for attempts := 0; len(codes) < count; attempts++ {
+		if attempts >= count+maxFilterAttempts {
+			return nil, ErrFilterAttemptsExceeded
+		}
+
+		idx, err := UniformIndices(g.reader, len(CrockfordAlphabet), groupLen*groups)
+		if err != nil {
+			return nil, err
+		}
+
+		var b strings.Builder
+		for i, j := range idx {
+			if i > 0 && i%groupLen == 0 {
+				b.WriteString(RecoveryCodeSeparator)
+			}
+			b.WriteByte(CrockfordAlphabet[j])
+		}
+
+		code := b.String()
+		if seen[code] {
+			continue
+		}
+		seen[code] = true
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// NormalizeRecoveryCode normalizes a recovery code typed by a user before it
+// is compared with the generated ones: as in Crockford's base32, letters are
+// lowercased, i and l are read as 1 and o as 0, and spaces and separators are
+// removed. Groups are then joined again by RecoveryCodeSeparator every
+// groupLen characters.
+func NormalizeRecoveryCode(code string, groupLen int) string {
+	var chars []rune
+	for _, r := range strings.ToLower(code) {
+		switch r {
+		case ' ', '\t', '-', '_', '.':
+			continue
+		case 'i', 'l':
+			r = '1'
+		case 'o':
+			r = '0'
+		}
+		chars = append(chars, r)
+	}
+
+	var b strings.Builder
+	for i, r := range chars {
+		if groupLen > 0 && i > 0 && i%groupLen == 0 {
+			b.WriteString(RecoveryCodeSeparator)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// GenerateRecoveryCodes is the package shortcut for
+// Generator.GenerateRecoveryCodes.
+func GenerateRecoveryCodes(count, groupLen, groups int) ([]string, error) {
+	return NewGenerator().GenerateRecoveryCodes(count, groupLen, groups)
+}
@@ -0,0 +1,60 @@
+package password
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	t.Parallel()
+
+	re := regexp.MustCompile(`^[0-9a-hjkmnp-tv-z]{4}-[0-9a-hjkmnp-tv-z]{4}-[0-9a-hjkmnp-tv-z]{4}$`)
+	codes, err := GenerateRecoveryCodes(10, 4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != 10 {
+		t.Fatalf("expected 10 codes, got %d", len(codes))
+	}
+	for _, code := range codes {
+		if !re.MatchString(code) {
+			t.Errorf("unexpected code %q", code)
+		}
+	}
+
+	// Every code of a single character is generated exactly once.
+	codes, err = GenerateRecoveryCodes(len(CrockfordAlphabet), 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if seen[code] {
+			t.Errorf("duplicate code %q", code)
+		}
+		seen[code] = true
+	}
+
+	for _, args := range [][3]int{{0, 4, 3}, {1, 0, 3}, {1, 4, 0}, {33, 1, 1}} {
+		if _, err := GenerateRecoveryCodes(args[0], args[1], args[2]); !errors.Is(err, ErrRecoveryCodes) {
+			t.Errorf("expected %v to be %v", err, ErrRecoveryCodes)
+		}
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	t.Parallel()
+
+	for code, want := range map[string]string{
+		"ved3-k9f2-8map":   "ved3-k9f2-8map",
+		"VED3 K9F2 8MAP":   "ved3-k9f2-8map",
+		"ved3k9f28map":     "ved3-k9f2-8map",
+		"OlI0-abcd-efgh":   "0110-abcd-efgh",
+		" ved3_k9f2.8map ": "ved3-k9f2-8map",
+	} {
+		if got := NormalizeRecoveryCode(code, 4); got != want {
+			t.Errorf("expected NormalizeRecoveryCode(%q) to be %q, got %q", code, want, got)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Redacted replaces secrets wherever they are printed, logged or encoded.
+const Redacted = "[REDACTED]"
+
+// Secret is a generated password which cannot be printed by accident. Its
+// String method, every fmt verb, JSON, text and slog encodings all produce
+// Redacted; only ExposeSecret returns the password. The zero Secret is empty.
+// A Secret holds a string, which cannot be wiped; Password can be.
+type Secret struct {
+	value string
+}
+
+// NewSecret wraps the given password.
+func NewSecret(s string) Secret {
+	return Secret{value: s}
+}
+
+// ExposeSecret returns the password. Call it only where the password is
+// actually needed, such as when setting it on the account.
+func (s Secret) ExposeSecret() string {
+	return s.value
+}
+
+// String returns Redacted.
+func (s Secret) String() string {
+	return Redacted
+}
+
+// GoString returns Redacted.
+func (s Secret) GoString() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter, so that no verb prints the password.
+func (s Secret) Format(f fmt.State, _ rune) {
+	io.WriteString(f, Redacted)
+}
+
+// MarshalText implements encoding.TextMarshaler, returning Redacted.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// LogValue implements slog.LogValuer, returning Redacted.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// GenerateSecret is the same as Generate, but returns the password as a
+// Secret.
+func (g Generator) GenerateSecret(input Input) (Secret, error) {
+	res, err := g.Generate(input)
+	if err != nil {
+		return Secret{}, err
+	}
+	return NewSecret(res), nil
+}
+
+// GenerateSecret is the package shortcut for Generator.GenerateSecret.
+func GenerateSecret(input Input) (Secret, error) {
+	return NewGenerator().GenerateSecret(input)
+}
+
+// Password is a generated password which cannot be printed by accident and
+// can be wiped from memory. Like Secret, its String method, every fmt verb,
+// JSON, text and slog encodings all produce Redacted; only Reveal and Bytes
+// return the password. Zero overwrites it once it is no longer needed.
+//
+// Copies of a Password share its memory, so that zeroing any of them zeroes
+// all. The zero Password is empty.
+type Password struct {
+	p *passwordBytes
+}
+
+// passwordBytes holds the bytes of a Password.
+type passwordBytes struct {
+	b []byte
+}
+
+// NewPassword wraps the given password, taking ownership of b: it is
+// overwritten by Zero.
+func NewPassword(b []byte) Password {
+	return Password{p: &passwordBytes{b: b}}
+}
+
+// Reveal returns the password, or an empty string once zeroed. The returned
+// string is a copy which Zero cannot wipe: prefer Bytes where the password is
+// accepted as bytes.
+func (p Password) Reveal() string {
+	return string(p.Bytes())
+}
+
+// Bytes returns the bytes of the password, without copying them. They are
+// overwritten by Zero and must not be modified.
+func (p Password) Bytes() []byte {
+	if p.p == nil {
+		return nil
+	}
+	return p.p.b
+}
+
+// Zero overwrites the password with zeros and empties it, in all copies of the
+// Password.
+func (p Password) Zero() {
+	if p.p == nil {
+		return
+	}
+	clear(p.p.b)
+	p.p.b = nil
+}
+
+// String returns Redacted.
+func (p Password) String() string {
+	return Redacted
+}
+
+// GoString returns Redacted.
+func (p Password) GoString() string {
+	return Redacted
+}
+
+// Format implements fmt.Formatter, so that no verb prints the password.
+func (p Password) Format(f fmt.State, _ rune) {
+	io.WriteString(f, Redacted)
+}
+
+// MarshalText implements encoding.TextMarshaler, returning Redacted.
+func (p Password) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// LogValue implements slog.LogValuer, returning Redacted.
+func (p Password) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// GeneratePassword is the same as Generate, but returns the password as a
+// Password. Unless the Generator has post-processors or filters, the password
+// is generated as by AppendPassword and never held in a string, so that Zero
+// wipes every copy of it.
+func (g Generator) GeneratePassword(input Input) (Password, error) {
+	b, err := g.AppendPassword(nil, input)
+	if errors.Is(err, ErrRequiresString) {
+		var res string
+		res, err = g.Generate(input)
+		b = []byte(res)
+	}
+	if err != nil {
+		return Password{}, err
+	}
+	return NewPassword(b), nil
+}
+
+// GeneratePassword is the package shortcut for Generator.GeneratePassword.
+func GeneratePassword(input Input) (Password, error) {
+	return NewGenerator().GeneratePassword(input)
+}
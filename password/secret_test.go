@@ -0,0 +1,103 @@
+package password
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSecret(t *testing.T) {
+	t.Parallel()
+
+	s, err := GenerateSecret(Input{Length: 24, Digits: 4, Symbols: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pw := s.ExposeSecret()
+	if len(pw) != 24 {
+		t.Fatalf("expected 24 characters, got %q", pw)
+	}
+
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%T %[1]v"} {
+		if got := fmt.Sprintf(verb, s); strings.Contains(got, pw) || !strings.Contains(got, Redacted) {
+			t.Errorf("expected %s to redact the secret, got %q", verb, got)
+		}
+	}
+	if got := fmt.Sprint(struct{ S Secret }{s}); strings.Contains(got, pw) {
+		t.Errorf("expected nested secret to be redacted, got %q", got)
+	}
+
+	b, err := json.Marshal(map[string]Secret{"password": s})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"password":"[REDACTED]"}`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("generated", "password", s)
+	if strings.Contains(buf.String(), pw) || !strings.Contains(buf.String(), Redacted) {
+		t.Errorf("expected log to redact the secret, got %q", buf.String())
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	t.Parallel()
+
+	p, err := GeneratePassword(Input{Length: 24, Digits: 4, Symbols: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pw := p.Reveal()
+	if len(pw) != 24 {
+		t.Fatalf("expected 24 characters, got %q", pw)
+	}
+
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%T %[1]v"} {
+		if got := fmt.Sprintf(verb, p); strings.Contains(got, pw) || !strings.Contains(got, Redacted) {
+			t.Errorf("expected %s to redact the password, got %q", verb, got)
+		}
+	}
+	b, err := json.Marshal(map[string]Password{"password": p})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(b), `{"password":"[REDACTED]"}`; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("generated", "password", p)
+	if strings.Contains(buf.String(), pw) || !strings.Contains(buf.String(), Redacted) {
+		t.Errorf("expected log to redact the password, got %q", buf.String())
+	}
+
+	b = p.Bytes()
+	c := p
+	c.Zero()
+	if !bytes.Equal(b, make([]byte, len(b))) {
+		t.Errorf("expected %q to be zeroed", b)
+	}
+	if got := p.Reveal(); got != "" {
+		t.Errorf("expected %q to be empty", got)
+	}
+	Password{}.Zero()
+}
+
+func TestGeneratePasswordPostProcessed(t *testing.T) {
+	t.Parallel()
+
+	g := NewGenerator().WithPostProcessors(PostProcessorFunc(func(s string) (string, error) {
+		return strings.ToUpper(s), nil
+	}))
+	p, err := g.GeneratePassword(Input{Length: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := p.Reveal(); got != strings.ToUpper(got) || len(got) != 16 {
+		t.Errorf("expected %q to be post-processed", got)
+	}
+}
@@ -0,0 +1,96 @@
+package password
+
+import (
+	"io"
+	"sync/atomic"
+)
+
+// Stats is the usage of the entropy source by one or more Generate calls.
+type Stats struct {
+	// Calls is the number of Generate and GenerateN calls, including failed
+	// ones.
+	Calls uint64
+
+	// RandomBytes is the number of bytes read from the Generator reader,
+	// including those read for passwords rejected by filters and for values
+	// rejected to avoid modulo bias.
+	RandomBytes uint64
+}
+
+// statsCounter accumulates the Stats of a Generator and its copies.
+type statsCounter struct {
+	calls       atomic.Uint64
+	randomBytes atomic.Uint64
+}
+
+// WithStats creates a new Generator from another Generator which accounts for
+// the usage of its entropy source, as reported by Stats. The accounting starts
+// from zero and is shared by all Generators derived from the new one. This is
+// meant for capacity planning of HSM-backed or otherwise metered sources.
+func (g Generator) WithStats() Generator {
+	return g.With(WithStats())
+}
+
+// WithStats returns an Option accounting for the usage of the entropy source,
+// as Generator.WithStats.
+func WithStats() Option {
+	return func(g *Generator) {
+		g.stats = new(statsCounter)
+	}
+}
+
+// Stats returns the total usage of the entropy source by the Generator and the
+// Generators derived from it since WithStats was called, or zero Stats if it
+// was not.
+func (g Generator) Stats() Stats {
+	if g.stats == nil {
+		return Stats{}
+	}
+	return Stats{
+		Calls:       g.stats.calls.Load(),
+		RandomBytes: g.stats.randomBytes.Load(),
+	}
+}
+
+// GenerateStats is the same as Generate, but also returns the usage of the
+// entropy source by this call, even if it failed.
+func (g Generator) GenerateStats(input Input) (string, Stats, error) {
+	var (
+		res string
+		err error
+	)
+	st := g.account(func(g Generator) {
+		res, err = g.generateFiltered(input)
+	})
+	return res, st, err
+}
+
+// account runs f with a copy of the Generator counting the bytes read from its
+// reader, and adds the usage to the Generator Stats as one call.
+func (g Generator) account(f func(g Generator)) Stats {
+	r := &countingReader{r: g.reader}
+	counted := g
+	counted.reader = r
+	f(counted)
+
+	st := Stats{Calls: 1, RandomBytes: r.n}
+	if g.stats != nil {
+		g.stats.calls.Add(st.Calls)
+		g.stats.randomBytes.Add(st.RandomBytes)
+	}
+	return st
+}
+
+// countingReader is an io.Reader counting the bytes read from another reader.
+// It is not safe for concurrent use.
+type countingReader struct {
+	r io.Reader
+	n uint64
+}
+
+// Read reads from the underlying reader.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += uint64(n)
+	return n, err
+}
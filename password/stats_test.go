@@ -0,0 +1,78 @@
+package password
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestGeneratorStats(t *testing.T) {
+	t.Parallel()
+
+	gen := NewGenerator().WithStats()
+	if st := gen.Stats(); st != (Stats{}) {
+		t.Errorf("expected zero stats, got %+v", st)
+	}
+
+	_, first, err := gen.GenerateStats(Input{Length: 16, Digits: 4, Symbols: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Calls != 1 || first.RandomBytes == 0 {
+		t.Errorf("unexpected stats %+v", first)
+	}
+
+	// Derived generators share the accounting.
+	derived := gen.WithDigits("0123")
+	if _, err := derived.Generate(Input{Length: 16, Digits: 4, AllowRepeat: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	st := gen.Stats()
+	if st.Calls != 2 || st.RandomBytes <= first.RandomBytes {
+		t.Errorf("unexpected total stats %+v", st)
+	}
+	if derived.Stats() != st {
+		t.Errorf("expected derived stats %+v to be %+v", derived.Stats(), st)
+	}
+
+	if st := NewGenerator().Stats(); st != (Stats{}) {
+		t.Errorf("expected zero stats without accounting, got %+v", st)
+	}
+}
+
+func TestGeneratorGenerateStats(t *testing.T) {
+	t.Parallel()
+
+	t.Run("exact", func(t *testing.T) {
+		t.Parallel()
+
+		// Every character and every position costs 8 bytes, and no value of
+		// the repeated pattern is rejected for bias.
+		gen := NewGenerator().WithReaders(bytes.NewReader(bytes.Repeat([]byte{1}, 1024)))
+		_, st, err := gen.GenerateStats(Input{Length: 4, Digits: 1, AllowRepeat: true, PreserveClassOrder: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if st.RandomBytes != 4*8 {
+			t.Errorf("expected 32 random bytes, got %+v", st)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		t.Parallel()
+
+		gen := NewGenerator().WithReaders(bytes.NewReader(make([]byte, 12))).WithStats()
+		_, st, err := gen.GenerateStats(Input{Length: 4, AllowRepeat: true})
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("expected %v to be %v", err, io.ErrUnexpectedEOF)
+		}
+		if st.Calls != 1 || st.RandomBytes != 12 {
+			t.Errorf("unexpected stats %+v", st)
+		}
+		if gen.Stats() != st {
+			t.Errorf("expected failed call to be accounted, got %+v", gen.Stats())
+		}
+	})
+}
@@ -0,0 +1,147 @@
+package password
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// Rating is the qualitative strength of a password, on the scale of zxcvbn.
+type Rating int
+
+const (
+	// RatingTooGuessable is the rating of passwords found in fewer than 10^3
+	// guesses, such as common passwords.
+	RatingTooGuessable Rating = iota
+
+	// RatingVeryGuessable is the rating of passwords found in fewer than 10^6
+	// guesses, which only resist throttled online attacks.
+	RatingVeryGuessable
+
+	// RatingSomewhatGuessable is the rating of passwords found in fewer than
+	// 10^8 guesses, which resist unthrottled online attacks.
+	RatingSomewhatGuessable
+
+	// RatingSafelyUnguessable is the rating of passwords found in fewer than
+	// 10^10 guesses, which resist offline attacks on slow hashes.
+	RatingSafelyUnguessable
+
+	// RatingVeryUnguessable is the rating of all other passwords.
+	RatingVeryUnguessable
+)
+
+// ratingGuessesLog10 are the base-10 logarithms of the guesses needed to reach
+// every rating above RatingTooGuessable.
+var ratingGuessesLog10 = [...]float64{3, 6, 8, 10}
+
+// String returns the name of the rating.
+func (r Rating) String() string {
+	switch r {
+	case RatingTooGuessable:
+		return "too guessable"
+	case RatingVeryGuessable:
+		return "very guessable"
+	case RatingSomewhatGuessable:
+		return "somewhat guessable"
+	case RatingSafelyUnguessable:
+		return "safely unguessable"
+	case RatingVeryUnguessable:
+		return "very unguessable"
+	}
+	return "unknown"
+}
+
+// Score is the estimated strength of a password, as returned by Strength.
+type Score struct {
+	// Rating is the qualitative strength of the password.
+	Rating Rating
+
+	// EntropyBits is the base-2 logarithm of the number of guesses needed to
+	// find the password. For passwords generated without patterns, it is the
+	// entropy of their generation.
+	EntropyBits float64
+
+	// Crack is the estimated number of guesses and time needed to find the
+	// password.
+	Crack CrackEstimate
+
+	// Warning explains why the password is weak, or is empty.
+	Warning string
+
+	// Suggestions are ways to make the password stronger. They are only given
+	// for passwords rated below RatingSafelyUnguessable.
+	Suggestions []string
+}
+
+// Strength estimates the strength of the password with the model, rating it
+// and giving feedback in the manner of zxcvbn.
+func (m StrengthModel) Strength(pw string) Score {
+	e := m.Estimate(pw)
+	s := Score{
+		Rating:      RatingVeryUnguessable,
+		EntropyBits: e.GuessesLog10 * math.Log2(10),
+		Crack:       e,
+	}
+	for i, log := range ratingGuessesLog10 {
+		if e.GuessesLog10 < log {
+			s.Rating = Rating(i)
+			break
+		}
+	}
+	if s.Rating >= RatingSafelyUnguessable {
+		return s
+	}
+
+	a := Analyze(pw)
+	feedback := []struct {
+		patterns   []Pattern
+		warning    string
+		suggestion string
+	}{
+		{a.DictionaryWords, "%q is a common word or password", "Avoid common words and passwords, even with substitutions"},
+		{a.KeyboardWalks, "keyboard walks like %q are easy to guess", "Avoid walks of adjacent keys"},
+		{a.Sequences, "sequences like %q are easy to guess", "Avoid sequences of letters or digits"},
+		{a.Runs, "repeated characters like %q are easy to guess", "Avoid repeated characters"},
+	}
+	for _, f := range feedback {
+		if len(f.patterns) == 0 {
+			continue
+		}
+		if s.Warning == "" {
+			s.Warning = fmt.Sprintf(f.warning, f.patterns[0].Value)
+		}
+		s.Suggestions = append(s.Suggestions, f.suggestion)
+	}
+	if s.Warning == "" {
+		s.Warning = "the password is too short"
+	}
+	s.Suggestions = append(s.Suggestions, "Use a longer password, or generate a random one")
+	return s
+}
+
+// Strength estimates the strength of the password, counting the guesses of a
+// character of every class as the size of the charset of the Generator for
+// that class. The score of a password generated by the Generator thus matches
+// the entropy of its generation, less the patterns it happens to contain.
+func (g Generator) Strength(pw string) Score {
+	m := DefaultStrengthModel()
+	for _, c := range []struct {
+		guesses *float64
+		charset string
+	}{
+		{&m.Classes.Lower, g.lowerLetters},
+		{&m.Classes.Upper, g.upperLetters},
+		{&m.Classes.Digit, g.digits},
+		{&m.Classes.Symbol, g.symbols},
+	} {
+		if n := utf8.RuneCountInString(c.charset); n > 0 {
+			*c.guesses = float64(n)
+		}
+	}
+	return m.Strength(pw)
+}
+
+// Strength is the package shortcut for Generator.Strength.
+func Strength(pw string) Score {
+	return NewGenerator().Strength(pw)
+}
@@ -0,0 +1,73 @@
+package password
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestStrength(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pw   string
+		want Rating
+	}{
+		{"", RatingTooGuessable},
+		{"abc", RatingTooGuessable},
+		{"password", RatingVeryGuessable},
+		{"qwerty1x", RatingVeryGuessable},
+		{"qwerty1x9Z", RatingSomewhatGuessable},
+		{"x7#Kq9!vLm2@", RatingVeryUnguessable},
+	}
+	for _, tc := range cases {
+		if got := Strength(tc.pw).Rating; got != tc.want {
+			t.Errorf("expected %q to be %v, got %v", tc.pw, tc.want, got)
+		}
+	}
+
+	s := Strength("aB3!")
+	if want := math.Log2(26 * 26 * 10 * float64(len(Symbols))); math.Abs(s.EntropyBits-want) > 1e-9 {
+		t.Errorf("expected %v bits, got %v", want, s.EntropyBits)
+	}
+	g := NewGenerator().WithSymbols("!@")
+	if want := math.Log2(26 * 26 * 10 * 2); math.Abs(g.Strength("aB3!").EntropyBits-want) > 1e-9 {
+		t.Errorf("expected the charsets of the generator to be used, got %v bits", g.Strength("aB3!").EntropyBits)
+	}
+
+	s = Strength("P4ssword")
+	if !strings.Contains(s.Warning, "P4ssword") || len(s.Suggestions) == 0 {
+		t.Errorf("expected feedback on the common password, got %+v", s)
+	}
+	if s.Crack.Seconds["online_throttled"] <= s.Crack.Seconds["offline_fast_hash"] {
+		t.Errorf("expected crack times for every scenario, got %v", s.Crack.Seconds)
+	}
+	if s := Strength("x7#Kq9!vLm2@"); s.Warning != "" || s.Suggestions != nil {
+		t.Errorf("expected no feedback on a strong password, got %+v", s)
+	}
+}
+
+func TestStrengthGenerated(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < N; i++ {
+		res, err := Generate(Input{Length: 16, Digits: 3, Symbols: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := Strength(res); s.Rating != RatingVeryUnguessable {
+			t.Fatalf("expected %q to be %v, got %+v", res, RatingVeryUnguessable, s)
+		}
+	}
+}
+
+func TestRatingString(t *testing.T) {
+	t.Parallel()
+
+	if got := RatingSafelyUnguessable.String(); got != "safely unguessable" {
+		t.Errorf("expected %q to be %q", got, "safely unguessable")
+	}
+	if got := Rating(-1).String(); got != "unknown" {
+		t.Errorf("expected %q to be %q", got, "unknown")
+	}
+}
@@ -0,0 +1,65 @@
+package password
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// ErrTokenSize is the error returned when a token of less than one byte is
+// requested.
+var ErrTokenSize = errors.New("token size must be positive")
+
+// tokenBase32 is the unpadded base32 encoding of tokens.
+var tokenBase32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateHex generates n random bytes, such as an API key, encoded as 2n
+// lowercase hexadecimal characters.
+func (g Generator) GenerateHex(n int) (string, error) {
+	return g.generateToken(n, hex.EncodeToString)
+}
+
+// GenerateBase32 generates n random bytes encoded as unpadded base32 of RFC
+// 4648, with uppercase letters and the digits 2 to 7. It is case-insensitive,
+// and suits TOTP secrets and codes read aloud.
+func (g Generator) GenerateBase32(n int) (string, error) {
+	return g.generateToken(n, tokenBase32.EncodeToString)
+}
+
+// GenerateBase64URL generates n random bytes encoded as unpadded base64url of
+// RFC 4648, which is safe in URLs, file names and HTTP headers.
+func (g Generator) GenerateBase64URL(n int) (string, error) {
+	return g.generateToken(n, base64.RawURLEncoding.EncodeToString)
+}
+
+// generateToken generates n random bytes, using the Generator reader, and
+// encodes them. The bytes are wiped once encoded.
+func (g Generator) generateToken(n int, encode func([]byte) string) (string, error) {
+	if n < 1 {
+		return "", ErrTokenSize
+	}
+
+	b := make([]byte, n)
+	defer clear(b)
+	if _, err := io.ReadFull(g.reader, b); err != nil {
+		return "", err
+	}
+	return encode(b), nil
+}
+
+// GenerateHex is the package shortcut for Generator.GenerateHex.
+func GenerateHex(n int) (string, error) {
+	return NewGenerator().GenerateHex(n)
+}
+
+// GenerateBase32 is the package shortcut for Generator.GenerateBase32.
+func GenerateBase32(n int) (string, error) {
+	return NewGenerator().GenerateBase32(n)
+}
+
+// GenerateBase64URL is the package shortcut for Generator.GenerateBase64URL.
+func GenerateBase64URL(n int) (string, error) {
+	return NewGenerator().GenerateBase64URL(n)
+}
@@ -0,0 +1,59 @@
+package password
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestGenerateTokens(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		generate func(int) (string, error)
+		decode   func(string) ([]byte, error)
+		length   int
+	}{
+		{"hex", GenerateHex, hex.DecodeString, 64},
+		{"base32", GenerateBase32, base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString, 52},
+		{"base64url", GenerateBase64URL, base64.RawURLEncoding.DecodeString, 43},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			seen := make(map[string]bool)
+			for i := 0; i < 100; i++ {
+				s, err := tc.generate(32)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(s) != tc.length {
+					t.Errorf("expected %q to have %d characters", s, tc.length)
+				}
+				b, err := tc.decode(s)
+				if err != nil || len(b) != 32 {
+					t.Errorf("expected %q to decode to 32 bytes, got %d: %v", s, len(b), err)
+				}
+				if seen[s] {
+					t.Errorf("duplicate token %q", s)
+				}
+				seen[s] = true
+			}
+
+			if _, err := tc.generate(0); !errors.Is(err, ErrTokenSize) {
+				t.Errorf("expected %v to be %v", err, ErrTokenSize)
+			}
+		})
+	}
+
+	if _, err := NewGenerator().WithReader(errReader{}).GenerateHex(16); err == nil {
+		t.Error("expected the reader error to be returned")
+	}
+}
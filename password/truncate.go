@@ -0,0 +1,81 @@
+package password
+
+// Truncate shortens the password to at most maxLen runes, for systems with a
+// maximum length discovered after the password was generated. Rather than
+// cutting the end blindly, it drops characters from the end whose class is in
+// surplus, keeping those needed for the minimum digits and symbols and the
+// required classes of the policy. The result is then checked against the
+// policy, and a *PolicyError is returned if it does not follow it.
+//
+// Truncate also returns the entropy, in bits, of the result, as estimated by
+// Generator.Entropy for a default Generator producing passwords with the same
+// number of letters, digits and symbols. ErrInvalidRange is returned if maxLen
+// is not positive.
+func Truncate(pw string, maxLen int, policy Policy) (string, float64, error) {
+	if maxLen < 1 {
+		return "", 0, ErrInvalidRange
+	}
+
+	runes := []rune(pw)
+	need := make(map[Class]int)
+	need[ClassDigit] = policy.MinDigits
+	need[ClassSymbol] = policy.MinSymbols
+	for _, c := range policy.RequiredClasses {
+		need[c] = max(need[c], 1)
+	}
+	counts := make(map[Class]int)
+	for _, r := range runes {
+		counts[ClassOf(r)]++
+	}
+
+	// Drop surplus characters from the end, then the end itself if the
+	// minimums cannot all be kept.
+	drop := len(runes) - maxLen
+	for i := len(runes) - 1; i >= 0 && drop > 0; i-- {
+		if c := ClassOf(runes[i]); counts[c] > need[c] {
+			counts[c]--
+			runes = append(runes[:i], runes[i+1:]...)
+			drop--
+		}
+	}
+	if drop > 0 {
+		runes = runes[:maxLen]
+	}
+
+	res := string(runes)
+	if err := policy.Validate(res); err != nil {
+		return "", 0, err
+	}
+	return res, truncatedEntropy(runes), nil
+}
+
+// truncatedEntropy estimates the entropy of the given password, as generated by
+// a default Generator with the same number of letters, digits and symbols.
+func truncatedEntropy(runes []rune) float64 {
+	input := Input{Length: len(runes), NoUpper: true}
+	seen := make(map[rune]struct{}, len(runes))
+	for _, r := range runes {
+		switch ClassOf(r) {
+		case ClassDigit:
+			input.Digits++
+		case ClassSymbol:
+			input.Symbols++
+		case ClassUpper:
+			input.NoUpper = false
+		}
+		if _, ok := seen[r]; ok {
+			input.AllowRepeat = true
+		}
+		seen[r] = struct{}{}
+	}
+
+	g := NewGenerator()
+	bits, err := g.Entropy(input)
+	if err != nil {
+		// The password has more distinct characters than the default
+		// charsets.
+		input.AllowRepeat = true
+		bits, _ = g.Entropy(input)
+	}
+	return bits
+}
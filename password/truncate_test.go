@@ -0,0 +1,78 @@
+package password
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestTruncate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("keeps minimums", func(t *testing.T) {
+		t.Parallel()
+
+		policy := Policy{MinDigits: 2, MinSymbols: 1, RequiredClasses: []Class{ClassUpper}}
+		res, bits, err := Truncate("abcdefGhij12!", 8, policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != "abcdG12!" {
+			t.Errorf("expected %q, got %q", "abcdG12!", res)
+		}
+
+		want, err := NewGenerator().Entropy(Input{Length: 8, Digits: 2, Symbols: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(bits-want) > 1e-9 {
+			t.Errorf("expected %f bits, got %f", want, bits)
+		}
+	})
+
+	t.Run("prefix", func(t *testing.T) {
+		t.Parallel()
+
+		res, _, err := Truncate("abcdefgh", 4, Policy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != "abcd" {
+			t.Errorf("expected %q, got %q", "abcd", res)
+		}
+
+		res, _, err = Truncate("abc", 4, Policy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != "abc" {
+			t.Errorf("expected %q, got %q", "abc", res)
+		}
+	})
+
+	t.Run("repeats", func(t *testing.T) {
+		t.Parallel()
+
+		_, bits, err := Truncate("aaaa", 4, Policy{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := 4 * math.Log2(26); math.Abs(bits-want) > 1e-9 {
+			t.Errorf("expected %f bits, got %f", want, bits)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+
+		if _, _, err := Truncate("abcd1234", 4, Policy{MinDigits: 2, MinLength: 4, RequiredClasses: []Class{ClassLower}}); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+		if _, _, err := Truncate("abcd1234", 2, Policy{MinDigits: 2, RequiredClasses: []Class{ClassLower}}); !errors.Is(err, ErrPolicyViolation) {
+			t.Errorf("expected %v to be %v", err, ErrPolicyViolation)
+		}
+		if _, _, err := Truncate("abcd", 0, Policy{}); !errors.Is(err, ErrInvalidRange) {
+			t.Errorf("expected %v to be %v", err, ErrInvalidRange)
+		}
+	})
+}
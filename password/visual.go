@@ -0,0 +1,57 @@
+package password
+
+import (
+	"crypto/sha256"
+	"image/color"
+)
+
+// VisualGridSize is the number of rows and columns of Visual.Grid.
+const VisualGridSize = 5
+
+// visualDomain separates visualization hashes from other uses of SHA-256.
+const visualDomain = "go-password visual v1\x00"
+
+// Visual is visualization data derived from a password, so that a user
+// interface can show "does this look like the password you saved?" without
+// displaying the password.
+type Visual struct {
+	// Colors are three colors, for example for a row of swatches.
+	Colors [3]color.RGBA
+
+	// Grid is a horizontally symmetric identicon: cells which are set are
+	// drawn in Colors[0].
+	Grid [VisualGridSize][VisualGridSize]bool
+}
+
+// Visualize returns the visualization data of the password. The same password
+// and salt always produce the same data. The salt, such as a user ID, makes
+// the data of the same password differ between users.
+//
+// The data is derived from a single SHA-256 hash of the password and reveals
+// about 87 bits of it. Guessing a weak password from its visualization is as
+// easy as from its hash, so only show it to the owner of the password.
+func Visualize(pw string, salt []byte) Visual {
+	h := sha256.New()
+	h.Write([]byte(visualDomain))
+	h.Write(salt)
+	h.Write([]byte{0})
+	h.Write([]byte(pw))
+	sum := h.Sum(nil)
+
+	var v Visual
+	for i := range v.Colors {
+		v.Colors[i] = color.RGBA{R: sum[3*i], G: sum[3*i+1], B: sum[3*i+2], A: 0xff}
+	}
+
+	bits := sum[9:]
+	n := 0
+	for row := 0; row < VisualGridSize; row++ {
+		for col := 0; col < (VisualGridSize+1)/2; col++ {
+			set := bits[n/8]&(1<<(n%8)) != 0
+			v.Grid[row][col] = set
+			v.Grid[row][VisualGridSize-1-col] = set
+			n++
+		}
+	}
+	return v
+}
@@ -0,0 +1,31 @@
+package password
+
+import "testing"
+
+func TestVisualize(t *testing.T) {
+	t.Parallel()
+
+	a := Visualize("correct horse battery staple", []byte("alice"))
+	if a != Visualize("correct horse battery staple", []byte("alice")) {
+		t.Error("expected visualization to be stable")
+	}
+	if a == Visualize("correct horse battery stapler", []byte("alice")) {
+		t.Error("expected different passwords to look different")
+	}
+	if a == Visualize("correct horse battery staple", []byte("bob")) {
+		t.Error("expected different salts to look different")
+	}
+
+	for row := range a.Grid {
+		for col := range a.Grid[row] {
+			if a.Grid[row][col] != a.Grid[row][VisualGridSize-1-col] {
+				t.Errorf("expected grid to be symmetric, row %d: %v", row, a.Grid[row])
+			}
+		}
+	}
+	for _, c := range a.Colors {
+		if c.A != 0xff {
+			t.Errorf("expected opaque colors, got %v", c)
+		}
+	}
+}
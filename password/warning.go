@@ -0,0 +1,53 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WeakEntropyBits is the entropy, in bits, below which GenerateWithWarnings
+// warns that the configuration yields weak passwords.
+const WeakEntropyBits = 40
+
+// ErrWeakCharsetWarning is the error matched by the Warning returned when the
+// charsets and input of a Generator yield less than WeakEntropyBits of
+// entropy.
+var ErrWeakCharsetWarning = errors.New("configuration yields weak passwords")
+
+// Warning is a problem with a generated password which does not prevent its
+// use, such as a configuration yielding little entropy. Err is the sentinel
+// error identifying the problem, such as ErrWeakCharsetWarning.
+type Warning struct {
+	Err     error
+	Message string
+}
+
+// Error implements error, so that a Warning can be returned or logged as one.
+func (w Warning) Error() string {
+	return fmt.Sprintf("%s: %s", w.Err, w.Message)
+}
+
+// Unwrap returns the sentinel error of the warning.
+func (w Warning) Unwrap() error {
+	return w.Err
+}
+
+// GenerateWithWarnings is the same as Generate, but also returns warnings
+// about the password instead of silently producing weak output. The password
+// is returned even if there are warnings; an error is only returned if no
+// password could be generated.
+func (g Generator) GenerateWithWarnings(input Input) (string, []Warning, error) {
+	res, err := g.Generate(input)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var warnings []Warning
+	if bits, err := g.Entropy(input); err == nil && bits < WeakEntropyBits {
+		warnings = append(warnings, Warning{
+			Err:     ErrWeakCharsetWarning,
+			Message: fmt.Sprintf("%.1f bits of entropy, less than %d", bits, WeakEntropyBits),
+		})
+	}
+	return res, warnings, nil
+}
@@ -0,0 +1,36 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGeneratorGenerateWithWarnings(t *testing.T) {
+	t.Parallel()
+
+	res, warnings, err := NewGenerator().GenerateWithWarnings(Input{Length: 16, Digits: 4, Symbols: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 16 || len(warnings) != 0 {
+		t.Errorf("expected a strong password without warnings, got %q %v", res, warnings)
+	}
+
+	res, warnings, err = NewGenerator().WithDigits("01").GenerateWithWarnings(Input{Length: 12, Digits: 12, AllowRepeat: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 12 {
+		t.Errorf("expected the weak password to be returned, got %q", res)
+	}
+	if len(warnings) != 1 || !errors.Is(warnings[0], ErrWeakCharsetWarning) {
+		t.Fatalf("expected a weak charset warning, got %v", warnings)
+	}
+	if got, want := warnings[0].Error(), "configuration yields weak passwords: 12.0 bits of entropy, less than 40"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	if _, _, err := NewGenerator().GenerateWithWarnings(Input{Length: 2, Digits: 3}); !errors.Is(err, ErrExceedsTotalLength) {
+		t.Errorf("expected %v to be %v", err, ErrExceedsTotalLength)
+	}
+}
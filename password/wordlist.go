@@ -0,0 +1,194 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrWordlistEmpty is the error returned when a wordlist has no usable
+	// words.
+	ErrWordlistEmpty = errors.New("wordlist has no words")
+
+	// ErrWordlistChecksum is the error returned when a wordlist does not match
+	// its expected checksum.
+	ErrWordlistChecksum = errors.New("wordlist does not match checksum")
+)
+
+// Wordlist is a list of unique words from which passphrases are built.
+type Wordlist []string
+
+// Entropy returns the number of bits of entropy contributed by a single word
+// chosen uniformly at random from the wordlist.
+func (w Wordlist) Entropy() float64 {
+	if len(w) == 0 {
+		return 0
+	}
+	return math.Log2(float64(len(w)))
+}
+
+// Checksum returns the hex-encoded SHA-256 checksum of the wordlist, computed
+// over the words in order with a newline after each word. This is the same as
+// the sha256sum of a file with one word per line, so the checksum of a
+// wordlist can be compared directly against published upstream files.
+//
+// This is unrelated to ChecksumWord, which guards passphrases against typos.
+func (w Wordlist) Checksum() string {
+	h := sha256.New()
+	for _, word := range w {
+		h.Write([]byte(word))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyIntegrity returns ErrWordlistChecksum if the checksum of the wordlist
+// is not the given hex-encoded SHA-256 checksum.
+func (w Wordlist) VerifyIntegrity(checksum string) error {
+	if got := w.Checksum(); !strings.EqualFold(got, checksum) {
+		return fmt.Errorf("%w: got %s, want %s", ErrWordlistChecksum, got, checksum)
+	}
+	return nil
+}
+
+// RandomWord returns a word chosen uniformly at random from the wordlist using
+// crypto/rand.
+func RandomWord(w Wordlist) (string, error) {
+	if len(w) == 0 {
+		return "", ErrWordlistEmpty
+	}
+	return Choice(w)
+}
+
+// WordlistOptions used to define input parameters for BuildWordlist.
+type WordlistOptions struct {
+	// MinLength and MaxLength bound the length of accepted words, in runes. A
+	// zero value means no bound.
+	MinLength int
+	MaxLength int
+
+	// Filter, if set, is called for every candidate word. Words for which it
+	// returns false are dropped from the wordlist.
+	Filter func(word string) bool
+	_      struct{}
+}
+
+// WordlistReport describes how a wordlist was built from a corpus.
+type WordlistReport struct {
+	// Candidates is the number of words read from the corpus.
+	Candidates int
+
+	// Invalid is the number of words dropped because they contained
+	// characters other than letters.
+	Invalid int
+
+	// Duplicates is the number of words dropped because they were already in
+	// the wordlist.
+	Duplicates int
+
+	// TooShort and TooLong are the number of words dropped by the length
+	// bounds.
+	TooShort int
+	TooLong  int
+
+	// Filtered is the number of words dropped by the filter.
+	Filtered int
+
+	// Words is the number of words in the resulting wordlist.
+	Words int
+
+	// EntropyPerWord is the number of bits of entropy contributed by each word
+	// of the resulting wordlist.
+	EntropyPerWord float64
+}
+
+// BuildWordlist builds a sanitized wordlist from the given corpus. The corpus
+// is split on whitespace, every word is trimmed of surrounding punctuation and
+// lowercased, and words containing anything other than letters are dropped.
+// The result is deduplicated, bounded by the given lengths, passed through the
+// optional filter and sorted.
+//
+// ErrWordlistEmpty is returned if no words survive sanitization.
+func BuildWordlist(corpus io.Reader, opts WordlistOptions) (Wordlist, WordlistReport, error) {
+	var report WordlistReport
+	seen := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(corpus)
+	scanner.Split(bufio.ScanWords)
+	for scanner.Scan() {
+		report.Candidates++
+
+		word := sanitizeWord(scanner.Text())
+		if word == "" {
+			report.Invalid++
+			continue
+		}
+
+		if _, ok := seen[word]; ok {
+			report.Duplicates++
+			continue
+		}
+
+		n := utf8.RuneCountInString(word)
+		if opts.MinLength > 0 && n < opts.MinLength {
+			report.TooShort++
+			continue
+		}
+		if opts.MaxLength > 0 && n > opts.MaxLength {
+			report.TooLong++
+			continue
+		}
+
+		if opts.Filter != nil && !opts.Filter(word) {
+			report.Filtered++
+			continue
+		}
+
+		seen[word] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, report, fmt.Errorf("failed to read corpus: %w", err)
+	}
+
+	if len(seen) == 0 {
+		return nil, report, ErrWordlistEmpty
+	}
+
+	list := make(Wordlist, 0, len(seen))
+	for word := range seen {
+		list = append(list, word)
+	}
+	sort.Strings(list)
+
+	report.Words = len(list)
+	report.EntropyPerWord = list.Entropy()
+	return list, report, nil
+}
+
+// sanitizeWord trims surrounding punctuation from the given word and lowercases
+// it. It returns the empty string if the word contains anything other than
+// letters.
+func sanitizeWord(s string) string {
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	})
+	if s == "" {
+		return ""
+	}
+
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			return ""
+		}
+	}
+	return strings.ToLower(s)
+}
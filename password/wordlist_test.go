@@ -0,0 +1,126 @@
+package password
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBuildWordlist(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sanitizes", func(t *testing.T) {
+		t.Parallel()
+
+		corpus := "The quick, brown fox... jumps over the lazy dog! R2D2 42 \"Fox\""
+		list, report, err := BuildWordlist(strings.NewReader(corpus), WordlistOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []string{"brown", "dog", "fox", "jumps", "lazy", "over", "quick", "the"}
+		if got := strings.Join(list, " "); got != strings.Join(want, " ") {
+			t.Errorf("expected %q to be %q", got, want)
+		}
+
+		if report.Candidates != 12 {
+			t.Errorf("expected %d candidates, got %d", 12, report.Candidates)
+		}
+		if report.Invalid != 2 {
+			t.Errorf("expected %d invalid, got %d", 2, report.Invalid)
+		}
+		if report.Duplicates != 2 {
+			t.Errorf("expected %d duplicates, got %d", 2, report.Duplicates)
+		}
+		if report.Words != len(want) {
+			t.Errorf("expected %d words, got %d", len(want), report.Words)
+		}
+		if report.EntropyPerWord != math.Log2(float64(len(want))) {
+			t.Errorf("unexpected entropy %f", report.EntropyPerWord)
+		}
+	})
+
+	t.Run("length_bounds", func(t *testing.T) {
+		t.Parallel()
+
+		list, report, err := BuildWordlist(strings.NewReader("a ab abc abcd abcde ёжик"), WordlistOptions{
+			MinLength: 2,
+			MaxLength: 4,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := strings.Join(list, " "), "ab abc abcd ёжик"; got != want {
+			t.Errorf("expected %q to be %q", got, want)
+		}
+		if report.TooShort != 1 || report.TooLong != 1 {
+			t.Errorf("expected 1 too short and 1 too long, got %d and %d", report.TooShort, report.TooLong)
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		t.Parallel()
+
+		list, report, err := BuildWordlist(strings.NewReader("apple banana cherry"), WordlistOptions{
+			Filter: func(word string) bool { return word != "banana" },
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got, want := strings.Join(list, " "), "apple cherry"; got != want {
+			t.Errorf("expected %q to be %q", got, want)
+		}
+		if report.Filtered != 1 {
+			t.Errorf("expected %d filtered, got %d", 1, report.Filtered)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		t.Parallel()
+
+		if _, _, err := BuildWordlist(strings.NewReader("42 !!"), WordlistOptions{}); !errors.Is(err, ErrWordlistEmpty) {
+			t.Errorf("expected %q to be %q", err, ErrWordlistEmpty)
+		}
+	})
+}
+
+func TestRandomWord(t *testing.T) {
+	t.Parallel()
+
+	list := Wordlist{"apple", "banana", "cherry"}
+	for i := 0; i < 100; i++ {
+		word, err := RandomWord(list)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if word != "apple" && word != "banana" && word != "cherry" {
+			t.Errorf("%q is not in the wordlist", word)
+		}
+	}
+
+	if _, err := RandomWord(nil); !errors.Is(err, ErrWordlistEmpty) {
+		t.Errorf("expected %q to be %q", err, ErrWordlistEmpty)
+	}
+}
+
+func TestWordlistVerifyIntegrity(t *testing.T) {
+	t.Parallel()
+
+	// printf 'apple\nbanana\n' | sha256sum
+	const sum = "ad4c2dd8abb59fc844e6f0b360786b5106a1c4e03b7f31c94a8bfacea783e618"
+
+	list := Wordlist{"apple", "banana"}
+	if got := list.Checksum(); got != sum {
+		t.Errorf("expected %q to be %q", got, sum)
+	}
+
+	if err := list.VerifyIntegrity(strings.ToUpper(sum)); err != nil {
+		t.Error(err)
+	}
+	if err := (Wordlist{"apple", "cherry"}).VerifyIntegrity(sum); !errors.Is(err, ErrWordlistChecksum) {
+		t.Errorf("expected %q to be %q", err, ErrWordlistChecksum)
+	}
+}
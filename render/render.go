@@ -0,0 +1,146 @@
+// Package render renders credential notifications, such as welcome emails and
+// runbook entries, from text or HTML templates.
+//
+// Templates are executed with a Credential, whose fields only hold metadata.
+// The secret is only ever written where the template explicitly asks for it
+// with the secret function; printing the Credential itself, in a template or
+// in a log, shows Redacted instead.
+//
+//	t, err := render.ParseText("welcome", "Hello {{.Account}}, your password is {{secret}}\n")
+//	if err != nil {
+//		return err
+//	}
+//	err = t.Execute(w, render.NewCredential("alice", pw))
+package render
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/juev/go-password/password"
+)
+
+// Redacted replaces the secret wherever it must not appear. It is the same
+// marker as password.Redacted.
+const Redacted = password.Redacted
+
+// SecretFunc is the name of the template function writing the secret.
+const SecretFunc = "secret"
+
+// Credential is a generated secret and its metadata.
+type Credential struct {
+	// Account is the account the credential belongs to, and Policy the policy
+	// it was generated for.
+	Account string
+	Policy  string
+
+	// IssuedAt is the time the credential was generated, and ExpiresAt the
+	// time it must be changed by, if any.
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+
+	// Metadata holds additional caller-defined attributes, such as the name
+	// of the recipient or the URL of the system.
+	Metadata map[string]string
+
+	secret string
+}
+
+// NewCredential creates a new Credential for the given account and secret,
+// issued now.
+func NewCredential(account, secret string) Credential {
+	return Credential{
+		Account:  account,
+		IssuedAt: time.Now().UTC(),
+		secret:   secret,
+	}
+}
+
+// String returns a description of the credential without its secret.
+func (c Credential) String() string {
+	return fmt.Sprintf("credential for %q: %s", c.Account, Redacted)
+}
+
+// Format implements fmt.Formatter, so that no verb prints the secret.
+func (c Credential) Format(f fmt.State, _ rune) {
+	io.WriteString(f, c.String())
+}
+
+// executor is the common interface of text and HTML templates.
+type executor interface {
+	Execute(w io.Writer, data any) error
+}
+
+// Template is a parsed text or HTML template. It is safe for concurrent use.
+type Template struct {
+	// clone returns a copy of the template in which the secret function
+	// returns the given value.
+	clone func(secret string) (executor, error)
+}
+
+// ParseText parses a text/template template.
+func ParseText(name, src string) (*Template, error) {
+	t, err := texttemplate.New(name).Funcs(texttemplate.FuncMap{SecretFunc: secretFunc(Redacted)}).Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return &Template{clone: func(secret string) (executor, error) {
+		c, err := t.Clone()
+		if err != nil {
+			return nil, err
+		}
+		return c.Funcs(texttemplate.FuncMap{SecretFunc: secretFunc(secret)}), nil
+	}}, nil
+}
+
+// ParseHTML parses an html/template template. The secret is escaped like any
+// other value.
+func ParseHTML(name, src string) (*Template, error) {
+	t, err := htmltemplate.New(name).Funcs(htmltemplate.FuncMap{SecretFunc: secretFunc(Redacted)}).Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return &Template{clone: func(secret string) (executor, error) {
+		c, err := t.Clone()
+		if err != nil {
+			return nil, err
+		}
+		return c.Funcs(htmltemplate.FuncMap{SecretFunc: secretFunc(secret)}), nil
+	}}, nil
+}
+
+// Execute renders the template for the credential to w, with the secret
+// written where the template calls the secret function.
+func (t *Template) Execute(w io.Writer, c Credential) error {
+	return t.execute(w, c, c.secret)
+}
+
+// Preview renders the template for the credential to w with Redacted in place
+// of the secret, for logs, reviews and dry runs.
+func (t *Template) Preview(w io.Writer, c Credential) error {
+	return t.execute(w, c, Redacted)
+}
+
+// execute renders the template with the given value of the secret function.
+func (t *Template) execute(w io.Writer, c Credential, secret string) error {
+	e, err := t.clone(secret)
+	if err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	if err := e.Execute(w, c); err != nil {
+		return fmt.Errorf("failed to render template: %w", err)
+	}
+	return nil
+}
+
+// secretFunc returns the template function writing the secret.
+func secretFunc(secret string) func() string {
+	return func() string {
+		return secret
+	}
+}
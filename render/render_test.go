@@ -0,0 +1,97 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testSecret = "s3cr<e>t&"
+
+func TestTemplateExecute(t *testing.T) {
+	t.Parallel()
+
+	c := NewCredential("alice", testSecret)
+	c.Policy = "default"
+	c.Metadata = map[string]string{"name": "Alice"}
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl, err := ParseText("welcome", "Hello {{.Metadata.name}} ({{.Account}}, {{.Policy}}): {{secret}}")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var b strings.Builder
+		if err := tmpl.Execute(&b, c); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := b.String(), "Hello Alice (alice, default): "+testSecret; got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+
+		b.Reset()
+		if err := tmpl.Preview(&b, c); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := b.String(), "Hello Alice (alice, default): "+Redacted; got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		t.Parallel()
+
+		tmpl, err := ParseHTML("welcome", "<p>{{.Account}}</p><code>{{secret}}</code>")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var b strings.Builder
+		if err := tmpl.Execute(&b, c); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := b.String(), "<p>alice</p><code>s3cr&lt;e&gt;t&amp;</code>"; got != want {
+			t.Errorf("expected %q, got %q", want, got)
+		}
+	})
+
+	t.Run("implicit", func(t *testing.T) {
+		t.Parallel()
+
+		for _, parse := range []func(name, src string) (*Template, error){ParseText, ParseHTML} {
+			tmpl, err := parse("dump", "{{.}} {{printf \"%+v %#v\" . .}}")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var b strings.Builder
+			if err := tmpl.Execute(&b, c); err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(b.String(), "s3cr") || !strings.Contains(b.String(), Redacted) {
+				t.Errorf("expected the secret to be redacted, got %q", b.String())
+			}
+
+			field, err := parse("field", "{{.secret}}")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := field.Execute(&b, c); err == nil {
+				t.Errorf("expected the secret field to be inaccessible, got %q", b.String())
+			}
+		}
+	})
+}
+
+func TestCredentialFormat(t *testing.T) {
+	t.Parallel()
+
+	c := NewCredential("alice", testSecret)
+	for _, verb := range []string{"%v", "%+v", "%#v", "%s", "%q"} {
+		if s := fmt.Sprintf(verb, c); strings.Contains(s, "s3cr") {
+			t.Errorf("expected %s to redact the secret, got %q", verb, s)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package rotation helps rotating credentials. It plans the rotation of many
+// accounts in waves, and builds the notifications sent to downstream systems
+// when a credential was rotated.
+package rotation
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// NotificationType is the type of rotation notifications.
+const NotificationType = "credential.rotated"
+
+// notificationVersion is the version of the notification payload format.
+const notificationVersion = 1
+
+// ErrFingerprintKey is the error returned when no fingerprint key is given.
+var ErrFingerprintKey = errors.New("fingerprint key must not be empty")
+
+// Notification is the payload sent to downstream systems when a credential was
+// rotated. It never contains the credential, only a keyed fingerprint which
+// lets systems holding the same key check which credential they have without
+// making the credential guessable from the notification.
+type Notification struct {
+	Type    string    `json:"type"`
+	Version int       `json:"version"`
+	ID      string    `json:"id"`
+	Time    time.Time `json:"time"`
+
+	// Account is the account the credential belongs to, and Policy the policy
+	// it was generated for.
+	Account string `json:"account"`
+	Policy  string `json:"policy,omitempty"`
+
+	// Fingerprint is the fingerprint of the new credential, and
+	// PreviousFingerprint the one of the credential it replaces, if known.
+	Fingerprint         string `json:"fingerprint"`
+	PreviousFingerprint string `json:"previous_fingerprint,omitempty"`
+
+	// Metadata holds additional caller-defined attributes, such as the
+	// system or the ticket the rotation belongs to.
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NotificationOptions used to define input parameters for NewNotification.
+type NotificationOptions struct {
+	// Policy is the policy the credential was generated for.
+	Policy string
+
+	// Previous is the credential which was replaced, if known.
+	Previous string
+
+	// Metadata is copied into the notification.
+	Metadata map[string]string
+
+	// Time is the time of the rotation. The default is the current time.
+	Time time.Time
+	_    struct{}
+}
+
+// Fingerprint returns the hex-encoded HMAC-SHA256 of the credential under the
+// given key, prefixed with the algorithm.
+func Fingerprint(key []byte, secret string) (string, error) {
+	if len(key) == 0 {
+		return "", ErrFingerprintKey
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(secret))
+	return "hmac-sha256:" + hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+// NewNotification builds the notification for the rotation of the credential
+// of the given account to secret. Fingerprints are keyed with key.
+func NewNotification(key []byte, account, secret string, opts NotificationOptions) (Notification, error) {
+	fingerprint, err := Fingerprint(key, secret)
+	if err != nil {
+		return Notification{}, err
+	}
+
+	var previous string
+	if opts.Previous != "" {
+		if previous, err = Fingerprint(key, opts.Previous); err != nil {
+			return Notification{}, err
+		}
+	}
+
+	id := make([]byte, 16)
+	if _, err := io.ReadFull(rand.Reader, id); err != nil {
+		return Notification{}, fmt.Errorf("failed to generate notification id: %w", err)
+	}
+
+	t := opts.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	var metadata map[string]string
+	if len(opts.Metadata) > 0 {
+		metadata = make(map[string]string, len(opts.Metadata))
+		for k, v := range opts.Metadata {
+			metadata[k] = v
+		}
+	}
+
+	return Notification{
+		Type:                NotificationType,
+		Version:             notificationVersion,
+		ID:                  hex.EncodeToString(id),
+		Time:                t.UTC(),
+		Account:             account,
+		Policy:              opts.Policy,
+		Fingerprint:         fingerprint,
+		PreviousFingerprint: previous,
+		Metadata:            metadata,
+	}, nil
+}
+
+// Payload returns the JSON payload of the notification.
+func (n Notification) Payload() ([]byte, error) {
+	b, err := json.Marshal(n)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode notification: %w", err)
+	}
+	return b, nil
+}
+
+// Matches reports whether the notification is about the given credential,
+// using the same fingerprint key.
+func (n Notification) Matches(key []byte, secret string) bool {
+	fingerprint, err := Fingerprint(key, secret)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal([]byte(fingerprint), []byte(n.Fingerprint))
+}
@@ -0,0 +1,66 @@
+package rotation
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewNotification(t *testing.T) {
+	t.Parallel()
+
+	key := []byte("fingerprint key")
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("CET", 3600))
+	metadata := map[string]string{"ticket": "CHG-42"}
+
+	n, err := NewNotification(key, "db-primary", "new-secret", NotificationOptions{
+		Policy:   "database",
+		Previous: "old-secret",
+		Metadata: metadata,
+		Time:     at,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata["ticket"] = "changed"
+
+	if n.Type != NotificationType || n.Version != 1 || len(n.ID) != 32 {
+		t.Errorf("unexpected header %+v", n)
+	}
+	if !n.Time.Equal(at) || n.Time.Location() != time.UTC {
+		t.Errorf("expected time %v in UTC, got %v", at, n.Time)
+	}
+	if n.Metadata["ticket"] != "CHG-42" {
+		t.Errorf("expected metadata to be copied, got %v", n.Metadata)
+	}
+	if !n.Matches(key, "new-secret") || n.Matches(key, "old-secret") || n.Matches([]byte("other"), "new-secret") {
+		t.Error("unexpected fingerprint match")
+	}
+	if want, _ := Fingerprint(key, "old-secret"); n.PreviousFingerprint != want {
+		t.Errorf("expected previous fingerprint %q, got %q", want, n.PreviousFingerprint)
+	}
+
+	b, err := n.Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "secret\"") || strings.Contains(string(b), "new-secret") {
+		t.Errorf("payload contains the credential: %s", b)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"type", "version", "id", "time", "account", "policy", "fingerprint", "previous_fingerprint", "metadata"} {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("expected payload to have field %q: %s", field, b)
+		}
+	}
+
+	if _, err := NewNotification(nil, "db-primary", "secret", NotificationOptions{}); !errors.Is(err, ErrFingerprintKey) {
+		t.Errorf("expected %v to be %v", err, ErrFingerprintKey)
+	}
+}
@@ -0,0 +1,218 @@
+package rotation
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/juev/go-password/password"
+)
+
+var (
+	// ErrInvalidSchedule is the error returned when the wave interval is not
+	// positive or the jitter is negative or not shorter than the interval.
+	ErrInvalidSchedule = errors.New("wave interval must be positive and longer than the jitter")
+
+	// ErrDuplicateAccount is the error returned when an account is listed more
+	// than once.
+	ErrDuplicateAccount = errors.New("duplicate account")
+
+	// ErrUnknownAccount is the error returned when an account depends on an
+	// account which is not listed.
+	ErrUnknownAccount = errors.New("dependency on unknown account")
+
+	// ErrDependencyCycle is the error returned when accounts depend on each
+	// other in a cycle.
+	ErrDependencyCycle = errors.New("accounts depend on each other in a cycle")
+
+	// ErrUnknownPolicy is the error returned when an account refers to a policy
+	// which is not defined.
+	ErrUnknownPolicy = errors.New("unknown policy")
+)
+
+// Account is an account whose credential is to be rotated.
+type Account struct {
+	Name   string
+	Policy string
+
+	// DependsOn are the names of the accounts which must be rotated in an
+	// earlier wave, for example the database whose credential an application
+	// account is derived from.
+	DependsOn []string
+}
+
+// PlanOptions used to define input parameters for NewPlan.
+type PlanOptions struct {
+	// Policies are the password requirements by policy name. Every account
+	// must refer to one of them, and each must be satisfiable by Generator.
+	Policies map[string]password.Input
+
+	// Generator is the generator the plan is checked against. The default is
+	// password.NewGenerator().
+	Generator *password.Generator
+
+	// Start is the time of the first wave, and Interval the time between the
+	// start of consecutive waves.
+	Start    time.Time
+	Interval time.Duration
+
+	// WaveSize, if positive, is the maximum number of rotations per wave.
+	WaveSize int
+
+	// Jitter, if positive, delays every rotation by a random duration of up
+	// to Jitter after the start of its wave, so that rotations do not hit
+	// downstream systems at the same instant. It must be shorter than
+	// Interval, so that waves never overlap.
+	Jitter time.Duration
+
+	// Reader is the source of randomness for the jitter. The default is
+	// crypto/rand.Reader.
+	Reader io.Reader
+	_      struct{}
+}
+
+// Plan is a schedule of rotations in waves. The caller executes it, for
+// example by generating a password for each rotation at its time and
+// notifying downstream systems with NewNotification.
+type Plan struct {
+	Waves []Wave
+}
+
+// Wave is a set of rotations which do not depend on each other.
+type Wave struct {
+	Start     time.Time
+	Rotations []Rotation
+}
+
+// Rotation is the scheduled rotation of the credential of an account.
+type Rotation struct {
+	Account string
+	Policy  string
+	Input   password.Input
+	At      time.Time
+
+	// Entropy is the entropy of the new credential in bits.
+	Entropy float64
+}
+
+// NewPlan plans the rotation of the given accounts. Every account is rotated
+// in a later wave than the accounts it depends on. Within these constraints,
+// accounts are placed in the earliest wave with room, in order of their names.
+func NewPlan(accounts []Account, opts PlanOptions) (Plan, error) {
+	if opts.Interval <= 0 || opts.Jitter < 0 || opts.Jitter >= opts.Interval {
+		return Plan{}, ErrInvalidSchedule
+	}
+
+	gen := password.NewGenerator()
+	if opts.Generator != nil {
+		gen = *opts.Generator
+	}
+	r := opts.Reader
+	if r == nil {
+		r = rand.Reader
+	}
+
+	order, err := topologicalOrder(accounts)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	var plan Plan
+	wave := make(map[string]int, len(accounts))
+	for _, a := range order {
+		input, ok := opts.Policies[a.Policy]
+		if !ok {
+			return Plan{}, fmt.Errorf("%w %q for account %q", ErrUnknownPolicy, a.Policy, a.Name)
+		}
+		entropy, err := gen.Entropy(input)
+		if err != nil {
+			return Plan{}, fmt.Errorf("policy %q for account %q: %w", a.Policy, a.Name, err)
+		}
+
+		w := 0
+		for _, dep := range a.DependsOn {
+			w = max(w, wave[dep]+1)
+		}
+		for opts.WaveSize > 0 && w < len(plan.Waves) && len(plan.Waves[w].Rotations) >= opts.WaveSize {
+			w++
+		}
+		for len(plan.Waves) <= w {
+			plan.Waves = append(plan.Waves, Wave{
+				Start: opts.Start.Add(time.Duration(len(plan.Waves)) * opts.Interval),
+			})
+		}
+
+		at := plan.Waves[w].Start
+		if ms := int(opts.Jitter / time.Millisecond); ms > 0 {
+			d, err := password.UniformIndex(r, ms)
+			if err != nil {
+				return Plan{}, fmt.Errorf("failed to compute jitter: %w", err)
+			}
+			at = at.Add(time.Duration(d) * time.Millisecond)
+		}
+
+		wave[a.Name] = w
+		plan.Waves[w].Rotations = append(plan.Waves[w].Rotations, Rotation{
+			Account: a.Name,
+			Policy:  a.Policy,
+			Input:   input,
+			At:      at,
+			Entropy: entropy,
+		})
+	}
+	return plan, nil
+}
+
+// topologicalOrder returns the accounts ordered so that every account comes
+// after its dependencies, breaking ties by name.
+func topologicalOrder(accounts []Account) ([]Account, error) {
+	byName := make(map[string]Account, len(accounts))
+	for _, a := range accounts {
+		if _, ok := byName[a.Name]; ok {
+			return nil, fmt.Errorf("%w %q", ErrDuplicateAccount, a.Name)
+		}
+		byName[a.Name] = a
+	}
+
+	pending := make(map[string]int, len(accounts))
+	dependents := make(map[string][]string, len(accounts))
+	for _, a := range accounts {
+		for _, dep := range a.DependsOn {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("%w %q for account %q", ErrUnknownAccount, dep, a.Name)
+			}
+			dependents[dep] = append(dependents[dep], a.Name)
+		}
+		pending[a.Name] = len(a.DependsOn)
+	}
+
+	var ready []string
+	for name, n := range pending {
+		if n == 0 {
+			ready = append(ready, name)
+		}
+	}
+
+	order := make([]Account, 0, len(accounts))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+
+		order = append(order, byName[name])
+		for _, d := range dependents[name] {
+			pending[d]--
+			if pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+
+	if len(order) != len(accounts) {
+		return nil, ErrDependencyCycle
+	}
+	return order, nil
+}
@@ -0,0 +1,124 @@
+package rotation
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juev/go-password/password"
+)
+
+func testPolicies() map[string]password.Input {
+	return map[string]password.Input{
+		"database": {Length: 32, Digits: 6, Symbols: 6},
+		"app":      {Length: 20, Digits: 4},
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	accounts := []Account{
+		{Name: "web", Policy: "app", DependsOn: []string{"db"}},
+		{Name: "worker", Policy: "app", DependsOn: []string{"db", "queue"}},
+		{Name: "db", Policy: "database"},
+		{Name: "queue", Policy: "app"},
+		{Name: "cache", Policy: "app"},
+	}
+
+	plan, err := NewPlan(accounts, PlanOptions{
+		Policies: testPolicies(),
+		Start:    start,
+		Interval: time.Hour,
+		WaveSize: 2,
+		Jitter:   10 * time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"cache", "db"}, {"queue", "web"}, {"worker"}}
+	if len(plan.Waves) != len(want) {
+		t.Fatalf("expected %d waves, got %+v", len(want), plan.Waves)
+	}
+
+	for i, w := range plan.Waves {
+		if !w.Start.Equal(start.Add(time.Duration(i) * time.Hour)) {
+			t.Errorf("wave %d: unexpected start %v", i, w.Start)
+		}
+		if len(w.Rotations) != len(want[i]) {
+			t.Fatalf("wave %d: expected %v, got %+v", i, want[i], w.Rotations)
+		}
+		for j, r := range w.Rotations {
+			if r.Account != want[i][j] {
+				t.Errorf("wave %d: expected %q, got %q", i, want[i][j], r.Account)
+			}
+			if r.At.Before(w.Start) || !r.At.Before(w.Start.Add(10*time.Minute)) {
+				t.Errorf("%s: rotation at %v is outside of the jitter window", r.Account, r.At)
+			}
+			if r.Entropy <= 0 || r.Input != testPolicies()[r.Policy] {
+				t.Errorf("%s: unexpected policy details %+v", r.Account, r)
+			}
+		}
+	}
+}
+
+func TestNewPlanErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		accounts []Account
+		opts     PlanOptions
+		err      error
+	}{
+		{"no_interval", nil, PlanOptions{}, ErrInvalidSchedule},
+		{"jitter", nil, PlanOptions{Interval: time.Minute, Jitter: time.Minute}, ErrInvalidSchedule},
+		{
+			"duplicate",
+			[]Account{{Name: "a", Policy: "app"}, {Name: "a", Policy: "app"}},
+			PlanOptions{Interval: time.Minute},
+			ErrDuplicateAccount,
+		},
+		{
+			"unknown_account",
+			[]Account{{Name: "a", Policy: "app", DependsOn: []string{"b"}}},
+			PlanOptions{Interval: time.Minute},
+			ErrUnknownAccount,
+		},
+		{
+			"cycle",
+			[]Account{{Name: "a", Policy: "app", DependsOn: []string{"b"}}, {Name: "b", Policy: "app", DependsOn: []string{"a"}}},
+			PlanOptions{Interval: time.Minute},
+			ErrDependencyCycle,
+		},
+		{
+			"unknown_policy",
+			[]Account{{Name: "a", Policy: "nope"}},
+			PlanOptions{Interval: time.Minute},
+			ErrUnknownPolicy,
+		},
+		{
+			"unsatisfiable_policy",
+			[]Account{{Name: "a", Policy: "bad"}},
+			PlanOptions{Interval: time.Minute, Policies: map[string]password.Input{"bad": {Length: 4, Digits: 5}}},
+			password.ErrExceedsTotalLength,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.opts.Policies == nil {
+				tc.opts.Policies = testPolicies()
+			}
+			if _, err := NewPlan(tc.accounts, tc.opts); !errors.Is(err, tc.err) {
+				t.Errorf("expected %v to be %v", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Package scim builds SCIM 2.0 (RFC 7643 and RFC 7644) payloads carrying
+// generated passwords, for provisioning users in identity providers: the body
+// of a user creation request, and the PATCH operation which resets the
+// password of an existing user.
+//
+//	pw, err := password.Generate(password.Input{Length: 24, Digits: 4, Symbols: 4})
+//	if err != nil {
+//		return err
+//	}
+//	u := scim.User{UserName: "alice@example.com", Password: pw}
+//	u.SetExtension(scim.EnterpriseUserSchema, "employeeNumber", "1042")
+//	body, err := json.Marshal(u)
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/juev/go-password/password"
+)
+
+// Schemas of RFC 7643 and RFC 7644.
+const (
+	UserSchema           = "urn:ietf:params:scim:schemas:core:2.0:User"
+	EnterpriseUserSchema = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User"
+	PatchOpSchema        = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+)
+
+var (
+	// ErrMissingUserName is the error returned when a user has no user name,
+	// which SCIM requires.
+	ErrMissingUserName = errors.New("missing user name")
+
+	// ErrInvalidExtension is the error returned when the core user schema is
+	// used as an extension.
+	ErrInvalidExtension = errors.New("core schema is not an extension")
+)
+
+// Name is the name of a user.
+type Name struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// Email is an email address of a user.
+type Email struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+// User is the body of a user creation request.
+type User struct {
+	UserName   string
+	ExternalID string
+	Name       *Name
+	Emails     []Email
+
+	// Active, if set, is whether the user can log in.
+	Active *bool
+
+	// Password is the initial password of the user. It is write-only in
+	// SCIM: identity providers never return it.
+	Password string
+
+	// Extensions holds the attributes of extension schemas, keyed by schema
+	// URN, such as the attributes with which an identity provider requires a
+	// password change at next login. Every schema is listed in the schemas
+	// of the payload.
+	Extensions map[string]map[string]any
+}
+
+// NewUser returns the user creation request of the given user name, with a
+// password generated by the Generator with the given input.
+func NewUser(g password.Generator, userName string, input password.Input) (User, error) {
+	if userName == "" {
+		return User{}, ErrMissingUserName
+	}
+
+	pw, err := g.Generate(input)
+	if err != nil {
+		return User{}, err
+	}
+	return User{UserName: userName, Password: pw}, nil
+}
+
+// SetExtension sets an attribute of an extension schema.
+func (u *User) SetExtension(schema, name string, value any) {
+	if u.Extensions == nil {
+		u.Extensions = make(map[string]map[string]any)
+	}
+	if u.Extensions[schema] == nil {
+		u.Extensions[schema] = make(map[string]any)
+	}
+	u.Extensions[schema][name] = value
+}
+
+// MarshalJSON implements json.Marshaler, with the schemas and the extension
+// attributes required by RFC 7643.
+func (u User) MarshalJSON() ([]byte, error) {
+	if u.UserName == "" {
+		return nil, ErrMissingUserName
+	}
+
+	m := map[string]any{"userName": u.UserName}
+	schemas := []string{UserSchema}
+	if u.ExternalID != "" {
+		m["externalId"] = u.ExternalID
+	}
+	if u.Name != nil {
+		m["name"] = u.Name
+	}
+	if len(u.Emails) > 0 {
+		m["emails"] = u.Emails
+	}
+	if u.Active != nil {
+		m["active"] = *u.Active
+	}
+	if u.Password != "" {
+		m["password"] = u.Password
+	}
+	for schema, attrs := range u.Extensions {
+		if schema == UserSchema {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidExtension, schema)
+		}
+		schemas = append(schemas, schema)
+		m[schema] = attrs
+	}
+
+	// Extension schemas are listed in a stable order.
+	sort.Strings(schemas[1:])
+	m["schemas"] = schemas
+	return json.Marshal(m)
+}
+
+// PatchOp is the body of a PATCH request.
+type PatchOp struct {
+	Operations []Operation
+}
+
+// Operation is an operation of a PATCH request.
+type Operation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// ReplacePassword returns the PATCH request which sets the password of an
+// existing user.
+func ReplacePassword(pw string) PatchOp {
+	return PatchOp{Operations: []Operation{{Op: "replace", Path: "password", Value: pw}}}
+}
+
+// MarshalJSON implements json.Marshaler.
+func (p PatchOp) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Schemas    []string    `json:"schemas"`
+		Operations []Operation `json:"Operations"`
+	}{[]string{PatchOpSchema}, p.Operations})
+}
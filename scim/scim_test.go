@@ -0,0 +1,97 @@
+package scim
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/juev/go-password/password"
+)
+
+func TestUser(t *testing.T) {
+	t.Parallel()
+
+	active := true
+	u := User{
+		UserName:   "alice@example.com",
+		ExternalID: "1042",
+		Name:       &Name{GivenName: "Alice", FamilyName: "Smith"},
+		Emails:     []Email{{Value: "alice@example.com", Type: "work", Primary: true}},
+		Active:     &active,
+		Password:   `p@ss"w\rd`,
+	}
+	u.SetExtension(EnterpriseUserSchema, "employeeNumber", "1042")
+	u.SetExtension("urn:example:params:scim:schemas:extension:2.0:User", "forcePasswordChange", true)
+
+	b, err := json.Marshal(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"schemas": []any{
+			UserSchema,
+			"urn:example:params:scim:schemas:extension:2.0:User",
+			EnterpriseUserSchema,
+		},
+		"userName":           "alice@example.com",
+		"externalId":         "1042",
+		"name":               map[string]any{"givenName": "Alice", "familyName": "Smith"},
+		"emails":             []any{map[string]any{"value": "alice@example.com", "type": "work", "primary": true}},
+		"active":             true,
+		"password":           `p@ss"w\rd`,
+		EnterpriseUserSchema: map[string]any{"employeeNumber": "1042"},
+		"urn:example:params:scim:schemas:extension:2.0:User": map[string]any{"forcePasswordChange": true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %s to be %v", b, want)
+	}
+
+	if _, err := json.Marshal(User{}); !errors.Is(err, ErrMissingUserName) {
+		t.Errorf("expected %v to be %v", err, ErrMissingUserName)
+	}
+
+	u = User{UserName: "bob"}
+	u.SetExtension(UserSchema, "password", "x")
+	if _, err := json.Marshal(u); !errors.Is(err, ErrInvalidExtension) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidExtension)
+	}
+}
+
+func TestNewUser(t *testing.T) {
+	t.Parallel()
+
+	input := password.Input{Length: 24, Digits: 4, Symbols: 4}
+	u, err := NewUser(password.NewGenerator(), "alice", input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.UserName != "alice" {
+		t.Errorf("expected %q to be %q", u.UserName, "alice")
+	}
+	if err := password.NewGenerator().Validate(u.Password, input); err != nil {
+		t.Errorf("expected %q to be generated, got %v", u.Password, err)
+	}
+
+	if _, err := NewUser(password.NewGenerator(), "", input); !errors.Is(err, ErrMissingUserName) {
+		t.Errorf("expected %v to be %v", err, ErrMissingUserName)
+	}
+}
+
+func TestReplacePassword(t *testing.T) {
+	t.Parallel()
+
+	b, err := json.Marshal(ReplacePassword("s3cr3t"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"schemas":["urn:ietf:params:scim:api:messages:2.0:PatchOp"],"Operations":[{"op":"replace","path":"password","value":"s3cr3t"}]}`
+	if string(b) != want {
+		t.Errorf("expected %s to be %s", b, want)
+	}
+}
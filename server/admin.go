@@ -0,0 +1,130 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/juev/go-password/audit"
+)
+
+// policiesPath is the path of the admin policies collection.
+const policiesPath = "/v1/admin/policies"
+
+// handleAdminPolicies serves the admin API for listing, reading, creating,
+// updating and deleting policies.
+func (s *Server) handleAdminPolicies(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, policiesPath), "/")
+
+	if name == "" {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		policies, err := s.policies.List(r.Context())
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string][]Policy{"policies": policies})
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		p, err := s.policies.Get(r.Context(), name)
+		if err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, p)
+
+	case http.MethodPut:
+		var p Policy
+		if err := decodeJSON(w, r, &p); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if p.Name == "" {
+			p.Name = name
+		}
+		if p.Name != name {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("policy name %q does not match path %q", p.Name, name))
+			return
+		}
+
+		if err := p.Validate(); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if err := checkSigningKeys(p, s.verifier.keys); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		if p.Escrow && s.escrow == nil {
+			writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%v %q: escrow is not configured", ErrInvalidPolicy, p.Name))
+			return
+		}
+
+		if err := s.policies.Put(r.Context(), p); err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		s.audit(r, audit.Event{Type: audit.TypePolicyPut, Policy: p.Name})
+		writeJSON(w, http.StatusOK, p)
+
+	case http.MethodDelete:
+		if err := s.policies.Delete(r.Context(), name); err != nil {
+			writeStoreError(w, err)
+			return
+		}
+		s.audit(r, audit.Event{Type: audit.TypePolicyDelete, Policy: name})
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// usagePath is the path of the admin usage resource.
+const usagePath = "/v1/admin/usage"
+
+// handleAdminUsage serves the admin API for listing and resetting usage.
+func (s *Server) handleAdminUsage(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		usage, err := s.usage.List(r.Context())
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "usage store failure")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string][]Usage{"usage": usage})
+
+	case http.MethodDelete:
+		if err := s.usage.Reset(r.Context()); err != nil {
+			writeError(w, http.StatusInternalServerError, "usage store failure")
+			return
+		}
+		s.audit(r, audit.Event{Type: audit.TypeUsageReset})
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.Header().Set("Allow", "GET, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// writeStoreError writes the error response for an error returned by a
+// PolicyStore.
+func writeStoreError(w http.ResponseWriter, err error) {
+	if errors.Is(err, ErrPolicyNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, "policy store failure")
+}
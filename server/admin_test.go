@@ -0,0 +1,93 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+const testAdminToken = "admin-token"
+
+func TestServerAdminPolicies(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t)
+	cfg.AdminTokenHashes = []string{HashToken(testAdminToken)}
+	s := testServer(t, cfg)
+
+	// Client tokens cannot use the admin API.
+	if w := testRequest(t, s, http.MethodGet, "/v1/admin/policies", testToken, ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	w := testRequest(t, s, http.MethodPut, "/v1/admin/policies/wifi", testAdminToken, `{"length": 20, "digits": 4}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+	}
+
+	w = testRequest(t, s, http.MethodGet, "/v1/admin/policies/wifi", testAdminToken, "")
+	var p Policy
+	if err := json.NewDecoder(w.Body).Decode(&p); err != nil {
+		t.Fatal(err)
+	}
+	if p.Name != "wifi" || p.Length != 20 || p.Digits != 4 {
+		t.Errorf("unexpected policy %+v", p)
+	}
+
+	if w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"wifi"}`); w.Code != http.StatusOK {
+		t.Errorf("expected new policy to be usable, got status %d: %s", w.Code, w.Body)
+	}
+
+	w = testRequest(t, s, http.MethodGet, "/v1/admin/policies", testAdminToken, "")
+	var list struct {
+		Policies []Policy `json:"policies"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Policies) != 3 || list.Policies[2].Name != "wifi" {
+		t.Errorf("unexpected policies %+v", list.Policies)
+	}
+
+	if w := testRequest(t, s, http.MethodDelete, "/v1/admin/policies/wifi", testAdminToken, ""); w.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"wifi"}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected deleted policy to be gone, got status %d", w.Code)
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"get_unknown", http.MethodGet, "/v1/admin/policies/nope", "", http.StatusNotFound},
+		{"delete_unknown", http.MethodDelete, "/v1/admin/policies/nope", "", http.StatusNotFound},
+		{"name_mismatch", http.MethodPut, "/v1/admin/policies/a", `{"name": "b", "length": 8}`, http.StatusBadRequest},
+		{"invalid", http.MethodPut, "/v1/admin/policies/a", `{"length": 8, "digits": 11}`, http.StatusUnprocessableEntity},
+		{"collection_method", http.MethodPost, "/v1/admin/policies", "", http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if w := testRequest(t, s, tc.method, tc.path, testAdminToken, tc.body); w.Code != tc.status {
+				t.Errorf("expected status %d, got %d: %s", tc.status, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestServerAdminDisabled(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t, testConfig(t))
+	if w := testRequest(t, s, http.MethodGet, "/v1/admin/policies", testAdminToken, ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
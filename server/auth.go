@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/juev/go-password/audit"
+)
+
+// ErrInvalidTokenHash is the error returned when a configured token hash is
+// not a hex-encoded SHA-256 hash.
+var ErrInvalidTokenHash = errors.New("token hash must be a hex-encoded SHA-256 hash")
+
+// clientIDLength is the number of hex characters of the token hash used to
+// identify a client in audit events and usage accounting.
+const clientIDLength = 16
+
+// clientKey is the context key of the client identifier.
+type clientKey struct{}
+
+// clientFromContext returns the identifier of the authenticated client of the
+// request, derived from the hash of its token.
+func clientFromContext(ctx context.Context) string {
+	client, _ := ctx.Value(clientKey{}).(string)
+	return client
+}
+
+// HashToken returns the hex-encoded SHA-256 hash of the given bearer token, as
+// used in Config.TokenHashes.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// parseTokenHashes decodes the given hex-encoded SHA-256 hashes.
+func parseTokenHashes(hashes []string) ([][]byte, error) {
+	res := make([][]byte, 0, len(hashes))
+	for _, h := range hashes {
+		b, err := hex.DecodeString(h)
+		if err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTokenHash, h)
+		}
+		res = append(res, b)
+	}
+	return res, nil
+}
+
+// authenticate returns the hash of the bearer token of the request, and false
+// if the request has no token or a token which is not in the given hashes. The
+// token hashes are compared in constant time.
+func authenticate(r *http.Request, tokens [][]byte) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	var found int
+	for _, h := range tokens {
+		found |= subtle.ConstantTimeCompare(sum[:], h)
+	}
+	if found == 0 {
+		return "", false
+	}
+	return hex.EncodeToString(sum[:]), true
+}
+
+// requireToken wraps the given handler to reject requests without one of the
+// given bearer tokens and requests exceeding the rate limit of their token.
+func (s *Server) requireToken(tokens [][]byte, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hash, ok := authenticate(r, tokens)
+		if !ok {
+			s.metrics.unauthorized.Add(1)
+			s.audit(r, audit.Event{Type: audit.TypeAuthFailure, Error: "missing or invalid bearer token"})
+			w.Header().Set("WWW-Authenticate", `Bearer realm="password-server"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+
+		client := hash[:clientIDLength]
+		if !s.limiter.allow(client) {
+			s.metrics.rateLimited.Add(1)
+			w.Header().Set("Retry-After", "1")
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientKey{}, client)))
+	})
+}
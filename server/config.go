@@ -0,0 +1,204 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/juev/go-password/audit"
+	"github.com/juev/go-password/escrow"
+	"github.com/juev/go-password/password"
+)
+
+var (
+	// ErrNoPolicies is the error returned when a configuration has no policies.
+	ErrNoPolicies = errors.New("configuration has no policies")
+
+	// ErrInvalidPolicy is the error returned when a policy is unnamed, named
+	// more than once, or cannot generate passwords.
+	ErrInvalidPolicy = errors.New("invalid policy")
+)
+
+// Policy is a named set of password requirements which clients request
+// passwords for.
+type Policy struct {
+	Name               string `json:"name"`
+	Length             int    `json:"length"`
+	Digits             int    `json:"digits"`
+	Symbols            int    `json:"symbols"`
+	NoUpper            bool   `json:"no_upper,omitempty"`
+	AllowRepeat        bool   `json:"allow_repeat,omitempty"`
+	PreserveClassOrder bool   `json:"preserve_class_order,omitempty"`
+	MaxSameClassRun    int    `json:"max_same_class_run,omitempty"`
+
+	// RequireFromEachClass makes every password include a lowercase and,
+	// unless NoUpper is set, an uppercase letter.
+	RequireFromEachClass bool `json:"require_from_each_class,omitempty"`
+
+	// SigningKeys, if set, are the names of the signing keys of which one
+	// must sign every generation request for the policy.
+	SigningKeys []string `json:"signing_keys,omitempty"`
+
+	// Quota, if positive, is the number of passwords each client may generate
+	// for the policy until usage is reset through the admin API.
+	Quota int64 `json:"quota,omitempty"`
+
+	// Escrow deposits every password generated for the policy in the escrow
+	// of the server for break-glass recovery. Generation fails if the
+	// password cannot be deposited.
+	Escrow bool `json:"escrow,omitempty"`
+}
+
+// Input returns the password.Input for the policy.
+func (p Policy) Input() password.Input {
+	return password.Input{
+		Length:               p.Length,
+		Digits:               p.Digits,
+		Symbols:              p.Symbols,
+		NoUpper:              p.NoUpper,
+		AllowRepeat:          p.AllowRepeat,
+		PreserveClassOrder:   p.PreserveClassOrder,
+		MaxSameClassRun:      p.MaxSameClassRun,
+		RequireFromEachClass: p.RequireFromEachClass,
+	}
+}
+
+// Validate returns an error if the policy has no name or cannot generate
+// passwords.
+func (p Policy) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("%w: missing name", ErrInvalidPolicy)
+	}
+	if p.Length <= 0 {
+		return fmt.Errorf("%w %q: length must be positive", ErrInvalidPolicy, p.Name)
+	}
+	if p.Quota < 0 {
+		return fmt.Errorf("%w %q: quota must not be negative", ErrInvalidPolicy, p.Name)
+	}
+	if _, err := password.NewGenerator().Entropy(p.Input()); err != nil {
+		return fmt.Errorf("%w %q: %w", ErrInvalidPolicy, p.Name, err)
+	}
+	return nil
+}
+
+// Config is the configuration of a Server.
+type Config struct {
+	// Policies are the policies clients may request passwords for.
+	Policies []Policy `json:"policies"`
+
+	// TokenHashes are the hex-encoded SHA-256 hashes of the bearer tokens
+	// accepted by the server. Only hashes are stored, so that the
+	// configuration file does not contain credentials.
+	TokenHashes []string `json:"token_sha256"`
+
+	// AdminTokenHashes are the hex-encoded SHA-256 hashes of the bearer tokens
+	// accepted by the admin API. Without admin tokens, the admin API is
+	// disabled.
+	AdminTokenHashes []string `json:"admin_token_sha256"`
+
+	// SigningKeys are the hex-encoded HMAC keys, by name, with which
+	// automation signs generation requests for policies requiring signed
+	// requests. Keys must be at least 32 bytes.
+	SigningKeys map[string]string `json:"signing_keys"`
+
+	// Store, if set, holds the policies instead of an in-memory store
+	// initialized from Policies. It cannot be set from a configuration file.
+	Store PolicyStore `json:"-"`
+
+	// OneTimeTTL is the number of seconds a one-time password can be
+	// retrieved for. The default is 15 minutes.
+	OneTimeTTL int `json:"one_time_ttl"`
+
+	// Usage, if set, counts the passwords generated by each client for each
+	// policy instead of an in-memory store. It cannot be set from a
+	// configuration file.
+	Usage UsageStore `json:"-"`
+
+	// Escrow, if set, receives the passwords generated for policies with
+	// escrow enabled. It cannot be set from a configuration file.
+	Escrow *escrow.Escrow `json:"-"`
+
+	// Audit configures where audit events are delivered when the server is
+	// run by cmd/password-server.
+	Audit AuditConfig `json:"audit"`
+
+	// AuditSink, if set, receives an audit event for every issued credential,
+	// failed generation, rejected request and policy change. It cannot be set
+	// from a configuration file.
+	AuditSink audit.Sink `json:"-"`
+
+	// RateLimit is the number of requests per second each token may make, and
+	// RateBurst the number of requests it may make at once. A zero RateLimit
+	// disables rate limiting.
+	RateLimit float64 `json:"rate_limit"`
+	RateBurst int     `json:"rate_burst"`
+}
+
+// AuditConfig configures the audit sinks.
+type AuditConfig struct {
+	// File is the path of a JSON lines file receiving audit events, rotated
+	// after MaxBytes keeping MaxBackups rotated files.
+	File       string `json:"file"`
+	MaxBytes   int64  `json:"max_bytes"`
+	MaxBackups int    `json:"max_backups"`
+
+	// WebhookURL is a URL receiving every audit event as a JSON POST request.
+	WebhookURL string `json:"webhook_url"`
+}
+
+// LoadConfig reads a JSON configuration from the file at the given path.
+func LoadConfig(path string) (*Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// Validate returns an error if the configuration is not usable.
+func (c *Config) Validate() error {
+	if len(c.Policies) == 0 && c.Store == nil {
+		return ErrNoPolicies
+	}
+
+	if c.OneTimeTTL < 0 {
+		return errors.New("invalid configuration: one_time_ttl must not be negative")
+	}
+
+	keys, err := parseSigningKeys(c.SigningKeys)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]struct{}, len(c.Policies))
+	for _, p := range c.Policies {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+		if err := checkSigningKeys(p, keys); err != nil {
+			return err
+		}
+		if _, ok := seen[p.Name]; ok {
+			return fmt.Errorf("%w %q: duplicate name", ErrInvalidPolicy, p.Name)
+		}
+		seen[p.Name] = struct{}{}
+	}
+
+	if _, err := parseTokenHashes(c.TokenHashes); err != nil {
+		return err
+	}
+	if _, err := parseTokenHashes(c.AdminTokenHashes); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPolicyEncoding is the error returned when a compact policy
+// encoding cannot be decoded.
+var ErrInvalidPolicyEncoding = errors.New("invalid policy encoding")
+
+// policyEncodingVersion is the version of the compact policy encoding.
+const policyEncodingVersion = 1
+
+// Flags of the compact policy encoding.
+const (
+	flagNoUpper = 1 << iota
+	flagAllowRepeat
+	flagPreserveClassOrder
+	flagEscrow
+	flagRequireFromEachClass
+
+	knownFlags = flagNoUpper | flagAllowRepeat | flagPreserveClassOrder | flagEscrow | flagRequireFromEachClass
+)
+
+// Encode returns a compact, URL-safe encoding of the policy, suitable for
+// browser extension storage or query parameters. DecodePolicy reverses it
+// exactly, except that empty SigningKeys decode as nil, as after a JSON round
+// trip. A typical policy encodes to about 20 characters.
+func (p Policy) Encode() string {
+	var flags byte
+	if p.NoUpper {
+		flags |= flagNoUpper
+	}
+	if p.AllowRepeat {
+		flags |= flagAllowRepeat
+	}
+	if p.PreserveClassOrder {
+		flags |= flagPreserveClassOrder
+	}
+	if p.Escrow {
+		flags |= flagEscrow
+	}
+	if p.RequireFromEachClass {
+		flags |= flagRequireFromEachClass
+	}
+
+	b := []byte{policyEncodingVersion, flags}
+	for _, v := range []int64{int64(p.Length), int64(p.Digits), int64(p.Symbols), int64(p.MaxSameClassRun), p.Quota} {
+		b = binary.AppendVarint(b, v)
+	}
+	b = appendString(b, p.Name)
+	b = binary.AppendUvarint(b, uint64(len(p.SigningKeys)))
+	for _, key := range p.SigningKeys {
+		b = appendString(b, key)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodePolicy decodes a policy encoded by Policy.Encode. The policy is not
+// validated.
+func DecodePolicy(s string) (Policy, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Policy{}, fmt.Errorf("%w: %w", ErrInvalidPolicyEncoding, err)
+	}
+
+	d := policyDecoder{b: b}
+	if version := d.byte(); version != policyEncodingVersion {
+		return Policy{}, fmt.Errorf("%w: unsupported version %d", ErrInvalidPolicyEncoding, version)
+	}
+	flags := d.byte()
+	if flags&^knownFlags != 0 {
+		return Policy{}, fmt.Errorf("%w: unknown flags %#x", ErrInvalidPolicyEncoding, flags)
+	}
+
+	p := Policy{
+		Length:               d.int(),
+		Digits:               d.int(),
+		Symbols:              d.int(),
+		MaxSameClassRun:      d.int(),
+		Quota:                d.varint(),
+		Name:                 d.string(),
+		NoUpper:              flags&flagNoUpper != 0,
+		AllowRepeat:          flags&flagAllowRepeat != 0,
+		PreserveClassOrder:   flags&flagPreserveClassOrder != 0,
+		RequireFromEachClass: flags&flagRequireFromEachClass != 0,
+		Escrow:               flags&flagEscrow != 0,
+	}
+	if n := d.uvarint(); n > 0 && d.err == nil {
+		if n > uint64(len(d.b)) {
+			return Policy{}, fmt.Errorf("%w: truncated", ErrInvalidPolicyEncoding)
+		}
+		p.SigningKeys = make([]string, n)
+		for i := range p.SigningKeys {
+			p.SigningKeys[i] = d.string()
+		}
+	}
+
+	if d.err != nil {
+		return Policy{}, d.err
+	}
+	if len(d.b) > 0 {
+		return Policy{}, fmt.Errorf("%w: trailing data", ErrInvalidPolicyEncoding)
+	}
+	return p, nil
+}
+
+// appendString appends the length-prefixed string to b.
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// policyDecoder reads the fields of a compact policy encoding. After the first
+// error, all reads return zero values.
+type policyDecoder struct {
+	b   []byte
+	err error
+}
+
+// fail records that the encoding is truncated or malformed.
+func (d *policyDecoder) fail() {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: truncated or malformed", ErrInvalidPolicyEncoding)
+	}
+	d.b = nil
+}
+
+// byte reads a single byte.
+func (d *policyDecoder) byte() byte {
+	if len(d.b) == 0 {
+		d.fail()
+		return 0
+	}
+	v := d.b[0]
+	d.b = d.b[1:]
+	return v
+}
+
+// varint reads a signed varint.
+func (d *policyDecoder) varint() int64 {
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+// int reads a signed varint which fits in an int.
+func (d *policyDecoder) int() int {
+	v := d.varint()
+	if int64(int(v)) != v {
+		d.fail()
+		return 0
+	}
+	return int(v)
+}
+
+// uvarint reads an unsigned varint.
+func (d *policyDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+// string reads a length-prefixed string.
+func (d *policyDecoder) string() string {
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		d.fail()
+		return ""
+	}
+	s := string(d.b[:n])
+	d.b = d.b[n:]
+	return s
+}
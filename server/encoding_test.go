@@ -0,0 +1,70 @@
+package server
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestPolicyEncode(t *testing.T) {
+	t.Parallel()
+
+	cases := []Policy{
+		{},
+		{Name: "database", Length: 32, Digits: 6, Symbols: 6},
+		{Name: "pin", Length: 6, Digits: 6, AllowRepeat: true},
+		{
+			Name: "root", Length: 64, Digits: 8, Symbols: 8,
+			NoUpper: true, AllowRepeat: true, PreserveClassOrder: true, MaxSameClassRun: 3, RequireFromEachClass: true,
+			SigningKeys: []string{"deploy", "ci"}, Quota: 1 << 40, Escrow: true,
+		},
+		{Name: "ünïcode", Length: -1, Digits: -2, Quota: -3},
+	}
+
+	for _, p := range cases {
+		s := p.Encode()
+		got, err := DecodePolicy(s)
+		if err != nil {
+			t.Fatalf("%q: %v", s, err)
+		}
+		if !reflect.DeepEqual(got, p) {
+			t.Errorf("expected %+v to round trip, got %+v", p, got)
+		}
+	}
+
+	empty := Policy{Name: "empty", Length: 8, SigningKeys: []string{}}
+	if a, b := empty.Encode(), (Policy{Name: "empty", Length: 8}).Encode(); a != b {
+		t.Errorf("expected %q to be %q", a, b)
+	}
+	got, err := DecodePolicy(empty.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.SigningKeys != nil {
+		t.Errorf("expected empty signing keys to decode as nil, got %#v", got.SigningKeys)
+	}
+
+	if s := cases[1].Encode(); len(s) > 24 {
+		t.Errorf("expected compact encoding, got %q", s)
+	}
+}
+
+func TestDecodePolicyErrors(t *testing.T) {
+	t.Parallel()
+
+	valid := Policy{Name: "root", Length: 64, SigningKeys: []string{"deploy"}}.Encode()
+
+	for _, s := range []string{
+		"",
+		"!!!",
+		"AgA",                // unsupported version
+		"AYA",                // unknown flags
+		valid[:len(valid)-2], // truncated
+		valid + "AA",         // trailing data
+		"AQAAAAAAAP____8P",   // absurd number of signing keys
+	} {
+		if _, err := DecodePolicy(s); !errors.Is(err, ErrInvalidPolicyEncoding) {
+			t.Errorf("%q: expected %v to be %v", s, err, ErrInvalidPolicyEncoding)
+		}
+	}
+}
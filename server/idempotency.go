@@ -0,0 +1,157 @@
+package server
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// IdempotencyKeyHeader is the header with which clients make retried
+// generation requests return the password of the first request instead of
+// generating a new one.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const (
+	// idempotencyTTL is how long the response to a request with an
+	// idempotency key is kept.
+	idempotencyTTL = 24 * time.Hour
+
+	// maxIdempotencyKeyLength is the maximum length of an idempotency key.
+	maxIdempotencyKeyLength = 255
+)
+
+var (
+	// errIdempotencyInProgress is the error returned when a request with the
+	// same idempotency key is still being processed.
+	errIdempotencyInProgress = errors.New("a request with the same idempotency key is in progress")
+
+	// errIdempotencyMismatch is the error returned when an idempotency key is
+	// reused with a different request body.
+	errIdempotencyMismatch = errors.New("idempotency key was used with a different request")
+)
+
+// idempotencyCache keeps the responses to requests with idempotency keys,
+// encrypted with a key which only exists in memory, so that cached passwords
+// are not readable from the cache itself. It is safe for concurrent use.
+type idempotencyCache struct {
+	aead cipher.AEAD
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+}
+
+// idempotencyEntry is the cached response to a request. A nil sealed response
+// means that the request is still in progress.
+type idempotencyEntry struct {
+	fingerprint [sha256.Size]byte
+	sealed      []byte
+	expiry      time.Time
+}
+
+// newIdempotencyCache creates a new idempotencyCache with a random encryption
+// key read from r.
+func newIdempotencyCache(r io.Reader) (*idempotencyCache, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(r, key); err != nil {
+		return nil, fmt.Errorf("failed to generate idempotency cache key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create idempotency cache cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create idempotency cache cipher: %w", err)
+	}
+
+	return &idempotencyCache{
+		aead:    aead,
+		now:     time.Now,
+		entries: make(map[string]*idempotencyEntry),
+	}, nil
+}
+
+// begin looks up the response to the request with the given id and body. If
+// there is none, the id is reserved until finish or abort is called and a nil
+// response is returned.
+func (c *idempotencyCache) begin(id string, body []byte) (*GenerateResponse, error) {
+	fingerprint := sha256.Sum256(body)
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, e := range c.entries {
+		if e.sealed != nil && now.After(e.expiry) {
+			delete(c.entries, k)
+		}
+	}
+
+	e, ok := c.entries[id]
+	if !ok {
+		c.entries[id] = &idempotencyEntry{fingerprint: fingerprint}
+		return nil, nil
+	}
+
+	if e.fingerprint != fingerprint {
+		return nil, errIdempotencyMismatch
+	}
+	if e.sealed == nil {
+		return nil, errIdempotencyInProgress
+	}
+
+	nonceSize := c.aead.NonceSize()
+	b, err := c.aead.Open(nil, e.sealed[:nonceSize], e.sealed[nonceSize:], []byte(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt cached response: %w", err)
+	}
+
+	var res GenerateResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("failed to decode cached response: %w", err)
+	}
+	return &res, nil
+}
+
+// finish stores the response to the request with the given id.
+func (c *idempotencyCache) finish(id string, res GenerateResponse) error {
+	b, err := json.Marshal(res)
+	if err != nil {
+		return fmt.Errorf("failed to encode response: %w", err)
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := c.aead.Seal(nonce, nonce, b, []byte(id))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[id]; ok {
+		e.sealed = sealed
+		e.expiry = c.now().Add(idempotencyTTL)
+	}
+	return nil
+}
+
+// abort releases the reservation of the given id after the request failed, so
+// that it can be retried.
+func (c *idempotencyCache) abort(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[id]; ok && e.sealed == nil {
+		delete(c.entries, id)
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testIdempotentRequest(tb testing.TB, h http.Handler, key, body string) *httptest.ResponseRecorder {
+	tb.Helper()
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/generate", strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+testToken)
+	r.Header.Set(IdempotencyKeyHeader, key)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestServerIdempotency(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t, testConfig(t))
+
+	decode := func(w *httptest.ResponseRecorder) string {
+		t.Helper()
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+		}
+		var res GenerateResponse
+		if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		return res.Password
+	}
+
+	first := decode(testIdempotentRequest(t, s, "req-1", `{"policy":"database"}`))
+
+	w := testIdempotentRequest(t, s, "req-1", `{"policy":"database"}`)
+	if w.Header().Get("Idempotent-Replayed") != "true" {
+		t.Error("expected replayed response to be marked")
+	}
+	if got := decode(w); got != first {
+		t.Errorf("expected retried request to return %q, got %q", first, got)
+	}
+
+	if got := decode(testIdempotentRequest(t, s, "req-2", `{"policy":"database"}`)); got == first {
+		t.Error("expected a new key to generate a new password")
+	}
+
+	if w := testIdempotentRequest(t, s, "req-1", `{"policy":"pin"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected reused key with different body to be rejected, got status %d", w.Code)
+	}
+	if w := testIdempotentRequest(t, s, strings.Repeat("k", maxIdempotencyKeyLength+1), `{"policy":"pin"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("expected long key to be rejected, got status %d", w.Code)
+	}
+}
+
+func TestIdempotencyCache(t *testing.T) {
+	t.Parallel()
+
+	c, err := newIdempotencyCache(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	body := []byte(`{"policy":"pin"}`)
+	if res, err := c.begin("a", body); res != nil || err != nil {
+		t.Fatalf("expected reservation, got %+v, %v", res, err)
+	}
+	if _, err := c.begin("a", body); !errors.Is(err, errIdempotencyInProgress) {
+		t.Errorf("expected %v to be %v", err, errIdempotencyInProgress)
+	}
+
+	c.abort("a")
+	if res, err := c.begin("a", body); res != nil || err != nil {
+		t.Fatalf("expected aborted key to be reserved again, got %+v, %v", res, err)
+	}
+
+	want := GenerateResponse{Policy: "pin", Password: "123456"}
+	if err := c.finish("a", want); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(c.entries["a"].sealed), want.Password) {
+		t.Error("expected cached response to be encrypted")
+	}
+	if res, err := c.begin("a", body); err != nil || res == nil || *res != want {
+		t.Errorf("expected %+v, got %+v, %v", want, res, err)
+	}
+
+	now = now.Add(idempotencyTTL + time.Second)
+	if res, err := c.begin("a", body); res != nil || err != nil {
+		t.Errorf("expected expired response to be dropped, got %+v, %v", res, err)
+	}
+}
@@ -0,0 +1,40 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sync/atomic"
+)
+
+// metrics are the counters exposed on the metrics endpoint.
+type metrics struct {
+	requests     atomic.Int64
+	generated    atomic.Int64
+	failures     atomic.Int64
+	unauthorized atomic.Int64
+	rateLimited  atomic.Int64
+	overQuota    atomic.Int64
+	auditErrors  atomic.Int64
+	randomBytes  atomic.Int64
+}
+
+// ServeHTTP writes the counters in the Prometheus text exposition format.
+func (m *metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	for _, c := range []struct {
+		name, help string
+		v          *atomic.Int64
+	}{
+		{"password_server_requests_total", "Number of generation requests.", &m.requests},
+		{"password_server_generated_total", "Number of passwords generated.", &m.generated},
+		{"password_server_failures_total", "Number of failed generations.", &m.failures},
+		{"password_server_unauthorized_total", "Number of requests rejected for a missing or invalid token.", &m.unauthorized},
+		{"password_server_rate_limited_total", "Number of requests rejected by the rate limit.", &m.rateLimited},
+		{"password_server_over_quota_total", "Number of requests rejected by a policy quota.", &m.overQuota},
+		{"password_server_audit_errors_total", "Number of audit events which could not be delivered.", &m.auditErrors},
+		{"password_server_random_bytes_total", "Number of bytes read from the entropy source by generations.", &m.randomBytes},
+	} {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", c.name, c.help, c.name, c.name, c.v.Load())
+	}
+}
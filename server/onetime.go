@@ -0,0 +1,148 @@
+package server
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// defaultOneTimeTTL is how long a one-time secret can be retrieved when the
+// configuration does not say otherwise.
+const defaultOneTimeTTL = 15 * time.Minute
+
+// retrievalTokenSize is the number of random bytes of a retrieval token.
+const retrievalTokenSize = 32
+
+// errSecretNotFound is the error returned when a retrieval token is unknown,
+// expired or already used.
+var errSecretNotFound = errors.New("secret not found, expired or already retrieved")
+
+// secretStore keeps generated secrets until they are retrieved once or expire.
+// Every secret is encrypted with a key derived from its retrieval token and
+// stored under a different hash of it, so the store cannot decrypt secrets
+// without their tokens. It is safe for concurrent use.
+type secretStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	secrets map[[sha256.Size]byte]sealedSecret
+}
+
+// sealedSecret is an encrypted secret.
+type sealedSecret struct {
+	sealed []byte
+	expiry time.Time
+}
+
+// newSecretStore creates a new secretStore keeping secrets for ttl.
+func newSecretStore(ttl time.Duration) *secretStore {
+	if ttl <= 0 {
+		ttl = defaultOneTimeTTL
+	}
+	return &secretStore{
+		ttl:     ttl,
+		now:     time.Now,
+		secrets: make(map[[sha256.Size]byte]sealedSecret),
+	}
+}
+
+// put stores the response and returns the token with which it can be retrieved
+// once, and the time it expires at.
+func (s *secretStore) put(res GenerateResponse) (string, time.Time, error) {
+	token := make([]byte, retrievalTokenSize)
+	if _, err := io.ReadFull(rand.Reader, token); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate retrieval token: %w", err)
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to encode secret: %w", err)
+	}
+
+	id, aead, err := secretKeys(token)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	// Every key encrypts a single secret, so a zero nonce is safe.
+	sealed := aead.Seal(nil, make([]byte, aead.NonceSize()), b, id[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.expire(now)
+
+	expiry := now.Add(s.ttl)
+	s.secrets[id] = sealedSecret{sealed: sealed, expiry: expiry}
+	return base64.RawURLEncoding.EncodeToString(token), expiry, nil
+}
+
+// take returns and deletes the secret for the given retrieval token.
+func (s *secretStore) take(encoded string) (GenerateResponse, error) {
+	token, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(token) != retrievalTokenSize {
+		return GenerateResponse{}, errSecretNotFound
+	}
+
+	id, aead, err := secretKeys(token)
+	if err != nil {
+		return GenerateResponse{}, err
+	}
+
+	s.mu.Lock()
+	s.expire(s.now())
+	secret, ok := s.secrets[id]
+	delete(s.secrets, id)
+	s.mu.Unlock()
+
+	if !ok {
+		return GenerateResponse{}, errSecretNotFound
+	}
+
+	b, err := aead.Open(nil, make([]byte, aead.NonceSize()), secret.sealed, id[:])
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+
+	var res GenerateResponse
+	if err := json.Unmarshal(b, &res); err != nil {
+		return GenerateResponse{}, fmt.Errorf("failed to decode secret: %w", err)
+	}
+	return res, nil
+}
+
+// expire deletes the secrets which expired before now. The caller must hold
+// the lock.
+func (s *secretStore) expire(now time.Time) {
+	for id, secret := range s.secrets {
+		if now.After(secret.expiry) {
+			delete(s.secrets, id)
+		}
+	}
+}
+
+// secretKeys derives the storage id and the cipher of a secret from its
+// retrieval token.
+func secretKeys(token []byte) ([sha256.Size]byte, cipher.AEAD, error) {
+	id := sha256.Sum256(append([]byte("id:"), token...))
+	key := sha256.Sum256(append([]byte("key:"), token...))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return id, nil, fmt.Errorf("failed to create secret cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return id, nil, fmt.Errorf("failed to create secret cipher: %w", err)
+	}
+	return id, aead, nil
+}
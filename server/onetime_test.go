@@ -0,0 +1,81 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServerOneTime(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t, testConfig(t))
+
+	w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"database","one_time":true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+	}
+	var res GenerateResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Password != "" {
+		t.Error("expected one-time response not to contain the password")
+	}
+	if res.RetrievalToken == "" || res.ExpiresAt == nil {
+		t.Fatalf("expected retrieval token and expiry, got %+v", res)
+	}
+
+	body := `{"token":"` + res.RetrievalToken + `"}`
+	w = testRequest(t, s, http.MethodPost, "/v1/retrieve", "", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+	}
+	var secret GenerateResponse
+	if err := json.NewDecoder(w.Body).Decode(&secret); err != nil {
+		t.Fatal(err)
+	}
+	if secret.Policy != "database" || len(secret.Password) != 32 {
+		t.Errorf("unexpected secret %+v", secret)
+	}
+
+	if w := testRequest(t, s, http.MethodPost, "/v1/retrieve", "", body); w.Code != http.StatusNotFound {
+		t.Errorf("expected second retrieval to fail, got status %d", w.Code)
+	}
+	if w := testRequest(t, s, http.MethodPost, "/v1/retrieve", "", `{"token":"bogus"}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected unknown token to fail, got status %d", w.Code)
+	}
+}
+
+func TestSecretStore(t *testing.T) {
+	t.Parallel()
+
+	s := newSecretStore(time.Minute)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	want := GenerateResponse{Policy: "pin", Password: "123456"}
+	token, expiry, err := s.put(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !expiry.Equal(now.Add(time.Minute)) {
+		t.Errorf("expected expiry %v, got %v", now.Add(time.Minute), expiry)
+	}
+	for _, secret := range s.secrets {
+		if strings.Contains(string(secret.sealed), want.Password) {
+			t.Error("expected secret to be encrypted")
+		}
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, err := s.take(token); !errors.Is(err, errSecretNotFound) {
+		t.Errorf("expected expired secret to be gone, got %v", err)
+	}
+	if len(s.secrets) != 0 {
+		t.Errorf("expected expired secrets to be deleted, got %d", len(s.secrets))
+	}
+}
@@ -0,0 +1,15 @@
+package server
+
+import (
+	"bytes"
+	_ "embed"
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+// OpenAPI returns the OpenAPI 3 document of the endpoints of the Server, in
+// JSON.
+func OpenAPI() []byte {
+	return bytes.Clone(openAPI)
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestOpenAPI(t *testing.T) {
+	t.Parallel()
+
+	var doc struct {
+		OpenAPI    string                                `json:"openapi"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components map[string]map[string]json.RawMessage `json:"components"`
+	}
+	if err := json.Unmarshal(OpenAPI(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("unexpected OpenAPI version %q", doc.OpenAPI)
+	}
+
+	for _, ref := range regexp.MustCompile(`"\$ref":\s*"#/components/(\w+)/(\w+)"`).FindAllStringSubmatch(string(OpenAPI()), -1) {
+		if _, ok := doc.Components[ref[1]][ref[2]]; !ok {
+			t.Errorf("expected %s to resolve", ref[0])
+		}
+	}
+
+	cfg := testConfig(t)
+	cfg.AdminTokenHashes = []string{HashToken(testAdminToken)}
+	s := testServer(t, cfg)
+
+	documented := make(map[string]bool)
+	for path, item := range doc.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			method = strings.ToUpper(method)
+			target := strings.ReplaceAll(path, "{name}", "database")
+
+			r, _ := http.NewRequest(method, target, nil)
+			_, pattern := s.mux.Handler(r)
+			if pattern == "" {
+				t.Errorf("expected %s %s to be routed", method, path)
+				continue
+			}
+			documented[pattern] = true
+
+			token := testToken
+			if strings.HasPrefix(path, "/v1/admin/") {
+				token = testAdminToken
+			}
+			if w := testRequest(t, s, method, target, token, ""); w.Code == http.StatusMethodNotAllowed {
+				t.Errorf("expected %s %s to be allowed", method, path)
+			}
+		}
+	}
+
+	for _, route := range s.routes {
+		if !documented[route] {
+			t.Errorf("expected route %q to be documented", route)
+		}
+	}
+}
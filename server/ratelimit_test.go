@@ -0,0 +1,38 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+	l := newRateLimiter(2, 3)
+	l.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		if !l.allow("a") {
+			t.Errorf("request %d should be allowed within the burst", i)
+		}
+	}
+	if l.allow("a") {
+		t.Error("request should exceed the burst")
+	}
+	if !l.allow("b") {
+		t.Error("other clients should have their own bucket")
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if !l.allow("a") {
+		t.Error("one token should have been refilled")
+	}
+	if l.allow("a") {
+		t.Error("only one token should have been refilled")
+	}
+
+	if !newRateLimiter(0, 0).allow("a") {
+		t.Error("a zero rate should allow everything")
+	}
+}
@@ -0,0 +1,563 @@
+// Package server implements an HTTP service which generates passwords for
+// named policies, so that small teams can run a credential-generation service
+// without writing glue. See cmd/password-server for a ready-to-deploy binary.
+//
+// Clients authenticate with a bearer token and request a password for a
+// policy:
+//
+//	POST /v1/generate
+//	Authorization: Bearer <token>
+//
+//	{"policy": "database"}
+//
+// The response contains the password:
+//
+//	{"policy": "database", "password": "..."}
+//
+// Policies can be managed at runtime through the admin API, which requires a
+// separate admin token:
+//
+//	GET    /v1/admin/policies
+//	GET    /v1/admin/policies/{name}
+//	PUT    /v1/admin/policies/{name}
+//	DELETE /v1/admin/policies/{name}
+//
+// The number of passwords each client has generated for each policy is tracked
+// and enforced against policy quotas. Usage is listed and reset, for example at
+// the start of a billing period, through the admin API:
+//
+//	GET    /v1/admin/usage
+//	DELETE /v1/admin/usage
+//
+// Policies may require generation requests to be signed in addition, so that
+// only authorized automation can mint their credentials. Signed requests carry
+// the name of an HMAC key, a timestamp and the signature of timestamp and body
+// computed by Sign:
+//
+//	X-Signature-Key: deploy
+//	X-Signature-Timestamp: 1700000000
+//	X-Signature: <hex HMAC-SHA256>
+//
+// Requests signed more than five minutes away from the server time, and
+// requests with a signature the server has already seen, are rejected.
+//
+// Passwords of policies with escrow enabled are deposited, envelope-encrypted,
+// in the escrow of the server, and the response holds the ID of the escrow
+// record. Recovery goes through package escrow and is never served over HTTP.
+//
+// Requests with "one_time": true do not return the password. Instead, it is
+// kept encrypted for a short time and a retrieval token is returned, so that
+// the password does not show up in the logs or responses of the provisioning
+// system. The password can be retrieved exactly once, without a bearer token,
+// by whoever holds the retrieval token:
+//
+//	POST /v1/retrieve
+//
+//	{"token": "<retrieval token>"}
+//
+// Provisioning systems which retry requests can send an Idempotency-Key header.
+// For a day, retried requests with the same key and body return the password of
+// the first request instead of generating a new one. Cached responses are kept
+// encrypted with a key which only exists in memory.
+//
+// Audit events are queued and delivered to the audit sink in the background,
+// so that a slow sink cannot delay or drop responses. Close delivers the events
+// still queued at shutdown.
+//
+// Request counters are exposed in the Prometheus text format on /metrics. The
+// unauthenticated /healthz and /readyz endpoints are meant for liveness and
+// readiness probes.
+//
+// The endpoints are described by an OpenAPI document, returned by OpenAPI, from
+// which clients in other languages can be generated.
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/juev/go-password/audit"
+	"github.com/juev/go-password/escrow"
+	"github.com/juev/go-password/password"
+)
+
+// maxRequestSize is the maximum size of a request body.
+const maxRequestSize = 1 << 16
+
+const (
+	// auditQueueSize is the number of audit events waiting for delivery
+	// beyond which new events are dropped.
+	auditQueueSize = 1024
+
+	// auditTimeout is the time the audit sink has to deliver an event,
+	// retries included.
+	auditTimeout = 5 * time.Second
+)
+
+// Server is the password generation service. It is safe for concurrent use.
+type Server struct {
+	entropy     io.Reader
+	generator   password.Generator
+	policies    PolicyStore
+	usage       UsageStore
+	tokens      [][]byte
+	admins      [][]byte
+	limiter     *rateLimiter
+	verifier    *verifier
+	idempotency *idempotencyCache
+	secrets     *secretStore
+	escrow      *escrow.Escrow
+	sink        audit.Sink
+	metrics     metrics
+	draining    atomic.Bool
+
+	// auditMu guards auditClosed and sending to auditEvents, which the
+	// audit sink is fed from until auditDone is closed.
+	auditMu     sync.RWMutex
+	auditClosed bool
+	auditEvents chan audit.Event
+	auditDone   chan struct{}
+
+	// routes are the patterns registered on mux.
+	routes []string
+	mux    *http.ServeMux
+}
+
+// New creates a new Server from the given configuration.
+func New(cfg *Config) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	tokens, err := parseTokenHashes(cfg.TokenHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	admins, err := parseTokenHashes(cfg.AdminTokenHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := parseSigningKeys(cfg.SigningKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range cfg.Policies {
+		if p.Escrow && cfg.Escrow == nil {
+			return nil, fmt.Errorf("%w %q: escrow is not configured", ErrInvalidPolicy, p.Name)
+		}
+	}
+
+	store := cfg.Store
+	if store == nil {
+		store = NewMemoryPolicyStore(cfg.Policies...)
+	}
+
+	idempotency, err := newIdempotencyCache(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := cfg.Usage
+	if usage == nil {
+		usage = NewMemoryUsageStore()
+	}
+
+	s := &Server{
+		entropy:     rand.Reader,
+		generator:   password.NewGenerator().WithReaders(rand.Reader),
+		policies:    store,
+		usage:       usage,
+		tokens:      tokens,
+		admins:      admins,
+		limiter:     newRateLimiter(cfg.RateLimit, cfg.RateBurst),
+		verifier:    newVerifier(keys),
+		idempotency: idempotency,
+		secrets:     newSecretStore(time.Duration(cfg.OneTimeTTL) * time.Second),
+		escrow:      cfg.Escrow,
+		sink:        cfg.AuditSink,
+		mux:         http.NewServeMux(),
+	}
+	if s.sink != nil {
+		s.auditEvents = make(chan audit.Event, auditQueueSize)
+		s.auditDone = make(chan struct{})
+		go s.deliverAudit()
+	}
+
+	s.handle("/v1/generate", s.requireToken(s.tokens, http.HandlerFunc(s.handleGenerate)))
+	if len(s.admins) > 0 {
+		admin := s.requireToken(s.admins, http.HandlerFunc(s.handleAdminPolicies))
+		s.handle(policiesPath, admin)
+		s.handle(policiesPath+"/", admin)
+		s.handle(usagePath, s.requireToken(s.admins, http.HandlerFunc(s.handleAdminUsage)))
+	}
+	s.handle("/v1/retrieve", http.HandlerFunc(s.handleRetrieve))
+	s.handle("/metrics", &s.metrics)
+	s.handle("/healthz", http.HandlerFunc(s.handleHealthz))
+	s.handle("/readyz", http.HandlerFunc(s.handleReadyz))
+	return s, nil
+}
+
+// handle registers the handler for the pattern. Every route must be described
+// in the OpenAPI document.
+func (s *Server) handle(pattern string, h http.Handler) {
+	s.routes = append(s.routes, pattern)
+	s.mux.Handle(pattern, h)
+}
+
+// Drain marks the server as shutting down, so that readiness probes fail and
+// load balancers stop sending new requests while in-flight requests complete.
+func (s *Server) Drain() {
+	s.draining.Store(true)
+}
+
+// Close delivers the audit events still queued and stops the delivery of new
+// ones, which are then counted as audit errors. It should be called once the
+// HTTP server has shut down.
+func (s *Server) Close() {
+	if s.sink == nil {
+		return
+	}
+
+	s.auditMu.Lock()
+	if !s.auditClosed {
+		s.auditClosed = true
+		close(s.auditEvents)
+	}
+	s.auditMu.Unlock()
+	<-s.auditDone
+}
+
+// ServeHTTP dispatches the request to the matching endpoint.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// GenerateRequest is the body of a generation request.
+type GenerateRequest struct {
+	Policy string `json:"policy"`
+
+	// OneTime requests a retrieval token instead of the password.
+	OneTime bool `json:"one_time,omitempty"`
+
+	// Subject names what the password is for, such as a host or an account.
+	// It is recorded with escrowed passwords.
+	Subject string `json:"subject,omitempty"`
+}
+
+// GenerateResponse is the body of a successful generation response. It holds
+// either the password, or for one-time requests the retrieval token and the
+// time it expires at.
+type GenerateResponse struct {
+	Policy         string     `json:"policy"`
+	Password       string     `json:"password,omitempty"`
+	RetrievalToken string     `json:"retrieval_token,omitempty"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+
+	// EscrowID is the ID of the escrow record of the password, if the policy
+	// has escrow enabled.
+	EscrowID string `json:"escrow_id,omitempty"`
+}
+
+// RetrieveRequest is the body of a one-time retrieval request.
+type RetrieveRequest struct {
+	Token string `json:"token"`
+}
+
+// handleGenerate generates a password for the requested policy.
+func (s *Server) handleGenerate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	s.metrics.requests.Add(1)
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var req GenerateRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	policy, err := s.policies.Get(r.Context(), req.Policy)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	if len(policy.SigningKeys) > 0 {
+		if err := s.verifier.verify(r, body, policy.SigningKeys); err != nil {
+			s.metrics.unauthorized.Add(1)
+			s.audit(r, audit.Event{Type: audit.TypeAuthFailure, Policy: policy.Name, Error: err.Error()})
+			writeError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+	}
+
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		if res, ok := s.issue(w, r, policy, req); ok {
+			writeJSON(w, http.StatusOK, res)
+		}
+		return
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		writeError(w, http.StatusBadRequest, "idempotency key too long")
+		return
+	}
+
+	id := clientFromContext(r.Context()) + "\x00" + key
+	cached, err := s.idempotency.begin(id, body)
+	switch {
+	case errors.Is(err, errIdempotencyInProgress):
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	case errors.Is(err, errIdempotencyMismatch):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, "idempotency cache failure")
+		return
+	case cached != nil:
+		w.Header().Set("Idempotent-Replayed", "true")
+		writeJSON(w, http.StatusOK, cached)
+		return
+	}
+
+	res, ok := s.issue(w, r, policy, req)
+	if !ok {
+		s.idempotency.abort(id)
+		return
+	}
+	if err := s.idempotency.finish(id, res); err != nil {
+		s.idempotency.abort(id)
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+// issue generates a password for the policy, enforcing its quota and depositing
+// it in escrow if enabled. For one-time requests, the password is stored for
+// one-time retrieval and the response holds the retrieval token instead. On
+// failure, it writes the error response and returns false, and the password
+// does not count towards the quota.
+func (s *Server) issue(w http.ResponseWriter, r *http.Request, policy Policy, req GenerateRequest) (GenerateResponse, bool) {
+	client := clientFromContext(r.Context())
+	if _, err := s.usage.Increment(r.Context(), policy.Name, client, policy.Quota); err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			s.metrics.overQuota.Add(1)
+			writeError(w, http.StatusTooManyRequests, fmt.Sprintf("quota of policy %q exceeded", policy.Name))
+			return GenerateResponse{}, false
+		}
+		writeError(w, http.StatusInternalServerError, "usage store failure")
+		return GenerateResponse{}, false
+	}
+
+	// Only passwords handed out count towards the quota, so every failure
+	// below gives the increment back.
+	refund := func() {
+		_ = s.usage.Decrement(r.Context(), policy.Name, client)
+	}
+
+	res, stats, err := s.generator.GenerateStats(policy.Input())
+	s.metrics.randomBytes.Add(int64(stats.RandomBytes))
+	if err != nil {
+		s.metrics.failures.Add(1)
+		s.audit(r, audit.Event{Type: audit.TypeGenerateFailure, Policy: policy.Name, RandomBytes: stats.RandomBytes, Error: err.Error()})
+		refund()
+		writeError(w, http.StatusInternalServerError, "failed to generate password")
+		return GenerateResponse{}, false
+	}
+
+	resp := GenerateResponse{
+		Policy:   policy.Name,
+		Password: res,
+	}
+	if policy.Escrow {
+		if s.escrow == nil {
+			s.metrics.failures.Add(1)
+			refund()
+			writeError(w, http.StatusInternalServerError, "escrow is not configured")
+			return GenerateResponse{}, false
+		}
+
+		resp.EscrowID, err = s.escrow.Deposit(r.Context(), policy.Name, req.Subject, res)
+		if err != nil {
+			s.metrics.failures.Add(1)
+			s.audit(r, audit.Event{Type: audit.TypeGenerateFailure, Policy: policy.Name, Error: err.Error()})
+			refund()
+			writeError(w, http.StatusInternalServerError, "failed to deposit password in escrow")
+			return GenerateResponse{}, false
+		}
+	}
+
+	s.metrics.generated.Add(1)
+	s.audit(r, audit.Event{Type: audit.TypeGenerate, Policy: policy.Name, Record: resp.EscrowID, RandomBytes: stats.RandomBytes})
+	if !req.OneTime {
+		return resp, true
+	}
+
+	token, expiry, err := s.secrets.put(resp)
+	if err != nil {
+		s.metrics.failures.Add(1)
+		refund()
+		writeError(w, http.StatusInternalServerError, "failed to store password")
+		return GenerateResponse{}, false
+	}
+	return GenerateResponse{
+		Policy:         policy.Name,
+		RetrievalToken: token,
+		ExpiresAt:      &expiry,
+		EscrowID:       resp.EscrowID,
+	}, true
+}
+
+// handleRetrieve returns a one-time password for its retrieval token, which
+// cannot be used again afterwards.
+func (s *Server) handleRetrieve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req RetrieveRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	res, err := s.secrets.take(req.Token)
+	if errors.Is(err, errSecretNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to retrieve password")
+		return
+	}
+
+	s.audit(r, audit.Event{Type: audit.TypeRetrieve, Policy: res.Policy})
+	writeJSON(w, http.StatusOK, res)
+}
+
+// audit queues the given event for the audit sink, if any, filling in the
+// time and caller of the request. Events are delivered in the background, so
+// that a slow sink never delays responses; events which do not fit in the
+// queue and delivery failures are counted but do not fail the request.
+func (s *Server) audit(r *http.Request, e audit.Event) {
+	if s.sink == nil {
+		return
+	}
+
+	e.Time = time.Now().UTC()
+	e.Client = clientFromContext(r.Context())
+	e.RemoteAddr = r.RemoteAddr
+
+	s.auditMu.RLock()
+	defer s.auditMu.RUnlock()
+	if s.auditClosed {
+		s.metrics.auditErrors.Add(1)
+		return
+	}
+	select {
+	case s.auditEvents <- e:
+	default:
+		s.metrics.auditErrors.Add(1)
+	}
+}
+
+// deliverAudit writes the queued audit events to the audit sink, giving it
+// auditTimeout for each, until the queue is closed.
+func (s *Server) deliverAudit() {
+	defer close(s.auditDone)
+	for e := range s.auditEvents {
+		ctx, cancel := context.WithTimeout(context.Background(), auditTimeout)
+		if err := s.sink.Write(ctx, e); err != nil {
+			s.metrics.auditErrors.Add(1)
+		}
+		cancel()
+	}
+}
+
+// healthCheckSize is the number of random bytes read by the entropy self-test.
+const healthCheckSize = 256
+
+// handleHealthz reports whether the server can generate passwords. It runs an
+// entropy self-test on the random source and generates a throwaway password.
+func (s *Server) handleHealthz(w http.ResponseWriter, _ *http.Request) {
+	if err := s.selfTest(); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReadyz reports whether the server accepts new requests.
+func (s *Server) handleReadyz(w http.ResponseWriter, _ *http.Request) {
+	if s.draining.Load() {
+		writeError(w, http.StatusServiceUnavailable, "shutting down")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// selfTest verifies that the entropy source passes its health checks and that
+// a password can be generated from it.
+func (s *Server) selfTest() error {
+	r := password.NewFailoverReader(s.entropy, nil, password.HealthOptions{})
+	if _, err := io.ReadFull(r, make([]byte, healthCheckSize)); err != nil {
+		return fmt.Errorf("entropy self-test failed: %w", err)
+	}
+
+	if _, err := s.generator.Generate(password.Input{Length: 16, Digits: 4, Symbols: 4}); err != nil {
+		return fmt.Errorf("generation self-test failed: %w", err)
+	}
+	return nil
+}
+
+// decodeJSON decodes the JSON request body into v, rejecting unknown fields
+// and oversized bodies.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestSize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	if dec.More() {
+		return errors.New("invalid request body: unexpected data after JSON object")
+	}
+	return nil
+}
+
+// writeJSON writes v as a JSON response. Responses are never cached since they
+// may contain secrets.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
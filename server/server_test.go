@@ -0,0 +1,393 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juev/go-password/audit"
+	"github.com/juev/go-password/escrow"
+	"github.com/juev/go-password/keywrap"
+)
+
+const testToken = "s3cret-token"
+
+func testConfig(tb testing.TB) *Config {
+	tb.Helper()
+
+	return &Config{
+		Policies: []Policy{
+			{Name: "database", Length: 32, Digits: 6, Symbols: 6},
+			{Name: "pin", Length: 6, Digits: 6, AllowRepeat: true},
+		},
+		TokenHashes: []string{HashToken(testToken)},
+	}
+}
+
+func testServer(tb testing.TB, cfg *Config) *Server {
+	tb.Helper()
+
+	s, err := New(cfg)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(s.Close)
+	return s
+}
+
+func testRequest(tb testing.TB, h http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	tb.Helper()
+
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestServerGenerate(t *testing.T) {
+	t.Parallel()
+
+	s := testServer(t, testConfig(t))
+
+	t.Run("generates", func(t *testing.T) {
+		t.Parallel()
+
+		w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"database"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+		}
+		if got := w.Header().Get("Cache-Control"); got != "no-store" {
+			t.Errorf("expected Cache-Control no-store, got %q", got)
+		}
+
+		var res GenerateResponse
+		if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		if res.Policy != "database" || len(res.Password) != 32 {
+			t.Errorf("unexpected response %+v", res)
+		}
+	})
+
+	cases := []struct {
+		name   string
+		method string
+		token  string
+		body   string
+		status int
+	}{
+		{"no_token", http.MethodPost, "", `{"policy":"pin"}`, http.StatusUnauthorized},
+		{"wrong_token", http.MethodPost, "nope", `{"policy":"pin"}`, http.StatusUnauthorized},
+		{"wrong_method", http.MethodGet, testToken, "", http.StatusMethodNotAllowed},
+		{"unknown_policy", http.MethodPost, testToken, `{"policy":"nope"}`, http.StatusNotFound},
+		{"unknown_field", http.MethodPost, testToken, `{"policy":"pin","length":4}`, http.StatusBadRequest},
+		{"invalid_json", http.MethodPost, testToken, `{`, http.StatusBadRequest},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := testRequest(t, s, tc.method, "/v1/generate", tc.token, tc.body)
+			if w.Code != tc.status {
+				t.Errorf("expected status %d, got %d: %s", tc.status, w.Code, w.Body)
+			}
+		})
+	}
+}
+
+func TestServerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t)
+	cfg.RateLimit = 0.001
+	cfg.RateBurst = 2
+	s := testServer(t, cfg)
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"pin"}`)
+		if w.Code != want {
+			t.Errorf("request %d: expected status %d, got %d", i, want, w.Code)
+		}
+	}
+
+	w := testRequest(t, s, http.MethodGet, "/metrics", "", "")
+	body, _ := io.ReadAll(w.Body)
+	for _, line := range []string{
+		"password_server_requests_total 2",
+		"password_server_generated_total 2",
+		"password_server_rate_limited_total 1",
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("expected metrics to contain %q, got:\n%s", line, body)
+		}
+	}
+}
+
+func TestServerProbes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("healthz", func(t *testing.T) {
+		t.Parallel()
+
+		s := testServer(t, testConfig(t))
+		if w := testRequest(t, s, http.MethodGet, "/healthz", "", ""); w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+		}
+
+		s.entropy = strings.NewReader(strings.Repeat("a", healthCheckSize))
+		if w := testRequest(t, s, http.MethodGet, "/healthz", "", ""); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status %d, got %d: %s", http.StatusServiceUnavailable, w.Code, w.Body)
+		}
+	})
+
+	t.Run("readyz", func(t *testing.T) {
+		t.Parallel()
+
+		s := testServer(t, testConfig(t))
+		if w := testRequest(t, s, http.MethodGet, "/readyz", "", ""); w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+		}
+
+		s.Drain()
+		if w := testRequest(t, s, http.MethodGet, "/readyz", "", ""); w.Code != http.StatusServiceUnavailable {
+			t.Errorf("expected status %d, got %d: %s", http.StatusServiceUnavailable, w.Code, w.Body)
+		}
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	write := func(t *testing.T, s string) string {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		if err := os.WriteFile(path, []byte(s), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := LoadConfig(write(t, `{
+			"policies": [{"name": "db", "length": 16, "digits": 2, "symbols": 2}],
+			"token_sha256": ["`+HashToken("x")+`"],
+			"rate_limit": 1.5
+		}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cfg.Policies) != 1 || cfg.Policies[0].Name != "db" || cfg.RateLimit != 1.5 {
+			t.Errorf("unexpected config %+v", cfg)
+		}
+	})
+
+	cases := []struct {
+		name string
+		json string
+		err  error
+	}{
+		{"no_policies", `{"policies": []}`, ErrNoPolicies},
+		{"unnamed", `{"policies": [{"length": 8}]}`, ErrInvalidPolicy},
+		{"duplicate", `{"policies": [{"name": "a", "length": 8}, {"name": "a", "length": 8}]}`, ErrInvalidPolicy},
+		{"unsatisfiable", `{"policies": [{"name": "a", "length": 8, "digits": 11}]}`, ErrInvalidPolicy},
+		{"token_hash", `{"policies": [{"name": "a", "length": 8}], "token_sha256": ["abc"]}`, ErrInvalidTokenHash},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := LoadConfig(write(t, tc.json)); !errors.Is(err, tc.err) {
+				t.Errorf("expected %q to be %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestServerAudit(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu     sync.Mutex
+		events []audit.Event
+	)
+	cfg := testConfig(t)
+	cfg.AdminTokenHashes = []string{HashToken(testAdminToken)}
+	cfg.AuditSink = audit.SinkFunc(func(_ context.Context, e audit.Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, e)
+		return nil
+	})
+	s := testServer(t, cfg)
+
+	testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"pin"}`)
+	testRequest(t, s, http.MethodPost, "/v1/generate", "wrong", `{"policy":"pin"}`)
+	testRequest(t, s, http.MethodPut, "/v1/admin/policies/wifi", testAdminToken, `{"length": 20}`)
+	testRequest(t, s, http.MethodDelete, "/v1/admin/policies/wifi", testAdminToken, "")
+	testRequest(t, s, http.MethodDelete, "/v1/admin/usage", testAdminToken, "")
+	s.Close()
+
+	want := []struct {
+		typ    string
+		policy string
+		client string
+	}{
+		{audit.TypeGenerate, "pin", HashToken(testToken)[:clientIDLength]},
+		{audit.TypeAuthFailure, "", ""},
+		{audit.TypePolicyPut, "wifi", HashToken(testAdminToken)[:clientIDLength]},
+		{audit.TypePolicyDelete, "wifi", HashToken(testAdminToken)[:clientIDLength]},
+		{audit.TypeUsageReset, "", HashToken(testAdminToken)[:clientIDLength]},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), events)
+	}
+	for i, e := range events {
+		if e.Type != want[i].typ || e.Policy != want[i].policy || e.Client != want[i].client {
+			t.Errorf("expected event %d to be %+v, got %+v", i, want[i], e)
+		}
+		if e.Time.IsZero() || e.RemoteAddr == "" {
+			t.Errorf("expected event %d to have time and remote address, got %+v", i, e)
+		}
+		if (e.Type == audit.TypeGenerate) != (e.RandomBytes > 0) {
+			t.Errorf("expected only generate events to account for random bytes, got %+v", e)
+		}
+	}
+}
+
+func TestServerAuditSlowSink(t *testing.T) {
+	t.Parallel()
+
+	release := make(chan struct{})
+	cfg := testConfig(t)
+	cfg.AuditSink = audit.SinkFunc(func(ctx context.Context, _ audit.Event) error {
+		select {
+		case <-release:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	s := testServer(t, cfg)
+
+	start := time.Now()
+	for i := 0; i < auditQueueSize+10; i++ {
+		if rec := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"pin"}`); rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+	}
+	if d := time.Since(start); d > auditTimeout {
+		t.Errorf("expected responses not to wait for the audit sink, took %v", d)
+	}
+	if n := s.metrics.auditErrors.Load(); n == 0 {
+		t.Error("expected events beyond the queue to be counted as dropped")
+	}
+
+	close(release)
+	s.Close()
+	s.Close()
+	before := s.metrics.auditErrors.Load()
+	testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"pin"}`)
+	if n := s.metrics.auditErrors.Load(); n != before+1 {
+		t.Errorf("expected events after Close to be counted, got %d errors", n-before)
+	}
+}
+
+func TestServerEscrow(t *testing.T) {
+	t.Parallel()
+
+	wrapper, err := keywrap.NewAES("test", []byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := escrow.New(escrow.NewMemoryStore(), wrapper, nil)
+
+	cfg := testConfig(t)
+	cfg.Policies[0].Escrow = true
+	if _, err := New(cfg); !errors.Is(err, ErrInvalidPolicy) {
+		t.Errorf("expected escrow policy without escrow to be rejected, got %v", err)
+	}
+
+	cfg.Escrow = e
+	s := testServer(t, cfg)
+
+	w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"database","subject":"db-primary"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+	}
+	var res GenerateResponse
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if res.EscrowID == "" {
+		t.Fatal("expected escrow id")
+	}
+
+	got, err := e.Recover(context.Background(), res.EscrowID, "alice", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != res.Password {
+		t.Errorf("expected escrowed password %q to be %q", got, res.Password)
+	}
+}
+
+// failingStore is an escrow.Store which fails to store records.
+type failingStore struct {
+	escrow.Store
+}
+
+func (failingStore) Put(context.Context, escrow.Record) error {
+	return errors.New("store unavailable")
+}
+
+func TestServerEscrowFailure(t *testing.T) {
+	t.Parallel()
+
+	wrapper, err := keywrap.NewAES("test", []byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := testConfig(t)
+	cfg.Policies[0].Escrow = true
+	cfg.Policies[0].Quota = 1
+	cfg.Escrow = escrow.New(failingStore{escrow.NewMemoryStore()}, wrapper, nil)
+	s := testServer(t, cfg)
+
+	for i := 0; i < 2; i++ {
+		w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"database"}`)
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("expected status %d, got %d: %s", http.StatusInternalServerError, w.Code, w.Body)
+		}
+	}
+
+	usage, err := s.usage.List(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != 0 {
+		t.Errorf("expected failed requests not to count, got %+v", usage)
+	}
+}
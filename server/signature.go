@@ -0,0 +1,158 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Request signature headers.
+const (
+	// SignatureKeyHeader is the header naming the signing key of a request.
+	SignatureKeyHeader = "X-Signature-Key"
+
+	// SignatureTimestampHeader is the header holding the time a request was
+	// signed at, in seconds since the Unix epoch.
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+
+	// SignatureHeader is the header holding the hex-encoded signature of a
+	// request, as computed by Sign.
+	SignatureHeader = "X-Signature"
+)
+
+// signatureMaxAge is the maximum difference between the signature timestamp of
+// a request and the time it is received.
+const signatureMaxAge = 5 * time.Minute
+
+var (
+	// ErrInvalidSigningKey is the error returned when a signing key is not
+	// hex-encoded or too short.
+	ErrInvalidSigningKey = errors.New("invalid signing key")
+
+	// ErrUnknownSigningKey is the error returned when a policy refers to a
+	// signing key which is not configured.
+	ErrUnknownSigningKey = errors.New("unknown signing key")
+
+	// ErrInvalidSignature is the error returned when a request requiring a
+	// signature is unsigned, signed with the wrong key, expired or replayed.
+	ErrInvalidSignature = errors.New("invalid request signature")
+)
+
+// minSigningKeySize is the minimum size of a signing key in bytes.
+const minSigningKeySize = 32
+
+// Sign returns the hex-encoded HMAC-SHA256 signature of a request body signed
+// with the given key at the given time. Clients send it in SignatureHeader,
+// along with the key name in SignatureKeyHeader and timestamp.Unix() in
+// SignatureTimestampHeader.
+func Sign(key []byte, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte{'\n'})
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseSigningKeys decodes the given hex-encoded signing keys.
+func parseSigningKeys(keys map[string]string) (map[string][]byte, error) {
+	res := make(map[string][]byte, len(keys))
+	for name, key := range keys {
+		b, err := hex.DecodeString(key)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidSigningKey, name, err)
+		}
+		if len(b) < minSigningKeySize {
+			return nil, fmt.Errorf("%w %q: must be at least %d bytes", ErrInvalidSigningKey, name, minSigningKeySize)
+		}
+		res[name] = b
+	}
+	return res, nil
+}
+
+// checkSigningKeys returns ErrUnknownSigningKey if the policy refers to a
+// signing key which is not in keys.
+func checkSigningKeys(p Policy, keys map[string][]byte) error {
+	for _, name := range p.SigningKeys {
+		if _, ok := keys[name]; !ok {
+			return fmt.Errorf("%w %q: %w %q", ErrInvalidPolicy, p.Name, ErrUnknownSigningKey, name)
+		}
+	}
+	return nil
+}
+
+// verifier verifies request signatures and remembers the signatures it has
+// seen until they expire, so that signed requests cannot be replayed. It is
+// safe for concurrent use.
+type verifier struct {
+	keys map[string][]byte
+	now  func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// newVerifier creates a new verifier for the given signing keys.
+func newVerifier(keys map[string][]byte) *verifier {
+	return &verifier{
+		keys: keys,
+		now:  time.Now,
+		seen: make(map[string]time.Time),
+	}
+}
+
+// verify verifies that the request with the given body is signed with one of
+// the allowed keys, is recent and has not been seen before.
+func (v *verifier) verify(r *http.Request, body []byte, allowed []string) error {
+	name := r.Header.Get(SignatureKeyHeader)
+	if !slices.Contains(allowed, name) {
+		return fmt.Errorf("%w: key %q is not allowed", ErrInvalidSignature, name)
+	}
+	key, ok := v.keys[name]
+	if !ok {
+		return fmt.Errorf("%w: %w %q", ErrInvalidSignature, ErrUnknownSigningKey, name)
+	}
+
+	unix, err := strconv.ParseInt(r.Header.Get(SignatureTimestampHeader), 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: invalid timestamp", ErrInvalidSignature)
+	}
+	timestamp := time.Unix(unix, 0)
+
+	now := v.now()
+	if math.Abs(float64(now.Sub(timestamp))) > float64(signatureMaxAge) {
+		return fmt.Errorf("%w: timestamp outside of the allowed window", ErrInvalidSignature)
+	}
+
+	sig, err := hex.DecodeString(r.Header.Get(SignatureHeader))
+	if err != nil {
+		return fmt.Errorf("%w: signature is not hex-encoded", ErrInvalidSignature)
+	}
+	want, _ := hex.DecodeString(Sign(key, timestamp, body))
+	if !hmac.Equal(sig, want) {
+		return fmt.Errorf("%w: signature mismatch", ErrInvalidSignature)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for s, expiry := range v.seen {
+		if now.After(expiry) {
+			delete(v.seen, s)
+		}
+	}
+
+	id := name + ":" + hex.EncodeToString(sig)
+	if _, ok := v.seen[id]; ok {
+		return fmt.Errorf("%w: replayed request", ErrInvalidSignature)
+	}
+	v.seen[id] = timestamp.Add(signatureMaxAge)
+	return nil
+}
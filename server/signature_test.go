@@ -0,0 +1,94 @@
+package server
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testSigningKey = []byte("0123456789abcdef0123456789abcdef")
+
+func testSignedRequest(tb testing.TB, h http.Handler, key []byte, keyName string, timestamp time.Time, body string) *httptest.ResponseRecorder {
+	tb.Helper()
+
+	r := httptest.NewRequest(http.MethodPost, "/v1/generate", strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+testToken)
+	r.Header.Set(SignatureKeyHeader, keyName)
+	r.Header.Set(SignatureTimestampHeader, strconv.FormatInt(timestamp.Unix(), 10))
+	r.Header.Set(SignatureHeader, Sign(key, timestamp, []byte(body)))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestServerSignedRequests(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t)
+	cfg.SigningKeys = map[string]string{"deploy": hex.EncodeToString(testSigningKey)}
+	cfg.Policies[0].SigningKeys = []string{"deploy"}
+	s := testServer(t, cfg)
+
+	now := time.Now()
+	body := `{"policy":"database"}`
+
+	if w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, body); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected unsigned request to be rejected, got status %d", w.Code)
+	}
+	if w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"pin"}`); w.Code != http.StatusOK {
+		t.Errorf("expected policy without signing keys to accept unsigned requests, got status %d", w.Code)
+	}
+
+	if w := testSignedRequest(t, s, testSigningKey, "deploy", now, body); w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+	}
+	if w := testSignedRequest(t, s, testSigningKey, "deploy", now, body); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected replayed request to be rejected, got status %d", w.Code)
+	}
+
+	cases := []struct {
+		name      string
+		key       []byte
+		keyName   string
+		timestamp time.Time
+	}{
+		{"wrong_key", []byte("fedcba9876543210fedcba9876543210"), "deploy", now.Add(time.Second)},
+		{"unknown_key", testSigningKey, "other", now.Add(2 * time.Second)},
+		{"expired", testSigningKey, "deploy", now.Add(-signatureMaxAge - time.Minute)},
+		{"future", testSigningKey, "deploy", now.Add(signatureMaxAge + time.Minute)},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if w := testSignedRequest(t, s, tc.key, tc.keyName, tc.timestamp, body); w.Code != http.StatusUnauthorized {
+				t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+			}
+		})
+	}
+}
+
+func TestConfigSigningKeys(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t)
+	cfg.SigningKeys = map[string]string{"short": "abcd"}
+	if err := cfg.Validate(); !errors.Is(err, ErrInvalidSigningKey) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidSigningKey)
+	}
+
+	cfg = testConfig(t)
+	cfg.Policies[0].SigningKeys = []string{"missing"}
+	if err := cfg.Validate(); !errors.Is(err, ErrUnknownSigningKey) {
+		t.Errorf("expected %v to be %v", err, ErrUnknownSigningKey)
+	}
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrPolicyNotFound is the error returned by a PolicyStore when a policy does
+// not exist.
+var ErrPolicyNotFound = errors.New("policy not found")
+
+// PolicyStore stores the named policies served by a Server. Implementations
+// must be safe for concurrent use and return ErrPolicyNotFound for unknown
+// policies. Policies are validated before they are stored.
+type PolicyStore interface {
+	// Get returns the policy with the given name.
+	Get(ctx context.Context, name string) (Policy, error)
+
+	// List returns all policies, sorted by name.
+	List(ctx context.Context) ([]Policy, error)
+
+	// Put creates or replaces the policy with the name of the given policy.
+	Put(ctx context.Context, p Policy) error
+
+	// Delete deletes the policy with the given name.
+	Delete(ctx context.Context, name string) error
+}
+
+// MemoryPolicyStore is a PolicyStore which keeps policies in memory. Changes
+// are lost when the process exits.
+type MemoryPolicyStore struct {
+	mu       sync.RWMutex
+	policies map[string]Policy
+}
+
+var _ PolicyStore = (*MemoryPolicyStore)(nil)
+
+// NewMemoryPolicyStore creates a new MemoryPolicyStore holding the given
+// policies.
+func NewMemoryPolicyStore(policies ...Policy) *MemoryPolicyStore {
+	m := &MemoryPolicyStore{policies: make(map[string]Policy, len(policies))}
+	for _, p := range policies {
+		m.policies[p.Name] = p
+	}
+	return m
+}
+
+// Get returns the policy with the given name.
+func (m *MemoryPolicyStore) Get(_ context.Context, name string) (Policy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	p, ok := m.policies[name]
+	if !ok {
+		return Policy{}, ErrPolicyNotFound
+	}
+	return p, nil
+}
+
+// List returns all policies, sorted by name.
+func (m *MemoryPolicyStore) List(_ context.Context) ([]Policy, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	res := make([]Policy, 0, len(m.policies))
+	for _, p := range m.policies {
+		res = append(res, p)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res, nil
+}
+
+// Put creates or replaces the policy with the name of the given policy.
+func (m *MemoryPolicyStore) Put(_ context.Context, p Policy) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.policies[p.Name] = p
+	return nil
+}
+
+// Delete deletes the policy with the given name.
+func (m *MemoryPolicyStore) Delete(_ context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.policies[name]; !ok {
+		return ErrPolicyNotFound
+	}
+	delete(m.policies, name)
+	return nil
+}
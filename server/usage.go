@@ -0,0 +1,121 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrQuotaExceeded is the error returned by a UsageStore when a client has
+// used up its quota for a policy.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Usage is the number of passwords a client has generated for a policy.
+type Usage struct {
+	Policy string `json:"policy"`
+	Client string `json:"client"`
+	Count  int64  `json:"count"`
+}
+
+// UsageStore counts the passwords generated by each client for each policy, for
+// billing, abuse detection and quota enforcement. Clients are identified by a
+// prefix of the hash of their token. Implementations must be safe for
+// concurrent use.
+type UsageStore interface {
+	// Increment adds one to the usage of the policy by the client and returns
+	// the new count. If quota is positive and the usage has already reached
+	// quota, the usage is left unchanged and ErrQuotaExceeded is returned.
+	Increment(ctx context.Context, policy, client string, quota int64) (int64, error)
+
+	// Decrement subtracts one from the usage of the policy by the client, to
+	// give back an increment for a password which was not handed out. The
+	// usage never drops below zero.
+	Decrement(ctx context.Context, policy, client string) error
+
+	// List returns the usage of all clients and policies, sorted by policy and
+	// client.
+	List(ctx context.Context) ([]Usage, error)
+
+	// Reset sets the usage of all clients and policies to zero, for example at
+	// the start of a billing period.
+	Reset(ctx context.Context) error
+}
+
+// MemoryUsageStore is a UsageStore which keeps usage in memory. Usage is lost
+// when the process exits.
+type MemoryUsageStore struct {
+	mu    sync.Mutex
+	usage map[usageKey]int64
+}
+
+// usageKey identifies the usage of a policy by a client.
+type usageKey struct {
+	policy, client string
+}
+
+var _ UsageStore = (*MemoryUsageStore)(nil)
+
+// NewMemoryUsageStore creates a new, empty MemoryUsageStore.
+func NewMemoryUsageStore() *MemoryUsageStore {
+	return &MemoryUsageStore{usage: make(map[usageKey]int64)}
+}
+
+// Increment adds one to the usage of the policy by the client, unless the quota
+// is reached.
+func (m *MemoryUsageStore) Increment(_ context.Context, policy, client string, quota int64) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := usageKey{policy: policy, client: client}
+	n := m.usage[key]
+	if quota > 0 && n >= quota {
+		return n, ErrQuotaExceeded
+	}
+
+	n++
+	m.usage[key] = n
+	return n, nil
+}
+
+// Decrement subtracts one from the usage of the policy by the client.
+func (m *MemoryUsageStore) Decrement(_ context.Context, policy, client string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := usageKey{policy: policy, client: client}
+	if n := m.usage[key]; n > 1 {
+		m.usage[key] = n - 1
+	} else {
+		delete(m.usage, key)
+	}
+	return nil
+}
+
+// List returns the usage of all clients and policies, sorted by policy and
+// client.
+func (m *MemoryUsageStore) List(_ context.Context) ([]Usage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	res := make([]Usage, 0, len(m.usage))
+	for k, n := range m.usage {
+		res = append(res, Usage{Policy: k.policy, Client: k.client, Count: n})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Policy != res[j].Policy {
+			return res[i].Policy < res[j].Policy
+		}
+		return res[i].Client < res[j].Client
+	})
+	return res, nil
+}
+
+// Reset sets the usage of all clients and policies to zero.
+func (m *MemoryUsageStore) Reset(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clear(m.usage)
+	return nil
+}
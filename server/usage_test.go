@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestMemoryUsageStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	m := NewMemoryUsageStore()
+
+	for i := int64(1); i <= 2; i++ {
+		n, err := m.Increment(ctx, "pin", "a", 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != i {
+			t.Errorf("expected count %d, got %d", i, n)
+		}
+	}
+	if _, err := m.Increment(ctx, "pin", "a", 2); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("expected %v to be %v", err, ErrQuotaExceeded)
+	}
+	if _, err := m.Increment(ctx, "pin", "b", 2); err != nil {
+		t.Errorf("expected quota to be per client, got %v", err)
+	}
+	if _, err := m.Increment(ctx, "database", "a", 0); err != nil {
+		t.Errorf("expected zero quota to be unlimited, got %v", err)
+	}
+
+	usage, err := m.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Usage{{"database", "a", 1}, {"pin", "a", 2}, {"pin", "b", 1}}
+	if len(usage) != len(want) {
+		t.Fatalf("expected %+v, got %+v", want, usage)
+	}
+	for i := range want {
+		if usage[i] != want[i] {
+			t.Errorf("expected %+v, got %+v", want[i], usage[i])
+		}
+	}
+
+	if err := m.Decrement(ctx, "pin", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Increment(ctx, "pin", "a", 2); err != nil {
+		t.Errorf("expected decrement to free quota, got %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := m.Decrement(ctx, "database", "a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if usage, _ := m.List(ctx); len(usage) != 2 {
+		t.Errorf("expected usage at zero to be dropped, got %+v", usage)
+	}
+
+	if err := m.Reset(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if usage, _ := m.List(ctx); len(usage) != 0 {
+		t.Errorf("expected usage to be reset, got %+v", usage)
+	}
+}
+
+func TestServerQuota(t *testing.T) {
+	t.Parallel()
+
+	cfg := testConfig(t)
+	cfg.AdminTokenHashes = []string{HashToken(testAdminToken)}
+	cfg.Policies[1].Quota = 2
+	s := testServer(t, cfg)
+
+	for i := 0; i < 2; i++ {
+		if w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"pin"}`); w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body)
+		}
+	}
+	if w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"pin"}`); w.Code != http.StatusTooManyRequests {
+		t.Errorf("expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+
+	w := testRequest(t, s, http.MethodGet, usagePath, testAdminToken, "")
+	var res struct {
+		Usage []Usage `json:"usage"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	want := Usage{Policy: "pin", Client: HashToken(testToken)[:clientIDLength], Count: 2}
+	if len(res.Usage) != 1 || res.Usage[0] != want {
+		t.Errorf("expected usage %+v, got %+v", want, res.Usage)
+	}
+
+	if w := testRequest(t, s, http.MethodDelete, usagePath, testAdminToken, ""); w.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	if w := testRequest(t, s, http.MethodPost, "/v1/generate", testToken, `{"policy":"pin"}`); w.Code != http.StatusOK {
+		t.Errorf("expected quota to be reset, got status %d", w.Code)
+	}
+}
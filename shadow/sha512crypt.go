@@ -0,0 +1,151 @@
+package shadow
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/juev/go-password/password"
+)
+
+// Rounds of sha512-crypt.
+const (
+	DefaultSHA512Rounds = 5000
+	MinSHA512Rounds     = 1000
+	MaxSHA512Rounds     = 999999999
+)
+
+// sha512SaltLength is the length of the salt of sha512-crypt, the longest
+// supported.
+const sha512SaltLength = 16
+
+// sha512Order is the order in which the bytes of the digest are encoded, in
+// groups of three.
+var sha512Order = [...]int{
+	0, 21, 42, 22, 43, 1, 44, 2, 23, 3, 24, 45, 25, 46, 4, 47, 5, 26, 6, 27, 48,
+	28, 49, 7, 50, 8, 29, 9, 30, 51, 31, 52, 10, 53, 11, 32, 12, 33, 54, 34, 55,
+	13, 56, 14, 35, 15, 36, 57, 37, 58, 16, 59, 17, 38, 18, 39, 60, 40, 61, 19,
+	62, 20, 41,
+}
+
+// SHA512Options used to define options for SHA512Crypt.
+type SHA512Options struct {
+	// Rounds is the number of rounds, between MinSHA512Rounds and
+	// MaxSHA512Rounds. The default is DefaultSHA512Rounds, which is left out
+	// of the hash.
+	Rounds int
+
+	// Reader is the source of randomness of the salt. The default is
+	// crypto/rand.Reader.
+	Reader io.Reader
+	_      struct{}
+}
+
+// SHA512Crypt returns the sha512-crypt hash of the password, such as
+// "$6$rounds=10000$salt$hash", with a random salt of 16 characters.
+func SHA512Crypt(pw string, opts SHA512Options) (string, error) {
+	rounds := opts.Rounds
+	if rounds == 0 {
+		rounds = DefaultSHA512Rounds
+	}
+	if rounds < MinSHA512Rounds || rounds > MaxSHA512Rounds {
+		return "", fmt.Errorf("%w: %d", ErrRounds, rounds)
+	}
+
+	idx, err := password.UniformIndices(reader(opts.Reader), len(alphabet), sha512SaltLength)
+	if err != nil {
+		return "", err
+	}
+	salt := make([]byte, len(idx))
+	for i, j := range idx {
+		salt[i] = alphabet[j]
+	}
+	return sha512Crypt([]byte(pw), salt, rounds), nil
+}
+
+// sha512Crypt returns the sha512-crypt hash of the password with the given
+// salt and rounds, as specified by Ulrich Drepper's "Unix crypt using SHA-256
+// and SHA-512".
+func sha512Crypt(pw, salt []byte, rounds int) string {
+	h := sha512.New()
+	h.Write(pw)
+	h.Write(salt)
+	h.Write(pw)
+	alt := h.Sum(nil)
+
+	h.Reset()
+	h.Write(pw)
+	h.Write(salt)
+	i := len(pw)
+	for ; i > sha512.Size; i -= sha512.Size {
+		h.Write(alt)
+	}
+	h.Write(alt[:i])
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			h.Write(alt)
+		} else {
+			h.Write(pw)
+		}
+	}
+	sum := h.Sum(nil)
+
+	h.Reset()
+	for range pw {
+		h.Write(pw)
+	}
+	p := repeat(h.Sum(nil), len(pw))
+
+	h.Reset()
+	for i := 0; i < 16+int(sum[0]); i++ {
+		h.Write(salt)
+	}
+	s := repeat(h.Sum(nil), len(salt))
+
+	for i := 0; i < rounds; i++ {
+		h.Reset()
+		if i&1 != 0 {
+			h.Write(p)
+		} else {
+			h.Write(sum)
+		}
+		if i%3 != 0 {
+			h.Write(s)
+		}
+		if i%7 != 0 {
+			h.Write(p)
+		}
+		if i&1 != 0 {
+			h.Write(sum)
+		} else {
+			h.Write(p)
+		}
+		sum = h.Sum(sum[:0])
+	}
+	clear(p)
+
+	var b strings.Builder
+	b.WriteString("$6$")
+	if rounds != DefaultSHA512Rounds {
+		b.WriteString("rounds=" + strconv.Itoa(rounds) + "$")
+	}
+	b.Write(salt)
+	b.WriteByte('$')
+	for i := 0; i < len(sha512Order); i += 3 {
+		encode24(&b, uint32(sum[sha512Order[i]])<<16|uint32(sum[sha512Order[i+1]])<<8|uint32(sum[sha512Order[i+2]]), 4)
+	}
+	encode24(&b, uint32(sum[63]), 2)
+	return b.String()
+}
+
+// repeat returns the first n bytes of the digest repeated.
+func repeat(digest []byte, n int) []byte {
+	b := make([]byte, 0, n+len(digest))
+	for len(b) < n {
+		b = append(b, digest...)
+	}
+	clear(digest)
+	return b[:n]
+}
@@ -0,0 +1,61 @@
+// Package shadow hashes passwords into the crypt(3) formats of /etc/shadow,
+// so that generated passwords of local Linux accounts can be written by
+// configuration management without shelling out to mkpasswd or chpasswd.
+//
+// Yescrypt is the default of current distributions, such as Debian since 11
+// and Fedora since 35. SHA512Crypt is supported everywhere glibc is.
+//
+//	pw, err := password.Generate(password.Input{Length: 24, Digits: 4, Symbols: 4})
+//	if err != nil {
+//		return err
+//	}
+//	hash, err := shadow.Yescrypt(pw, shadow.YescryptOptions{})
+//	// alice:$y$j9T$...:19700:0:99999:7:::
+package shadow
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// alphabet is the base64 alphabet of crypt(3), in the order of its values.
+const alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+var (
+	// ErrRounds is the error returned when the rounds of sha512-crypt are out
+	// of range.
+	ErrRounds = errors.New("sha512-crypt rounds out of range")
+
+	// ErrCost is the error returned when the cost of yescrypt is out of
+	// range.
+	ErrCost = errors.New("yescrypt cost out of range")
+)
+
+// reader returns r, or crypto/rand.Reader if r is nil.
+func reader(r io.Reader) io.Reader {
+	if r == nil {
+		return rand.Reader
+	}
+	return r
+}
+
+// randomBytes returns n bytes read from r, or from crypto/rand if r is nil.
+func randomBytes(r io.Reader, n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(reader(r), b); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return b, nil
+}
+
+// encode24 appends to b the n characters encoding the low bits of w, least
+// significant first.
+func encode24(b *strings.Builder, w uint32, n int) {
+	for ; n > 0; n-- {
+		b.WriteByte(alphabet[w&0x3f])
+		w >>= 6
+	}
+}
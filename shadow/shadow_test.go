@@ -0,0 +1,101 @@
+package shadow
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// The expected hashes were computed with crypt(3) of libxcrypt.
+
+func TestSHA512Crypt(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pw, salt string
+		rounds   int
+		want     string
+	}{
+		{"password", "saltstring", 5000, "$6$saltstring$adDbXsJjcDlq2662QPgd.tkSOVmnG9Tt3oXl4HR60SusC3AGjirnDenVZp3DGwLwqy6iYKCzannhaX9DR72nN1"},
+		{"password", "saltstring", 10000, "$6$rounds=10000$saltstring$t8jRkZue4ZqkvRTF6Ly63E8QTtoCevHn5vWVdXcomlAvhV8iTse.xlPFOmGov1eQRZLufNVYaEFrHVyRed8gC/"},
+		{"", "abc", 1000, "$6$rounds=1000$abc$noBronbzNMcAtG61/dMHzc1H.fuLjHArF9.wCx8LbkVDMMEuChQCPee28tiPcXHL/CMNpCqo6OPTyoEpoCMsY/"},
+		{strings.Repeat("x", 100), "0123456789abcdef", 5000, "$6$0123456789abcdef$hdKQR3U1ofzUBt70lpJrj9gRNuOHUPP5vMHbJtWeAVw9d3x7kUXPRZHmXi8rquaE8P8IDbMUrlAn6yB.w0e9w1"},
+	}
+
+	for _, tc := range cases {
+		if got := sha512Crypt([]byte(tc.pw), []byte(tc.salt), tc.rounds); got != tc.want {
+			t.Errorf("expected %v to be %v", got, tc.want)
+		}
+	}
+}
+
+func TestYescrypt(t *testing.T) {
+	t.Parallel()
+
+	salt := []byte("0123456789abcdef")
+	cases := []struct {
+		pw   string
+		cost int
+		want string
+	}{
+		{"password", 1, "$y$j75$k2XAnEHBqQ1Ct2aMXFKNa/$m4lwJ4nFEuCl0FFCrU4dJtyuhT0Ai2jNWLnkYlySGEB"},
+		{"", 1, "$y$j75$k2XAnEHBqQ1Ct2aMXFKNa/$Fywp4PbybjVM2qU3oGjZgUNcOBJeOhgvl.Dc1YbWJk7"},
+		{strings.Repeat("x", 100), 2, "$y$j85$k2XAnEHBqQ1Ct2aMXFKNa/$ehizkDcTESrRaaMeTmp5KjTea7yhcTLmkQhJE4uXAW1"},
+		{"password", 3, "$y$j7T$k2XAnEHBqQ1Ct2aMXFKNa/$yY.KGcwM7OktRpgmwZHVC.Bvi8Eqy41vo94G6bu1bhC"},
+		{"password", 5, "$y$j9T$k2XAnEHBqQ1Ct2aMXFKNa/$OVYXzjlkiQpWT/F1CUE0JrvV4phLY8FB.ofDttnrSQ7"},
+	}
+
+	for _, tc := range cases {
+		if got := yescryptCrypt([]byte(tc.pw), salt, tc.cost); got != tc.want {
+			t.Errorf("expected %v to be %v", got, tc.want)
+		}
+	}
+}
+
+func TestSalts(t *testing.T) {
+	t.Parallel()
+
+	sha, err := SHA512Crypt("password", SHA512Options{Rounds: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^\$6\$rounds=1000\$[./0-9A-Za-z]{16}\$[./0-9A-Za-z]{86}$`).MatchString(sha) {
+		t.Errorf("unexpected hash %q", sha)
+	}
+
+	y, err := Yescrypt("password", YescryptOptions{Cost: 1, Reader: bytes.NewReader([]byte("0123456789abcdef"))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "$y$j75$k2XAnEHBqQ1Ct2aMXFKNa/$m4lwJ4nFEuCl0FFCrU4dJtyuhT0Ai2jNWLnkYlySGEB"; y != want {
+		t.Errorf("expected %v to be %v", y, want)
+	}
+
+	other, err := Yescrypt("password", YescryptOptions{Cost: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other[:7] != y[:7] || other[7:29] == y[7:29] {
+		t.Errorf("expected %q to have the parameters and a new salt of %q", other, y)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, rounds := range []int{-1, 999, MaxSHA512Rounds + 1} {
+		if _, err := SHA512Crypt("password", SHA512Options{Rounds: rounds}); !errors.Is(err, ErrRounds) {
+			t.Errorf("expected %v to be %v", err, ErrRounds)
+		}
+	}
+	for _, cost := range []int{-1, MaxYescryptCost + 1} {
+		if _, err := Yescrypt("password", YescryptOptions{Cost: cost}); !errors.Is(err, ErrCost) {
+			t.Errorf("expected %v to be %v", err, ErrCost)
+		}
+	}
+	if _, err := Yescrypt("password", YescryptOptions{Reader: bytes.NewReader(nil)}); err == nil {
+		t.Error("expected an error from an empty reader")
+	}
+}
@@ -0,0 +1,391 @@
+package shadow
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+)
+
+// Costs of yescrypt, as accepted by libxcrypt. Every step doubles the memory
+// and time: cost 5 uses 16 MiB.
+const (
+	DefaultYescryptCost = 5
+	MinYescryptCost     = 1
+	MaxYescryptCost     = 11
+)
+
+// yescryptSaltSize is the size of the salt of yescrypt, in bytes, as chosen by
+// libxcrypt.
+const yescryptSaltSize = 16
+
+// yescryptFlavor is the encoded flavor of the default yescrypt flags of
+// libxcrypt: read-write mode with 6 pwxform rounds, 4-way gather, 2-way
+// simple and 12 KiB S-boxes.
+const yescryptFlavor = 'j'
+
+// Parameters of pwxform matching yescryptFlavor.
+const (
+	pwxSimple = 2
+	pwxGather = 4
+	pwxRounds = 6
+	sWidth    = 8
+
+	pwxWords  = pwxGather * pwxSimple * 2
+	sboxWords = (1 << sWidth) * pwxSimple * 2
+	sMask     = ((1 << sWidth) - 1) * pwxSimple * 8
+)
+
+// YescryptOptions used to define options for Yescrypt.
+type YescryptOptions struct {
+	// Cost is the cost, between MinYescryptCost and MaxYescryptCost. The
+	// default is DefaultYescryptCost, the default of libxcrypt.
+	Cost int
+
+	// Reader is the source of randomness of the salt. The default is
+	// crypto/rand.Reader.
+	Reader io.Reader
+	_      struct{}
+}
+
+// Yescrypt returns the yescrypt hash of the password, such as
+// "$y$j9T$salt$hash", with a random salt of 16 bytes.
+func Yescrypt(pw string, opts YescryptOptions) (string, error) {
+	cost := opts.Cost
+	if cost == 0 {
+		cost = DefaultYescryptCost
+	}
+	if cost < MinYescryptCost || cost > MaxYescryptCost {
+		return "", fmt.Errorf("%w: %d", ErrCost, cost)
+	}
+
+	salt, err := randomBytes(opts.Reader, yescryptSaltSize)
+	if err != nil {
+		return "", err
+	}
+	return yescryptCrypt([]byte(pw), salt, cost), nil
+}
+
+// yescryptCrypt returns the yescrypt hash of the password with the given salt
+// and cost, with the parameters libxcrypt chooses for the cost.
+func yescryptCrypt(pw, salt []byte, cost int) string {
+	r, logN := 32, cost+7
+	if cost <= 2 {
+		r, logN = 8, cost+9
+	}
+	hash := yescrypt(pw, salt, 1<<logN, r)
+
+	var b strings.Builder
+	b.WriteString("$y$")
+	b.WriteByte(yescryptFlavor)
+	b.WriteByte(alphabet[logN-1])
+	b.WriteByte(alphabet[r-1])
+	b.WriteByte('$')
+	encode64(&b, salt)
+	b.WriteByte('$')
+	encode64(&b, hash[:])
+	return b.String()
+}
+
+// encode64 appends the encoding of src used by yescrypt: every three bytes,
+// read as a little-endian number, are encoded least significant bits first.
+func encode64(b *strings.Builder, src []byte) {
+	for i := 0; i < len(src); i += 3 {
+		var w uint32
+		n := min(len(src)-i, 3)
+		for j := 0; j < n; j++ {
+			w |= uint32(src[i+j]) << (8 * j)
+		}
+		encode24(b, w, n+1)
+	}
+}
+
+// yescrypt returns the yescrypt key of the password with the given salt, N and
+// r, with p = 1, t = 0 and the flags of yescryptFlavor. As in the reference
+// implementation, large inputs are first hashed with N/64.
+func yescrypt(pw, salt []byte, n uint64, r int) [sha256.Size]byte {
+	if n >= 0x100 && n*uint64(r) >= 0x20000 {
+		dk := yescryptBody(pw, salt, n>>6, r, true)
+		defer clear(dk[:])
+		pw = dk[:]
+	}
+	return yescryptBody(pw, salt, n, r, false)
+}
+
+// yescryptBody returns the yescrypt key of the password, as specified by the
+// reference implementation of yescrypt.
+func yescryptBody(pw, salt []byte, n uint64, r int, prehash bool) [sha256.Size]byte {
+	key := "yescrypt"
+	if prehash {
+		key = "yescrypt-prehash"
+	}
+	pw = hmacSHA256([]byte(key), pw)
+	defer clear(pw)
+
+	raw := pbkdf2SHA256(pw, salt, 128*r)
+	defer clear(raw)
+	copy(pw, raw)
+
+	b := make([]uint32, 32*r)
+	for i := range b {
+		b[i] = binary.LittleEndian.Uint32(raw[4*i:])
+	}
+	smix(b, r, n, pw)
+	for i, w := range b {
+		binary.LittleEndian.PutUint32(raw[4*i:], w)
+	}
+	clear(b)
+
+	var dk [sha256.Size]byte
+	copy(dk[:], pbkdf2SHA256(pw, raw, sha256.Size))
+	if !prehash {
+		dk = sha256.Sum256(hmacSHA256(dk[:], []byte("Client Key")))
+	}
+	return dk
+}
+
+// pwxform holds the S-boxes and write position of pwxform.
+type pwxform struct {
+	s0, s1, s2 []uint32
+	w          int
+}
+
+// smix mixes the block b with N and r, with p = 1 and t = 0, and updates pw as
+// the reference implementation does.
+func smix(b []uint32, r int, n uint64, pw []byte) {
+	s := 32 * r
+	nloop := (n + 2) / 3
+	nloop += nloop & 1
+
+	xy := make([]uint32, 2*s)
+	v := make([]uint32, uint64(s)*n)
+	sbox := make([]uint32, 3*sboxWords)
+	defer clear(v)
+	defer clear(sbox)
+
+	// The S-boxes are filled with the first 128 bytes of the block.
+	smix1(b[:32], 1, uint64(len(sbox)/32), false, sbox, xy, nil)
+	ctx := &pwxform{s2: sbox[:sboxWords], s1: sbox[sboxWords : 2*sboxWords], s0: sbox[2*sboxWords:]}
+
+	var last [64]byte
+	for i, w := range b[s-16:] {
+		binary.LittleEndian.PutUint32(last[4*i:], w)
+	}
+	copy(pw, hmacSHA256(last[:], pw))
+
+	smix1(b, r, n, true, v, xy, ctx)
+	smix2(b, r, p2floor(n), nloop, v, xy, ctx)
+}
+
+// smix1 fills v with n successive states of the block, as SMix1 of yescrypt.
+func smix1(b []uint32, r int, n uint64, rw bool, v, xy []uint32, ctx *pwxform) {
+	s := 32 * r
+	x, y := xy[:s], xy[s:]
+	shuffle(x, b)
+
+	for i := uint64(0); i < n; i++ {
+		copy(v[i*uint64(s):], x)
+		if rw && i > 1 {
+			j := wrap(integerify(x, r), i)
+			xor(x, v[j*uint64(s):])
+		}
+
+		if ctx != nil {
+			blockmixPwxform(x, ctx, r)
+		} else {
+			blockmixSalsa8(x, y, r)
+		}
+	}
+	unshuffle(b, x)
+}
+
+// smix2 mixes the block with nloop pseudorandom states of v, which it updates,
+// as SMix2 of yescrypt.
+func smix2(b []uint32, r int, n, nloop uint64, v, xy []uint32, ctx *pwxform) {
+	s := 32 * r
+	x := xy[:s]
+	shuffle(x, b)
+
+	for i := uint64(0); i < nloop; i++ {
+		j := integerify(x, r) & (n - 1)
+		vj := v[j*uint64(s) : (j+1)*uint64(s)]
+		xor(x, vj)
+		copy(vj, x)
+		blockmixPwxform(x, ctx, r)
+	}
+	unshuffle(b, x)
+}
+
+// shuffle copies the 64-byte blocks of src to dst in the word order used by
+// the reference implementation for SIMD.
+func shuffle(dst, src []uint32) {
+	for k := 0; k < len(src); k += 16 {
+		for i := 0; i < 16; i++ {
+			dst[k+i] = src[k+i*5%16]
+		}
+	}
+}
+
+// unshuffle reverses shuffle.
+func unshuffle(dst, src []uint32) {
+	for k := 0; k < len(src); k += 16 {
+		for i := 0; i < 16; i++ {
+			dst[k+i*5%16] = src[k+i]
+		}
+	}
+}
+
+// blockmixSalsa8 is the BlockMix of scrypt with Salsa20/8.
+func blockmixSalsa8(b, y []uint32, r int) {
+	var x [16]uint32
+	copy(x[:], b[(2*r-1)*16:])
+	for i := 0; i < 2*r; i++ {
+		xor(x[:], b[i*16:])
+		salsa20(x[:], 8)
+		copy(y[i*16:], x[:])
+	}
+	for i := 0; i < r; i++ {
+		copy(b[i*16:], y[2*i*16:(2*i+1)*16])
+		copy(b[(i+r)*16:], y[(2*i+1)*16:(2*i+2)*16])
+	}
+}
+
+// blockmixPwxform is the BlockMix of yescrypt with pwxform.
+func blockmixPwxform(b []uint32, ctx *pwxform, r int) {
+	var x [pwxWords]uint32
+	r1 := 2 * r
+	copy(x[:], b[(r1-1)*pwxWords:])
+	for i := 0; i < r1; i++ {
+		if r1 > 1 {
+			xor(x[:], b[i*pwxWords:])
+		}
+		ctx.transform(x[:])
+		copy(b[i*pwxWords:], x[:])
+	}
+	salsa20(b[(r1-1)*16:], 2)
+}
+
+// transform applies pwxform to the block.
+func (c *pwxform) transform(b []uint32) {
+	s0, s1, s2, w := c.s0, c.s1, c.s2, c.w
+	for i := 0; i < pwxRounds; i++ {
+		for j := 0; j < pwxGather; j++ {
+			x := b[j*pwxSimple*2 : (j+1)*pwxSimple*2]
+			p0 := s0[(x[0]&sMask)/4:]
+			p1 := s1[(x[1]&sMask)/4:]
+			for k := 0; k < pwxSimple; k++ {
+				v := uint64(x[2*k+1]) * uint64(x[2*k])
+				v += uint64(p0[2*k+1])<<32 | uint64(p0[2*k])
+				v ^= uint64(p1[2*k+1])<<32 | uint64(p1[2*k])
+				x[2*k], x[2*k+1] = uint32(v), uint32(v>>32)
+
+				if i != 0 && i != pwxRounds-1 {
+					s2[w], s2[w+1] = uint32(v), uint32(v>>32)
+					w += 2
+				}
+			}
+		}
+	}
+	c.s0, c.s1, c.s2 = s2, s0, s1
+	c.w = w & (sboxWords - 1)
+}
+
+// salsa20 applies the Salsa20 core with the given rounds to a shuffled block.
+func salsa20(b []uint32, rounds int) {
+	var x [16]uint32
+	for i := range x {
+		x[i*5%16] = b[i]
+	}
+
+	rotl := bits.RotateLeft32
+	for i := 0; i < rounds; i += 2 {
+		x[4] ^= rotl(x[0]+x[12], 7)
+		x[8] ^= rotl(x[4]+x[0], 9)
+		x[12] ^= rotl(x[8]+x[4], 13)
+		x[0] ^= rotl(x[12]+x[8], 18)
+		x[9] ^= rotl(x[5]+x[1], 7)
+		x[13] ^= rotl(x[9]+x[5], 9)
+		x[1] ^= rotl(x[13]+x[9], 13)
+		x[5] ^= rotl(x[1]+x[13], 18)
+		x[14] ^= rotl(x[10]+x[6], 7)
+		x[2] ^= rotl(x[14]+x[10], 9)
+		x[6] ^= rotl(x[2]+x[14], 13)
+		x[10] ^= rotl(x[6]+x[2], 18)
+		x[3] ^= rotl(x[15]+x[11], 7)
+		x[7] ^= rotl(x[3]+x[15], 9)
+		x[11] ^= rotl(x[7]+x[3], 13)
+		x[15] ^= rotl(x[11]+x[7], 18)
+
+		x[1] ^= rotl(x[0]+x[3], 7)
+		x[2] ^= rotl(x[1]+x[0], 9)
+		x[3] ^= rotl(x[2]+x[1], 13)
+		x[0] ^= rotl(x[3]+x[2], 18)
+		x[6] ^= rotl(x[5]+x[4], 7)
+		x[7] ^= rotl(x[6]+x[5], 9)
+		x[4] ^= rotl(x[7]+x[6], 13)
+		x[5] ^= rotl(x[4]+x[7], 18)
+		x[11] ^= rotl(x[10]+x[9], 7)
+		x[8] ^= rotl(x[11]+x[10], 9)
+		x[9] ^= rotl(x[8]+x[11], 13)
+		x[10] ^= rotl(x[9]+x[8], 18)
+		x[12] ^= rotl(x[15]+x[14], 7)
+		x[13] ^= rotl(x[12]+x[15], 9)
+		x[14] ^= rotl(x[13]+x[12], 13)
+		x[15] ^= rotl(x[14]+x[13], 18)
+	}
+
+	for i := range x {
+		b[i] += x[i*5%16]
+	}
+}
+
+// integerify returns the first 64 bits of the last 64-byte block of a shuffled
+// block.
+func integerify(b []uint32, r int) uint64 {
+	x := b[(2*r-1)*16:]
+	return uint64(x[13])<<32 | uint64(x[0])
+}
+
+// wrap returns x modulo the largest power of two not above i, offset so that
+// it indexes the states most recently written.
+func wrap(x, i uint64) uint64 {
+	n := p2floor(i)
+	return x&(n-1) + (i - n)
+}
+
+// p2floor returns the largest power of two not above x.
+func p2floor(x uint64) uint64 {
+	return 1 << (63 - bits.LeadingZeros64(x))
+}
+
+// xor sets dst to dst xor src.
+func xor(dst, src []uint32) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of msg with the given key.
+func hmacSHA256(key, msg []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(msg)
+	return h.Sum(nil)
+}
+
+// pbkdf2SHA256 returns the key of the given size derived from the password and
+// salt with PBKDF2-HMAC-SHA256 and a single iteration, as specified by RFC
+// 8018.
+func pbkdf2SHA256(pw, salt []byte, size int) []byte {
+	prf := hmac.New(sha256.New, pw)
+	key := make([]byte, 0, size+sha256.Size)
+	for block := uint32(1); len(key) < size; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, block))
+		key = prf.Sum(key)
+	}
+	return key[:size]
+}
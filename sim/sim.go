@@ -0,0 +1,157 @@
+// Package sim generates numeric codes which are entered through SIM toolkits
+// and handset keypads, such as SIM PINs and PUKs, or carrier service codes.
+// The constraints of the code, which vary between carriers, are supplied by
+// the caller and checked by Validate.
+//
+//	code, err := sim.Generate(sim.PIN)
+//	if err != nil {
+//		return err
+//	}
+package sim
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/juev/go-password/password"
+)
+
+// maxAttempts is the maximum number of codes generated before giving up when
+// they violate the constraints.
+const maxAttempts = 1000
+
+var (
+	// ErrInvalidConstraints is the error returned when the constraints cannot
+	// be satisfied by any code.
+	ErrInvalidConstraints = errors.New("invalid code constraints")
+
+	// ErrLength is the error returned when a code is too short or too long.
+	ErrLength = errors.New("code length is out of range")
+
+	// ErrNotNumeric is the error returned when a code contains a character
+	// which is not a digit.
+	ErrNotNumeric = errors.New("code must only contain digits")
+
+	// ErrLeadingZero is the error returned when a code starts with a zero and
+	// the constraints forbid it.
+	ErrLeadingZero = errors.New("code must not start with a zero")
+
+	// ErrRepeatedDigits is the error returned when a code has more identical
+	// digits in a row than the constraints allow.
+	ErrRepeatedDigits = errors.New("code has too many repeated digits in a row")
+
+	// ErrForbiddenCode is the error returned when a code is one of the
+	// forbidden codes of the constraints.
+	ErrForbiddenCode = errors.New("code is forbidden")
+
+	// ErrAttemptsExceeded is the error returned when no code satisfying the
+	// constraints was generated within the maximum number of attempts.
+	ErrAttemptsExceeded = errors.New("no code satisfying the constraints was generated")
+)
+
+// Constraints are the carrier-specific constraints of a code.
+type Constraints struct {
+	// Length is the length of generated codes. The default is MinLength.
+	Length int
+
+	// MinLength and MaxLength are the range of lengths accepted by Validate.
+	MinLength int
+	MaxLength int
+
+	// NoLeadingZero forbids codes starting with a zero, for systems which
+	// store codes as integers.
+	NoLeadingZero bool
+
+	// MaxRepeat, if positive, is the maximum number of identical digits in a
+	// row.
+	MaxRepeat int
+
+	// Forbidden is the list of codes the carrier rejects, such as the default
+	// PIN of its cards.
+	Forbidden []string
+	_         struct{}
+}
+
+var (
+	// PIN is the constraints of a SIM PIN (CHV1), 4 to 8 digits. Generated
+	// PINs have 4 digits and are never the usual factory defaults.
+	PIN = Constraints{
+		Length:    4,
+		MinLength: 4,
+		MaxLength: 8,
+		Forbidden: []string{"0000", "1111", "1234"},
+	}
+
+	// PUK is the constraints of a SIM PUK, exactly 8 digits.
+	PUK = Constraints{
+		Length:    8,
+		MinLength: 8,
+		MaxLength: 8,
+	}
+)
+
+// Generate generates a numeric code satisfying the given constraints.
+func Generate(c Constraints) (string, error) {
+	n := c.length()
+	if c.MinLength <= 0 || c.MaxLength < c.MinLength || n < c.MinLength || n > c.MaxLength {
+		return "", fmt.Errorf("%w: length %d not in [%d, %d]", ErrInvalidConstraints, n, c.MinLength, c.MaxLength)
+	}
+	for i := 0; i < maxAttempts; i++ {
+		res, err := password.Generate(password.Input{
+			Length:      n,
+			Digits:      n,
+			AllowRepeat: true,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to generate code: %w", err)
+		}
+
+		if Validate(res, c) == nil {
+			return res, nil
+		}
+	}
+	return "", ErrAttemptsExceeded
+}
+
+// Validate checks that the code satisfies the given constraints.
+func Validate(code string, c Constraints) error {
+	if len(code) < c.MinLength || (c.MaxLength > 0 && len(code) > c.MaxLength) {
+		return ErrLength
+	}
+	if strings.Trim(code, password.Digits) != "" {
+		return ErrNotNumeric
+	}
+	if c.NoLeadingZero && strings.HasPrefix(code, "0") {
+		return ErrLeadingZero
+	}
+
+	if c.MaxRepeat > 0 {
+		run := 0
+		for i := 0; i < len(code); i++ {
+			if i > 0 && code[i] == code[i-1] {
+				run++
+			} else {
+				run = 1
+			}
+			if run > c.MaxRepeat {
+				return ErrRepeatedDigits
+			}
+		}
+	}
+
+	for _, f := range c.Forbidden {
+		if code == f {
+			return ErrForbiddenCode
+		}
+	}
+	return nil
+}
+
+// length returns the length of generated codes, or the default.
+func (c Constraints) length() int {
+	if c.Length == 0 {
+		return c.MinLength
+	}
+	return c.Length
+}
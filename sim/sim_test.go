@@ -0,0 +1,95 @@
+package sim
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("presets", func(t *testing.T) {
+		t.Parallel()
+
+		for _, c := range []Constraints{PIN, PUK} {
+			code, err := Generate(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(code) != c.Length {
+				t.Errorf("expected %d digits, got %q", c.Length, code)
+			}
+			if err := Validate(code, c); err != nil {
+				t.Errorf("expected %q to be valid: %s", code, err)
+			}
+		}
+	})
+
+	t.Run("no leading zero", func(t *testing.T) {
+		t.Parallel()
+
+		c := Constraints{MinLength: 2, MaxLength: 2, NoLeadingZero: true, MaxRepeat: 1}
+		for i := 0; i < 200; i++ {
+			code, err := Generate(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if code[0] == '0' || code[0] == code[1] {
+				t.Fatalf("unexpected code %q", code)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Generate(Constraints{Length: 10, MinLength: 4, MaxLength: 8})
+		if !errors.Is(err, ErrInvalidConstraints) {
+			t.Errorf("expected %v to be %v", err, ErrInvalidConstraints)
+		}
+	})
+
+	t.Run("unsatisfiable", func(t *testing.T) {
+		t.Parallel()
+
+		c := Constraints{MinLength: 1, MaxLength: 1, NoLeadingZero: true}
+		for i := 1; i <= 9; i++ {
+			c.Forbidden = append(c.Forbidden, string(rune('0'+i)))
+		}
+		if _, err := Generate(c); !errors.Is(err, ErrAttemptsExceeded) {
+			t.Errorf("expected %v to be %v", err, ErrAttemptsExceeded)
+		}
+	})
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		code string
+		c    Constraints
+		err  error
+	}{
+		{"valid", "4821", PIN, nil},
+		{"short", "482", PIN, ErrLength},
+		{"long", "482193756", PIN, ErrLength},
+		{"letters", "48a1", PIN, ErrNotNumeric},
+		{"forbidden", "1234", PIN, ErrForbiddenCode},
+		{"leading zero", "0482", Constraints{MinLength: 4, MaxLength: 4, NoLeadingZero: true}, ErrLeadingZero},
+		{"repeat", "4882", Constraints{MinLength: 4, MaxLength: 4, MaxRepeat: 1}, ErrRepeatedDigits},
+		{"repeat allowed", "4882", Constraints{MinLength: 4, MaxLength: 4, MaxRepeat: 2}, nil},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := Validate(tc.code, tc.c); !errors.Is(err, tc.err) {
+				t.Errorf("expected %v to be %v", err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package site
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RegexpRule returns a rule which applies f to the submatches of the first
+// match of the case-insensitive pattern in the message.
+func RegexpRule(pattern string, f func(m []string, c *Constraints)) Rule {
+	re := regexp.MustCompile("(?i)" + pattern)
+	return RuleFunc(func(msg string, c *Constraints) bool {
+		m := re.FindStringSubmatch(msg)
+		if m == nil {
+			return false
+		}
+		f(m, c)
+		return true
+	})
+}
+
+// count matches a number written in digits or as a small English word.
+const count = `(\d+|an?|one|two|three|four|five|six|seven|eight|nine|ten)`
+
+// lengthEnd matches what follows a number which is a password length, so that
+// "max 16 chars" is a length but "max 3 repeated characters" is not.
+const lengthEnd = `(?:\s*(?:char|long)|\s*$|[.,;)])`
+
+// parseCount parses a number matched by count.
+func parseCount(s string) int {
+	switch strings.ToLower(s) {
+	case "a", "an", "one":
+		return 1
+	case "two":
+		return 2
+	case "three":
+		return 3
+	case "four":
+		return 4
+	case "five":
+		return 5
+	case "six":
+		return 6
+	case "seven":
+		return 7
+	case "eight":
+		return 8
+	case "nine":
+		return 9
+	case "ten":
+		return 10
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// DefaultRules returns rules understanding common English rejection messages,
+// such as "no symbols allowed", "max 16 chars", "must be between 8 and 20
+// characters", "must contain at least one number", "must include an uppercase
+// letter", "no repeated characters" and "allowed special characters are !@#".
+func DefaultRules() []Rule {
+	return []Rule{
+		// Length bounds.
+		RegexpRule(`between (\d+) and (\d+) char`, func(m []string, c *Constraints) {
+			c.MinLength, c.MaxLength = parseCount(m[1]), parseCount(m[2])
+		}),
+		RegexpRule(`(\d+)\s*(?:-|to)\s*(\d+) char`, func(m []string, c *Constraints) {
+			c.MinLength, c.MaxLength = parseCount(m[1]), parseCount(m[2])
+		}),
+		RegexpRule(`(?:max(?:imum)?(?: length)?(?: of| is)?|at most|no more than|up to|not exceed) (\d+)`+lengthEnd, func(m []string, c *Constraints) {
+			c.MaxLength = parseCount(m[1])
+		}),
+		RegexpRule(`(?:min(?:imum)?(?: length)?(?: of| is)?|at least|no fewer than) (\d+)`+lengthEnd, func(m []string, c *Constraints) {
+			c.MinLength = parseCount(m[1])
+		}),
+
+		// Symbols.
+		RegexpRule(`(?:no|not allow|don't allow|cannot contain|can't contain|must not contain) (?:any )?(?:special|symbol|punctuation)|(?:special characters?|symbols?) (?:are |is )?not (?:allowed|permitted|supported)|only (?:contain |use |include )?(?:letters and (?:numbers|digits)|alphanumeric)`, func(_ []string, c *Constraints) {
+			c.Input.Symbols, c.MinSymbols, c.Symbols = 0, 0, ""
+		}),
+		RegexpRule(`(?:allowed|permitted|valid|supported) (?:special characters|symbols)(?: are| is)?:?\s*(\S+)`, func(m []string, c *Constraints) {
+			c.Symbols = m[1]
+		}),
+		RegexpRule(`at least `+count+` (?:special|symbol|non-alphanumeric)|(?:contain|include|have) `+count+` (?:special|symbol|non-alphanumeric)`, func(m []string, c *Constraints) {
+			c.MinSymbols = max(c.MinSymbols, parseCount(m[1]+m[2]))
+		}),
+
+		// Digits.
+		RegexpRule(`at least `+count+` (?:number|digit|numeric)|(?:contain|include|have) `+count+` (?:number|digit|numeric)`, func(m []string, c *Constraints) {
+			c.MinDigits = max(c.MinDigits, parseCount(m[1]+m[2]))
+		}),
+
+		// Letters.
+		RegexpRule(`(?:no|not allow|cannot contain|must not contain) (?:any )?(?:upper ?case|capital)|lower ?case only|only lower ?case`, func(_ []string, c *Constraints) {
+			c.Input.NoUpper = true
+		}),
+		RegexpRule(`(?:contain|include|have|at least) (?:an?|one|1) (?:upper ?case|capital)`, func(_ []string, c *Constraints) {
+			c.Input.NoUpper = false
+		}),
+
+		// Repetition.
+		RegexpRule(`(?:no|not allow|cannot contain|must not contain) (?:any )?(?:repeat|repeated|repeating|duplicate)`, func(_ []string, c *Constraints) {
+			c.Input.AllowRepeat = false
+		}),
+	}
+}
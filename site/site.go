@@ -0,0 +1,183 @@
+// Package site learns the password constraints of a site from the messages it
+// rejects passwords with, so that automated account creation converges on a
+// password the site accepts.
+//
+//	s := site.NewSolver(password.Input{Length: 32, Digits: 6, Symbols: 6})
+//	for {
+//		pw, err := s.Generate()
+//		...
+//		msg, ok := submit(pw)
+//		if ok {
+//			break
+//		}
+//		if err := s.Reject(msg); err != nil {
+//			return err // the message was not understood
+//		}
+//	}
+//
+// Messages are interpreted by rules. DefaultRules understands common English
+// phrasings; sites with unusual wording can add their own rules.
+package site
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/juev/go-password/password"
+)
+
+var (
+	// ErrNoRuleMatched is the error returned by Solver.Reject when no rule
+	// understood the rejection message.
+	ErrNoRuleMatched = errors.New("no rule matched the rejection message")
+
+	// ErrUnsatisfiable is the error returned when the learned constraints
+	// cannot be satisfied by any password.
+	ErrUnsatisfiable = errors.New("site constraints cannot be satisfied")
+)
+
+// Constraints are the password requirements of a site as learned so far.
+type Constraints struct {
+	Input password.Input
+
+	// MinLength and MaxLength bound Input.Length once the site revealed them.
+	// A zero value means no bound is known.
+	MinLength int
+	MaxLength int
+
+	// MinDigits and MinSymbols are the numbers of digits and symbols the site
+	// requires at least.
+	MinDigits  int
+	MinSymbols int
+
+	// Symbols, if set, are the only symbols the site accepts.
+	Symbols string
+}
+
+// Generator returns the generator satisfying the character constraints.
+func (c Constraints) Generator() password.Generator {
+	g := password.NewGenerator()
+	if c.Symbols != "" {
+		g = g.WithSymbols(c.Symbols)
+	}
+	return g
+}
+
+// Rule interprets rejection messages.
+type Rule interface {
+	// Apply updates the constraints if it understands the message, and
+	// reports whether it did.
+	Apply(msg string, c *Constraints) bool
+}
+
+// RuleFunc is an adapter to allow the use of ordinary functions as rules.
+type RuleFunc func(msg string, c *Constraints) bool
+
+// Apply calls f(msg, c).
+func (f RuleFunc) Apply(msg string, c *Constraints) bool {
+	return f(msg, c)
+}
+
+// Solver adjusts password requirements after rejections. It is not safe for
+// concurrent use.
+type Solver struct {
+	rules []Rule
+	c     Constraints
+}
+
+// NewSolver creates a new Solver starting from the given input. Without rules,
+// DefaultRules are used.
+func NewSolver(initial password.Input, rules ...Rule) *Solver {
+	if len(rules) == 0 {
+		rules = DefaultRules()
+	}
+	return &Solver{
+		rules: append([]Rule(nil), rules...),
+		c:     Constraints{Input: initial},
+	}
+}
+
+// Constraints returns the constraints learned so far.
+func (s *Solver) Constraints() Constraints {
+	return s.c
+}
+
+// Input returns the current password requirements.
+func (s *Solver) Input() password.Input {
+	return s.c.Input
+}
+
+// Generator returns the generator satisfying the learned character
+// constraints.
+func (s *Solver) Generator() password.Generator {
+	return s.c.Generator()
+}
+
+// Generate generates a password satisfying the learned constraints.
+func (s *Solver) Generate() (string, error) {
+	res, err := s.Generator().Generate(s.c.Input)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return res, nil
+}
+
+// Reject learns from the message a site rejected a password with. Every rule
+// which understands the message is applied. The constraints are unchanged if
+// an error is returned.
+func (s *Solver) Reject(msg string) error {
+	c := s.c
+	matched := false
+	for _, rule := range s.rules {
+		if rule.Apply(msg, &c) {
+			matched = true
+		}
+	}
+	if !matched {
+		return fmt.Errorf("%w: %q", ErrNoRuleMatched, msg)
+	}
+
+	if err := c.fit(); err != nil {
+		return err
+	}
+	if _, err := c.Generator().Entropy(c.Input); err != nil {
+		return fmt.Errorf("%w: %w", ErrUnsatisfiable, err)
+	}
+
+	s.c = c
+	return nil
+}
+
+// fit adjusts the input to the known bounds. The number of digits and symbols
+// is raised to the required minimums and otherwise shrunk to fit the length,
+// keeping at least one letter.
+func (c *Constraints) fit() error {
+	if c.MinLength > 0 && c.MaxLength > 0 && c.MinLength > c.MaxLength {
+		return fmt.Errorf("%w: minimum length %d exceeds maximum length %d", ErrUnsatisfiable, c.MinLength, c.MaxLength)
+	}
+
+	in := &c.Input
+	if c.MaxLength > 0 && in.Length > c.MaxLength {
+		in.Length = c.MaxLength
+	}
+	if c.MinLength > 0 && in.Length < c.MinLength {
+		in.Length = c.MinLength
+	}
+
+	in.Digits = max(in.Digits, c.MinDigits)
+	in.Symbols = max(in.Symbols, c.MinSymbols)
+	if c.Symbols != "" && !in.AllowRepeat {
+		in.Symbols = min(in.Symbols, len(c.Symbols))
+	}
+	for in.Digits+in.Symbols >= in.Length {
+		switch {
+		case in.Symbols > c.MinSymbols && (in.Symbols >= in.Digits || in.Digits <= c.MinDigits):
+			in.Symbols--
+		case in.Digits > c.MinDigits:
+			in.Digits--
+		default:
+			return fmt.Errorf("%w: %d digits and %d symbols do not fit in %d characters", ErrUnsatisfiable, in.Digits, in.Symbols, in.Length)
+		}
+	}
+	return nil
+}
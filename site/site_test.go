@@ -0,0 +1,129 @@
+package site
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/juev/go-password/password"
+)
+
+func TestSolverReject(t *testing.T) {
+	t.Parallel()
+
+	initial := password.Input{Length: 32, Digits: 6, Symbols: 6}
+
+	cases := []struct {
+		msg  string
+		want password.Input
+	}{
+		{"No symbols allowed", password.Input{Length: 32, Digits: 6}},
+		{"Special characters are not permitted.", password.Input{Length: 32, Digits: 6}},
+		{"Password may only contain letters and numbers", password.Input{Length: 32, Digits: 6}},
+		{"max 16 chars", password.Input{Length: 16, Digits: 6, Symbols: 6}},
+		{"Password must be at most 12 characters long", password.Input{Length: 12, Digits: 6, Symbols: 5}},
+		{"Must be between 8 and 10 characters", password.Input{Length: 10, Digits: 5, Symbols: 4}},
+		{"Use 6-8 characters", password.Input{Length: 8, Digits: 4, Symbols: 3}},
+		{"Your password must contain at least ten digits", password.Input{Length: 32, Digits: 10, Symbols: 6}},
+		{"Include at least 8 special characters", password.Input{Length: 32, Digits: 6, Symbols: 8}},
+		{"Passwords must not contain uppercase letters", password.Input{Length: 32, Digits: 6, Symbols: 6, NoUpper: true}},
+		{"Allowed special characters are: !@#", password.Input{Length: 32, Digits: 6, Symbols: 3}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.msg, func(t *testing.T) {
+			t.Parallel()
+
+			s := NewSolver(initial)
+			if err := s.Reject(tc.msg); err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Input(); got != tc.want {
+				t.Errorf("expected %+v, got %+v", tc.want, got)
+			}
+
+			pw, err := s.Generate()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pw) != tc.want.Length {
+				t.Errorf("expected %d characters, got %q", tc.want.Length, pw)
+			}
+		})
+	}
+}
+
+func TestSolverConverges(t *testing.T) {
+	t.Parallel()
+
+	// site accepts 8 to 12 characters with at least two digits and only the
+	// symbols ! and ?.
+	forbidden := strings.NewReplacer("!", "", "?", "").Replace(password.Symbols)
+	site := func(pw string) string {
+		switch {
+		case len(pw) > 12:
+			return "Password must be no more than 12 characters."
+		case strings.ContainsAny(pw, forbidden):
+			return "Allowed symbols: !?"
+		case strings.IndexAny(pw, password.Digits) == -1:
+			return "Password must contain at least two numbers"
+		}
+		return ""
+	}
+
+	s := NewSolver(password.Input{Length: 24, Symbols: 4, AllowRepeat: true})
+	for i := 0; ; i++ {
+		if i == 10 {
+			t.Fatalf("did not converge, constraints %+v", s.Constraints())
+		}
+
+		pw, err := s.Generate()
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg := site(pw)
+		if msg == "" {
+			break
+		}
+		if err := s.Reject(msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := s.Constraints()
+	if c.MaxLength != 12 || c.Symbols != "!?" || c.MinDigits != 2 {
+		t.Errorf("unexpected constraints %+v", c)
+	}
+}
+
+func TestSolverErrors(t *testing.T) {
+	t.Parallel()
+
+	s := NewSolver(password.Input{Length: 16, Digits: 4})
+	if err := s.Reject("computer says no"); !errors.Is(err, ErrNoRuleMatched) {
+		t.Errorf("expected %v to be %v", err, ErrNoRuleMatched)
+	}
+
+	if err := s.Reject("max 4 characters"); err != nil {
+		t.Fatal(err)
+	}
+	before := s.Input()
+	if err := s.Reject("must contain at least 5 numbers"); !errors.Is(err, ErrUnsatisfiable) {
+		t.Errorf("expected %v to be %v", err, ErrUnsatisfiable)
+	}
+	if s.Input() != before {
+		t.Errorf("expected constraints to be unchanged after an error, got %+v", s.Input())
+	}
+
+	custom := NewSolver(password.Input{Length: 16}, RegexpRule(`zu lang`, func(_ []string, c *Constraints) {
+		c.MaxLength = 8
+	}))
+	if err := custom.Reject("Das Passwort ist zu lang"); err != nil {
+		t.Fatal(err)
+	}
+	if custom.Input().Length != 8 {
+		t.Errorf("expected custom rule to apply, got %+v", custom.Input())
+	}
+}
@@ -0,0 +1,188 @@
+// Package smartcard generates PIN and PUK pairs for provisioning PIV and
+// OpenPGP smart cards. Both codes are numeric, so they can be entered on PIN
+// pads, and codes which are trivial to guess, such as 111111 or 123456, are
+// never generated.
+//
+//	p, err := smartcard.GeneratePINPUK(smartcard.PIVPINLength, smartcard.PIVPUKLength)
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(p.PIN, p.PUK)
+package smartcard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/juev/go-password/password"
+)
+
+const (
+	// MinPINLength is the minimum length of a PIN, as required by PIV and the
+	// OpenPGP card user PIN.
+	MinPINLength = 6
+
+	// MinPUKLength is the minimum length of a PUK, as required by PIV and the
+	// OpenPGP card admin PIN.
+	MinPUKLength = 8
+
+	// MaxLength is the maximum length of a PIN or PUK. PIV cards accept at
+	// most 8 digits, OpenPGP cards usually more.
+	MaxLength = 127
+
+	// PIVPINLength is the PIN length used for PIV cards.
+	PIVPINLength = 6
+
+	// PIVPUKLength is the PUK length of PIV cards.
+	PIVPUKLength = 8
+)
+
+// maxAttempts is the maximum number of codes generated before giving up when
+// they are trivial.
+const maxAttempts = 1000
+
+var (
+	// ErrPINLength is the error returned when the PIN length is out of range.
+	ErrPINLength = errors.New("PIN length must be between 6 and 127 digits")
+
+	// ErrPUKLength is the error returned when the PUK length is out of range.
+	ErrPUKLength = errors.New("PUK length must be between 8 and 127 digits")
+
+	// ErrNotNumeric is the error returned when a PIN or PUK contains a
+	// character which is not a digit.
+	ErrNotNumeric = errors.New("code must only contain digits")
+
+	// ErrTrivialCode is the error returned when a PIN or PUK is a repeated
+	// digit or an ascending or descending sequence.
+	ErrTrivialCode = errors.New("code must not be a repeated digit or a sequence")
+
+	// ErrPUKMatchesPIN is the error returned when the PUK equals the PIN or
+	// starts with it.
+	ErrPUKMatchesPIN = errors.New("PUK must not start with the PIN")
+
+	// ErrAttemptsExceeded is the error returned when no non-trivial pair was
+	// generated within the maximum number of attempts.
+	ErrAttemptsExceeded = errors.New("no acceptable PIN and PUK were generated")
+)
+
+// Options used to define options for GeneratePINPUKWith.
+type Options struct {
+	// Generator is the generator of the codes. The default is
+	// password.NewGenerator(). Its digits are set to 0-9.
+	Generator *password.Generator
+	_         struct{}
+}
+
+// PINPUK is a PIN and the PUK unblocking it.
+type PINPUK struct {
+	PIN string
+	PUK string
+}
+
+// GeneratePINPUK generates a numeric PIN of pinLen digits and a numeric PUK of
+// pukLen digits which pass Validate.
+func GeneratePINPUK(pinLen, pukLen int) (PINPUK, error) {
+	return GeneratePINPUKWith(pinLen, pukLen, Options{})
+}
+
+// GeneratePINPUKWith is GeneratePINPUK with options, such as the Generator of
+// the codes.
+func GeneratePINPUKWith(pinLen, pukLen int, opts Options) (PINPUK, error) {
+	if pinLen < MinPINLength || pinLen > MaxLength {
+		return PINPUK{}, ErrPINLength
+	}
+	if pukLen < MinPUKLength || pukLen > MaxLength {
+		return PINPUK{}, ErrPUKLength
+	}
+
+	gen := password.NewGenerator()
+	if opts.Generator != nil {
+		gen = *opts.Generator
+	}
+	gen = gen.WithDigits(password.Digits)
+
+	for i := 0; i < maxAttempts; i++ {
+		pin, err := generateCode(gen, pinLen)
+		if err != nil {
+			return PINPUK{}, err
+		}
+		puk, err := generateCode(gen, pukLen)
+		if err != nil {
+			return PINPUK{}, err
+		}
+
+		p := PINPUK{PIN: pin, PUK: puk}
+		if p.Validate() == nil {
+			return p, nil
+		}
+	}
+	return PINPUK{}, ErrAttemptsExceeded
+}
+
+// Validate checks that the PIN and PUK have valid lengths, are numeric and
+// non-trivial, and that the PUK does not start with the PIN.
+func (p PINPUK) Validate() error {
+	if len(p.PIN) < MinPINLength || len(p.PIN) > MaxLength {
+		return ErrPINLength
+	}
+	if len(p.PUK) < MinPUKLength || len(p.PUK) > MaxLength {
+		return ErrPUKLength
+	}
+	if err := validateCode(p.PIN); err != nil {
+		return fmt.Errorf("invalid PIN: %w", err)
+	}
+	if err := validateCode(p.PUK); err != nil {
+		return fmt.Errorf("invalid PUK: %w", err)
+	}
+	if strings.HasPrefix(p.PUK, p.PIN) {
+		return ErrPUKMatchesPIN
+	}
+	return nil
+}
+
+// generateCode generates a numeric code of n digits with the Generator.
+func generateCode(gen password.Generator, n int) (string, error) {
+	res, err := gen.Generate(password.Input{
+		Length:      n,
+		Digits:      n,
+		AllowRepeat: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to generate code: %w", err)
+	}
+	return res, nil
+}
+
+// validateCode checks that s is numeric and not trivial.
+func validateCode(s string) error {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return ErrNotNumeric
+		}
+	}
+	if trivial(s) {
+		return ErrTrivialCode
+	}
+	return nil
+}
+
+// trivial reports whether s is a single repeated digit, or a sequence in
+// which every digit is one more or one less than the previous one, wrapping
+// around from 9 to 0.
+func trivial(s string) bool {
+	if len(s) < 2 {
+		return true
+	}
+
+	step := (int(s[1]) - int(s[0]) + 10) % 10
+	if step != 0 && step != 1 && step != 9 {
+		return false
+	}
+	for i := 2; i < len(s); i++ {
+		if (int(s[i])-int(s[i-1])+10)%10 != step {
+			return false
+		}
+	}
+	return true
+}
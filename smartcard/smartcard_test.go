@@ -0,0 +1,126 @@
+package smartcard
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/juev/go-password/password"
+)
+
+func TestGeneratePINPUK(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 100; i++ {
+		p, err := GeneratePINPUK(PIVPINLength, PIVPUKLength)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p.PIN) != PIVPINLength || len(p.PUK) != PIVPUKLength {
+			t.Fatalf("unexpected lengths %+v", p)
+		}
+		if strings.Trim(p.PIN+p.PUK, password.Digits) != "" {
+			t.Fatalf("expected only digits, got %+v", p)
+		}
+		if err := p.Validate(); err != nil {
+			t.Fatalf("expected %+v to be valid: %s", p, err)
+		}
+	}
+
+	if _, err := GeneratePINPUK(4, 8); !errors.Is(err, ErrPINLength) {
+		t.Errorf("expected %v to be %v", err, ErrPINLength)
+	}
+	if _, err := GeneratePINPUK(6, 6); !errors.Is(err, ErrPUKLength) {
+		t.Errorf("expected %v to be %v", err, ErrPUKLength)
+	}
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+	return n, err
+}
+
+func TestGeneratePINPUKWith(t *testing.T) {
+	t.Parallel()
+
+	generate := func() (PINPUK, int) {
+		r := &countingReader{r: password.NewDerivedReader([]byte("key"), "smartcard")}
+		g := password.NewGenerator().WithReader(r)
+		p, err := GeneratePINPUKWith(PIVPINLength, PIVPUKLength, Options{Generator: &g})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p, r.n
+	}
+
+	p, n := generate()
+	if n == 0 {
+		t.Error("expected the generator reader to be used")
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("expected %+v to be valid: %s", p, err)
+	}
+	if q, _ := generate(); q != p {
+		t.Errorf("expected %+v to be %+v", q, p)
+	}
+
+	g := password.NewGenerator().WithReader(iotest.ErrReader(io.ErrUnexpectedEOF))
+	if _, err := GeneratePINPUKWith(PIVPINLength, PIVPUKLength, Options{Generator: &g}); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected %v to be %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestGeneratePINPUKLongPIN(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 100; i++ {
+		p, err := GeneratePINPUK(10, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p.PIN) != 10 || len(p.PUK) != 8 {
+			t.Fatalf("unexpected lengths %+v", p)
+		}
+	}
+}
+
+func TestPINPUK_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		p    PINPUK
+		err  error
+	}{
+		{"valid", PINPUK{"493817", "20586413"}, nil},
+		{"short pin", PINPUK{"4938", "20586413"}, ErrPINLength},
+		{"short puk", PINPUK{"493817", "205864"}, ErrPUKLength},
+		{"letters", PINPUK{"49381a", "20586413"}, ErrNotNumeric},
+		{"repeated", PINPUK{"111111", "20586413"}, ErrTrivialCode},
+		{"ascending", PINPUK{"789012", "20586413"}, ErrTrivialCode},
+		{"descending", PINPUK{"493817", "87654321"}, ErrTrivialCode},
+		{"puk starts with pin", PINPUK{"493817", "49381766"}, ErrPUKMatchesPIN},
+		{"pin longer than puk", PINPUK{"4938176605", "49381766"}, nil},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := tc.p.Validate(); !errors.Is(err, tc.err) {
+				t.Errorf("expected %v to be %v", err, tc.err)
+			}
+		})
+	}
+}
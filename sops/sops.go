@@ -0,0 +1,251 @@
+// Package sops writes generated credentials as YAML documents encrypted in the
+// format of SOPS, so that GitOps repositories can store them and deployments
+// decrypt them with the sops tool or its integrations, such as Flux and the
+// helm-secrets plugin.
+//
+// Every value is encrypted with AES-256-GCM under a random data key, which is
+// wrapped with every given KeyWrapper: keywrap/awskms, keywrap/gcpkms and
+// keywrap/transit are recorded as the kms, gcp_kms and hc_vault keys of
+// SOPS. Any one of them can decrypt the document.
+//
+//	pw, err := password.Generate(password.Input{Length: 32, Digits: 6, Symbols: 6})
+//	if err != nil {
+//		return err
+//	}
+//	doc, err := sops.Encrypt(ctx, map[string]string{
+//		"username_unencrypted": "app",
+//		"password":             pw,
+//	}, []keywrap.KeyWrapper{kms}, sops.Options{})
+package sops
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juev/go-password/keywrap"
+	"github.com/juev/go-password/keywrap/awskms"
+	"github.com/juev/go-password/keywrap/gcpkms"
+	"github.com/juev/go-password/keywrap/transit"
+)
+
+// UnencryptedSuffix is the suffix of the keys whose values SOPS leaves in
+// plain text.
+const UnencryptedSuffix = "_unencrypted"
+
+// Version is the SOPS version recorded in documents.
+const Version = "3.8.1"
+
+const (
+	// dataKeySize is the size of the data key, in bytes.
+	dataKeySize = 32
+
+	// nonceSize is the size of the GCM nonces of SOPS, in bytes.
+	nonceSize = 32
+)
+
+var (
+	// ErrNoKeyWrappers is the error returned when no KeyWrapper is given, so
+	// that the document could never be decrypted.
+	ErrNoKeyWrappers = errors.New("no key wrappers")
+
+	// ErrUnsupportedKeyWrapper is the error returned for a KeyWrapper which
+	// SOPS cannot use.
+	ErrUnsupportedKeyWrapper = errors.New("key wrapper not supported by SOPS")
+
+	// ErrInvalidKey is the error returned for an empty key.
+	ErrInvalidKey = errors.New("invalid key")
+)
+
+// plainKey matches the keys written without quotes, unless they are YAML
+// booleans or null.
+var plainKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// Options used to define options for Encrypt.
+type Options struct {
+	// Reader is the source of randomness of the data key and nonces. The
+	// default is crypto/rand.Reader.
+	Reader io.Reader
+	_      struct{}
+}
+
+// Encrypt returns the SOPS-encrypted YAML document holding the given values,
+// in the order of their keys. Values whose key ends with UnencryptedSuffix,
+// such as user names, are written in plain text but still authenticated.
+func Encrypt(ctx context.Context, values map[string]string, wrappers []keywrap.KeyWrapper, opts Options) ([]byte, error) {
+	return encrypt(ctx, values, wrappers, opts, time.Now())
+}
+
+// encrypt is Encrypt at the given time.
+func encrypt(ctx context.Context, values map[string]string, wrappers []keywrap.KeyWrapper, opts Options, now time.Time) ([]byte, error) {
+	if len(wrappers) == 0 {
+		return nil, ErrNoKeyWrappers
+	}
+	r := opts.Reader
+	if r == nil {
+		r = rand.Reader
+	}
+	now = now.UTC().Truncate(time.Second)
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := io.ReadFull(r, dataKey); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	defer clear(dataKey)
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		if k == "" {
+			return nil, ErrInvalidKey
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	mac := sha512.New()
+	for _, k := range keys {
+		v := values[k]
+		mac.Write([]byte(v))
+
+		if strings.HasSuffix(k, UnencryptedSuffix) {
+			fmt.Fprintf(&b, "%s: %s\n", quoteKey(k), strconv.Quote(v))
+			continue
+		}
+		enc, err := encryptValue(r, dataKey, v, k+":")
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&b, "%s: %s\n", quoteKey(k), enc)
+	}
+
+	groups := map[string][][]string{}
+	created := strconv.Quote(now.Format(time.RFC3339))
+	for _, w := range wrappers {
+		wrapped, err := w.Wrap(ctx, dataKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap data key with %s: %w", w.KeyID(), err)
+		}
+
+		group, entry, err := keyEntry(w, wrapped)
+		if err != nil {
+			return nil, err
+		}
+		groups[group] = append(groups[group], append(entry, "created_at: "+created))
+	}
+
+	sum, err := encryptValue(r, dataKey, fmt.Sprintf("%X", mac.Sum(nil)), now.Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+
+	b.WriteString("sops:\n")
+	for _, group := range []string{"kms", "gcp_kms", "hc_vault"} {
+		if len(groups[group]) == 0 {
+			continue
+		}
+		b.WriteString("    " + group + ":\n")
+		for _, entry := range groups[group] {
+			for i, line := range entry {
+				if i == 0 {
+					b.WriteString("        - " + line + "\n")
+				} else {
+					b.WriteString("          " + line + "\n")
+				}
+			}
+		}
+	}
+	fmt.Fprintf(&b, "    lastmodified: %s\n", created)
+	fmt.Fprintf(&b, "    mac: %s\n", sum)
+	fmt.Fprintf(&b, "    unencrypted_suffix: %s\n", UnencryptedSuffix)
+	fmt.Fprintf(&b, "    version: %s\n", Version)
+	return []byte(b.String()), nil
+}
+
+// keyEntry returns the metadata group and the fields of the SOPS key of the
+// KeyWrapper, with the wrapped data key.
+func keyEntry(w keywrap.KeyWrapper, wrapped []byte) (string, []string, error) {
+	switch w := w.(type) {
+	case *awskms.KeyWrapper:
+		entry := []string{"arn: " + strconv.Quote(w.KeyID())}
+		if ec := w.EncryptionContext(); len(ec) > 0 {
+			entry = append(entry, "context:")
+			names := make([]string, 0, len(ec))
+			for name := range ec {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				entry = append(entry, "    "+quoteKey(name)+": "+strconv.Quote(ec[name]))
+			}
+		}
+		return "kms", append(entry, "enc: "+base64.StdEncoding.EncodeToString(wrapped)), nil
+	case *gcpkms.KeyWrapper:
+		return "gcp_kms", []string{
+			"resource_id: " + strconv.Quote(w.KeyID()),
+			"enc: " + base64.StdEncoding.EncodeToString(wrapped),
+		}, nil
+	case *transit.KeyWrapper:
+		return "hc_vault", []string{
+			"vault_address: " + strconv.Quote(w.Address()),
+			"engine_path: " + strconv.Quote(w.Mount()),
+			"key_name: " + strconv.Quote(w.Key()),
+			"enc: " + strconv.Quote(string(wrapped)),
+		}, nil
+	default:
+		return "", nil, fmt.Errorf("%w: %T", ErrUnsupportedKeyWrapper, w)
+	}
+}
+
+// encryptValue returns the SOPS encoding of the value encrypted with the data
+// key, authenticating the given additional data. As in SOPS, empty values are
+// left empty.
+func encryptValue(r io.Reader, key []byte, value, additionalData string) (string, error) {
+	if value == "" {
+		return `""`, nil
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, nonceSize)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(r, nonce); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	out := gcm.Seal(nil, nonce, []byte(value), []byte(additionalData))
+	tag := len(out) - gcm.Overhead()
+	return fmt.Sprintf("ENC[AES256_GCM,data:%s,iv:%s,tag:%s,type:str]",
+		base64.StdEncoding.EncodeToString(out[:tag]),
+		base64.StdEncoding.EncodeToString(nonce),
+		base64.StdEncoding.EncodeToString(out[tag:])), nil
+}
+
+// quoteKey returns the key as a YAML scalar, quoted unless it is a plain
+// identifier.
+func quoteKey(k string) string {
+	switch strings.ToLower(k) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null":
+		return strconv.Quote(k)
+	}
+	if plainKey.MatchString(k) {
+		return k
+	}
+	return strconv.Quote(k)
+}
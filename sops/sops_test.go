@@ -0,0 +1,162 @@
+package sops
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juev/go-password/keywrap"
+	"github.com/juev/go-password/keywrap/transit"
+)
+
+// testTransit returns a transit KeyWrapper backed by a fake engine which
+// "encrypts" by prefixing the plaintext.
+func testTransit(tb testing.TB) *transit.KeyWrapper {
+	tb.Helper()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || r.URL.Path != "/v1/sops/encrypt/app" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]string{"ciphertext": "vault:v1:" + req["plaintext"]},
+		})
+	}))
+	tb.Cleanup(ts.Close)
+	return transit.New(transit.Options{Address: ts.URL, Token: "s.token", Mount: "sops", Key: "app", Client: ts.Client()})
+}
+
+// encValue matches the encrypted values of a document.
+var encValue = regexp.MustCompile(`ENC\[AES256_GCM,data:([^,]*),iv:([^,]*),tag:([^,]*),type:str\]`)
+
+// testDecrypt decrypts an encrypted value of a document.
+func testDecrypt(tb testing.TB, key []byte, value, additionalData string) string {
+	tb.Helper()
+
+	m := encValue.FindStringSubmatch(value)
+	if m == nil {
+		tb.Fatalf("%q is not an encrypted value", value)
+	}
+	var parts [3][]byte
+	for i := range parts {
+		var err error
+		if parts[i], err = base64.StdEncoding.DecodeString(m[i+1]); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, nonceSize)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	plaintext, err := gcm.Open(nil, parts[1], append(parts[0], parts[2]...), []byte(additionalData))
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return string(plaintext)
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+	values := map[string]string{
+		"password":             "s3cr3t!",
+		"username_unencrypted": "app",
+		"api key":              "k",
+		"empty":                "",
+	}
+	doc, err := encrypt(context.Background(), values, []keywrap.KeyWrapper{testTransit(t)}, Options{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := make(map[string]string)
+	for _, line := range strings.Split(string(doc), "\n") {
+		if k, v, ok := strings.Cut(strings.TrimSpace(line), ": "); ok {
+			fields[k] = v
+		}
+	}
+
+	wrapped := strings.Trim(fields["enc"], `"`)
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(wrapped, "vault:v1:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := testDecrypt(t, key, fields["password"], "password:"); got != "s3cr3t!" {
+		t.Errorf("expected %q to be %q", got, "s3cr3t!")
+	}
+	if got := testDecrypt(t, key, fields[`"api key"`], "api key:"); got != "k" {
+		t.Errorf("expected %q to be %q", got, "k")
+	}
+	for k, want := range map[string]string{
+		"username_unencrypted": `"app"`,
+		"empty":                `""`,
+		"vault_address":        fields["vault_address"],
+		"engine_path":          `"sops"`,
+		"key_name":             `"app"`,
+		"created_at":           `"2024-05-01T12:30:00Z"`,
+		"lastmodified":         `"2024-05-01T12:30:00Z"`,
+		"unencrypted_suffix":   UnencryptedSuffix,
+		"version":              Version,
+	} {
+		if fields[k] != want {
+			t.Errorf("expected %s to be %v, got %v", k, want, fields[k])
+		}
+	}
+
+	// The MAC covers the values in the order of the document.
+	sum := sha512.Sum512([]byte("k" + "" + "s3cr3t!" + "app"))
+	if got, want := testDecrypt(t, key, fields["mac"], "2024-05-01T12:30:00Z"), fmt.Sprintf("%X", sum); got != want {
+		t.Errorf("expected %v to be %v", got, want)
+	}
+
+	if !strings.Contains(string(doc), "sops:\n    hc_vault:\n        - vault_address: ") {
+		t.Errorf("unexpected metadata in %s", doc)
+	}
+}
+
+type fakeWrapper struct{}
+
+func (fakeWrapper) KeyID() string { return "fake" }
+
+func (fakeWrapper) Wrap(_ context.Context, key []byte) ([]byte, error) { return key, nil }
+
+func (fakeWrapper) Unwrap(_ context.Context, wrapped []byte) ([]byte, error) { return wrapped, nil }
+
+func TestEncryptErrors(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	values := map[string]string{"password": "x"}
+	if _, err := Encrypt(ctx, values, nil, Options{}); !errors.Is(err, ErrNoKeyWrappers) {
+		t.Errorf("expected %v to be %v", err, ErrNoKeyWrappers)
+	}
+	if _, err := Encrypt(ctx, values, []keywrap.KeyWrapper{fakeWrapper{}}, Options{}); !errors.Is(err, ErrUnsupportedKeyWrapper) {
+		t.Errorf("expected %v to be %v", err, ErrUnsupportedKeyWrapper)
+	}
+	if _, err := Encrypt(ctx, map[string]string{"": "x"}, []keywrap.KeyWrapper{testTransit(t)}, Options{}); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidKey)
+	}
+	if got := quoteKey("yes"); got != `"yes"` {
+		t.Errorf("expected %v to be %v", got, `"yes"`)
+	}
+}
@@ -0,0 +1,205 @@
+// Package systemdcred hands generated secrets to services through systemd
+// credentials instead of environment variables, which leak through
+// /proc/*/environ, child processes and crash reports.
+//
+// WriteFile stores a credential in a directory such as /etc/credstore, readable
+// by root only, and LoadCredential returns the unit file directive which passes
+// it to a service. Encrypt seals a credential with systemd-creds, so that it
+// can only be decrypted on the same host or TPM, and is loaded with
+// LoadCredentialEncrypted. The service reads it with Read.
+//
+//	pw, err := password.Generate(password.Input{Length: 32, Digits: 6, Symbols: 6})
+//	if err != nil {
+//		return err
+//	}
+//	path, err := systemdcred.WriteFile("/etc/credstore", "db-password", []byte(pw))
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(systemdcred.LoadCredential("db-password", path))
+package systemdcred
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultDir is the directory systemd searches for credentials which are
+	// loaded without a path.
+	DefaultDir = "/etc/credstore"
+
+	// DefaultEncryptedDir is the directory systemd searches for encrypted
+	// credentials which are loaded without a path.
+	DefaultEncryptedDir = "/etc/credstore.encrypted"
+
+	// maxNameLength is the maximum length of a credential name.
+	maxNameLength = 255
+)
+
+var (
+	// ErrInvalidName is the error returned when a credential name is not a
+	// valid file name, or contains ':' which separates it from the path in
+	// unit file directives.
+	ErrInvalidName = errors.New("invalid credential name")
+
+	// ErrNoCredentialsDirectory is the error returned by Read when the
+	// process was not started with credentials.
+	ErrNoCredentialsDirectory = errors.New("CREDENTIALS_DIRECTORY is not set")
+)
+
+// ValidName reports whether name can be used as a credential name: a non-empty
+// file name of at most 255 bytes, other than "." and "..", without '/', ':' or
+// NUL.
+func ValidName(name string) bool {
+	return name != "" && len(name) <= maxNameLength &&
+		name != "." && name != ".." &&
+		!strings.ContainsAny(name, "/:\x00")
+}
+
+// WriteFile writes the secret to the credential file of the given name in dir,
+// creating dir if needed, and returns its path. The directory is only
+// accessible by its owner and the file is only readable by its owner, as
+// systemd expects of credential files. The file is replaced atomically, so
+// that services never read a partial secret.
+func WriteFile(dir, name string, secret []byte) (string, error) {
+	if !ValidName(name) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create credential directory: %w", err)
+	}
+
+	path := filepath.Join(dir, name)
+	if err := writeFileAtomic(path, secret); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path, readable by
+// its owner only, and renames it to path.
+func writeFileAtomic(path string, data []byte) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create credential file: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if err := f.Chmod(0o400); err != nil {
+		return fmt.Errorf("failed to set credential file permissions: %w", err)
+	}
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("failed to write credential file: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to write credential file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write credential file: %w", err)
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace credential file: %w", err)
+	}
+	return nil
+}
+
+// LoadCredential returns the unit file directive which passes the credential
+// file at path to the service under the given name. If path is empty, systemd
+// searches the credential directories, such as DefaultDir.
+func LoadCredential(name, path string) string {
+	return directive("LoadCredential", name, path)
+}
+
+// LoadCredentialEncrypted returns the unit file directive which decrypts the
+// credential file at path, as written by Encrypt, and passes it to the service
+// under the given name. If path is empty, systemd searches the encrypted
+// credential directories, such as DefaultEncryptedDir.
+func LoadCredentialEncrypted(name, path string) string {
+	return directive("LoadCredentialEncrypted", name, path)
+}
+
+// directive returns a credential directive, escaping the specifiers systemd
+// expands in unit files.
+func directive(key, name, path string) string {
+	value := name
+	if path != "" {
+		value += ":" + path
+	}
+	return key + "=" + strings.ReplaceAll(value, "%", "%%")
+}
+
+// EncryptOptions are the options of Encrypt.
+type EncryptOptions struct {
+	// Key is the key to encrypt with, as accepted by the --with-key option
+	// of systemd-creds, such as "host", "tpm2" or "host+tpm2". If empty,
+	// systemd-creds picks the best available key.
+	Key string
+
+	// Command is the systemd-creds executable. If empty, it is searched in
+	// PATH.
+	Command string
+}
+
+// Encrypt encrypts the secret with systemd-creds for the credential of the
+// given name, which must match the name it is loaded under. The result is
+// meant to be written with WriteFile and loaded with LoadCredentialEncrypted.
+func Encrypt(ctx context.Context, name string, secret []byte, opts EncryptOptions) ([]byte, error) {
+	if !ValidName(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	command := opts.Command
+	if command == "" {
+		command = "systemd-creds"
+	}
+	args := []string{"encrypt", "--name=" + name}
+	if opts.Key != "" {
+		args = append(args, "--with-key="+opts.Key)
+	}
+	args = append(args, "-", "-")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Stdin = bytes.NewReader(secret)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("failed to encrypt credential: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("failed to encrypt credential: %w", err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// Read reads the credential of the given name passed to the current service,
+// from the directory systemd exposes as $CREDENTIALS_DIRECTORY. Encrypted
+// credentials are already decrypted.
+func Read(name string) ([]byte, error) {
+	if !ValidName(name) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidName, name)
+	}
+
+	dir := os.Getenv("CREDENTIALS_DIRECTORY")
+	if dir == "" {
+		return nil, ErrNoCredentialsDirectory
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credential: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,170 @@
+package systemdcred
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/juev/go-password/password"
+)
+
+func TestValidName(t *testing.T) {
+	t.Parallel()
+
+	for name, valid := range map[string]bool{
+		"db-password":             true,
+		"app.token":               true,
+		"":                        false,
+		".":                       false,
+		"..":                      false,
+		"a/b":                     false,
+		"a:b":                     false,
+		"a\x00b":                  false,
+		string(make([]byte, 256)): false,
+	} {
+		if got := ValidName(name); got != valid {
+			t.Errorf("expected ValidName(%q) to be %t", name, valid)
+		}
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.Join(t.TempDir(), "credstore")
+	pw := password.MustGenerate(password.Input{Length: 32, Digits: 6, Symbols: 6})
+	path, err := WriteFile(dir, "db-password", []byte(pw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != filepath.Join(dir, "db-password") {
+		t.Errorf("unexpected path %q", path)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != pw {
+		t.Errorf("expected %q to be %q", b, pw)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o400 {
+			t.Errorf("expected file permissions %o to be %o", perm, 0o400)
+		}
+		info, err = os.Stat(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o700 {
+			t.Errorf("expected directory permissions %o to be %o", perm, 0o700)
+		}
+	}
+
+	// Replacing a read-only credential must succeed.
+	if _, err := WriteFile(dir, "db-password", []byte("rotated")); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := os.ReadFile(path); string(b) != "rotated" {
+		t.Errorf("expected %q to be %q", b, "rotated")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary files to be left, got %d entries", len(entries))
+	}
+
+	if _, err := WriteFile(dir, "../escape", []byte(pw)); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidName)
+	}
+}
+
+func TestLoadCredential(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		got, want string
+	}{
+		{LoadCredential("db-password", "/etc/credstore/db-password"), "LoadCredential=db-password:/etc/credstore/db-password"},
+		{LoadCredential("db-password", ""), "LoadCredential=db-password"},
+		{LoadCredential("token", "/run/50%/token"), "LoadCredential=token:/run/50%%/token"},
+		{LoadCredentialEncrypted("token", "/etc/credstore.encrypted/token"), "LoadCredentialEncrypted=token:/etc/credstore.encrypted/token"},
+	}
+
+	for _, tc := range cases {
+		if tc.got != tc.want {
+			t.Errorf("expected %q to be %q", tc.got, tc.want)
+		}
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	// The fake systemd-creds prints its arguments and echoes its input.
+	command := filepath.Join(t.TempDir(), "systemd-creds")
+	script := "#!/bin/sh\necho \"$@\"\ncat\n"
+	if err := os.WriteFile(command, []byte(script), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := Encrypt(context.Background(), "token", []byte("secret"), EncryptOptions{Key: "host", Command: command})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "encrypt --name=token --with-key=host - -\nsecret"; string(b) != want {
+		t.Errorf("expected %q to be %q", b, want)
+	}
+
+	failing := filepath.Join(t.TempDir(), "systemd-creds")
+	if err := os.WriteFile(failing, []byte("#!/bin/sh\necho 'no key' >&2\nexit 1\n"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Encrypt(context.Background(), "token", []byte("secret"), EncryptOptions{Command: failing}); err == nil {
+		t.Error("expected the systemd-creds error to be returned")
+	}
+
+	if _, err := Encrypt(context.Background(), "a:b", nil, EncryptOptions{Command: command}); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("expected %v to be %v", err, ErrInvalidName)
+	}
+}
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "token"), []byte("secret"), 0o400); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CREDENTIALS_DIRECTORY", "")
+	if _, err := Read("token"); !errors.Is(err, ErrNoCredentialsDirectory) {
+		t.Errorf("expected %v to be %v", err, ErrNoCredentialsDirectory)
+	}
+
+	t.Setenv("CREDENTIALS_DIRECTORY", dir)
+	b, err := Read("token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "secret" {
+		t.Errorf("expected %q to be %q", b, "secret")
+	}
+
+	if _, err := Read("missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected %v to be %v", err, os.ErrNotExist)
+	}
+}
@@ -0,0 +1,197 @@
+// Package yubikey generates passwords for the static password mode of YubiKey
+// OTP slots, and the ykman commands programming them. A static password is
+// typed by the key as keystrokes, so it must only contain characters the
+// chosen keyboard layout can produce, and is at most MaxStaticLength
+// characters long.
+//
+//	s, err := yubikey.Generate(password.Input{Length: 38}, yubikey.StaticOptions{Slot: 2})
+//	if err != nil {
+//		return err
+//	}
+//	fmt.Println(s.CommandLine())
+package yubikey
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/juev/go-password/password"
+)
+
+// MaxStaticLength is the maximum length of a static password.
+const MaxStaticLength = 38
+
+// ModHex is the alphabet of the modhex keyboard layout. Its characters are on
+// the same keys on virtually all keyboard layouts, so a modhex password types
+// correctly wherever the key is plugged in.
+const ModHex = "cbdefghijklnrtuv"
+
+var (
+	// ErrInvalidSlot is the error returned when the slot is neither 1 nor 2.
+	ErrInvalidSlot = errors.New("slot must be 1 or 2")
+
+	// ErrStaticLength is the error returned when a static password is empty or
+	// longer than MaxStaticLength.
+	ErrStaticLength = errors.New("static password must be between 1 and 38 characters")
+
+	// ErrLayoutCharacter is the error returned when a static password contains
+	// a character the keyboard layout cannot type.
+	ErrLayoutCharacter = errors.New("character cannot be typed with the keyboard layout")
+
+	// ErrUnknownLayout is the error returned for an unsupported keyboard
+	// layout.
+	ErrUnknownLayout = errors.New("unknown keyboard layout")
+)
+
+// Layout is the keyboard layout a static password is typed with, as named by
+// ykman.
+type Layout string
+
+const (
+	// LayoutModHex types only the ModHex characters. It is the default.
+	LayoutModHex Layout = "MODHEX"
+
+	// LayoutUS types printable ASCII on a US keyboard.
+	LayoutUS Layout = "US"
+)
+
+// StaticOptions used to define input parameters for Generate and NewStatic.
+type StaticOptions struct {
+	// Slot is the OTP slot to program, 1 (short touch) or 2 (long touch).
+	Slot int
+
+	// Layout is the keyboard layout. The default is LayoutModHex.
+	Layout Layout
+
+	// NoEnter disables the Enter keystroke after the password.
+	NoEnter bool
+
+	// Generator is the generator used by Generate. The default is
+	// password.NewGenerator(). For LayoutModHex, its lowercase letters are
+	// replaced with ModHex. For LayoutUS, its character sets are used as they
+	// are, and NewStatic rejects passwords the layout cannot type.
+	Generator *password.Generator
+	_         struct{}
+}
+
+// Static is a static password and the slot it is programmed into.
+type Static struct {
+	Slot     int
+	Layout   Layout
+	NoEnter  bool
+	Password string
+}
+
+// Fit returns the input adjusted to the static password constraints of the
+// layout: the length is capped at MaxStaticLength, and for LayoutModHex the
+// password is made of lowercase letters only, repeats allowed, since ModHex
+// has no digits, symbols or uppercase letters and only 16 characters. The
+// counts and ranges of digits and symbols are dropped, and so is
+// RequireFromEachClass.
+func Fit(in password.Input, layout Layout) password.Input {
+	in.Length = min(in.Length, MaxStaticLength)
+	if layout == LayoutModHex || layout == "" {
+		in.Digits, in.Symbols = 0, 0
+		in.MinDigits, in.MaxDigits = 0, 0
+		in.MinSymbols, in.MaxSymbols = 0, 0
+		in.NoUpper = true
+		in.RequireFromEachClass = false
+		in.AllowRepeat = true
+		in.MaxSameClassRun = 0
+	}
+	return in
+}
+
+// Generate generates a static password from the input adjusted by Fit.
+func Generate(in password.Input, opts StaticOptions) (Static, error) {
+	gen := password.NewGenerator()
+	if opts.Generator != nil {
+		gen = *opts.Generator
+	}
+
+	switch opts.layout() {
+	case LayoutModHex:
+		gen = gen.WithLowerLetters(ModHex)
+	case LayoutUS:
+	default:
+		return Static{}, fmt.Errorf("%w %q", ErrUnknownLayout, opts.Layout)
+	}
+
+	res, err := gen.Generate(Fit(in, opts.Layout))
+	if err != nil {
+		return Static{}, fmt.Errorf("failed to generate static password: %w", err)
+	}
+	return NewStatic(res, opts)
+}
+
+// NewStatic checks that the password can be programmed as a static password
+// with the given options.
+func NewStatic(pw string, opts StaticOptions) (Static, error) {
+	if opts.Slot != 1 && opts.Slot != 2 {
+		return Static{}, ErrInvalidSlot
+	}
+	if pw == "" || len(pw) > MaxStaticLength {
+		return Static{}, ErrStaticLength
+	}
+
+	layout := opts.layout()
+	for _, r := range pw {
+		var ok bool
+		switch layout {
+		case LayoutModHex:
+			ok = strings.ContainsRune(ModHex, r)
+		case LayoutUS:
+			ok = r >= ' ' && r <= '~'
+		default:
+			return Static{}, fmt.Errorf("%w %q", ErrUnknownLayout, opts.Layout)
+		}
+		if !ok {
+			return Static{}, fmt.Errorf("%w %s: %q", ErrLayoutCharacter, layout, r)
+		}
+	}
+
+	return Static{
+		Slot:     opts.Slot,
+		Layout:   layout,
+		NoEnter:  opts.NoEnter,
+		Password: pw,
+	}, nil
+}
+
+// layout returns the layout, or the default.
+func (o StaticOptions) layout() Layout {
+	if o.Layout == "" {
+		return LayoutModHex
+	}
+	return o.Layout
+}
+
+// Command returns the arguments of the ykman command programming the static
+// password, overwriting the slot without confirmation.
+func (s Static) Command() []string {
+	args := []string{"ykman", "otp", "static", "--force", "--keyboard-layout", string(s.Layout)}
+	if s.NoEnter {
+		args = append(args, "--no-enter")
+	}
+	return append(args, fmt.Sprint(s.Slot), s.Password)
+}
+
+// CommandLine returns the ykman command as a line for a POSIX shell script.
+// The password is part of the line, so the script must be protected like the
+// password itself.
+func (s Static) CommandLine() string {
+	args := s.Command()
+	for i, arg := range args {
+		args[i] = shellQuote(arg)
+	}
+	return strings.Join(args, " ")
+}
+
+// shellQuote quotes s for a POSIX shell, if needed.
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,122 @@
+package yubikey
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/juev/go-password/password"
+)
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("modhex", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := Generate(password.Input{Length: 64, Digits: 10, Symbols: 10}, StaticOptions{Slot: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s.Password) != MaxStaticLength {
+			t.Errorf("expected %d characters, got %q", MaxStaticLength, s.Password)
+		}
+		if strings.Trim(s.Password, ModHex) != "" {
+			t.Errorf("expected only modhex characters, got %q", s.Password)
+		}
+		if s.Layout != LayoutModHex || s.Slot != 2 {
+			t.Errorf("unexpected static password %+v", s)
+		}
+	})
+
+	t.Run("modhex ranges", func(t *testing.T) {
+		t.Parallel()
+
+		in := password.Input{Length: 32, MinDigits: 2, MaxDigits: 4, MinSymbols: 1, RequireFromEachClass: true}
+		s, err := Generate(in, StaticOptions{Slot: 1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Trim(s.Password, ModHex) != "" {
+			t.Errorf("expected only modhex characters, got %q", s.Password)
+		}
+	})
+
+	t.Run("us", func(t *testing.T) {
+		t.Parallel()
+
+		s, err := Generate(password.Input{Length: 20, Digits: 4, Symbols: 4}, StaticOptions{Slot: 1, Layout: LayoutUS})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(s.Password) != 20 {
+			t.Errorf("expected 20 characters, got %q", s.Password)
+		}
+		if strings.IndexAny(s.Password, password.Digits) < 0 {
+			t.Errorf("expected digits, got %q", s.Password)
+		}
+	})
+
+	t.Run("unknown layout", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := Generate(password.Input{Length: 20}, StaticOptions{Slot: 1, Layout: "DVORAK"})
+		if !errors.Is(err, ErrUnknownLayout) {
+			t.Errorf("expected %q to be %q", err, ErrUnknownLayout)
+		}
+	})
+}
+
+func TestNewStatic(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		pw   string
+		opts StaticOptions
+		err  error
+	}{
+		{"modhex", "cbdefghijklnrtuv", StaticOptions{Slot: 1}, nil},
+		{"us", "Hello, World!", StaticOptions{Slot: 2, Layout: LayoutUS}, nil},
+		{"slot", "cbdef", StaticOptions{Slot: 3}, ErrInvalidSlot},
+		{"empty", "", StaticOptions{Slot: 1}, ErrStaticLength},
+		{"long", strings.Repeat("c", MaxStaticLength+1), StaticOptions{Slot: 1}, ErrStaticLength},
+		{"modhex upper", "Cbdef", StaticOptions{Slot: 1}, ErrLayoutCharacter},
+		{"modhex digit", "cbdef1", StaticOptions{Slot: 1}, ErrLayoutCharacter},
+		{"us non-ascii", "café", StaticOptions{Slot: 1, Layout: LayoutUS}, ErrLayoutCharacter},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewStatic(tc.pw, tc.opts)
+			if !errors.Is(err, tc.err) {
+				t.Errorf("expected %v to be %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestStaticCommand(t *testing.T) {
+	t.Parallel()
+
+	s := Static{Slot: 2, Layout: LayoutUS, NoEnter: true, Password: "it's$x"}
+	want := []string{"ykman", "otp", "static", "--force", "--keyboard-layout", "US", "--no-enter", "2", "it's$x"}
+	if got := s.Command(); !slices.Equal(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	wantLine := `ykman otp static --force --keyboard-layout US --no-enter 2 'it'\''s$x'`
+	if got := s.CommandLine(); got != wantLine {
+		t.Errorf("expected %q, got %q", wantLine, got)
+	}
+
+	s = Static{Slot: 1, Layout: LayoutModHex, Password: "cbdef"}
+	if got, want := s.CommandLine(), "ykman otp static --force --keyboard-layout MODHEX 1 cbdef"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}